@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/notify"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/query"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// maxAlertLines caps the issues listed in one alert message
+const maxAlertLines = 20
+
+// Reasons an issue is alerted on
+const (
+	alertNew       = "新问题"
+	alertEscalated = "优先级升级"
+	alertReopened  = "重新打开"
+	alertRepeated  = "重复通知"
+)
+
+// alertItem is an issue an alert notifies about, with the reason
+type alertItem struct {
+	Issue  model.Issue
+	Reason string
+}
+
+// alertCommand posts the issues newly matching the saved-search alerts
+func alertCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "alert",
+		Usage: "按 notify.alerts 和各 portfolio 的保存搜索发送告警, 只通知新匹配、优先级升级或重新打开的问题",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "name",
+				Usage: "只处理指定名称的告警 (portfolio 的保存搜索为 <portfolio>/<搜索名>)",
+			},
+			&cli.BoolFlag{
+				Name:  "renotify",
+				Usage: "忽略发送记录, 重新通知所有匹配的问题",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "只打印将要发送的告警, 不发送也不记录",
+			},
+		},
+		Action: runAlert,
+	}
+}
+
+// runAlert evaluates the configured alerts against the stored issues
+func runAlert(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	alerts := config.Alerts()
+	if name := c.String("name"); name != "" {
+		var selected []notify.Alert
+		for _, alert := range alerts {
+			if alert.Name == name {
+				selected = append(selected, alert)
+			}
+		}
+		if len(selected) == 0 {
+			return fmt.Errorf("unknown alert: %s", name)
+		}
+		alerts = selected
+	}
+	if len(alerts) == 0 {
+		fmt.Println("未配置告警 (notify.alerts 或 portfolios 的 searches)")
+		return nil
+	}
+	if !c.Bool("dry-run") && !config.AlertsEnabled() {
+		return fmt.Errorf("no webhook configured for alerts (set notify.webhooks or the alert's webhooks)")
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	return postAlerts(c.Context, config, store, alerts, c.Bool("renotify"), c.Bool("dry-run"))
+}
+
+// postAlerts posts the pending issues of alerts and records them as sent.
+// Issues already notified are skipped unless renotify is set, or their
+// priority rose or they were reopened since.
+func postAlerts(ctx context.Context, config scraper.Config, store *storage.Store, alerts []notify.Alert, renotify, dryRun bool) error {
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	config.Portfolios.MarkCorpus(issues)
+	sent, err := store.LoadNotificationsSent()
+	if err != nil {
+		return fmt.Errorf("failed to load sent notifications: %w", err)
+	}
+
+	now := time.Now()
+	var firstErr error
+	for _, alert := range alerts {
+		alertConfig := config.Notify.AlertConfig(alert)
+		if !dryRun && !alertConfig.Enabled() {
+			continue
+		}
+		search, err := query.Parse(alert.Query)
+		if err != nil {
+			return fmt.Errorf("alert %s: %w", alert.Name, err)
+		}
+
+		items := pendingAlerts(search, issues, sent[alert.Name], renotify)
+		if len(items) == 0 {
+			continue
+		}
+		text := alertText(alert, items)
+		if dryRun {
+			fmt.Println(text)
+			fmt.Println()
+			continue
+		}
+		if err := notify.Post(ctx, alertConfig, map[string]interface{}{"text": text}); err != nil {
+			log.Printf("⚠️  警告: 未能发送告警 %s: %v", alert.Name, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		if sent[alert.Name] == nil {
+			sent[alert.Name] = make(map[string]notify.Sent)
+		}
+		for _, item := range items {
+			sent[alert.Name][model.IssueRef(item.Issue.Repository, item.Issue.Number)] = notify.Sent{
+				Priority:   item.Issue.Priority,
+				State:      item.Issue.State,
+				NotifiedAt: now,
+			}
+		}
+		log.Printf("🔔 告警 %s: 已通知 %d 个问题", alert.Name, len(items))
+
+		for _, team := range config.Teams {
+			if len(team.Webhooks) == 0 {
+				continue
+			}
+			var routed []alertItem
+			for _, item := range items {
+				if item.Issue.AssignedTeam == team.Name {
+					routed = append(routed, item)
+				}
+			}
+			if len(routed) == 0 {
+				continue
+			}
+			teamConfig := config.Notify
+			teamConfig.Webhooks = team.Webhooks
+			if err := notify.Post(ctx, teamConfig, map[string]interface{}{"text": alertText(alert, routed)}); err != nil {
+				log.Printf("⚠️  警告: 未能向团队 %s 发送告警 %s: %v", team.Name, alert.Name, err)
+				continue
+			}
+			log.Printf("🔔 告警 %s: 已通知团队 %s %d 个问题", alert.Name, team.Name, len(routed))
+		}
+	}
+
+	if dryRun {
+		return firstErr
+	}
+	if err := store.SaveNotificationsSent(sent); err != nil {
+		return fmt.Errorf("failed to save sent notifications: %w", err)
+	}
+	return firstErr
+}
+
+// pendingAlerts returns the matching issues to notify about, highest
+// score first
+func pendingAlerts(search query.AdvancedSearch, issues map[string][]model.Issue, sent map[string]notify.Sent, renotify bool) []alertItem {
+	var items []alertItem
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			issue.Repository = repoName
+			if !search.Match(issue) {
+				continue
+			}
+			previous, ok := sent[model.IssueRef(repoName, issue.Number)]
+			switch {
+			case !ok:
+				items = append(items, alertItem{Issue: issue, Reason: alertNew})
+			case scraper.Escalated(previous.Priority, issue.Priority):
+				items = append(items, alertItem{Issue: issue, Reason: alertEscalated})
+			case previous.State == "closed" && issue.State == "open":
+				items = append(items, alertItem{Issue: issue, Reason: alertReopened})
+			case renotify:
+				items = append(items, alertItem{Issue: issue, Reason: alertRepeated})
+			}
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Issue.Score > items[j].Issue.Score })
+	return items
+}
+
+// alertText formats an alert message for Slack-compatible webhooks
+func alertText(alert notify.Alert, items []alertItem) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🔔 *%s*: %d 个问题需要关注", alert.Name, len(items))
+	for i, item := range items {
+		if i == maxAlertLines {
+			fmt.Fprintf(&b, "\n…还有 %d 个", len(items)-maxAlertLines)
+			break
+		}
+		issue := item.Issue
+		fmt.Fprintf(&b, "\n• <%s|%s#%d> %s (评分 %.1f, 优先级 %s, %s)",
+			issue.URL, issue.Repository, issue.Number, issue.Title, issue.Score, orDefault(issue.Priority, "-"), item.Reason)
+	}
+	return b.String()
+}
+
+// escalationLabels describe escalation kinds in messages
+var escalationLabels = map[string]string{
+	model.EscalationPriority:  "优先级",
+	model.EscalationReactions: "反应数",
+	model.EscalationReopened:  "状态",
+}
+
+// postEscalations posts the escalations detected by a scrape to
+// notify.webhooks, separately from alerts about new issues, those in a
+// portfolio's repositories to the portfolio's webhooks and those of a
+// team's issues to the team's webhooks
+func postEscalations(ctx context.Context, config scraper.Config, escalations []model.Escalation) {
+	if len(escalations) == 0 {
+		return
+	}
+	if config.Notify.Enabled() {
+		if err := notify.Post(ctx, config.Notify, map[string]interface{}{"text": escalationText(escalations)}); err != nil {
+			log.Printf("⚠️  警告: 未能发送问题升级通知: %v", err)
+		} else {
+			log.Printf("⬆️  已发送 %d 个问题升级通知", len(escalations))
+		}
+	}
+
+	for _, portfolio := range config.Portfolios {
+		if len(portfolio.Webhooks) == 0 {
+			continue
+		}
+		var routed []model.Escalation
+		for _, escalation := range escalations {
+			if portfolio.Contains(escalation.Repository) {
+				routed = append(routed, escalation)
+			}
+		}
+		if len(routed) == 0 {
+			continue
+		}
+		portfolioConfig := config.Notify
+		portfolioConfig.Webhooks = portfolio.Webhooks
+		if err := notify.Post(ctx, portfolioConfig, map[string]interface{}{"text": escalationText(routed)}); err != nil {
+			log.Printf("⚠️  警告: 未能向 portfolio %s 发送问题升级通知: %v", portfolio.Name, err)
+			continue
+		}
+		log.Printf("⬆️  已向 portfolio %s 发送 %d 个问题升级通知", portfolio.Name, len(routed))
+	}
+
+	for _, team := range config.Teams {
+		if len(team.Webhooks) == 0 {
+			continue
+		}
+		var routed []model.Escalation
+		for _, escalation := range escalations {
+			if escalation.Team == team.Name {
+				routed = append(routed, escalation)
+			}
+		}
+		if len(routed) == 0 {
+			continue
+		}
+		teamConfig := config.Notify
+		teamConfig.Webhooks = team.Webhooks
+		if err := notify.Post(ctx, teamConfig, map[string]interface{}{"text": escalationText(routed)}); err != nil {
+			log.Printf("⚠️  警告: 未能向团队 %s 发送问题升级通知: %v", team.Name, err)
+			continue
+		}
+		log.Printf("⬆️  已向团队 %s 发送 %d 个问题升级通知", team.Name, len(routed))
+	}
+}
+
+// escalationText formats escalations for Slack-compatible webhooks
+func escalationText(escalations []model.Escalation) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "⬆️ *问题升级*: %d 个已有问题变得更严重", len(escalations))
+	for i, escalation := range escalations {
+		if i == maxAlertLines {
+			fmt.Fprintf(&b, "\n…还有 %d 个", len(escalations)-maxAlertLines)
+			break
+		}
+		fmt.Fprintf(&b, "\n• <%s|%s#%d> %s (%s %s → %s)",
+			escalation.URL, escalation.Repository, escalation.IssueNumber, escalation.Title,
+			escalationLabels[escalation.Kind], orDefault(escalation.From, "-"), escalation.To)
+	}
+	return b.String()
+}
@@ -0,0 +1,463 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/analytics"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/query"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// analyticsCommand groups analytics over locally stored issues
+func analyticsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "analytics",
+		Usage: "分析本地已保存的问题数据",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "sla",
+				Usage: "统计问题老化情况并列出超出 SLA 的问题",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "untriaged-days",
+						Usage: "本地未分派 SLA 天数 (覆盖配置)",
+					},
+					&cli.IntFlag{
+						Name:  "unanswered-days",
+						Usage: "上游无回复 SLA 天数 (覆盖配置)",
+					},
+				},
+				Action: runAnalyticsSLA,
+			},
+			{
+				Name:  "reporters",
+				Usage: "列出跨仓库报告踩坑问题的用户及其信誉",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "all",
+						Usage: "包含只在单个仓库报告问题的用户",
+					},
+					&cli.IntFlag{
+						Name:  "limit",
+						Value: 20,
+						Usage: "最多显示的用户数",
+					},
+				},
+				Action: runAnalyticsReporters,
+			},
+			{
+				Name:      "group",
+				Usage:     "按任意维度和时间粒度聚合问题",
+				ArgsUsage: "[查询条件]",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "by",
+						Usage: "聚合维度 (repo/category/team/author/state/label/platform/source), 可多次指定",
+					},
+					&cli.StringFlag{
+						Name:  "bucket",
+						Usage: "按创建时间分桶 (day/week/month)",
+					},
+				},
+				Action: runAnalyticsGroup,
+			},
+			{
+				Name:      "timeseries",
+				Usage:     "按时间统计问题数量和平均评分趋势",
+				ArgsUsage: "[查询条件]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "bucket",
+						Value: analytics.BucketDay,
+						Usage: "时间粒度 (day/week/month)",
+					},
+					&cli.StringFlag{
+						Name:  "group-by",
+						Usage: "按维度拆分为多条序列 (repo/category/team/author/state/label/platform/source)",
+					},
+					&cli.IntSliceFlag{
+						Name:  "rolling",
+						Usage: "滚动平均窗口天数, 例如 --rolling 7 --rolling 30",
+					},
+					&cli.BoolFlag{
+						Name:  "pct-change",
+						Usage: "计算环比变化百分比",
+					},
+					&cli.BoolFlag{
+						Name:  "normalize",
+						Usage: "同时输出按星期和节假日校正后的数量 (默认使用 trends.normalize)",
+					},
+					&cli.StringFlag{
+						Name:  "fill",
+						Usage: "空缺时间段的填充方式 (zero/previous/null)",
+					},
+					&cli.TimestampFlag{
+						Name:   "from",
+						Layout: "2006-01-02",
+						Usage:  "填充区间起点 (YYYY-MM-DD)",
+					},
+					&cli.TimestampFlag{
+						Name:   "to",
+						Layout: "2006-01-02",
+						Usage:  "填充区间终点 (YYYY-MM-DD)",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "以 JSON 输出",
+					},
+				},
+				Action: runAnalyticsTimeSeries,
+			},
+			{
+				Name:  "reclassified",
+				Usage: "列出分类或优先级的历史变更, 区分规则变更与问题本身的变化",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "repo",
+						Usage: "只显示该仓库的变更",
+					},
+					&cli.BoolFlag{
+						Name:  "taxonomy",
+						Usage: "只显示因分类规则版本变化产生的变更",
+					},
+				},
+				Action: runAnalyticsReclassified,
+			},
+			{
+				Name:   "usage",
+				Usage:  "按月份和提供方显示 API 请求用量及预算",
+				Action: runAnalyticsUsage,
+			},
+			{
+				Name:  "pool",
+				Usage: "按 API 主机显示每次抓取的并发槽位使用 (等待次数和时长), 超过 pool_alerts 阈值的标记 ⚠️",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "limit",
+						Value: 10,
+						Usage: "每个主机显示最近 N 次抓取",
+					},
+				},
+				Action: runAnalyticsPool,
+			},
+		},
+	}
+}
+
+// runAnalyticsUsage prints recorded API requests per month and provider
+func runAnalyticsUsage(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	usage, err := storage.NewStore(config.Storage.Dir).LoadProviderUsage()
+	if err != nil {
+		return fmt.Errorf("failed to load provider usage: %w", err)
+	}
+	if len(usage) == 0 {
+		fmt.Println("还没有记录 API 用量")
+		return nil
+	}
+
+	month := ""
+	for _, u := range usage {
+		if u.Month != month {
+			month = u.Month
+			total := model.MonthlyRequests(usage, month)
+			if config.Budget.MonthlyRequests > 0 {
+				fmt.Printf("%s: %d / %d 次请求\n", month, total, config.Budget.MonthlyRequests)
+			} else {
+				fmt.Printf("%s: %d 次请求\n", month, total)
+			}
+		}
+		fmt.Printf("  %-32s %8d 次请求  %4d 次运行\n", u.Provider, u.Requests, u.Runs)
+	}
+	return nil
+}
+
+// runAnalyticsPool prints the recorded slot usage per API host, latest
+// scrapes last
+func runAnalyticsPool(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	samples, err := storage.NewStore(config.Storage.Dir).LoadPoolSamples()
+	if err != nil {
+		return fmt.Errorf("failed to load pool metrics: %w", err)
+	}
+	if len(samples) == 0 {
+		fmt.Println("还没有记录并发槽位指标, 抓取后再查看")
+		return nil
+	}
+
+	byHost := make(map[string][]model.PoolSample)
+	for _, sample := range samples {
+		byHost[sample.Host] = append(byHost[sample.Host], sample)
+	}
+	for _, latest := range model.LatestPoolSamples(samples) {
+		hostSamples := byHost[latest.Host]
+		if limit := c.Int("limit"); limit > 0 && len(hostSamples) > limit {
+			hostSamples = hostSamples[len(hostSamples)-limit:]
+		}
+		fmt.Printf("%s (%d 个槽位)\n", latest.Host, latest.Slots)
+		for _, sample := range hostSamples {
+			mark := ""
+			if config.PoolAlerts.Exceeded(sample) {
+				mark = "  ⚠️"
+			}
+			fmt.Printf("  %s  %6d 次请求  %6d 次等待  累计 %8.1fs  最长 %6.1fs  限速等待 %8.1fs%s\n",
+				sample.SampledAt.In(config.Location()).Format("2006-01-02 15:04"), sample.Requests, sample.Waits,
+				sample.WaitSeconds, sample.MaxWaitSeconds, sample.PacedSeconds, mark)
+		}
+	}
+	return nil
+}
+
+// runAnalyticsReclassified prints the classification history for auditing
+func runAnalyticsReclassified(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	history, err := storage.NewStore(config.Storage.Dir).LoadClassificationHistory()
+	if err != nil {
+		return fmt.Errorf("failed to load classification history: %w", err)
+	}
+
+	taxonomy, shown := 0, 0
+	for _, change := range history {
+		if repo := c.String("repo"); repo != "" && !strings.EqualFold(change.Repository, repo) {
+			continue
+		}
+		if c.Bool("taxonomy") && !change.TaxonomyChange() {
+			continue
+		}
+
+		cause := "问题变化"
+		if change.Author != "" {
+			cause = fmt.Sprintf("人工修改 (%s)", change.Author)
+		} else if change.TaxonomyChange() {
+			cause = fmt.Sprintf("规则 %s -> %s", change.PreviousVersion, change.Version)
+			taxonomy++
+		}
+		fmt.Printf("%s  %s#%d  %s/%s -> %s/%s  (%s)\n",
+			change.ChangedAt.Format("2006-01-02 15:04"), change.Repository, change.IssueNumber,
+			change.PreviousCategory, change.PreviousPriority, change.Category, change.Priority, cause)
+		shown++
+	}
+
+	fmt.Printf("\n共 %d 条变更, 其中 %d 条由分类规则变化引起\n", shown, taxonomy)
+	return nil
+}
+
+// runAnalyticsSLA prints the SLA breach report for stored issues
+func runAnalyticsSLA(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if c.IsSet("untriaged-days") {
+		config.SLA.UntriagedDays = c.Int("untriaged-days")
+	}
+	if c.IsSet("unanswered-days") {
+		config.SLA.UnansweredDays = c.Int("unanswered-days")
+	}
+
+	issues, err := storage.NewStore(config.Storage.Dir).LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+
+	report := checkSLA(config, issues)
+
+	fmt.Printf("问题总数: %d\n", report.TotalIssues)
+	fmt.Printf("本地未分派: %d (平均等待 %.1f 天, SLA %d 天)\n",
+		report.Untriaged, analytics.Days(report.AvgUntriagedAge), config.SLA.UntriagedDays)
+	fmt.Printf("上游无回复: %d (SLA %d 天)\n", report.Unanswered, config.SLA.UnansweredDays)
+	fmt.Printf("超出 SLA: %d\n", len(report.Breaches))
+
+	for _, breach := range report.Breaches {
+		fmt.Printf("  [%s] %s#%d %.1f天 > %.0f天  %s\n",
+			breach.Kind, breach.Issue.Repository, breach.Issue.Number,
+			analytics.Days(breach.Age), analytics.Days(breach.Limit), breach.Issue.Title)
+	}
+
+	return nil
+}
+
+// runAnalyticsReporters prints reporters ranked by reputation
+func runAnalyticsReporters(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	issues, err := storage.NewStore(config.Storage.Dir).LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+
+	reporters := analytics.ComputeReporters(issues)
+	if !c.Bool("all") {
+		reporters = analytics.CrossRepoReporters(reporters)
+	}
+
+	fmt.Printf("报告者数量: %d\n", len(reporters))
+	for i, reporter := range reporters {
+		if i == c.Int("limit") {
+			break
+		}
+		fmt.Printf("  %-24s 信誉 %.2f  问题 %d  平均评分 %.1f  仓库 %v\n",
+			reporter.Author, reporter.Reputation, reporter.IssueCount, reporter.AvgScore, reporter.Repositories)
+	}
+
+	return nil
+}
+
+// runAnalyticsGroup prints issue counts grouped by dimensions and time buckets
+func runAnalyticsGroup(c *cli.Context) error {
+	search, err := query.Parse(strings.Join(c.Args().Slice(), " "))
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	issues, err := storage.NewStore(config.Storage.Dir).LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+
+	bucket := c.String("bucket")
+	dimensions := c.StringSlice("by")
+	if len(dimensions) == 0 && bucket == "" {
+		dimensions = []string{"category"}
+	}
+
+	rows, err := analytics.Aggregate(search.Filter(issues), dimensions, bucket, config.Location())
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		var columns []string
+		if bucket != "" {
+			columns = append(columns, analytics.FormatBucket(row.Bucket, bucket))
+		}
+		columns = append(columns, row.Keys...)
+		fmt.Printf("%-50s %6d  平均评分 %.1f\n", strings.Join(columns, "  "), row.Count, row.AvgScore)
+	}
+
+	return nil
+}
+
+// runAnalyticsTimeSeries prints per-bucket metrics with optional smoothing
+func runAnalyticsTimeSeries(c *cli.Context) error {
+	search, err := query.Parse(strings.Join(c.Args().Slice(), " "))
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	issues, err := storage.NewStore(config.Storage.Dir).LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+
+	if c.IsSet("normalize") {
+		config.Trends.Normalize = c.Bool("normalize")
+	}
+	calendar, err := trendCalendar(config)
+	if err != nil {
+		return err
+	}
+
+	result, err := analytics.TimeSeriesQuery(search.Filter(issues), analytics.TimeSeriesOptions{
+		Bucket:        c.String("bucket"),
+		GroupBy:       c.String("group-by"),
+		RollingDays:   c.IntSlice("rolling"),
+		PercentChange: c.Bool("pct-change"),
+		Fill:          c.String("fill"),
+		From:          timestampValue(c, "from", config.Location()),
+		To:            timestampValue(c, "to", config.Location()),
+		Location:      config.Location(),
+		Calendar:      calendar,
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("json") {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, series := range result.Series {
+		if series.Group != "" {
+			fmt.Printf("== %s ==\n", series.Group)
+		}
+		for _, point := range series.Points {
+			if point.Values == nil {
+				fmt.Printf("%-10s 数量    -  平均评分     -", analytics.FormatBucket(point.Bucket, result.Bucket))
+			} else {
+				fmt.Printf("%-10s 数量 %4.0f  平均评分 %5.1f", analytics.FormatBucket(point.Bucket, result.Bucket),
+					point.Values[analytics.MetricCount], point.Values[analytics.MetricAvgScore])
+				if normalized, ok := point.Values[analytics.MetricCountNormalized]; ok {
+					fmt.Printf("  校正数量 %6.1f", normalized)
+				}
+			}
+			for _, days := range c.IntSlice("rolling") {
+				fmt.Printf("  %dd均值 %5.2f", days, point.Rolling[fmt.Sprintf("%s_%dd", analytics.MetricCount, days)])
+			}
+			if change, ok := point.PercentChange[analytics.MetricCount]; ok {
+				fmt.Printf("  环比 %+.1f%%", change)
+			}
+			if change, ok := point.PercentChange[analytics.MetricCountNormalized]; ok {
+				fmt.Printf("  校正环比 %+.1f%%", change)
+			}
+			fmt.Println()
+		}
+	}
+
+	return nil
+}
+
+// timestampValue returns a timestamp flag value read as wall-clock time
+// in loc, or the zero time if unset
+func timestampValue(c *cli.Context, name string, loc *time.Location) time.Time {
+	if t := c.Timestamp(name); t != nil {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	}
+	return time.Time{}
+}
+
+// checkSLA evaluates issues against the configured SLA windows
+func checkSLA(config scraper.Config, issues map[string][]model.Issue) analytics.SLAReport {
+	day := 24 * time.Hour
+	return analytics.CheckSLA(issues,
+		time.Duration(config.SLA.UntriagedDays)*day,
+		time.Duration(config.SLA.UnansweredDays)*day,
+		time.Now())
+}
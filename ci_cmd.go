@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// ciCommand checks the current project's dependencies for new pitfalls
+func ciCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "ci",
+		Usage: "CI 模式: 抓取当前项目依赖仓库的问题, 报告新发现的高严重性踩坑, 超过阈值时以非零状态退出",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "project",
+				Value: ".",
+				Usage: "项目目录 (读取 go.mod 和 package.json)",
+			},
+			&cli.BoolFlag{
+				Name:  "transitive",
+				Usage: "同时检查间接依赖 (go.sum、package-lock.json)",
+			},
+			&cli.IntFlag{
+				Name:  "max-new",
+				Usage: "新发现的高严重性踩坑超过此数量时失败 (覆盖 ci.max_new)",
+			},
+			&cli.BoolFlag{
+				Name:  "baseline",
+				Usage: "只记录当前的踩坑作为基线, 不报告也不失败",
+			},
+		},
+		Action: runCI,
+	}
+}
+
+// runCI scrapes the project's dependency repositories and reports the
+// high-severity pitfalls not reported by earlier runs
+func runCI(c *cli.Context) error {
+	dependencies, err := detectDependencies(c.String("project"), c.Bool("transitive"))
+	if err != nil {
+		return fmt.Errorf("failed to read project manifests: %w", err)
+	}
+	if len(dependencies) == 0 {
+		fmt.Println("未在项目中检测到 GitHub 上的依赖")
+		return nil
+	}
+
+	config, err := ciConfig(c.String("config"), dependencies)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if token := c.String("token"); token != "" {
+		config.GitHubToken = token
+	} else if config.GitHubToken == "" {
+		config.GitHubToken = os.Getenv("GITHUB_TOKEN")
+	}
+	if c.IsSet("max-new") {
+		config.CI.MaxNew = c.Int("max-new")
+	}
+	config.Repositories = scopedRepositories(config, dependencies)
+	config.StackOverflow.Enabled = false
+
+	ctx, cancel := context.WithTimeout(c.Context, 30*time.Minute)
+	defer cancel()
+	store := storage.NewStore(config.Storage.Dir)
+	scraperInstance := scraper.NewScraper(config)
+	useCorrections(scraperInstance, store)
+
+	var found []model.Issue
+	scanned := 0
+	err = scraperInstance.ScrapeEach(ctx, config, func(repoName string, issues []model.Issue) {
+		scanned++
+		filtered := scraperInstance.FilterAndScoreIssues(map[string][]model.Issue{repoName: issues}, config)
+		for _, issue := range filtered[repoName] {
+			if config.CI.HighSeverity(issue) {
+				found = append(found, issue)
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scrape dependencies: %w", err)
+	}
+	printFailures(scraperInstance.Failures())
+	if scanned == 0 {
+		return fmt.Errorf("no dependency repository could be scraped")
+	}
+
+	seen, err := store.LoadCIFindings()
+	if err != nil {
+		return fmt.Errorf("failed to load previous findings: %w", err)
+	}
+	now := time.Now()
+	var fresh []model.Issue
+	for _, issue := range found {
+		ref := model.IssueRef(issue.Repository, issue.Number)
+		if _, ok := seen[ref]; !ok {
+			fresh = append(fresh, issue)
+			seen[ref] = now
+		}
+	}
+	sort.SliceStable(fresh, func(i, j int) bool { return fresh[i].Score > fresh[j].Score })
+	if err := store.SaveCIFindings(seen); err != nil {
+		return fmt.Errorf("failed to save findings: %w", err)
+	}
+
+	if c.Bool("baseline") {
+		fmt.Printf("📌 已将 %d 个高严重性踩坑记录为基线\n", len(found))
+		return nil
+	}
+	reportCIFindings(fresh, len(found), scanned)
+	if len(fresh) > config.CI.MaxNew {
+		return cli.Exit(fmt.Sprintf("发现 %d 个新的高严重性踩坑, 超过阈值 %d", len(fresh), config.CI.MaxNew), 1)
+	}
+	return nil
+}
+
+// ciConfig loads the config file, or a minimal config for the detected
+// dependencies when the project has none
+func ciConfig(configPath string, dependencies []dependencyRepo) (scraper.Config, error) {
+	if _, err := os.Stat(configPath); err == nil {
+		return loadConfig(configPath)
+	}
+
+	file, err := os.CreateTemp("", "pitfall-ci-*.yaml")
+	if err != nil {
+		return scraper.Config{}, err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(renderInitConfig("", dependencies, ecosystemKeywords)); err != nil {
+		file.Close()
+		return scraper.Config{}, err
+	}
+	if err := file.Close(); err != nil {
+		return scraper.Config{}, err
+	}
+	log.Printf("📄 未找到配置文件 %s, 使用依赖仓库和默认关键词", configPath)
+	return loadConfig(file.Name())
+}
+
+// scopedRepositories limits the scrape to the dependency repositories,
+// keeping the configured settings of those already tracked
+func scopedRepositories(config scraper.Config, dependencies []dependencyRepo) []scraper.RepositoryConfig {
+	configured := make(map[string]scraper.RepositoryConfig)
+	for _, repo := range config.Repositories {
+		configured[strings.ToLower(repo.Name)] = repo
+	}
+
+	repos := make([]scraper.RepositoryConfig, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		repo, ok := configured[strings.ToLower(dependency.Name)]
+		if !ok {
+			repo = scraper.RepositoryConfig{
+				Name:      dependency.Name,
+				Keywords:  ecosystemKeywords[dependency.Ecosystem],
+				MaxIssues: 100,
+			}
+		}
+		repo.Enabled = true
+		repos = append(repos, repo)
+	}
+	return repos
+}
+
+// reportCIFindings prints new pitfalls as GitHub Actions annotations, or as
+// plain lines outside Actions, and writes the job summary
+func reportCIFindings(fresh []model.Issue, found, scanned int) {
+	actions := os.Getenv("GITHUB_ACTIONS") == "true"
+	for _, issue := range fresh {
+		message := fmt.Sprintf("%s (评分 %.1f, 优先级 %s) %s", issue.Title, issue.Score, orDefault(issue.Priority, "-"), issue.URL)
+		if actions {
+			title := fmt.Sprintf("踩坑 %s#%d", issue.Repository, issue.Number)
+			fmt.Printf("::warning title=%s::%s\n", escapeWorkflowProperty(title), escapeWorkflowData(message))
+		} else {
+			fmt.Printf("⚠️  %s#%d %s\n", issue.Repository, issue.Number, message)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("## 🧯 依赖踩坑检查\n\n")
+	fmt.Fprintf(&b, "扫描 %d 个依赖仓库, 高严重性踩坑 %d 个, 其中新发现 %d 个。\n", scanned, found, len(fresh))
+	if len(fresh) > 0 {
+		b.WriteString("\n| 仓库 | 问题 | 评分 | 优先级 |\n")
+		b.WriteString("|------|------|------|--------|\n")
+		for _, issue := range fresh {
+			title := strings.NewReplacer("|", "\\|", "\n", " ", "\r", "").Replace(issue.Title)
+			fmt.Fprintf(&b, "| %s | [#%d %s](%s) | %.1f | %s |\n",
+				issue.Repository, issue.Number, title, issue.URL, issue.Score, orDefault(issue.Priority, "-"))
+		}
+	}
+
+	if path := os.Getenv("GITHUB_STEP_SUMMARY"); path != "" {
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err == nil {
+			_, err = file.WriteString(b.String())
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		if err == nil {
+			return
+		}
+		log.Printf("⚠️  警告: 未能写入 job summary: %v", err)
+	}
+	fmt.Print("\n" + b.String())
+}
+
+// escapeWorkflowData escapes the message of a workflow command
+func escapeWorkflowData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeWorkflowProperty escapes a property value of a workflow command
+func escapeWorkflowProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/analytics"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/output"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// classifyCommand manages manual category corrections and reports how
+// well the classification rules agree with them
+func classifyCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "classify",
+		Usage: "人工修正问题分类并评估分类规则质量",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "correct",
+				Usage: "修正问题的分类 (后续分类以修正为准)",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "repo",
+						Usage:    "仓库 (owner/repo)",
+						Required: true,
+					},
+					&cli.IntFlag{
+						Name:     "issue",
+						Usage:    "Issue 编号",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "category",
+						Usage:    fmt.Sprintf("正确的分类 (%v)", scraper.Categories()),
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "author",
+						Value: os.Getenv("USER"),
+						Usage: "修正人",
+					},
+				},
+				Action: runClassifyCorrect,
+			},
+			{
+				Name:  "quality",
+				Usage: "输出分类规则相对人工修正的混淆矩阵与各类别精确率/召回率",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Value: "markdown",
+						Usage: "输出格式 (markdown/json)",
+					},
+				},
+				Action: runClassifyQuality,
+			},
+		},
+	}
+}
+
+// runClassifyCorrect records a category correction and applies it to the stored issue
+func runClassifyCorrect(c *cli.Context) error {
+	category := c.String("category")
+	if !contains(scraper.Categories(), category) {
+		return fmt.Errorf("--category must be one of: %v", scraper.Categories())
+	}
+
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+
+	repo, number := c.String("repo"), c.Int("issue")
+	repoIssues := issues[repo]
+	index := -1
+	for i := range repoIssues {
+		if repoIssues[i].Number == number {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return fmt.Errorf("issue %s#%d is not stored", repo, number)
+	}
+
+	correction := model.CategoryCorrection{
+		Repository:  repo,
+		IssueNumber: number,
+		Predicted:   scraper.NewFilter(config.Filter).Categorize(repoIssues[index]),
+		Category:    category,
+		Author:      c.String("author"),
+		CorrectedAt: time.Now(),
+	}
+	if err := store.SetCorrection(correction); err != nil {
+		return fmt.Errorf("failed to save correction: %w", err)
+	}
+
+	repoIssues[index].Category = category
+	if err := store.SaveIssues(issues); err != nil {
+		return fmt.Errorf("failed to save issues: %w", err)
+	}
+
+	fmt.Printf("✅ 已将 %s#%d 的分类从 %s 修正为 %s\n", repo, number, correction.Predicted, category)
+	return nil
+}
+
+// runClassifyQuality prints the confusion matrix of the current rules
+// against the manual corrections
+func runClassifyQuality(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	report, err := classificationQuality(config, storage.NewStore(config.Storage.Dir))
+	if err != nil {
+		return err
+	}
+	if report.Total == 0 {
+		fmt.Println("还没有人工分类修正, 请先使用 classify correct")
+		return nil
+	}
+
+	if c.String("format") == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	section := output.ClassificationQualitySection(report)
+	fmt.Printf("## %s\n\n%s", section.Title, section.Markdown)
+	return nil
+}
+
+// classificationQuality compares what the current rules predict for each
+// corrected issue with its correction. Issues no longer stored are
+// compared using the prediction recorded with the correction.
+func classificationQuality(config scraper.Config, store *storage.Store) (analytics.ConfusionReport, error) {
+	corrections, err := store.LoadCorrections()
+	if err != nil {
+		return analytics.ConfusionReport{}, fmt.Errorf("failed to load corrections: %w", err)
+	}
+	if len(corrections) == 0 {
+		return analytics.ConfusionReport{}, nil
+	}
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return analytics.ConfusionReport{}, fmt.Errorf("failed to load stored issues: %w", err)
+	}
+
+	byRef := make(map[string]model.Issue)
+	for _, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			byRef[model.IssueRef(issue.Repository, issue.Number)] = issue
+		}
+	}
+
+	filter := scraper.NewFilter(config.Filter)
+	pairs := make([]analytics.CategoryPair, 0, len(corrections))
+	for _, correction := range corrections {
+		if correction.Category == "" {
+			continue
+		}
+		predicted := correction.Predicted
+		if issue, ok := byRef[model.IssueRef(correction.Repository, correction.IssueNumber)]; ok {
+			predicted = filter.Categorize(issue)
+		}
+		pairs = append(pairs, analytics.CategoryPair{Predicted: predicted, Actual: correction.Category})
+	}
+	report := analytics.BuildConfusion(pairs)
+
+	// How reliable the stored confidence was: bands compare the rules'
+	// choice in the latest recorded run with the correction
+	records, err := store.LoadClassifications()
+	if err != nil {
+		return analytics.ConfusionReport{}, fmt.Errorf("failed to load classification results: %w", err)
+	}
+	latest := make(map[string]model.ClassificationResult)
+	for _, record := range records {
+		latest[model.IssueRef(record.Repository, record.IssueNumber)] = record.Result
+	}
+	var scored []analytics.ScoredPair
+	for _, correction := range corrections {
+		result, ok := latest[model.IssueRef(correction.Repository, correction.IssueNumber)]
+		if !ok || correction.Category == "" {
+			continue
+		}
+		scored = append(scored, analytics.ScoredPair{
+			CategoryPair: analytics.CategoryPair{Predicted: result.RuleCategory(), Actual: correction.Category},
+			Confidence:   result.Confidence,
+		})
+	}
+	report.Confidence = analytics.ConfidenceBands(scored)
+	return report, nil
+}
+
+// recordClassifications stores the classification result of every issue
+// for this run, so later runs and the quality report can compare them
+func recordClassifications(scraperInstance *scraper.Scraper, store *storage.Store, issues map[string][]model.Issue) {
+	now := time.Now().UTC()
+	var records []model.ClassificationRecord
+	for repoName, repoIssues := range issues {
+		explained := append([]model.Issue(nil), repoIssues...)
+		scraperInstance.ExplainClassification(explained)
+		for _, issue := range explained {
+			records = append(records, model.ClassificationRecord{
+				Repository:  repoName,
+				IssueNumber: issue.Number,
+				RunAt:       now,
+				Result:      *issue.Classification,
+			})
+		}
+	}
+	if err := store.AppendClassifications(records); err != nil {
+		log.Printf("⚠️  警告: 未能保存分类结果: %v", err)
+	}
+}
+
+// useCorrections makes the scraper honor the stored category corrections
+func useCorrections(scraperInstance *scraper.Scraper, store *storage.Store) {
+	corrections, err := store.LoadCorrections()
+	if err != nil {
+		log.Printf("⚠️  警告: 未能读取分类修正: %v", err)
+		return
+	}
+	scraperInstance.UseCorrections(corrections)
+}
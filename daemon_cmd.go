@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/jobs"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// Maintenance and analytics tasks scheduled in daemon.tasks
+const (
+	taskPipeline  = "pipeline"
+	taskHousekeep = "housekeep"
+	taskDigest    = "digest"
+	taskAlert     = "alert"
+	taskDB        = "db-maintain"
+)
+
+// daemonTasks lists the schedulable tasks in the order due tasks run
+var daemonTasks = []string{taskPipeline, taskHousekeep, taskDB, taskDigest, taskAlert}
+
+// daemonJob is a scrape of repositories sharing a schedule, or a task
+type daemonJob struct {
+	cron  jobs.Cron
+	repos []string
+	task  string
+}
+
+func (j daemonJob) String() string {
+	if j.task != "" {
+		return "task " + j.task
+	}
+	return "scrape " + strings.Join(j.repos, ", ")
+}
+
+// validateDaemon checks the cron schedules of repositories and tasks
+func validateDaemon(config scraper.Config) error {
+	_, err := daemonJobs(config)
+	return err
+}
+
+// daemonJobs builds the scheduled jobs of a config: one scrape per
+// distinct schedule of the enabled repositories and one job per task
+func daemonJobs(config scraper.Config) ([]daemonJob, error) {
+	if _, err := jobs.ParseCron(config.Daemon.Schedule); err != nil {
+		return nil, fmt.Errorf("daemon.schedule: %w", err)
+	}
+
+	var scheduled []daemonJob
+	bySchedule := make(map[string]int)
+	for _, repo := range config.Repositories {
+		spec := repo.Schedule
+		if spec == "" {
+			spec = config.Daemon.Schedule
+		}
+		cron, err := jobs.ParseCron(spec)
+		if err != nil {
+			return nil, fmt.Errorf("repository %s: schedule: %w", repo.Name, err)
+		}
+		if !repo.Enabled {
+			continue
+		}
+		if i, ok := bySchedule[spec]; ok {
+			scheduled[i].repos = append(scheduled[i].repos, repo.Name)
+			continue
+		}
+		bySchedule[spec] = len(scheduled)
+		scheduled = append(scheduled, daemonJob{cron: cron, repos: []string{repo.Name}})
+	}
+
+	tasks := make([]string, 0, len(config.Daemon.Tasks))
+	for task := range config.Daemon.Tasks {
+		tasks = append(tasks, task)
+	}
+	sort.Strings(tasks)
+	for _, task := range tasks {
+		if !contains(daemonTasks, task) {
+			return nil, fmt.Errorf("daemon.tasks: unknown task %q (expected one of: %s)", task, strings.Join(daemonTasks, ", "))
+		}
+		cron, err := jobs.ParseCron(config.Daemon.Tasks[task])
+		if err != nil {
+			return nil, fmt.Errorf("daemon.tasks.%s: %w", task, err)
+		}
+		scheduled = append(scheduled, daemonJob{cron: cron, task: task})
+	}
+	return scheduled, nil
+}
+
+// nextDaemonRun returns the jobs that fire first after now, and when
+func nextDaemonRun(scheduled []daemonJob, now time.Time) ([]daemonJob, time.Time) {
+	var due []daemonJob
+	var at time.Time
+	for _, job := range scheduled {
+		next := job.cron.Next(now)
+		switch {
+		case next.IsZero():
+		case at.IsZero() || next.Before(at):
+			due, at = []daemonJob{job}, next
+		case next.Equal(at):
+			due = append(due, job)
+		}
+	}
+	return due, at
+}
+
+// runDaemon keeps running until interrupted, scraping repositories and
+// running tasks when their schedules fire, in the configured timezone.
+// Jobs run one at a time; slots missed while a job runs are skipped, and
+// errors are logged so the next run can recover. With full, the first
+// scrape ignores the last scrape time.
+func runDaemon(ctx context.Context, config scraper.Config, full bool) error {
+	scheduled, err := daemonJobs(config)
+	if err != nil {
+		return err
+	}
+	if len(scheduled) == 0 {
+		return fmt.Errorf("nothing to schedule: no enabled repositories or daemon tasks")
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	now := time.Now().In(config.Location())
+	log.Printf("🕰️  守护模式: %d 个计划 (按 Ctrl+C 退出)", len(scheduled))
+	for _, job := range scheduled {
+		log.Printf("   %-12s 下次 %s  %s", job.cron, job.cron.Next(now).Format("2006-01-02 15:04"), job)
+	}
+
+	for {
+		due, at := nextDaemonRun(scheduled, now)
+		if at.IsZero() {
+			return fmt.Errorf("no schedule fires within five years")
+		}
+		timer := time.NewTimer(time.Until(at))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Println("👋 守护模式已停止")
+			return nil
+		case <-timer.C:
+		}
+
+		runDaemonJobs(ctx, config, due, full)
+		full = false
+		now = time.Now().In(config.Location())
+	}
+}
+
+// runDaemonJobs scrapes the repositories of the due scrape jobs together,
+// then runs the due tasks
+func runDaemonJobs(ctx context.Context, config scraper.Config, due []daemonJob, full bool) {
+	selected := make(map[string]bool)
+	tasks := make(map[string]bool)
+	for _, job := range due {
+		for _, repoName := range job.repos {
+			selected[repoName] = true
+		}
+		if job.task != "" {
+			tasks[job.task] = true
+		}
+	}
+
+	if len(selected) > 0 {
+		scrapeConfig := config
+		scrapeConfig.Repositories = nil
+		for _, repo := range config.Repositories {
+			if selected[repo.Name] {
+				scrapeConfig.Repositories = append(scrapeConfig.Repositories, repo)
+			}
+		}
+		log.Printf("⏰ 计划抓取 %d 个仓库", len(scrapeConfig.Repositories))
+		if err := runScrape(ctx, scrapeConfig, full); err != nil {
+			log.Printf("⚠️  警告: 计划抓取失败: %v", err)
+		}
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	for _, task := range daemonTasks {
+		if !tasks[task] || ctx.Err() != nil {
+			continue
+		}
+		log.Printf("⏰ 计划任务: %s", task)
+		if err := runDaemonTask(ctx, config, store, task); err != nil {
+			log.Printf("⚠️  警告: 计划任务 %s 失败: %v", task, err)
+		}
+	}
+}
+
+// runDaemonTask runs one scheduled task
+func runDaemonTask(ctx context.Context, config scraper.Config, store *storage.Store, task string) error {
+	switch task {
+	case taskPipeline:
+		results, err := runPipeline(ctx, config, store, nil, false)
+		for _, result := range results {
+			if result.Error != "" {
+				log.Printf("⚠️  警告: 流水线步骤 %s 失败: %s", result.Step, result.Error)
+			}
+		}
+		return err
+	case taskHousekeep:
+		// Exports write the output directory under the store lock
+		return store.Update(func() error {
+			_, err := housekeepOutput(config, nil, false, false)
+			return err
+		})
+	case taskDB:
+		_, err := maintainStorage(ctx, config, store)
+		return err
+	case taskDigest:
+		postScheduledDigest(ctx, config, store)
+		return nil
+	case taskAlert:
+		if !config.AlertsEnabled() {
+			return nil
+		}
+		return postAlerts(ctx, config, store, config.Alerts(), false, false)
+	}
+	return fmt.Errorf("unknown task %q", task)
+}
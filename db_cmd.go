@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/notify"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// dbCommand groups maintenance commands for the local issue store
+func dbCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "db",
+		Usage: "本地问题存储的维护命令",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "rehash",
+				Usage: "用指定算法重新计算内容哈希, 并重新关联哈希相同的重复问题",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "algorithm",
+						Value: model.DefaultHashAlgorithm,
+						Usage: "哈希算法 (" + strings.Join(model.HashAlgorithms, ", ") + ")",
+					},
+					&cli.IntFlag{
+						Name:  "batch",
+						Value: 500,
+						Usage: "每批重新计算的问题数, 每批完成后保存",
+					},
+				},
+				Action: runRehash,
+			},
+			{
+				Name:  "maintain",
+				Usage: "清理中断保存留下的临时文件, 显示各文件大小, 超过 storage.max_size_mb 时发送告警",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "只显示占用和将清理的临时文件, 不删除也不告警",
+					},
+				},
+				Action: runDBMaintain,
+			},
+		},
+	}
+}
+
+// runDBMaintain prints the disk usage of the store and maintains it
+func runDBMaintain(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	store := storage.NewStore(config.Storage.Dir)
+	usage, err := store.Usage(time.Now())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("存储目录 %s: %d 个文件, 共 %.1f MB", config.Storage.Dir, len(usage.Files), megabytes(usage.Total))
+	if limit := config.Storage.MaxSizeMB; limit > 0 {
+		fmt.Printf(" (上限 %d MB, 已用 %.0f%%)", limit, 100*megabytes(usage.Total)/float64(limit))
+	}
+	fmt.Println()
+	for _, file := range usage.Files {
+		fmt.Printf("  %-32s %10.2f MB  %s\n", file.Name, megabytes(file.Size), file.ModTime.Format("2006-01-02 15:04"))
+	}
+	if c.Bool("dry-run") {
+		items := make([]string, len(usage.Stale))
+		for i, file := range usage.Stale {
+			items[i] = fmt.Sprintf("%s  (%d 字节, %s)", file.Name, file.Size, file.ModTime.Format("2006-01-02 15:04"))
+		}
+		printDryRun("将删除的临时文件", items)
+		return nil
+	}
+	_, err = maintainStorage(c.Context, config, store)
+	return err
+}
+
+// maintainStorage removes temporary files left by interrupted saves and
+// checks the size of the store against storage.max_size_mb, alerting
+// notify.webhooks while it is exceeded
+func maintainStorage(ctx context.Context, config scraper.Config, store *storage.Store) (storage.Usage, error) {
+	usage, err := store.Usage(time.Now())
+	if err != nil {
+		return usage, err
+	}
+	if len(usage.Stale) > 0 {
+		freed, err := store.RemoveStale(usage.Stale)
+		usage.Total -= freed
+		if err != nil {
+			return usage, err
+		}
+		log.Printf("🧹 已清理 %d 个中断保存留下的临时文件, 释放 %.1f MB", len(usage.Stale), megabytes(freed))
+	}
+
+	limit := config.Storage.MaxSizeMB
+	if limit == 0 || megabytes(usage.Total) <= float64(limit) {
+		return usage, nil
+	}
+	var largest []string
+	for i, file := range usage.Files {
+		if i == 3 {
+			break
+		}
+		largest = append(largest, fmt.Sprintf("%s (%.1f MB)", file.Name, megabytes(file.Size)))
+	}
+	text := fmt.Sprintf("Pitfall store %s is %.1f MB, over storage.max_size_mb (%d MB). Largest files: %s",
+		config.Storage.Dir, megabytes(usage.Total), limit, strings.Join(largest, ", "))
+	log.Printf("⚠️  警告: 存储目录 %.1f MB, 超过上限 storage.max_size_mb (%d MB); 最大的文件: %s", megabytes(usage.Total), limit, strings.Join(largest, ", "))
+	if config.Notify.Enabled() {
+		if err := notify.Post(ctx, config.Notify, map[string]interface{}{"text": text}); err != nil {
+			log.Printf("⚠️  警告: 未能发送存储容量告警: %v", err)
+		}
+	}
+	return usage, nil
+}
+
+// megabytes converts bytes to megabytes
+func megabytes(bytes int64) float64 {
+	return float64(bytes) / 1024 / 1024
+}
+
+// runRehash recomputes content hashes of issues not yet hashed with the
+// requested algorithm and rebuilds the content hash duplicate links
+func runRehash(c *cli.Context) error {
+	algorithm := c.String("algorithm")
+	if !contains(model.HashAlgorithms, algorithm) {
+		return fmt.Errorf("unknown hash algorithm %q (available: %v)", algorithm, model.HashAlgorithms)
+	}
+	batch := c.Int("batch")
+	if batch <= 0 {
+		return fmt.Errorf("--batch must be positive")
+	}
+
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+
+	repoNames := make([]string, 0, len(issues))
+	for repoName := range issues {
+		repoNames = append(repoNames, repoName)
+	}
+	sort.Strings(repoNames)
+
+	rehashed, pending := 0, 0
+	for _, repoName := range repoNames {
+		repoIssues := issues[repoName]
+		for i := range repoIssues {
+			if repoIssues[i].HashAlgorithm == algorithm && repoIssues[i].ContentHash != "" {
+				continue
+			}
+			hash, err := model.ContentHash(repoIssues[i], algorithm)
+			if err != nil {
+				return err
+			}
+			repoIssues[i].ContentHash = hash
+			repoIssues[i].HashAlgorithm = algorithm
+			rehashed++
+
+			if pending++; pending == batch {
+				if err := store.SaveIssues(issues); err != nil {
+					return fmt.Errorf("failed to save issues: %w", err)
+				}
+				log.Printf("💾 已重新计算 %d 个问题的哈希", rehashed)
+				pending = 0
+			}
+		}
+	}
+	if pending > 0 {
+		if err := store.SaveIssues(issues); err != nil {
+			return fmt.Errorf("failed to save issues: %w", err)
+		}
+	}
+	fmt.Printf("已用 %s 算法重新计算 %d 个问题的内容哈希\n", algorithm, rehashed)
+
+	return relinkContentHashes(store, issues)
+}
+
+// relinkContentHashes replaces the content hash duplicate links and reports
+// the groups whose membership changed
+func relinkContentHashes(store *storage.Store, issues map[string][]model.Issue) error {
+	links, err := store.LoadDuplicateLinks()
+	if err != nil {
+		return fmt.Errorf("failed to load duplicate links: %w", err)
+	}
+	before := groupKeys(model.DuplicateGroups(links, model.DuplicateSourceContentHash))
+
+	newLinks := model.ContentHashLinks(issues, time.Now())
+	if err := store.ReplaceDuplicateLinks(model.DuplicateSourceContentHash, newLinks); err != nil {
+		return fmt.Errorf("failed to save duplicate links: %w", err)
+	}
+	after := groupKeys(model.DuplicateGroups(newLinks))
+
+	dissolved, formed := 0, 0
+	for key := range before {
+		if !after[key] {
+			dissolved++
+		}
+	}
+	for key := range after {
+		if !before[key] {
+			formed++
+		}
+	}
+	fmt.Printf("重复分组: %d 组 (成员变化: %d 组解散或变更, %d 组新建或变更)\n", len(after), dissolved, formed)
+	return nil
+}
+
+// groupKeys identifies duplicate groups by their sorted members
+func groupKeys(groups [][]string) map[string]bool {
+	keys := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		members := append([]string(nil), group...)
+		sort.Strings(members)
+		keys[strings.Join(members, " ")] = true
+	}
+	return keys
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// dedupSweep are the cross-source thresholds compared by dedup evaluate
+var dedupSweep = []float64{0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+
+// dedupCommand groups duplicate detection subcommands
+func dedupCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "dedup",
+		Usage: "重复问题检测工具",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "evaluate",
+				Usage: "用人工标注的问题对评估当前去重配置的精确率/召回率",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "truth",
+						Usage:    "标注 CSV 文件 (列: a,b,duplicate; a/b 形如 owner/repo#123)",
+						Required: true,
+					},
+					&cli.Float64Flag{
+						Name:  "min-similarity",
+						Usage: "覆盖 dedup.cross_source_min_similarity",
+					},
+					&cli.IntFlag{
+						Name:  "limit",
+						Value: 20,
+						Usage: "误报/漏报各最多列出的数量 (0 = 全部)",
+					},
+				},
+				Action: runDedupEvaluate,
+			},
+			{
+				Name:  "prune",
+				Usage: "按 dedup.policy 删除上游标记为重复的问题",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "只列出将被删除的问题",
+					},
+					&cli.BoolFlag{
+						Name:  "confirm",
+						Usage: "确认删除超过 maintenance.confirm_threshold 个问题",
+					},
+				},
+				Action: runDedupPrune,
+			},
+		},
+	}
+}
+
+// runDedupEvaluate reports how well automatic duplicate linking matches
+// labeled duplicate pairs
+func runDedupEvaluate(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if c.IsSet("min-similarity") {
+		config.Dedup.CrossSourceMinSimilarity = c.Float64("min-similarity")
+	}
+
+	file, err := os.Open(c.String("truth"))
+	if err != nil {
+		return fmt.Errorf("failed to open truth pairs: %w", err)
+	}
+	pairs, err := scraper.ReadTruthPairs(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read truth pairs: %w", err)
+	}
+
+	issues, err := storage.NewStore(config.Storage.Dir).LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+
+	result := scraper.EvaluateDedup(issues, pairs, config.Dedup)
+	if len(result.Missing) > 0 {
+		fmt.Printf("⚠️  %d 对标注包含不在问题库中的问题, 已跳过\n", len(result.Missing))
+	}
+	if len(result.Outcomes) == 0 {
+		return fmt.Errorf("no truth pair names two stored issues")
+	}
+
+	fmt.Printf("🔁 去重评估: %d 对 (跨来源链接: %v, 相似度阈值 %.2f)\n\n", len(result.Outcomes), config.Dedup.CrossSource, config.Dedup.CrossSourceMinSimilarity)
+	fmt.Printf("  精确率: %.1f%%\n  召回率: %.1f%%\n  F1:     %.3f\n", result.Precision()*100, result.Recall()*100, result.F1())
+	fmt.Printf("  TP %d  FP %d  FN %d  TN %d\n", result.TruePositives, result.FalsePositives, result.FalseNegatives, result.TrueNegatives)
+
+	if config.Dedup.CrossSource {
+		fmt.Println("\n阈值对比:")
+		fmt.Printf("  %6s %8s %8s %7s\n", "阈值", "精确率", "召回率", "F1")
+		for _, threshold := range dedupSweep {
+			eval := result.AtThreshold(threshold)
+			fmt.Printf("  %6.2f %7.1f%% %7.1f%% %7.3f\n", threshold, eval.Precision()*100, eval.Recall()*100, eval.F1())
+		}
+	}
+
+	var falsePositives, falseNegatives []scraper.PairOutcome
+	for _, outcome := range result.Outcomes {
+		switch {
+		case outcome.Predicted && !outcome.Pair.Duplicate:
+			falsePositives = append(falsePositives, outcome)
+		case !outcome.Predicted && outcome.Pair.Duplicate:
+			falseNegatives = append(falseNegatives, outcome)
+		}
+	}
+	printPairOutcomes("误报 (被链接但不是重复)", falsePositives, c.Int("limit"))
+	printPairOutcomes("漏报 (是重复但未被链接)", falseNegatives, c.Int("limit"))
+	return nil
+}
+
+// runDedupPrune deletes the stored upstream duplicates selected by the
+// dedup policy
+func runDedupPrune(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if config.Dedup.Policy == scraper.DuplicatePolicyMarkOnly {
+		fmt.Printf("dedup.policy 为 %s, 不会删除任何问题\n", scraper.DuplicatePolicyMarkOnly)
+		return nil
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	links, err := store.LoadDuplicateLinks()
+	if err != nil {
+		return fmt.Errorf("failed to load duplicate links: %w", err)
+	}
+	duplicates := scraper.DuplicatesToPrune(issues, links, config.Dedup, time.Now())
+	if len(duplicates) == 0 {
+		fmt.Println("没有需要删除的重复问题")
+		return nil
+	}
+
+	if c.Bool("dry-run") {
+		items := make([]string, len(duplicates))
+		for i, issue := range duplicates {
+			items[i] = fmt.Sprintf("%s#%d %s (评分 %.1f)", issue.Repository, issue.Number, issue.Title, issue.Score)
+		}
+		printDryRun(fmt.Sprintf("将删除的重复问题 (dedup.policy %s)", config.Dedup.Policy), items)
+		return nil
+	}
+	if err := checkConfirmed(config, len(duplicates), c.Bool("confirm")); err != nil {
+		return err
+	}
+	removed, err := deleteIssues(store, issues, duplicates, model.TombstoneDuplicate)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("已删除 %d 个重复问题 (dedup.policy %s), 已记录删除标记供增量导出同步\n", removed, config.Dedup.Policy)
+	return nil
+}
+
+// deleteIssues deletes issues from the stored corpus in two phases: their
+// tombstones are recorded first, then the corpus is saved without them, so
+// incremental exports learn of every deletion that reached the store
+func deleteIssues(store *storage.Store, corpus map[string][]model.Issue, remove []model.Issue, reason string) (int, error) {
+	if err := store.AppendTombstones(model.NewTombstones(remove, reason, time.Now())); err != nil {
+		return 0, fmt.Errorf("failed to record tombstones: %w", err)
+	}
+	removed := scraper.RemoveIssues(corpus, remove)
+	if err := store.SaveIssues(corpus); err != nil {
+		return 0, fmt.Errorf("failed to save issues: %w", err)
+	}
+	return removed, nil
+}
+
+func printPairOutcomes(title string, outcomes []scraper.PairOutcome, limit int) {
+	if len(outcomes) == 0 {
+		return
+	}
+	fmt.Printf("\n%s: %d\n", title, len(outcomes))
+	for i, outcome := range outcomes {
+		if limit > 0 && i >= limit {
+			fmt.Printf("  ... 另有 %d 对\n", len(outcomes)-limit)
+			break
+		}
+		reason := outcome.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Printf("  %s ↔ %s  相似度 %.3f  %s\n", outcome.Pair.A, outcome.Pair.B, outcome.Similarity, reason)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/analytics"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/notify"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/output"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// digestCommand summarizes recent pitfalls for chat tools
+func digestCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "digest",
+		Usage: "生成近期新增踩坑问题的简报 (Slack 或 Markdown)",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "period",
+				Usage: "统计周期, 如 7d、2w (默认使用 notify.digest_period)",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "输出格式 (slack/markdown), 默认使用 notify.digest_format",
+			},
+			&cli.BoolFlag{
+				Name:  "post",
+				Usage: "发送到 notify.webhooks 而不是打印",
+			},
+		},
+		Action: runDigest,
+	}
+}
+
+// runDigest prints the digest, or posts it to the configured webhooks
+func runDigest(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if period := c.String("period"); period != "" {
+		config.Notify.DigestPeriod = period
+	}
+	if format := c.String("format"); format != "" {
+		config.Notify.DigestFormat = format
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+
+	payload, err := buildDigest(config, issues, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if !c.Bool("post") {
+		if config.Notify.DigestFormat == output.DigestMarkdown {
+			fmt.Print(payload["text"])
+			return nil
+		}
+		data, err := json.MarshalIndent(payload, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if !config.Notify.Enabled() {
+		return fmt.Errorf("notify.webhooks is empty")
+	}
+	if err := notify.Post(c.Context, config.Notify, payload); err != nil {
+		return err
+	}
+	fmt.Printf("已发送简报到 %d 个 webhook\n", len(config.Notify.Webhooks))
+	return nil
+}
+
+// buildDigest computes the digest for the configured period and renders it
+// in the configured format
+func buildDigest(config scraper.Config, issues map[string][]model.Issue, now time.Time) (map[string]interface{}, error) {
+	period, err := analytics.ParsePeriod(config.Notify.DigestPeriod)
+	if err != nil {
+		return nil, err
+	}
+	live, _ := model.ExcludeDeletedUpstream(issues)
+	calendar, err := trendCalendar(config)
+	if err != nil {
+		return nil, err
+	}
+	return output.FormatDigest(analytics.BuildDigest(live, period, now.In(config.Location()), calendar), config.Notify.DigestFormat)
+}
+
+// trendCalendar returns the calendar trends are normalized with, or nil
+// when trends.normalize is off
+func trendCalendar(config scraper.Config) (*analytics.Calendar, error) {
+	if !config.Trends.Normalize {
+		return nil, nil
+	}
+	return analytics.NewCalendar(config.Trends.Holidays, config.Location())
+}
+
+// postScheduledDigest posts the digest to the configured webhooks when the
+// last automatic digest is at least one digest period old
+func postScheduledDigest(ctx context.Context, config scraper.Config, store *storage.Store) {
+	if !config.Notify.Enabled() {
+		return
+	}
+	period, err := analytics.ParsePeriod(config.Notify.DigestPeriod)
+	if err != nil {
+		return
+	}
+
+	state, err := store.LoadDigestState()
+	if err != nil {
+		log.Printf("⚠️  警告: 未能读取简报状态: %v", err)
+		return
+	}
+	now := time.Now()
+	if now.Sub(state.LastPostedAt) < period {
+		return
+	}
+
+	issues, err := store.LoadIssues()
+	if err != nil {
+		log.Printf("⚠️  警告: 未能读取问题库, 跳过简报: %v", err)
+		return
+	}
+	payload, err := buildDigest(config, issues, now)
+	if err != nil {
+		log.Printf("⚠️  警告: 未能生成简报: %v", err)
+		return
+	}
+	if err := notify.Post(ctx, config.Notify, payload); err != nil {
+		log.Printf("⚠️  警告: 未能发送简报: %v", err)
+		return
+	}
+
+	if err := store.SaveDigestState(notify.DigestState{LastPostedAt: now}); err != nil {
+		log.Printf("⚠️  警告: 未能记录简报状态: %v", err)
+	}
+	log.Printf("📬 已发送简报到 %d 个 webhook", len(config.Notify.Webhooks))
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/output"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// exportCommand exports stored issues in full or as a delta against a
+// previous export
+func exportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "将本地问题库导出为 JSON, 或只导出相对上次导出的变化",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "output",
+				Aliases:  []string{"o"},
+				Required: true,
+				Usage:    "导出文件路径",
+			},
+			&cli.StringFlag{
+				Name:  "diff-base",
+				Usage: "上次的完整导出文件; 指定后只导出新增、变更和删除的记录, 附带此后从问题库删除的删除标记 (tombstones), 并写出变更清单",
+			},
+			&cli.IntFlag{
+				Name:  "export-schema",
+				Value: output.ExportSchemaVersion,
+				Usage: "导出使用的 schema 版本, 供尚未升级的下游使用旧版本",
+			},
+			&cli.StringFlag{
+				Name:  "snapshot",
+				Usage: "同时写出完整导出, 作为下次 --diff-base 的基准",
+			},
+			&cli.StringFlag{
+				Name:  "duplicates",
+				Value: model.DuplicatesInclude,
+				Usage: "重复问题的处理方式: include 全部保留, exclude 只保留每组的主问题, collapse 只保留主问题并附带 member_count 和成员列表",
+			},
+			&cli.BoolFlag{
+				Name:  "classification",
+				Usage: "为每个问题附带分类依据 (命中的规则关键词或人工修正), 字段 classification",
+			},
+			&cli.BoolFlag{
+				Name:  "comments",
+				Usage: "为每个问题附带已抓取的评论, 字段 discussion",
+			},
+			&cli.StringFlag{
+				Name:  "fields",
+				Usage: "只导出这些字段 (逗号分隔, 如 number,title,score,category,html_url); repository 和 number 总会保留, --snapshot 不受影响",
+			},
+		},
+		Action: runExport,
+	}
+}
+
+// runExport writes a full export, or with --diff-base a delta and a
+// changes manifest next to it
+func runExport(c *cli.Context) error {
+	duplicates := c.String("duplicates")
+	if !contains(model.DuplicateModes, duplicates) {
+		return fmt.Errorf("--duplicates must be one of: %v", model.DuplicateModes)
+	}
+
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	// Issues stored again after their deletion are not deleted downstream
+	stored := make(map[string]bool)
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			stored[model.IssueRef(repoName, issue.Number)] = true
+		}
+	}
+	snapshots, err := store.LoadRepoSnapshots()
+	if err != nil {
+		return fmt.Errorf("failed to load repository snapshots: %w", err)
+	}
+	model.MarkRepoRisks(issues, snapshots)
+	if c.Bool("comments") {
+		if err := store.AttachComments(issues); err != nil {
+			return fmt.Errorf("failed to load stored comments: %w", err)
+		}
+	}
+	if !config.Output.IncludeDeleted {
+		issues, _ = model.ExcludeDeletedUpstream(issues)
+	}
+	if duplicates != model.DuplicatesInclude {
+		links, err := store.LoadDuplicateLinks()
+		if err != nil {
+			return fmt.Errorf("failed to load duplicate links: %w", err)
+		}
+		issues = model.ApplyDuplicateMode(issues, links, duplicates)
+	}
+	if c.Bool("classification") {
+		scraperInstance := scraper.NewScraper(config)
+		useCorrections(scraperInstance, store)
+		for _, repoIssues := range issues {
+			scraperInstance.ExplainClassification(repoIssues)
+		}
+	}
+
+	schema := c.Int("export-schema")
+	var fields []string
+	if spec := c.String("fields"); spec != "" {
+		if fields, err = output.ParseFields(spec); err != nil {
+			return fmt.Errorf("invalid --fields: %w", err)
+		}
+	}
+	current := output.NewExport(issues, time.Now())
+	if snapshot := c.String("snapshot"); snapshot != "" {
+		if err := writeExport(snapshot, current, schema, nil); err != nil {
+			return err
+		}
+		if _, err := writeChecksums(config, snapshot+".sha256", []string{snapshot}); err != nil {
+			return err
+		}
+	}
+
+	path := c.String("output")
+	basePath := c.String("diff-base")
+	if basePath == "" {
+		if err := writeExport(path, current, schema, fields); err != nil {
+			return err
+		}
+		if _, err := writeChecksums(config, path+".sha256", []string{path}); err != nil {
+			return err
+		}
+		fmt.Printf("已导出 %d 个问题到 %s (schema %d)\n", len(current.Issues), path, schema)
+		return nil
+	}
+
+	base, err := output.ReadExport(basePath)
+	if err != nil {
+		return err
+	}
+	tombstones, err := store.LoadTombstones()
+	if err != nil {
+		return fmt.Errorf("failed to load tombstones: %w", err)
+	}
+	delta, manifest := output.DiffExport(base, current, model.TombstonesSince(tombstones, base.GeneratedAt, stored))
+	manifest.Base = basePath
+
+	if err := writeExport(path, delta, schema, fields); err != nil {
+		return err
+	}
+	manifestPath := strings.TrimSuffix(path, ".json") + ".manifest.json"
+	if err := output.WriteJSONFile(manifestPath, manifest); err != nil {
+		return err
+	}
+	if _, err := writeChecksums(config, path+".sha256", []string{path, manifestPath}); err != nil {
+		return err
+	}
+
+	fmt.Printf("相对 %s: 新增 %d, 变更 %d, 删除 %d, 删除标记 %d, 未变 %d\n",
+		basePath, len(manifest.Added), len(manifest.Changed), len(manifest.Removed), len(manifest.Deleted), manifest.Unchanged)
+	fmt.Printf("变化已导出到 %s, 变更清单: %s\n", path, manifestPath)
+	return nil
+}
+
+// writeExport writes an export document in the requested schema version,
+// keeping only the given issue fields when there are any
+func writeExport(path string, document interface{}, schema int, fields []string) error {
+	converted, err := output.ConvertExport(document, schema)
+	if err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		output.ProjectExport(converted, fields)
+	}
+	return output.WriteJSONFile(path, converted)
+}
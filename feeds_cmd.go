@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/export"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// feedsCommand builds the declarative downstream feeds of export.feeds
+func feedsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "feeds",
+		Usage: "按 export.feeds 的配置生成并上传下游数据源",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "列出已配置的数据源",
+				Action: runFeedsList,
+			},
+			{
+				Name:  "run",
+				Usage: "生成数据源 (筛选 → 转换 → 格式化 → 上传)",
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "name",
+						Usage: "只生成这些数据源 (可重复), 默认全部",
+					},
+				},
+				Action: runFeedsRun,
+			},
+		},
+	}
+}
+
+// runFeedsList prints the configured feeds
+func runFeedsList(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if len(config.Export.Feeds) == 0 {
+		fmt.Println("export.feeds 中没有配置数据源")
+		return nil
+	}
+	for _, feed := range config.Export.Feeds {
+		filter := feed.Filter
+		if filter == "" {
+			filter = "(全部问题)"
+		}
+		fmt.Printf("%-24s %-7s %d 个阶段  %s  -> %s\n", feed.Name, feed.Format, len(feed.Stages), filter, feed.Output)
+		if len(feed.Destinations) > 0 {
+			fmt.Printf("%-24s 上传到 %s\n", "", strings.Join(feed.Destinations, ", "))
+		}
+	}
+	return nil
+}
+
+// runFeedsRun builds the selected feeds and records their uploads
+func runFeedsRun(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	names := c.StringSlice("name")
+	var feeds []*export.Feed
+	for _, feedConfig := range config.Export.Feeds {
+		if len(names) > 0 && !contains(names, feedConfig.Name) {
+			continue
+		}
+		feed, err := export.NewFeed(feedConfig)
+		if err != nil {
+			return err
+		}
+		feeds = append(feeds, feed)
+	}
+	if len(feeds) == 0 {
+		return fmt.Errorf("no feeds configured in export.feeds match %v", names)
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	snapshots, err := store.LoadRepoSnapshots()
+	if err != nil {
+		return fmt.Errorf("failed to load repository snapshots: %w", err)
+	}
+	model.MarkRepoRisks(issues, snapshots)
+	if !config.Output.IncludeDeleted {
+		issues, _ = model.ExcludeDeletedUpstream(issues)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context, 30*time.Minute)
+	defer cancel()
+
+	var results []export.Result
+	failed := 0
+	for _, feed := range feeds {
+		rows, uploads, err := feed.Run(ctx, issues, config.Export.Retries)
+		if err != nil {
+			log.Printf("⚠️  警告: 数据源 %s 生成失败: %v", feed.Name(), err)
+			failed++
+			continue
+		}
+		fmt.Printf("✅ %s: %d 行\n", feed.Name(), rows)
+		for _, result := range uploads {
+			if result.Error != "" {
+				log.Printf("⚠️  警告: 未能上传 %s 到 %s: %s", result.File, result.Destination, result.Error)
+				failed++
+				continue
+			}
+			fmt.Printf("   📤 %s\n", result.URL)
+		}
+		results = append(results, uploads...)
+	}
+
+	if len(results) > 0 {
+		if err := store.AppendExportResults(results); err != nil {
+			log.Printf("⚠️  警告: 未能记录导出结果: %v", err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d feed steps failed", failed)
+	}
+	return nil
+}
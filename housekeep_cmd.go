@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/output"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+)
+
+// maxDryRunSamples caps the affected items a dry run lists
+const maxDryRunSamples = 20
+
+// housekeepCommand applies the output retention limits on demand
+func housekeepCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "housekeep",
+		Usage: "按保留策略清理输出目录中生成的旧报告 (其他文件不受影响)",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "只列出将被删除的文件",
+			},
+			&cli.BoolFlag{
+				Name:  "confirm",
+				Usage: "确认删除超过 maintenance.confirm_threshold 个文件",
+			},
+		},
+		Action: runHousekeep,
+	}
+}
+
+// runHousekeep cleans up the output directory
+func runHousekeep(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !config.Output.Retention.Enabled() {
+		fmt.Println("未配置 output.retention, 不会删除任何文件")
+		return nil
+	}
+
+	expired, err := housekeepOutput(config, nil, c.Bool("dry-run"), c.Bool("confirm"))
+	if err != nil {
+		return err
+	}
+	if len(expired) == 0 {
+		fmt.Println("没有需要清理的文件")
+	}
+	return nil
+}
+
+// housekeepOutput removes (or with dryRun lists) output files beyond the
+// retention limits, never touching the files in keep. Removing more files
+// than maintenance.confirm_threshold needs confirmed.
+func housekeepOutput(config scraper.Config, keep []string, dryRun, confirmed bool) ([]output.ExpiredFile, error) {
+	now := time.Now()
+	expired, err := output.Housekeep(config.Output.OutputDir, config.Output.Retention, keep, true, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check the output directory: %w", err)
+	}
+
+	var freed int64
+	items := make([]string, len(expired))
+	for i, file := range expired {
+		freed += file.Size
+		items[i] = fmt.Sprintf("%s  (%s, %s, %d 字节)", file.Path, file.Reason, file.ModTime.Format("2006-01-02 15:04"), file.Size)
+	}
+	if dryRun {
+		if len(expired) > 0 {
+			printDryRun(fmt.Sprintf("将删除的文件 (释放 %.1f MB)", float64(freed)/1024/1024), items)
+		}
+		return expired, nil
+	}
+	if len(expired) == 0 {
+		return nil, nil
+	}
+	if err := checkConfirmed(config, len(expired), confirmed); err != nil {
+		return expired, err
+	}
+
+	expired, err = output.Housekeep(config.Output.OutputDir, config.Output.Retention, keep, false, now)
+	if err != nil {
+		return expired, fmt.Errorf("failed to clean up the output directory: %w", err)
+	}
+	log.Printf("🧹 已清理 %d 个旧输出文件, 释放 %.1f MB", len(expired), float64(freed)/1024/1024)
+	return expired, nil
+}
+
+// printDryRun prints how many items an operation would affect, listing
+// the first of them
+func printDryRun(action string, items []string) {
+	fmt.Printf("%s: %d 项\n", action, len(items))
+	for i, item := range items {
+		if i == maxDryRunSamples {
+			fmt.Printf("  ... 另有 %d 项, 共 %d 项\n", len(items)-maxDryRunSamples, len(items))
+			break
+		}
+		fmt.Printf("  %s\n", item)
+	}
+}
+
+// checkConfirmed refuses to delete more items than
+// maintenance.confirm_threshold without confirmation
+func checkConfirmed(config scraper.Config, count int, confirmed bool) error {
+	threshold := config.Maintenance.ConfirmThreshold
+	if threshold == 0 || count <= threshold || confirmed {
+		return nil
+	}
+	return fmt.Errorf("%d items would be deleted, more than maintenance.confirm_threshold (%d): review them with --dry-run and rerun with --confirm", count, threshold)
+}
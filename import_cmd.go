@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/output"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// importCommand loads issues exported by this or another tool into the
+// local store
+func importCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "将其他版本或工具导出的问题 (JSON、NDJSON、CSV) 导入本地问题库, 可用映射文件适配不同的列名和格式",
+		ArgsUsage: "<文件>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "mapping",
+				Usage: "列映射文件 (YAML/JSON): fields 列出 source 列 → target 字段及 split/join/date/number/lower 转换, defaults 补充缺少的字段, strict 丢弃未映射的列",
+			},
+			&cli.BoolFlag{
+				Name:  "classify",
+				Usage: "导入前按当前规则重新分类",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "只校验映射和数据并打印统计, 不写入问题库",
+			},
+		},
+		Action: runImport,
+	}
+}
+
+// runImport converts the whole dataset with the mapping before storing
+// anything, so a dataset that does not fit is rejected as a whole
+func runImport(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("usage: import <file> [--mapping mapping.yaml]")
+	}
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var mapping output.ColumnMapping
+	if path := c.String("mapping"); path != "" {
+		if mapping, err = loadColumnMapping(path); err != nil {
+			return err
+		}
+	}
+	records, err := output.ReadRecords(c.Args().First())
+	if err != nil {
+		return err
+	}
+	imported, err := output.ApplyMapping(records, mapping)
+	if err != nil {
+		return fmt.Errorf("failed to map %s: %w", c.Args().First(), err)
+	}
+
+	byRepo := make(map[string][]model.Issue)
+	for _, issue := range imported {
+		byRepo[issue.Repository] = append(byRepo[issue.Repository], issue)
+	}
+	if c.Bool("classify") {
+		scraperInstance := scraper.NewScraper(config)
+		useCorrections(scraperInstance, storage.NewStore(config.Storage.Dir))
+		scraperInstance.ClassifyIssues(byRepo)
+		printFailures(scraperInstance.Failures())
+	}
+
+	repos := make([]string, 0, len(byRepo))
+	for repoName := range byRepo {
+		repos = append(repos, repoName)
+	}
+	sort.Strings(repos)
+	if c.Bool("dry-run") {
+		fmt.Printf("校验通过: %d 条记录可导入 %d 个仓库\n", len(imported), len(repos))
+		for _, repoName := range repos {
+			fmt.Printf("  %-40s %d\n", repoName, len(byRepo[repoName]))
+		}
+		return nil
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	stored, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	added, updated := 0, 0
+	merged := make(map[string][]model.Issue, len(byRepo))
+	for _, repoName := range repos {
+		index := make(map[int]int)
+		repoIssues := append([]model.Issue(nil), stored[repoName]...)
+		for i, issue := range repoIssues {
+			index[issue.Number] = i
+		}
+		for _, issue := range byRepo[repoName] {
+			if i, ok := index[issue.Number]; ok {
+				repoIssues[i] = issue
+				updated++
+				continue
+			}
+			index[issue.Number] = len(repoIssues)
+			repoIssues = append(repoIssues, issue)
+			added++
+		}
+		merged[repoName] = repoIssues
+	}
+	if err := store.SaveIssues(merged); err != nil {
+		return fmt.Errorf("failed to save imported issues: %w", err)
+	}
+	fmt.Printf("已导入 %d 个问题 (新增 %d, 更新 %d), 涉及 %d 个仓库\n", len(imported), added, updated, len(repos))
+	return nil
+}
+
+// loadColumnMapping reads and validates an import mapping file
+func loadColumnMapping(path string) (output.ColumnMapping, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return output.ColumnMapping{}, fmt.Errorf("failed to read mapping %s: %w", path, err)
+	}
+	var mapping output.ColumnMapping
+	if err := v.Unmarshal(&mapping, func(dc *mapstructure.DecoderConfig) {
+		dc.TagName = "yaml"
+	}); err != nil {
+		return output.ColumnMapping{}, fmt.Errorf("failed to decode mapping %s: %w", path, err)
+	}
+	if err := mapping.Validate(); err != nil {
+		return output.ColumnMapping{}, fmt.Errorf("invalid mapping %s: %w", path, err)
+	}
+	return mapping, nil
+}
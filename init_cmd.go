@@ -0,0 +1,503 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// Ecosystems detected from project manifests
+const (
+	ecosystemGo   = "go"
+	ecosystemNode = "node"
+)
+
+// ecosystemKeywords are the starter keywords of the repositories of each
+// ecosystem; "" covers repositories added by hand
+var ecosystemKeywords = map[string][]string{
+	"":            {"performance", "memory", "crash", "regression"},
+	ecosystemGo:   {"panic", "deadlock", "goroutine leak", "race", "memory leak", "performance"},
+	ecosystemNode: {"memory leak", "heap out of memory", "unhandled rejection", "event loop", "performance", "regression"},
+}
+
+// githubRepoPattern extracts owner/name from GitHub module paths and URLs
+var githubRepoPattern = regexp.MustCompile(`github\.com[/:]([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+)`)
+
+// dependencyRepo is a GitHub repository a local project depends on
+type dependencyRepo struct {
+	Name      string
+	Ecosystem string
+	Direct    bool
+}
+
+// initCommand creates a configuration interactively
+func initCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "init",
+		Usage: "交互式创建配置: 根据本地项目依赖推荐仓库和关键词, 初始化存储并试抓取",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "project",
+				Value: ".",
+				Usage: "用于检测依赖的本地项目目录 (读取 go.mod 和 package.json)",
+			},
+			&cli.BoolFlag{
+				Name:  "yes",
+				Usage: "不提问, 全部采用默认值",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "覆盖已存在的配置文件",
+			},
+			&cli.IntFlag{
+				Name:  "sample",
+				Value: 5,
+				Usage: "试抓取时每个仓库抽样的问题数, 0 表示跳过试抓取",
+			},
+		},
+		Action: runInit,
+	}
+}
+
+// prompter asks questions on the terminal, or takes the defaults
+type prompter struct {
+	in  *bufio.Reader
+	yes bool
+}
+
+// ask returns the answer to question, or def for an empty answer
+func (p *prompter) ask(question, def string) (string, error) {
+	if p.yes {
+		return def, nil
+	}
+	if def != "" {
+		fmt.Printf("%s [%s]: ", question, def)
+	} else {
+		fmt.Printf("%s: ", question)
+	}
+	// A closed stdin answers with the defaults
+	answer, err := p.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// confirm asks a yes/no question; without prompting the answer is yes
+func (p *prompter) confirm(question string, def bool) (bool, error) {
+	if p.yes {
+		return true, nil
+	}
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	answer, err := p.ask(question+" ("+hint+")", "")
+	if err != nil || answer == "" {
+		return def, err
+	}
+	return strings.HasPrefix(strings.ToLower(answer), "y"), nil
+}
+
+// runInit asks for a token, repositories and keywords, writes the config,
+// initializes the store and runs a sample scrape
+func runInit(c *cli.Context) error {
+	configPath := c.String("config")
+	if _, err := os.Stat(configPath); err == nil && !c.Bool("force") {
+		return fmt.Errorf("config file %s already exists (use --force to overwrite)", configPath)
+	}
+	p := &prompter{in: bufio.NewReader(os.Stdin), yes: c.Bool("yes")}
+
+	fmt.Println("🧭 gh-pitfall-scraper 初始化向导")
+	token, err := p.ask("GitHub Token (留空则匿名访问, 速率限制较低)", c.String("token"))
+	if err != nil {
+		return err
+	}
+
+	dependencies, err := detectDependencies(c.String("project"), false)
+	if err != nil {
+		return err
+	}
+	repos, err := chooseRepos(p, dependencies)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return fmt.Errorf("no repositories selected")
+	}
+
+	keywords := make(map[string][]string)
+	for _, repo := range repos {
+		if _, ok := keywords[repo.Ecosystem]; ok {
+			continue
+		}
+		answer, err := p.ask(fmt.Sprintf("%s 仓库的关键词 (逗号分隔)", ecosystemLabel(repo.Ecosystem)),
+			strings.Join(ecosystemKeywords[repo.Ecosystem], ", "))
+		if err != nil {
+			return err
+		}
+		keywords[repo.Ecosystem] = splitList(answer)
+	}
+
+	if err := os.WriteFile(configPath, []byte(renderInitConfig(token, repos, keywords)), 0600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	fmt.Printf("📝 已写入配置文件: %s\n", configPath)
+
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("generated config is invalid: %w", err)
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to read store: %w", err)
+	}
+	if len(issues) == 0 {
+		if err := os.MkdirAll(config.Storage.Dir, 0755); err != nil {
+			return fmt.Errorf("failed to create store: %w", err)
+		}
+		if err := store.SaveIssues(issues); err != nil {
+			return fmt.Errorf("failed to initialize store: %w", err)
+		}
+	}
+	fmt.Printf("🗄️  存储目录: %s\n", config.Storage.Dir)
+
+	sample := c.Int("sample")
+	if sample <= 0 {
+		return nil
+	}
+	run, err := p.confirm(fmt.Sprintf("是否试抓取 (每个仓库 %d 个问题) 以验证配置?", sample), true)
+	if err != nil || !run {
+		return err
+	}
+	config.Sample = sample
+	if err := runScrape(context.Background(), config, false); err != nil {
+		return fmt.Errorf("sample scrape failed: %w", err)
+	}
+	fmt.Println("✅ 初始化完成, 运行 gh-pitfall-scraper 开始完整抓取")
+	return nil
+}
+
+// chooseRepos lets the user pick detected repositories and add others
+func chooseRepos(p *prompter, dependencies []dependencyRepo) ([]dependencyRepo, error) {
+	var repos []dependencyRepo
+	if len(dependencies) == 0 {
+		fmt.Println("未在项目中检测到 GitHub 上的依赖")
+	} else {
+		fmt.Println("\n检测到的依赖仓库:")
+		for i, dependency := range dependencies {
+			fmt.Printf("  %2d. %s (%s)\n", i+1, dependency.Name, ecosystemLabel(dependency.Ecosystem))
+		}
+		answer, err := p.ask("选择要跟踪的仓库 (逗号分隔的编号, all 为全部)", "all")
+		if err != nil {
+			return nil, err
+		}
+		if answer == "all" {
+			repos = append(repos, dependencies...)
+		} else {
+			for _, item := range splitList(answer) {
+				n, err := strconv.Atoi(item)
+				if err != nil || n < 1 || n > len(dependencies) {
+					return nil, fmt.Errorf("invalid selection %q", item)
+				}
+				repos = append(repos, dependencies[n-1])
+			}
+		}
+	}
+
+	answer, err := p.ask("其他仓库 (owner/name, 逗号分隔, 可留空)", "")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range splitList(answer) {
+		if !strings.Contains(name, "/") {
+			return nil, fmt.Errorf("repository name %s must be in format owner/repo", name)
+		}
+		repos = append(repos, dependencyRepo{Name: name})
+	}
+	return repos, nil
+}
+
+// detectDependencies lists the GitHub repositories of the direct
+// dependencies in the project's go.mod and package.json, and with
+// transitive also those of go.sum and package-lock.json. npm packages are
+// resolved through the repository field of their installed package.json.
+func detectDependencies(dir string, transitive bool) ([]dependencyRepo, error) {
+	seen := make(map[string]bool)
+	var repos []dependencyRepo
+	add := func(name, ecosystem string, direct bool) {
+		if !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			repos = append(repos, dependencyRepo{Name: name, Ecosystem: ecosystem, Direct: direct})
+		}
+	}
+	addModules := func(modules []string, direct bool) {
+		for _, module := range modules {
+			if match := githubRepoPattern.FindStringSubmatch(module); match != nil {
+				add(match[1]+"/"+match[2], ecosystemGo, direct)
+			}
+		}
+	}
+	addPackages := func(packages []string, direct bool) {
+		for _, pkg := range packages {
+			if repo := npmRepository(filepath.Join(dir, "node_modules", pkg, "package.json")); repo != "" {
+				add(repo, ecosystemNode, direct)
+			}
+		}
+	}
+
+	direct, indirect, err := goModRequires(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	packages, err := packageDependencies(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil, err
+	}
+	addModules(direct, true)
+	addPackages(packages, true)
+	if !transitive {
+		return repos, nil
+	}
+
+	sums, err := goSumModules(filepath.Join(dir, "go.sum"))
+	if err != nil {
+		return nil, err
+	}
+	locked, err := lockedPackages(filepath.Join(dir, "package-lock.json"))
+	if err != nil {
+		return nil, err
+	}
+	addModules(indirect, false)
+	addModules(sums, false)
+	addPackages(locked, false)
+	return repos, nil
+}
+
+// goModRequires returns the direct and indirect requirements of a go.mod
+// file
+func goModRequires(path string) ([]string, []string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var direct, indirect []string
+	inBlock := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "require (":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimPrefix(line, "require ")
+		case !inBlock:
+			continue
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) < 2:
+		case strings.Contains(line, "// indirect"):
+			indirect = append(indirect, fields[0])
+		default:
+			direct = append(direct, fields[0])
+		}
+	}
+	return direct, indirect, nil
+}
+
+// goSumModules returns the modules of a go.sum file, which covers the
+// whole module graph
+func goSumModules(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var modules []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if fields := strings.Fields(line); len(fields) == 3 {
+			modules = append(modules, fields[0])
+		}
+	}
+	return modules, nil
+}
+
+// lockedPackages returns the package names of a package-lock.json file,
+// from its packages (lockfile v2 and later) or dependencies (v1)
+func lockedPackages(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var lock struct {
+		Packages     map[string]json.RawMessage `json:"packages"`
+		Dependencies map[string]json.RawMessage `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	seen := make(map[string]bool)
+	for key := range lock.Packages {
+		// Keys are install paths such as node_modules/a/node_modules/b
+		if i := strings.LastIndex(key, "node_modules/"); i >= 0 {
+			seen[key[i+len("node_modules/"):]] = true
+		}
+	}
+	if len(lock.Packages) == 0 {
+		for name := range lock.Dependencies {
+			seen[name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// packageDependencies returns the dependency names of a package.json file,
+// sorted
+func packageDependencies(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var manifest struct {
+		Dependencies map[string]string `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	names := make([]string, 0, len(manifest.Dependencies))
+	for name := range manifest.Dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// npmRepository returns the GitHub repository of an installed npm package,
+// or "" when it is not installed or not hosted on GitHub
+func npmRepository(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var manifest struct {
+		Repository json.RawMessage `json:"repository"`
+	}
+	if json.Unmarshal(data, &manifest) != nil {
+		return ""
+	}
+	// repository is either a URL string or an object with a url
+	var url string
+	if json.Unmarshal(manifest.Repository, &url) != nil {
+		var repository struct {
+			URL string `json:"url"`
+		}
+		json.Unmarshal(manifest.Repository, &repository)
+		url = repository.URL
+	}
+	if strings.HasPrefix(url, "github:") {
+		url = "github.com/" + strings.TrimPrefix(url, "github:")
+	}
+	match := githubRepoPattern.FindStringSubmatch(url)
+	if match == nil {
+		return ""
+	}
+	return match[1] + "/" + strings.TrimSuffix(match[2], ".git")
+}
+
+// renderInitConfig renders a minimal config; loadConfig supplies the
+// defaults of every other setting, documented in the bundled config.yaml
+func renderInitConfig(token string, repos []dependencyRepo, keywords map[string][]string) string {
+	var b strings.Builder
+	b.WriteString("# Generated by gh-pitfall-scraper init. See the bundled config.yaml for\n")
+	b.WriteString("# every other setting and its default.\n\n")
+	b.WriteString("# GitHub Token for API access (optional but recommended for higher rate limits)\n")
+	fmt.Fprintf(&b, "github_token: %s\n\n", strconv.Quote(token))
+
+	b.WriteString("# Repository configurations\nrepositories:\n")
+	b.WriteString(renderRepoEntries(repos, keywords))
+
+	b.WriteString("# Local issue store\nstorage:\n  dir: \"./data\"\n")
+	return b.String()
+}
+
+// renderRepoEntries renders repositories as entries of the repositories
+// list, with the keywords of their ecosystem
+func renderRepoEntries(repos []dependencyRepo, keywords map[string][]string) string {
+	var b strings.Builder
+	for _, repo := range repos {
+		words := keywords[repo.Ecosystem]
+		quoted := make([]string, len(words))
+		for i, word := range words {
+			quoted[i] = strconv.Quote(word)
+		}
+		fmt.Fprintf(&b, "  - name: %s\n", strconv.Quote(repo.Name))
+		b.WriteString("    enabled: true\n")
+		fmt.Fprintf(&b, "    keywords: [%s]\n", strings.Join(quoted, ", "))
+		b.WriteString("    min_score: 20.0\n")
+		b.WriteString("    max_issues: 100\n\n")
+	}
+	return b.String()
+}
+
+// ecosystemLabel names an ecosystem for prompts
+func ecosystemLabel(ecosystem string) string {
+	switch ecosystem {
+	case ecosystemGo:
+		return "Go"
+	case ecosystemNode:
+		return "Node.js"
+	default:
+		return "其他"
+	}
+}
+
+// splitList splits a comma-separated answer, dropping empty items
+func splitList(answer string) []string {
+	var items []string
+	for _, item := range strings.Split(answer, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
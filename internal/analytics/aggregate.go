@@ -0,0 +1,177 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// Time buckets accepted by Aggregate
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+)
+
+// Dimensions are the issue fields that can be grouped by
+var Dimensions = []string{"repo", "category", "team", "author", "state", "label", "platform", "source"}
+
+// AggregateRow is one group of an aggregation
+type AggregateRow struct {
+	Bucket   time.Time `json:"bucket,omitempty"`
+	Keys     []string  `json:"keys"`
+	Count    int       `json:"count"`
+	AvgScore float64   `json:"avg_score"`
+}
+
+// Aggregate groups issues by any combination of dimensions and an optional
+// creation-time bucket, with day boundaries in loc (UTC when nil). Issues
+// with several labels or platforms count once per value when grouping by
+// label or platform.
+func Aggregate(issues []model.Issue, dimensions []string, bucket string, loc *time.Location) ([]AggregateRow, error) {
+	for _, dimension := range dimensions {
+		if !isDimension(dimension) {
+			return nil, fmt.Errorf("unknown dimension: %s (expected one of %v)", dimension, Dimensions)
+		}
+	}
+	if bucket != "" && bucket != BucketDay && bucket != BucketWeek && bucket != BucketMonth {
+		return nil, fmt.Errorf("unknown bucket: %s (expected day, week or month)", bucket)
+	}
+
+	type group struct {
+		row        AggregateRow
+		totalScore float64
+	}
+	groups := make(map[string]*group)
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	for _, issue := range issues {
+		var start time.Time
+		if bucket != "" {
+			start = BucketStart(issue.CreatedAt.In(loc), bucket)
+		}
+
+		for _, keys := range combinations(issue, dimensions) {
+			id := start.Format(time.RFC3339) + "\x00" + strings.Join(keys, "\x00")
+			g := groups[id]
+			if g == nil {
+				g = &group{row: AggregateRow{Bucket: start, Keys: keys}}
+				groups[id] = g
+			}
+			g.row.Count++
+			g.totalScore += issue.Score
+		}
+	}
+
+	rows := make([]AggregateRow, 0, len(groups))
+	for _, g := range groups {
+		g.row.AvgScore = g.totalScore / float64(g.row.Count)
+		rows = append(rows, g.row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].Bucket.Equal(rows[j].Bucket) {
+			return rows[i].Bucket.Before(rows[j].Bucket)
+		}
+		if rows[i].Count != rows[j].Count {
+			return rows[i].Count > rows[j].Count
+		}
+		return strings.Join(rows[i].Keys, "/") < strings.Join(rows[j].Keys, "/")
+	})
+
+	return rows, nil
+}
+
+// BucketStart truncates t to the start of its day, ISO week (Monday) or month
+func BucketStart(t time.Time, bucket string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	switch bucket {
+	case BucketWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case BucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// FormatBucket renders a bucket start for display
+func FormatBucket(start time.Time, bucket string) string {
+	switch bucket {
+	case BucketMonth:
+		return start.Format("2006-01")
+	case BucketWeek:
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	default:
+		return start.Format("2006-01-02")
+	}
+}
+
+// combinations returns every key tuple an issue contributes to
+func combinations(issue model.Issue, dimensions []string) [][]string {
+	result := [][]string{{}}
+	for _, dimension := range dimensions {
+		values := dimensionValues(issue, dimension)
+		var next [][]string
+		for _, prefix := range result {
+			for _, value := range values {
+				keys := append(append([]string{}, prefix...), value)
+				next = append(next, keys)
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+// dimensionValues returns the values of a dimension for an issue
+func dimensionValues(issue model.Issue, dimension string) []string {
+	var values []string
+	switch dimension {
+	case "repo":
+		values = []string{issue.Repository}
+	case "category":
+		values = []string{issue.Category}
+	case "team":
+		values = []string{issue.AssignedTeam}
+	case "author":
+		values = []string{issue.Author}
+	case "state":
+		values = []string{issue.State}
+	case "label":
+		for _, label := range issue.Labels {
+			values = append(values, label.Name)
+		}
+	case "platform":
+		values = append(values, issue.Platforms...)
+	case "source":
+		values = []string{issue.SourceName()}
+	}
+
+	if len(values) == 0 {
+		return []string{"(none)"}
+	}
+	for i, value := range values {
+		if value == "" {
+			values[i] = "(none)"
+		}
+	}
+	return values
+}
+
+func isDimension(name string) bool {
+	for _, dimension := range Dimensions {
+		if dimension == name {
+			return true
+		}
+	}
+	return false
+}
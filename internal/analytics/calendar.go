@@ -0,0 +1,114 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// holidayType is the day type of holidays, after the seven weekdays
+const holidayType = 7
+
+// minDayFactor keeps quiet day types from dividing counts by zero
+const minDayFactor = 0.1
+
+// Calendar classifies days as weekdays or holidays in a timezone, to
+// normalize issue rates against calendar effects
+type Calendar struct {
+	holidays map[string]bool
+	location *time.Location
+}
+
+// DayProfile is the relative issue rate of each weekday (Sunday first)
+// and of holidays; 1 is the average rate over all days
+type DayProfile [8]float64
+
+// NewCalendar creates a calendar with the given holidays (YYYY-MM-DD) in
+// loc (UTC when nil)
+func NewCalendar(holidays []string, loc *time.Location) (*Calendar, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	calendar := &Calendar{holidays: make(map[string]bool), location: loc}
+	for _, holiday := range holidays {
+		if _, err := time.Parse("2006-01-02", holiday); err != nil {
+			return nil, fmt.Errorf("invalid holiday %q (expected YYYY-MM-DD)", holiday)
+		}
+		calendar.holidays[holiday] = true
+	}
+	return calendar, nil
+}
+
+// dayType returns the weekday of t, or holidayType on holidays
+func (c *Calendar) dayType(t time.Time) int {
+	t = t.In(c.location)
+	if c.holidays[t.Format("2006-01-02")] {
+		return holidayType
+	}
+	return int(t.Weekday())
+}
+
+// Profile estimates how many issues are created on each day type relative
+// to the average day, over the days from the first to the last issue
+func (c *Calendar) Profile(issues []model.Issue) DayProfile {
+	profile := DayProfile{1, 1, 1, 1, 1, 1, 1, 1}
+	if len(issues) == 0 {
+		return profile
+	}
+
+	var first, last time.Time
+	var created [8]int
+	for _, issue := range issues {
+		day := BucketStart(issue.CreatedAt.In(c.location), BucketDay)
+		if first.IsZero() || day.Before(first) {
+			first = day
+		}
+		if day.After(last) {
+			last = day
+		}
+		created[c.dayType(day)]++
+	}
+
+	var days [8]int
+	total := 0
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		days[c.dayType(day)]++
+		total++
+	}
+
+	average := float64(len(issues)) / float64(total)
+	for dayType := range profile {
+		if days[dayType] == 0 {
+			continue
+		}
+		rate := float64(created[dayType]) / float64(days[dayType])
+		profile[dayType] = math.Max(rate/average, minDayFactor)
+	}
+	return profile
+}
+
+// Weight returns how many average days the days from start up to end are
+// worth under the profile: a week with a holiday is worth less than 7
+func (c *Calendar) Weight(profile DayProfile, start, end time.Time) float64 {
+	weight := 0.0
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		weight += profile[c.dayType(day)]
+	}
+	return weight
+}
+
+// Normalize scales a count over the days from start up to end to what an
+// average stretch of as many days would have
+func (c *Calendar) Normalize(profile DayProfile, count float64, start, end time.Time) float64 {
+	weight := c.Weight(profile, start, end)
+	if weight == 0 {
+		return count
+	}
+	days := 0
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		days++
+	}
+	return count * float64(days) / weight
+}
@@ -0,0 +1,88 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// TestCalendarProfile covers two weeks from Monday, May 6th 2024, with
+// three issues on each working day and one on each weekend day and on the
+// Wednesday holiday: 32 issues in 14 days
+func TestCalendarProfile(t *testing.T) {
+	calendar, err := NewCalendar([]string{"2024-05-15"}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create calendar: %v", err)
+	}
+	var issues []model.Issue
+	for day := 6; day <= 19; day++ {
+		created := time.Date(2024, 5, day, 9, 0, 0, 0, time.UTC)
+		perDay := 3
+		if weekday := created.Weekday(); weekday == time.Saturday || weekday == time.Sunday || day == 15 {
+			perDay = 1
+		}
+		for i := 0; i < perDay; i++ {
+			issues = append(issues, model.Issue{Number: len(issues) + 1, CreatedAt: created})
+		}
+	}
+
+	// Working days see 3 issues against an average of 32/14, quiet days 1
+	profile := calendar.Profile(issues)
+	expected := DayProfile{7.0 / 16, 21.0 / 16, 21.0 / 16, 21.0 / 16, 21.0 / 16, 21.0 / 16, 7.0 / 16, 7.0 / 16}
+	for dayType := range expected {
+		if math.Abs(profile[dayType]-expected[dayType]) > 1e-9 {
+			t.Errorf("Expected day type %d weighted %.4f, got %.4f", dayType, expected[dayType], profile[dayType])
+		}
+	}
+
+	// The holiday week has fewer issues but normalizes to the same rate
+	weeks := []struct {
+		start  time.Time
+		count  float64
+		weight float64
+	}{
+		{time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), 17, 119.0 / 16},
+		{time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC), 15, 105.0 / 16},
+	}
+	for _, week := range weeks {
+		end := week.start.AddDate(0, 0, 7)
+		if weight := calendar.Weight(profile, week.start, end); math.Abs(weight-week.weight) > 1e-9 {
+			t.Errorf("Expected the week of %s to weigh %.4f days, got %.4f", week.start.Format("2006-01-02"), week.weight, weight)
+		}
+		if normalized := calendar.Normalize(profile, week.count, week.start, end); math.Abs(normalized-16) > 1e-9 {
+			t.Errorf("Expected the week of %s normalized to 16 issues, got %.4f", week.start.Format("2006-01-02"), normalized)
+		}
+	}
+}
+
+func TestCalendarProfileQuietDays(t *testing.T) {
+	calendar, err := NewCalendar(nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to create calendar: %v", err)
+	}
+	if profile := calendar.Profile(nil); profile != (DayProfile{1, 1, 1, 1, 1, 1, 1, 1}) {
+		t.Errorf("Expected a flat profile without issues, got %v", profile)
+	}
+
+	// Monday to Friday: weekends are outside the range and stay average
+	var issues []model.Issue
+	for day := 6; day <= 10; day++ {
+		issues = append(issues, model.Issue{Number: day, CreatedAt: time.Date(2024, 5, day, 9, 0, 0, 0, time.UTC)})
+	}
+	profile := calendar.Profile(issues)
+	if profile[time.Monday] != 1 || profile[time.Saturday] != 1 || profile[holidayType] != 1 {
+		t.Errorf("Expected only the days between the first and last issue weighted, got %v", profile)
+	}
+	// Up to the next Monday: the weekend without issues gets the minimum
+	// factor instead of zero
+	issues = append(issues, model.Issue{Number: 13, CreatedAt: time.Date(2024, 5, 13, 9, 0, 0, 0, time.UTC)})
+	profile = calendar.Profile(issues)
+	if profile[time.Saturday] != minDayFactor || profile[holidayType] != 1 {
+		t.Errorf("Expected weekends at the minimum factor and holidays average, got %v", profile)
+	}
+	if _, err := NewCalendar([]string{"05/15/2024"}, nil); err == nil {
+		t.Error("Expected an invalid holiday date to be rejected")
+	}
+}
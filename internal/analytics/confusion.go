@@ -0,0 +1,149 @@
+package analytics
+
+import "sort"
+
+// CategoryPair is the category the rules predict for an issue and the
+// category a human corrected it to
+type CategoryPair struct {
+	Predicted string
+	Actual    string
+}
+
+// CategoryQuality is the precision and recall of the rules for one category
+type CategoryQuality struct {
+	Category      string  `json:"category"`
+	Predicted     int     `json:"predicted"`
+	Actual        int     `json:"actual"`
+	TruePositives int     `json:"true_positives"`
+	Precision     float64 `json:"precision"`
+	Recall        float64 `json:"recall"`
+}
+
+// ConfusionReport compares predicted with corrected categories
+type ConfusionReport struct {
+	Categories []string `json:"categories"`
+	// Matrix counts pairs by predicted, then actual category
+	Matrix  map[string]map[string]int `json:"matrix"`
+	Total   int                       `json:"total"`
+	Correct int                       `json:"correct"`
+	Quality []CategoryQuality         `json:"quality"`
+	// Confidence is the accuracy per confidence band of recorded results
+	Confidence []ConfidenceBand `json:"confidence,omitempty"`
+}
+
+// ScoredPair is a category pair with the confidence of the prediction
+type ScoredPair struct {
+	CategoryPair
+	Confidence float64
+}
+
+// ConfidenceBand is the accuracy of predictions with a confidence in
+// [Min, Max)
+type ConfidenceBand struct {
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Total    int     `json:"total"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// confidenceBounds are the lower bounds of the confidence bands
+var confidenceBounds = []float64{0, 0.5, 0.75, 1}
+
+// ConfidenceBands groups scored pairs into confidence bands; the last band
+// holds the predictions where only the winning rule matched. Empty bands
+// are left out.
+func ConfidenceBands(pairs []ScoredPair) []ConfidenceBand {
+	if len(pairs) == 0 {
+		return nil
+	}
+	bands := make([]ConfidenceBand, len(confidenceBounds))
+	for i, min := range confidenceBounds {
+		bands[i].Min, bands[i].Max = min, 1
+		if i+1 < len(confidenceBounds) {
+			bands[i].Max = confidenceBounds[i+1]
+		}
+	}
+	for _, pair := range pairs {
+		i := len(bands) - 1
+		for i > 0 && pair.Confidence < bands[i].Min {
+			i--
+		}
+		bands[i].Total++
+		if pair.Predicted == pair.Actual {
+			bands[i].Correct++
+		}
+	}
+
+	var filled []ConfidenceBand
+	for _, band := range bands {
+		if band.Total == 0 {
+			continue
+		}
+		band.Accuracy = float64(band.Correct) / float64(band.Total)
+		filled = append(filled, band)
+	}
+	return filled
+}
+
+// Accuracy is the share of pairs the rules got right
+func (r ConfusionReport) Accuracy() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Correct) / float64(r.Total)
+}
+
+// BuildConfusion builds the confusion matrix and per-category quality of
+// category pairs. Categories are sorted by how often they are the actual
+// category, so the ones that matter most come first.
+func BuildConfusion(pairs []CategoryPair) ConfusionReport {
+	report := ConfusionReport{Matrix: make(map[string]map[string]int), Total: len(pairs)}
+	predicted := make(map[string]int)
+	actual := make(map[string]int)
+	for _, pair := range pairs {
+		if report.Matrix[pair.Predicted] == nil {
+			report.Matrix[pair.Predicted] = make(map[string]int)
+		}
+		report.Matrix[pair.Predicted][pair.Actual]++
+		predicted[pair.Predicted]++
+		actual[pair.Actual]++
+		if pair.Predicted == pair.Actual {
+			report.Correct++
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, counts := range []map[string]int{actual, predicted} {
+		for category := range counts {
+			if !seen[category] {
+				seen[category] = true
+				report.Categories = append(report.Categories, category)
+			}
+		}
+	}
+	sort.Slice(report.Categories, func(i, j int) bool {
+		a, b := report.Categories[i], report.Categories[j]
+		if actual[a] != actual[b] {
+			return actual[a] > actual[b]
+		}
+		return a < b
+	})
+
+	for _, category := range report.Categories {
+		quality := CategoryQuality{
+			Category:      category,
+			Predicted:     predicted[category],
+			Actual:        actual[category],
+			TruePositives: report.Matrix[category][category],
+		}
+		if quality.Predicted > 0 {
+			quality.Precision = float64(quality.TruePositives) / float64(quality.Predicted)
+		}
+		if quality.Actual > 0 {
+			quality.Recall = float64(quality.TruePositives) / float64(quality.Actual)
+		}
+		report.Quality = append(report.Quality, quality)
+	}
+	return report
+}
@@ -0,0 +1,70 @@
+package analytics
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestBuildConfusion(t *testing.T) {
+	pairs := []CategoryPair{
+		{"crashes", "crashes"},
+		{"crashes", "crashes"},
+		{"crashes", "performance"},
+		{"performance", "performance"},
+		{"other", "performance"},
+	}
+	report := BuildConfusion(pairs)
+
+	if report.Total != 5 || report.Correct != 3 || report.Accuracy() != 0.6 {
+		t.Errorf("Expected 3 of 5 correct, got %d of %d (%.2f)", report.Correct, report.Total, report.Accuracy())
+	}
+	if report.Matrix["crashes"]["performance"] != 1 || report.Matrix["crashes"]["crashes"] != 2 {
+		t.Errorf("Unexpected matrix %v", report.Matrix)
+	}
+	// Most often actual first; categories only ever predicted come last
+	if categories := []string{"performance", "crashes", "other"}; !reflect.DeepEqual(report.Categories, categories) {
+		t.Errorf("Expected categories %v, got %v", categories, report.Categories)
+	}
+
+	expected := []CategoryQuality{
+		{Category: "performance", Predicted: 1, Actual: 3, TruePositives: 1, Precision: 1, Recall: 1.0 / 3},
+		{Category: "crashes", Predicted: 3, Actual: 2, TruePositives: 2, Precision: 2.0 / 3, Recall: 1},
+		{Category: "other", Predicted: 1},
+	}
+	if len(report.Quality) != len(expected) {
+		t.Fatalf("Expected quality %+v, got %+v", expected, report.Quality)
+	}
+	for i, quality := range report.Quality {
+		if quality.Category != expected[i].Category || quality.Predicted != expected[i].Predicted ||
+			quality.Actual != expected[i].Actual || quality.TruePositives != expected[i].TruePositives ||
+			math.Abs(quality.Precision-expected[i].Precision) > 1e-9 || math.Abs(quality.Recall-expected[i].Recall) > 1e-9 {
+			t.Errorf("Expected %+v, got %+v", expected[i], quality)
+		}
+	}
+
+	if empty := BuildConfusion(nil); empty.Total != 0 || empty.Accuracy() != 0 || len(empty.Categories) != 0 {
+		t.Errorf("Expected an empty report, got %+v", empty)
+	}
+}
+
+func TestConfidenceBands(t *testing.T) {
+	pairs := []ScoredPair{
+		{CategoryPair{"crashes", "crashes"}, 0.2},
+		{CategoryPair{"crashes", "performance"}, 0.6},
+		{CategoryPair{"performance", "performance"}, 0.7},
+		{CategoryPair{"memory", "memory"}, 1},
+	}
+	// The 0.75-1 band has no pairs and is left out
+	expected := []ConfidenceBand{
+		{Min: 0, Max: 0.5, Total: 1, Correct: 1, Accuracy: 1},
+		{Min: 0.5, Max: 0.75, Total: 2, Correct: 1, Accuracy: 0.5},
+		{Min: 1, Max: 1, Total: 1, Correct: 1, Accuracy: 1},
+	}
+	if bands := ConfidenceBands(pairs); !reflect.DeepEqual(bands, expected) {
+		t.Errorf("Expected bands %+v, got %+v", expected, bands)
+	}
+	if bands := ConfidenceBands(nil); bands != nil {
+		t.Errorf("Expected no bands without pairs, got %+v", bands)
+	}
+}
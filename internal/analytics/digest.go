@@ -0,0 +1,143 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// digestTopN is the number of new pitfalls listed in a digest
+const digestTopN = 5
+
+// Trend changes below these thresholds are not considered notable
+const (
+	minTrendIssues = 3
+	minTrendChange = 50.0
+)
+
+// Digest is a compact summary of the pitfalls first seen in a period
+type Digest struct {
+	From     time.Time     `json:"from"`
+	To       time.Time     `json:"to"`
+	NewCount int           `json:"new_count"`
+	Top      []model.Issue `json:"top"`
+	Trends   []TrendChange `json:"trends"`
+	Corpus   int           `json:"corpus"`
+}
+
+// TrendChange compares the new pitfalls of a category with the previous
+// period of the same length
+type TrendChange struct {
+	Category string  `json:"category"`
+	Previous int     `json:"previous"`
+	Current  int     `json:"current"`
+	Change   float64 `json:"change_pct"`
+}
+
+// ParsePeriod parses a digest period such as "7d", "2w" or a Go duration
+func ParsePeriod(period string) (time.Duration, error) {
+	if n, ok := strings.CutSuffix(period, "d"); ok {
+		if days, err := strconv.Atoi(n); err == nil && days > 0 {
+			return time.Duration(days) * 24 * time.Hour, nil
+		}
+	}
+	if n, ok := strings.CutSuffix(period, "w"); ok {
+		if weeks, err := strconv.Atoi(n); err == nil && weeks > 0 {
+			return time.Duration(weeks) * 7 * 24 * time.Hour, nil
+		}
+	}
+	if d, err := time.ParseDuration(period); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("invalid period %q (e.g. 7d, 2w, 36h)", period)
+}
+
+// BuildDigest summarizes the pitfalls first seen during the period ending
+// at now: how many are new, the highest scoring ones and the categories
+// whose number of new pitfalls changed notably from the previous period.
+// With a calendar, the change is computed on counts normalized for the
+// weekdays and holidays each period covers.
+func BuildDigest(issues map[string][]model.Issue, period time.Duration, now time.Time, calendar *Calendar) Digest {
+	from := now.Add(-period)
+	previousFrom := from.Add(-period)
+	digest := Digest{From: from, To: now}
+
+	current := make(map[string]int)
+	previous := make(map[string]int)
+	var fresh []model.Issue
+	for _, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			digest.Corpus++
+			seen := issue.FirstSeenAt
+			if seen.IsZero() {
+				seen = issue.CreatedAt
+			}
+
+			category := issue.Category
+			if category == "" {
+				category = "other"
+			}
+
+			switch {
+			case !seen.Before(from) && !seen.After(now):
+				fresh = append(fresh, issue)
+				current[category]++
+			case !seen.Before(previousFrom) && seen.Before(from):
+				previous[category]++
+			}
+		}
+	}
+
+	digest.NewCount = len(fresh)
+	sort.Slice(fresh, func(i, j int) bool {
+		if fresh[i].Score != fresh[j].Score {
+			return fresh[i].Score > fresh[j].Score
+		}
+		return fresh[i].Reactions > fresh[j].Reactions
+	})
+	if len(fresh) > digestTopN {
+		fresh = fresh[:digestTopN]
+	}
+	digest.Top = fresh
+
+	var profile DayProfile
+	if calendar != nil {
+		var all []model.Issue
+		for _, repoIssues := range issues {
+			all = append(all, repoIssues...)
+		}
+		profile = calendar.Profile(all)
+	}
+
+	categories := make(map[string]bool)
+	for category := range current {
+		categories[category] = true
+	}
+	for category := range previous {
+		categories[category] = true
+	}
+	for category := range categories {
+		cur, prev := current[category], previous[category]
+		if cur < minTrendIssues && prev < minTrendIssues {
+			continue
+		}
+		change := percentChange(float64(prev), float64(cur))
+		if calendar != nil {
+			change = percentChange(calendar.Normalize(profile, float64(prev), previousFrom, from),
+				calendar.Normalize(profile, float64(cur), from, now))
+		}
+		if prev > 0 && change > -minTrendChange && change < minTrendChange {
+			continue
+		}
+		digest.Trends = append(digest.Trends, TrendChange{Category: category, Previous: prev, Current: cur, Change: change})
+	}
+	sort.Slice(digest.Trends, func(i, j int) bool {
+		return math.Abs(digest.Trends[i].Change) > math.Abs(digest.Trends[j].Change)
+	})
+	return digest
+}
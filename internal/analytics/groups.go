@@ -0,0 +1,105 @@
+package analytics
+
+import (
+	"sort"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// GroupSummary describes one pitfall together with its duplicates
+type GroupSummary struct {
+	Master         string   `json:"master"`
+	MasterTitle    string   `json:"master_title"`
+	MasterURL      string   `json:"master_url"`
+	MemberCount    int      `json:"member_count"`
+	Members        []string `json:"members"`
+	Repositories   []string `json:"repositories"`
+	Reactions      int      `json:"reactions"`
+	BestWorkaround string   `json:"best_workaround,omitempty"`
+	Score          float64  `json:"score"`
+}
+
+// SummarizeGroups folds issues into one summary per duplicate group, with
+// issues outside any group as groups of one. Groups are formed from all
+// duplicate links; only members present in issues contribute reactions,
+// repositories and workarounds, and the highest-scoring one is the master.
+// Summaries are ordered by master score.
+func SummarizeGroups(issues map[string][]model.Issue, links []model.DuplicateLink) []GroupSummary {
+	byRef := make(map[string]model.Issue)
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			byRef[model.IssueRef(repoName, issue.Number)] = issue
+		}
+	}
+
+	grouped := make(map[string]bool)
+	var summaries []GroupSummary
+	for _, group := range model.DuplicateGroups(links) {
+		if summary, ok := summarizeGroup(group, byRef); ok {
+			summaries = append(summaries, summary)
+			for _, ref := range group {
+				grouped[ref] = true
+			}
+		}
+	}
+
+	for ref := range byRef {
+		if !grouped[ref] {
+			summary, _ := summarizeGroup([]string{ref}, byRef)
+			summaries = append(summaries, summary)
+		}
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Score != summaries[j].Score {
+			return summaries[i].Score > summaries[j].Score
+		}
+		return summaries[i].Master < summaries[j].Master
+	})
+	return summaries
+}
+
+// summarizeGroup summarizes a group of references, reporting false when
+// none of its members are known
+func summarizeGroup(group []string, byRef map[string]model.Issue) (GroupSummary, bool) {
+	summary := GroupSummary{MemberCount: len(group), Members: group}
+	repos := make(map[string]bool)
+	var master *model.Issue
+	var masterRef string
+	var best *model.Workaround
+
+	for _, ref := range group {
+		issue, ok := byRef[ref]
+		if !ok {
+			continue
+		}
+
+		repo, _, _ := model.ParseIssueRef(ref)
+		summary.Reactions += issue.Reactions
+		repos[repo] = true
+		if issue.Workaround != nil && (best == nil || issue.Workaround.Confidence > best.Confidence) {
+			best = issue.Workaround
+		}
+		if master == nil || issue.Score > master.Score {
+			m := issue
+			master, masterRef = &m, ref
+		}
+	}
+
+	if master == nil {
+		return summary, false
+	}
+
+	summary.Master = masterRef
+	summary.MasterTitle = master.Title
+	summary.MasterURL = master.URL
+	summary.Score = master.Score
+	if best != nil {
+		summary.BestWorkaround = best.URL
+	}
+	for repo := range repos {
+		summary.Repositories = append(summary.Repositories, repo)
+	}
+	sort.Strings(summary.Repositories)
+	return summary, true
+}
@@ -0,0 +1,191 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// PortfolioStats rolls up the issues of one portfolio
+type PortfolioStats struct {
+	Name         string         `json:"name"`
+	Repositories int            `json:"repositories"`
+	Issues       int            `json:"issues"`
+	Open         int            `json:"open"`
+	HighPriority int            `json:"high_priority"`
+	AverageScore float64        `json:"average_score"`
+	TopCategory  string         `json:"top_category,omitempty"`
+	ByCategory   map[string]int `json:"by_category"`
+}
+
+// PortfolioRollup summarizes the issues of each named portfolio, in the
+// given order. Repositories counts the repositories with issues; high
+// priority issues are those with critical or high upstream priority.
+func PortfolioRollup(issues map[string][]model.Issue, names []string) []PortfolioStats {
+	rollup := make([]PortfolioStats, len(names))
+	for i, name := range names {
+		stats := PortfolioStats{Name: name, ByCategory: make(map[string]int)}
+		total := 0.0
+		for _, repoIssues := range issues {
+			counted := false
+			for _, issue := range repoIssues {
+				if !containsString(issue.Portfolios, name) {
+					continue
+				}
+				if !counted {
+					stats.Repositories++
+					counted = true
+				}
+				stats.Issues++
+				total += issue.Score
+				if issue.State == "open" {
+					stats.Open++
+				}
+				if issue.Priority == "critical" || issue.Priority == "high" {
+					stats.HighPriority++
+				}
+				stats.ByCategory[issue.Category]++
+			}
+		}
+		if stats.Issues > 0 {
+			stats.AverageScore = total / float64(stats.Issues)
+		}
+		stats.TopCategory = topKey(stats.ByCategory)
+		rollup[i] = stats
+	}
+	return rollup
+}
+
+// Risk trend directions
+const (
+	TrendRising  = "rising"
+	TrendFalling = "falling"
+	TrendStable  = "stable"
+)
+
+// riskSaturation is the number of unresolved high-severity pitfalls at
+// which that signal reaches half of its weight
+const riskSaturation = 10
+
+// RiskWeights weighs the signals of the portfolio risk score
+type RiskWeights struct {
+	Unresolved float64
+	Health     float64
+	Trend      float64
+}
+
+// PortfolioRisk is the risk score of one portfolio at a point in time
+type PortfolioRisk struct {
+	Name         string    `json:"name"`
+	ComputedAt   time.Time `json:"computed_at"`
+	Score        float64   `json:"score"` // 0-100, higher is riskier
+	Unresolved   int       `json:"unresolved"`
+	Repositories int       `json:"repositories"`
+	Archived     int       `json:"archived"`
+	Unresponsive float64   `json:"unresponsive"` // share of open issues without upstream response
+	Recent       int       `json:"recent"`
+	Previous     int       `json:"previous"`
+	Trend        string    `json:"trend"`
+}
+
+// PortfolioRiskScores scores the risk of each named portfolio, in the given
+// order, by combining three signals of 0-1 with weights:
+//
+//   - unresolved: open high-severity pitfalls, saturating as they pile up
+//   - health: the share of archived repositories (from RepoRisks) averaged
+//     with the share of open issues left without upstream response
+//   - trend: high-severity pitfalls opened in the last window compared with
+//     the window before, 0.5 when both are equal
+func PortfolioRiskScores(issues map[string][]model.Issue, names []string, weights RiskWeights, window time.Duration, now time.Time) []PortfolioRisk {
+	risks := make([]PortfolioRisk, len(names))
+	for i, name := range names {
+		risk := PortfolioRisk{Name: name, ComputedAt: now}
+		open, unresponsive := 0, 0
+		for _, repoIssues := range issues {
+			counted := false
+			for _, issue := range repoIssues {
+				if !containsString(issue.Portfolios, name) {
+					continue
+				}
+				if !counted {
+					risk.Repositories++
+					if containsString(issue.RepoRisks, model.RepoRiskArchived) {
+						risk.Archived++
+					}
+					counted = true
+				}
+				if issue.State == "open" {
+					open++
+					if issue.IsAbandoned {
+						unresponsive++
+					}
+				}
+				if issue.Priority != "critical" && issue.Priority != "high" {
+					continue
+				}
+				if issue.State == "open" {
+					risk.Unresolved++
+				}
+				switch age := now.Sub(issue.CreatedAt); {
+				case age < window:
+					risk.Recent++
+				case age < 2*window:
+					risk.Previous++
+				}
+			}
+		}
+		if open > 0 {
+			risk.Unresponsive = float64(unresponsive) / float64(open)
+		}
+
+		unresolved := float64(risk.Unresolved) / float64(risk.Unresolved+riskSaturation)
+		health := risk.Unresponsive / 2
+		if risk.Repositories > 0 {
+			health += float64(risk.Archived) / float64(risk.Repositories) / 2
+		}
+		trend := 0.5
+		if risk.Recent+risk.Previous > 0 {
+			trend = float64(risk.Recent) / float64(risk.Recent+risk.Previous)
+		}
+		switch {
+		case risk.Recent > risk.Previous:
+			risk.Trend = TrendRising
+		case risk.Recent < risk.Previous:
+			risk.Trend = TrendFalling
+		default:
+			risk.Trend = TrendStable
+		}
+
+		if total := weights.Unresolved + weights.Health + weights.Trend; total > 0 {
+			risk.Score = 100 * (weights.Unresolved*unresolved + weights.Health*health + weights.Trend*trend) / total
+		}
+		risks[i] = risk
+	}
+	return risks
+}
+
+// topKey returns the key with the highest count, the first by name on ties
+func topKey(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	top := ""
+	for _, key := range keys {
+		if top == "" || counts[key] > counts[top] {
+			top = key
+		}
+	}
+	return top
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
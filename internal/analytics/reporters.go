@@ -0,0 +1,100 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// reputationRepoTarget is the number of repositories at which cross-repo
+// reach stops increasing a reporter's reputation
+const reputationRepoTarget = 3
+
+// ReporterStats summarizes a single reporter's pitfalls across repositories
+type ReporterStats struct {
+	Author       string   `json:"author"`
+	Repositories []string `json:"repositories"`
+	IssueCount   int      `json:"issue_count"`
+	AvgScore     float64  `json:"avg_score"`
+	Reputation   float64  `json:"reputation"`
+}
+
+// ComputeReporters aggregates pitfall reporters across repositories.
+//
+// Reputation is in the range 0-1: the reporter's average pitfall score,
+// without the points earned by reputation itself, scaled by how many
+// repositories they report in, saturating at reputationRepoTarget
+// repositories. Results are ordered by reputation.
+func ComputeReporters(issues map[string][]model.Issue) []ReporterStats {
+	type accumulator struct {
+		repos      map[string]bool
+		count      int
+		totalScore float64
+	}
+	byAuthor := make(map[string]*accumulator)
+
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			if issue.Author == "" {
+				continue
+			}
+			acc := byAuthor[issue.Author]
+			if acc == nil {
+				acc = &accumulator{repos: make(map[string]bool)}
+				byAuthor[issue.Author] = acc
+			}
+			acc.repos[repoName] = true
+			acc.count++
+			acc.totalScore += issue.BaseScore()
+		}
+	}
+
+	reporters := make([]ReporterStats, 0, len(byAuthor))
+	for author, acc := range byAuthor {
+		repos := make([]string, 0, len(acc.repos))
+		for repoName := range acc.repos {
+			repos = append(repos, repoName)
+		}
+		sort.Strings(repos)
+
+		avg := acc.totalScore / float64(acc.count)
+		reach := math.Min(1, float64(len(repos))/reputationRepoTarget)
+		reporters = append(reporters, ReporterStats{
+			Author:       author,
+			Repositories: repos,
+			IssueCount:   acc.count,
+			AvgScore:     avg,
+			Reputation:   math.Min(1, avg/100) * reach,
+		})
+	}
+
+	sort.Slice(reporters, func(i, j int) bool {
+		if reporters[i].Reputation != reporters[j].Reputation {
+			return reporters[i].Reputation > reporters[j].Reputation
+		}
+		return reporters[i].Author < reporters[j].Author
+	})
+
+	return reporters
+}
+
+// CrossRepoReporters returns only reporters active in more than one repository
+func CrossRepoReporters(reporters []ReporterStats) []ReporterStats {
+	var overlap []ReporterStats
+	for _, reporter := range reporters {
+		if len(reporter.Repositories) > 1 {
+			overlap = append(overlap, reporter)
+		}
+	}
+	return overlap
+}
+
+// ReputationMap indexes reporter reputation by author
+func ReputationMap(reporters []ReporterStats) map[string]float64 {
+	reputation := make(map[string]float64, len(reporters))
+	for _, reporter := range reporters {
+		reputation[reporter.Author] = reporter.Reputation
+	}
+	return reputation
+}
@@ -0,0 +1,98 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// SLA breach kinds
+const (
+	BreachUntriaged  = "untriaged"
+	BreachUnanswered = "unanswered"
+)
+
+// SLABreach describes a single issue that exceeded an SLA window
+type SLABreach struct {
+	Issue model.Issue   `json:"issue"`
+	Kind  string        `json:"kind"`
+	Age   time.Duration `json:"age"`
+	Limit time.Duration `json:"limit"`
+}
+
+// SLAReport summarizes issue aging against the configured SLA windows
+type SLAReport struct {
+	GeneratedAt     time.Time     `json:"generated_at"`
+	TotalIssues     int           `json:"total_issues"`
+	Untriaged       int           `json:"untriaged"`
+	Unanswered      int           `json:"unanswered"`
+	AvgUntriagedAge time.Duration `json:"avg_untriaged_age"`
+	Breaches        []SLABreach   `json:"breaches"`
+}
+
+// CheckSLA computes issue aging and SLA breaches.
+//
+// An issue is untriaged locally while it has no assigned team; its age is
+// measured from when it was first seen locally. An issue is unanswered
+// upstream while it is open without any comments; its age is measured from
+// its creation on GitHub. A zero window disables the corresponding check.
+func CheckSLA(issues map[string][]model.Issue, untriagedWindow, unansweredWindow time.Duration, now time.Time) SLAReport {
+	report := SLAReport{GeneratedAt: now}
+	var untriagedTotal time.Duration
+
+	for _, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			report.TotalIssues++
+
+			if issue.AssignedTeam == "" {
+				seen := issue.FirstSeenAt
+				if seen.IsZero() {
+					seen = issue.CreatedAt
+				}
+				age := now.Sub(seen)
+				report.Untriaged++
+				untriagedTotal += age
+
+				if untriagedWindow > 0 && age > untriagedWindow {
+					report.Breaches = append(report.Breaches, SLABreach{
+						Issue: issue,
+						Kind:  BreachUntriaged,
+						Age:   age,
+						Limit: untriagedWindow,
+					})
+				}
+			}
+
+			if issue.State == "open" && issue.Comments == 0 {
+				age := now.Sub(issue.CreatedAt)
+				report.Unanswered++
+
+				if unansweredWindow > 0 && age > unansweredWindow {
+					report.Breaches = append(report.Breaches, SLABreach{
+						Issue: issue,
+						Kind:  BreachUnanswered,
+						Age:   age,
+						Limit: unansweredWindow,
+					})
+				}
+			}
+		}
+	}
+
+	if report.Untriaged > 0 {
+		report.AvgUntriagedAge = untriagedTotal / time.Duration(report.Untriaged)
+	}
+
+	// Worst offenders first: by how far past the window they are
+	sort.SliceStable(report.Breaches, func(i, j int) bool {
+		return report.Breaches[i].Age-report.Breaches[i].Limit > report.Breaches[j].Age-report.Breaches[j].Limit
+	})
+
+	return report
+}
+
+// Days converts a duration to fractional days for display
+func Days(d time.Duration) float64 {
+	return d.Hours() / 24
+}
@@ -0,0 +1,279 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// Time series metrics
+const (
+	MetricCount    = "count"
+	MetricAvgScore = "avg_score"
+	// MetricCountNormalized is the count adjusted for weekday and holiday effects
+	MetricCountNormalized = "count_normalized"
+)
+
+// Gap-filling modes for buckets without issues
+const (
+	FillNone     = ""
+	FillZero     = "zero"
+	FillPrevious = "previous"
+	FillNull     = "null"
+)
+
+// TimeSeriesOptions configures TimeSeriesQuery
+type TimeSeriesOptions struct {
+	Bucket        string         // day, week or month
+	GroupBy       string         // optional dimension producing one series per value
+	RollingDays   []int          // trailing windows for rolling averages, e.g. 7 and 30
+	PercentChange bool           // period-over-period change per metric
+	Fill          string         // gap filling: zero, previous or null (default: no filling)
+	From, To      time.Time      // interval to fill; defaults to the range of the data
+	Location      *time.Location // timezone of bucket boundaries (UTC when nil)
+	Calendar      *Calendar      // adds count_normalized next to the raw count when set
+}
+
+// TimeSeriesPoint is one bucket of a series
+type TimeSeriesPoint struct {
+	Bucket        time.Time          `json:"bucket"`
+	Values        map[string]float64 `json:"values"`
+	Rolling       map[string]float64 `json:"rolling,omitempty"`
+	PercentChange map[string]float64 `json:"percent_change,omitempty"`
+}
+
+// TimeSeries is the series of one group
+type TimeSeries struct {
+	Group  string            `json:"group,omitempty"`
+	Points []TimeSeriesPoint `json:"points"`
+}
+
+// TimeSeriesResult holds all series of a query
+type TimeSeriesResult struct {
+	Bucket string       `json:"bucket"`
+	Series []TimeSeries `json:"series"`
+}
+
+// TimeSeriesQuery buckets issues by creation time and computes per-bucket
+// metrics, optional rolling averages and period-over-period change.
+//
+// Rolling averages cover the trailing buckets spanning the window (a 7-day
+// window is 7 daily buckets or 1 weekly bucket); buckets without issues
+// count as zero issues and are skipped for the average score.
+// Percent change compares each metric with the immediately preceding
+// bucket after gap filling, so a zero-filled bucket counts as zero issues;
+// it is omitted for metrics the preceding bucket has no value or a zero
+// value for.
+func TimeSeriesQuery(issues []model.Issue, opts TimeSeriesOptions) (TimeSeriesResult, error) {
+	if opts.Bucket == "" {
+		opts.Bucket = BucketDay
+	}
+	switch opts.Fill {
+	case FillNone, FillZero, FillPrevious, FillNull:
+	default:
+		return TimeSeriesResult{}, fmt.Errorf("unknown fill mode: %s (expected zero, previous or null)", opts.Fill)
+	}
+	var dimensions []string
+	if opts.GroupBy != "" {
+		dimensions = []string{opts.GroupBy}
+	}
+
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	rows, err := Aggregate(issues, dimensions, opts.Bucket, opts.Location)
+	if err != nil {
+		return TimeSeriesResult{}, err
+	}
+	var profile DayProfile
+	if opts.Calendar != nil {
+		profile = opts.Calendar.Profile(issues)
+	}
+
+	byGroup := make(map[string]map[time.Time]AggregateRow)
+	var first, last time.Time
+	for _, row := range rows {
+		group := ""
+		if len(row.Keys) > 0 {
+			group = row.Keys[0]
+		}
+		if byGroup[group] == nil {
+			byGroup[group] = make(map[time.Time]AggregateRow)
+		}
+		byGroup[group][row.Bucket] = row
+
+		if first.IsZero() || row.Bucket.Before(first) {
+			first = row.Bucket
+		}
+		if row.Bucket.After(last) {
+			last = row.Bucket
+		}
+	}
+
+	// All groups share the same filled interval so series line up
+	if !opts.From.IsZero() {
+		first = BucketStart(opts.From.In(opts.Location), opts.Bucket)
+	}
+	if !opts.To.IsZero() {
+		last = BucketStart(opts.To.In(opts.Location), opts.Bucket)
+	}
+
+	result := TimeSeriesResult{Bucket: opts.Bucket}
+	for group, buckets := range byGroup {
+		series := TimeSeries{Group: group}
+
+		var starts []time.Time
+		if opts.Fill == FillNone {
+			for start := range buckets {
+				starts = append(starts, start)
+			}
+			sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+		} else {
+			for start := first; !start.After(last); start = AddBuckets(start, opts.Bucket, 1) {
+				starts = append(starts, start)
+			}
+		}
+
+		var previous, preceding map[string]float64
+		var precedingStart time.Time
+		for _, start := range starts {
+			point := TimeSeriesPoint{Bucket: start}
+
+			if row, ok := buckets[start]; ok {
+				point.Values = map[string]float64{
+					MetricCount:    float64(row.Count),
+					MetricAvgScore: row.AvgScore,
+				}
+			} else {
+				point.Values = fillValues(opts.Fill, previous)
+			}
+			if opts.Calendar != nil && point.Values != nil {
+				end := AddBuckets(start, opts.Bucket, 1)
+				point.Values[MetricCountNormalized] = opts.Calendar.Normalize(profile, point.Values[MetricCount], start, end)
+			}
+			if point.Values != nil {
+				previous = point.Values
+			}
+
+			for _, days := range opts.RollingDays {
+				if point.Rolling == nil {
+					point.Rolling = make(map[string]float64)
+				}
+				count, avgScore := rollingAverage(buckets, start, opts.Bucket, days)
+				point.Rolling[fmt.Sprintf("%s_%dd", MetricCount, days)] = count
+				if !math.IsNaN(avgScore) {
+					point.Rolling[fmt.Sprintf("%s_%dd", MetricAvgScore, days)] = avgScore
+				}
+			}
+
+			if opts.PercentChange && point.Values != nil && preceding != nil && precedingStart.Equal(AddBuckets(start, opts.Bucket, -1)) {
+				point.PercentChange = percentChanges(preceding, point.Values)
+			}
+			preceding, precedingStart = point.Values, start
+
+			series.Points = append(series.Points, point)
+		}
+
+		result.Series = append(result.Series, series)
+	}
+
+	sort.Slice(result.Series, func(i, j int) bool { return result.Series[i].Group < result.Series[j].Group })
+	return result, nil
+}
+
+// fillValues returns the values for a bucket without issues.
+// Null filling (and previous filling before the first value) yields nil.
+func fillValues(fill string, previous map[string]float64) map[string]float64 {
+	switch fill {
+	case FillZero:
+		return map[string]float64{MetricCount: 0, MetricAvgScore: 0}
+	case FillPrevious:
+		if previous == nil {
+			return nil
+		}
+		values := make(map[string]float64, len(previous))
+		for metric, value := range previous {
+			values[metric] = value
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+// AddBuckets moves a bucket start by n buckets
+func AddBuckets(start time.Time, bucket string, n int) time.Time {
+	switch bucket {
+	case BucketWeek:
+		return start.AddDate(0, 0, 7*n)
+	case BucketMonth:
+		return start.AddDate(0, n, 0)
+	default:
+		return start.AddDate(0, 0, n)
+	}
+}
+
+// bucketsInWindow returns how many buckets a trailing window of days spans
+func bucketsInWindow(bucket string, days int) int {
+	length := 1
+	switch bucket {
+	case BucketWeek:
+		length = 7
+	case BucketMonth:
+		length = 30
+	}
+	n := int(math.Round(float64(days) / float64(length)))
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// rollingAverage averages count and score over the trailing window ending at start.
+// The average score is NaN when no bucket in the window has issues.
+func rollingAverage(buckets map[time.Time]AggregateRow, start time.Time, bucket string, days int) (float64, float64) {
+	n := bucketsInWindow(bucket, days)
+	var count, totalScore float64
+	var scored int
+
+	for i := 0; i < n; i++ {
+		row, ok := buckets[AddBuckets(start, bucket, -i)]
+		if !ok {
+			continue
+		}
+		count += float64(row.Count)
+		totalScore += row.AvgScore
+		scored++
+	}
+
+	avgScore := math.NaN()
+	if scored > 0 {
+		avgScore = totalScore / float64(scored)
+	}
+	return count / float64(n), avgScore
+}
+
+// percentChanges returns the change of each metric from previous to
+// current, or nil when no metric has a nonzero previous value
+func percentChanges(previous, current map[string]float64) map[string]float64 {
+	var changes map[string]float64
+	for metric, value := range current {
+		if before, ok := previous[metric]; ok && before != 0 {
+			if changes == nil {
+				changes = make(map[string]float64)
+			}
+			changes[metric] = percentChange(before, value)
+		}
+	}
+	return changes
+}
+
+func percentChange(previous, current float64) float64 {
+	if previous == 0 {
+		return 0
+	}
+	return (current - previous) / previous * 100
+}
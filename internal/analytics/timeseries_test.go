@@ -0,0 +1,98 @@
+package analytics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// seriesIssues has two issues on May 1st (scores 10 and 20), none on the
+// 2nd and one on the 3rd (score 30)
+func seriesIssues() []model.Issue {
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 12, 0, 0, 0, time.UTC) }
+	return []model.Issue{
+		{Number: 1, CreatedAt: day(1), Score: 10},
+		{Number: 2, CreatedAt: day(1), Score: 20},
+		{Number: 3, CreatedAt: day(3), Score: 30},
+	}
+}
+
+// points runs a daily query and returns the points of its single series
+func points(t *testing.T, opts TimeSeriesOptions) []TimeSeriesPoint {
+	t.Helper()
+	opts.Bucket = BucketDay
+	result, err := TimeSeriesQuery(seriesIssues(), opts)
+	if err != nil {
+		t.Fatalf("Failed to query: %v", err)
+	}
+	if len(result.Series) != 1 {
+		t.Fatalf("Expected one series, got %d", len(result.Series))
+	}
+	return result.Series[0].Points
+}
+
+func TestTimeSeriesFill(t *testing.T) {
+	tests := []struct {
+		fill   string
+		values []map[string]float64
+	}{
+		{FillNone, []map[string]float64{{"count": 2, "avg_score": 15}, {"count": 1, "avg_score": 30}}},
+		{FillZero, []map[string]float64{{"count": 2, "avg_score": 15}, {"count": 0, "avg_score": 0}, {"count": 1, "avg_score": 30}}},
+		{FillPrevious, []map[string]float64{{"count": 2, "avg_score": 15}, {"count": 2, "avg_score": 15}, {"count": 1, "avg_score": 30}}},
+		{FillNull, []map[string]float64{{"count": 2, "avg_score": 15}, nil, {"count": 1, "avg_score": 30}}},
+	}
+	for _, test := range tests {
+		var values []map[string]float64
+		for _, point := range points(t, TimeSeriesOptions{Fill: test.fill}) {
+			values = append(values, point.Values)
+		}
+		if !reflect.DeepEqual(values, test.values) {
+			t.Errorf("Expected fill %q to give %v, got %v", test.fill, test.values, values)
+		}
+	}
+
+	if _, err := TimeSeriesQuery(seriesIssues(), TimeSeriesOptions{Fill: "linear"}); err == nil {
+		t.Error("Expected an unknown fill mode to be rejected")
+	}
+}
+
+func TestTimeSeriesPercentChange(t *testing.T) {
+	tests := []struct {
+		fill    string
+		changes []map[string]float64
+	}{
+		// May 1st and 3rd are not adjacent
+		{FillNone, []map[string]float64{nil, nil}},
+		// Nothing is compared with the zero-filled 2nd
+		{FillZero, []map[string]float64{nil, {"count": -100, "avg_score": -100}, nil}},
+		{FillPrevious, []map[string]float64{nil, {"count": 0, "avg_score": 0}, {"count": -50, "avg_score": 100}}},
+		{FillNull, []map[string]float64{nil, nil, nil}},
+	}
+	for _, test := range tests {
+		var changes []map[string]float64
+		for _, point := range points(t, TimeSeriesOptions{Fill: test.fill, PercentChange: true}) {
+			changes = append(changes, point.PercentChange)
+		}
+		if !reflect.DeepEqual(changes, test.changes) {
+			t.Errorf("Expected fill %q to give percent changes %v, got %v", test.fill, test.changes, changes)
+		}
+	}
+}
+
+func TestTimeSeriesRolling(t *testing.T) {
+	expected := []map[string]float64{
+		{"count_2d": 1, "avg_score_2d": 15},
+		{"count_2d": 1, "avg_score_2d": 15},
+		// The 2nd has no issues: it counts as zero and is skipped for the score
+		{"count_2d": 0.5, "avg_score_2d": 30},
+	}
+	var rolling []map[string]float64
+	for _, point := range points(t, TimeSeriesOptions{Fill: FillZero, RollingDays: []int{2}}) {
+		rolling = append(rolling, point.Rolling)
+	}
+	if !reflect.DeepEqual(rolling, expected) {
+		t.Errorf("Expected 2-day rolling averages %v, got %v", expected, rolling)
+	}
+}
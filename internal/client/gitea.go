@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// giteaPageSize is the page size requested from Gitea; servers may cap it lower
+const giteaPageSize = 50
+
+// GiteaClient is a minimal client for the Gitea/Forgejo issues API (v1)
+type GiteaClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// GiteaIssue is the subset of a Gitea/Forgejo issue the scraper uses
+type GiteaIssue struct {
+	ID        int64        `json:"id"`
+	Number    int          `json:"number"`
+	Title     string       `json:"title"`
+	Body      string       `json:"body"`
+	State     string       `json:"state"`
+	HTMLURL   string       `json:"html_url"`
+	Labels    []GiteaLabel `json:"labels"`
+	Comments  int          `json:"comments"`
+	User      GiteaUser    `json:"user"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// GiteaLabel is a Gitea/Forgejo label
+type GiteaLabel struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Color       string `json:"color"`
+}
+
+// GiteaUser is a Gitea/Forgejo user reference
+type GiteaUser struct {
+	Login string `json:"login"`
+}
+
+// NewGiteaClient creates a client for a Gitea or Forgejo instance sending its
+// requests through transport (see NewPoliteTransport)
+func NewGiteaClient(baseURL, token string, transport http.RoundTripper) *GiteaClient {
+	return &GiteaClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Transport: transport},
+	}
+}
+
+// GetIssues retrieves up to maxIssues issues (no pull requests) of a repository
+func (c *GiteaClient) GetIssues(ctx context.Context, owner, repo string, state string, maxIssues int) ([]GiteaIssue, error) {
+	var allIssues []GiteaIssue
+
+	for page := 1; ; page++ {
+		query := url.Values{
+			"state": {state},
+			"type":  {"issues"},
+			"limit": {strconv.Itoa(giteaPageSize)},
+			"page":  {strconv.Itoa(page)},
+		}
+		endpoint := fmt.Sprintf("%s/api/v1/repos/%s/%s/issues?%s", c.baseURL, url.PathEscape(owner), url.PathEscape(repo), query.Encode())
+
+		var issues []GiteaIssue
+		if err := c.get(ctx, endpoint, &issues); err != nil {
+			return nil, fmt.Errorf("failed to fetch issues: %w", err)
+		}
+
+		allIssues = append(allIssues, issues...)
+		if len(issues) == 0 || len(allIssues) >= maxIssues {
+			break
+		}
+	}
+
+	log.Printf("Retrieved %d issues from %s/%s (%s)", len(allIssues), owner, repo, c.baseURL)
+	return allIssues, nil
+}
+
+// get decodes a JSON response into v
+func (c *GiteaClient) get(ctx context.Context, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "token "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", endpoint, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -2,34 +2,105 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"sync/atomic"
 	"time"
 	
 	"github.com/google/go-github/v67/github"
 )
 
+// ErrTimelineTooLong is returned when an issue timeline exceeds the requested event budget
+var ErrTimelineTooLong = errors.New("timeline exceeds event budget")
+
+// ErrNotModified is returned by conditional requests when the resource is
+// unchanged since the given ETag
+var ErrNotModified = errors.New("not modified")
+
+// ErrIssueGone is returned when an issue was deleted upstream or is no longer accessible
+var ErrIssueGone = errors.New("issue deleted or inaccessible")
+
 // GitHubClient wraps the GitHub API client
 type GitHubClient struct {
-	client *github.Client
-	token  string
+	client   *github.Client
+	token    string
+	requests *attributedTransport
+}
+
+// AttributionConfig identifies the tool and its operator in GitHub
+// requests, as GitHub's API guidelines ask of integrations
+type AttributionConfig struct {
+	UserAgent string `yaml:"user_agent"`
+	Contact   string `yaml:"contact"`
+}
+
+// attributedTransport adds the attribution headers and counts requests
+type attributedTransport struct {
+	base    http.RoundTripper
+	contact string
+	count   atomic.Int64
+}
+
+func (t *attributedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.count.Add(1)
+	if t.contact != "" {
+		// RoundTrippers must not modify the caller's request
+		req = req.Clone(req.Context())
+		req.Header.Set("From", t.contact)
+	}
+	return t.base.RoundTrip(req)
 }
 
-// NewGitHubClient creates a new GitHub API client
-func NewGitHubClient(token string) *GitHubClient {
-	client := github.NewClient(nil)
+// NewGitHubClient creates a new GitHub API client sending its requests
+// through transport (see NewPoliteTransport), pausing and retrying them
+// around the token's rate limits as configured by politeness
+func NewGitHubClient(token string, attribution AttributionConfig, politeness PolitenessConfig, transport http.RoundTripper) *GitHubClient {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	requests := &attributedTransport{base: newRateLimitTransport(transport, politeness), contact: attribution.Contact}
+	client := github.NewClient(&http.Client{Transport: requests})
+	if attribution.UserAgent != "" {
+		client.UserAgent = attribution.UserAgent
+	}
 	if token != "" {
-		client = github.NewClient(nil).WithAuthToken(token)
+		client = client.WithAuthToken(token)
 	}
 	
 	return &GitHubClient{
-		client: client,
-		token:  token,
+		client:   client,
+		token:    token,
+		requests: requests,
+	}
+}
+
+// Requests returns the number of requests sent so far
+func (c *GitHubClient) Requests() int {
+	return int(c.requests.count.Load())
+}
+
+// TokenLabel identifies the client's token in logs without revealing it
+func (c *GitHubClient) TokenLabel() string {
+	if len(c.token) < 8 {
+		if c.token == "" {
+			return "anonymous"
+		}
+		return "token"
 	}
+	return "…" + c.token[len(c.token)-4:]
 }
 
 // GetIssues retrieves issues from a repository
 func (c *GitHubClient) GetIssues(ctx context.Context, owner, repo string, state string, maxIssues int) ([]*github.Issue, error) {
+	return c.GetIssuesSince(ctx, owner, repo, state, maxIssues, time.Time{})
+}
+
+// GetIssuesSince retrieves up to maxIssues issues updated at or after since,
+// most recently updated first; a zero since retrieves all issues and a zero
+// maxIssues every page
+func (c *GitHubClient) GetIssuesSince(ctx context.Context, owner, repo string, state string, maxIssues int, since time.Time) ([]*github.Issue, error) {
 	var allIssues []*github.Issue
 	page := 1
 	perPage := 100
@@ -39,6 +110,7 @@ func (c *GitHubClient) GetIssues(ctx context.Context, owner, repo string, state
 			State:       state,
 			Sort:        "updated",
 			Direction:   "desc",
+			Since:       since,
 			ListOptions: github.ListOptions{Page: page, PerPage: perPage},
 		})
 		
@@ -52,7 +124,7 @@ func (c *GitHubClient) GetIssues(ctx context.Context, owner, repo string, state
 		
 		allIssues = append(allIssues, issues...)
 		
-		if len(allIssues) >= maxIssues {
+		if maxIssues > 0 && len(allIssues) >= maxIssues {
 			break
 		}
 		
@@ -61,25 +133,114 @@ func (c *GitHubClient) GetIssues(ctx context.Context, owner, repo string, state
 		}
 		
 		page = resp.NextPage
-		
-		// Rate limiting
-		time.Sleep(100 * time.Millisecond)
 	}
 	
 	log.Printf("Retrieved %d issues from %s/%s", len(allIssues), owner, repo)
 	return allIssues, nil
 }
 
-// GetIssueComments retrieves comments for an issue
-func (c *GitHubClient) GetIssueComments(ctx context.Context, owner, repo string, issueNumber int) ([]*github.IssueComment, error) {
-	comments, _, err := c.client.Issues.ListComments(ctx, owner, repo, issueNumber, &github.IssueListCommentsOptions{
-		ListOptions: github.ListOptions{PerPage: 100},
-	})
+// SampleIssues retrieves about n issues in the given state spread across the
+// repository's history. Instead of paging through everything, it reads a few
+// evenly spaced pages of the issue list (newest first) and takes evenly spaced
+// issues from each, so both recent and old issues are represented.
+func (c *GitHubClient) SampleIssues(ctx context.Context, owner, repo string, state string, n int) ([]*github.Issue, error) {
+	const perPage = 100
+	const maxStrata = 10
+	
+	listPage := func(page int) ([]*github.Issue, *github.Response, error) {
+		issues, resp, err := c.client.Issues.ListByRepo(ctx, owner, repo, &github.IssueListByRepoOptions{
+			State:       state,
+			Sort:        "created",
+			Direction:   "desc",
+			ListOptions: github.ListOptions{Page: page, PerPage: perPage},
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch issues: %w", err)
+		}
+		return issues, resp, nil
+	}
+	
+	first, resp, err := listPage(1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch comments for issue %d: %w", issueNumber, err)
+		return nil, err
+	}
+	
+	lastPage := resp.LastPage
+	if lastPage == 0 {
+		lastPage = 1
 	}
 	
-	return comments, nil
+	strata := (n + perPage - 1) / perPage
+	if strata < maxStrata {
+		strata = maxStrata
+	}
+	if strata > lastPage {
+		strata = lastPage
+	}
+	
+	var sampled []*github.Issue
+	for i := 0; i < strata; i++ {
+		// Spread the remaining quota over the remaining strata
+		quota := (n - len(sampled)) / (strata - i)
+		
+		page := 1
+		if strata > 1 {
+			page = 1 + i*(lastPage-1)/(strata-1)
+		}
+		
+		issues := first
+		if page != 1 {
+			if issues, _, err = listPage(page); err != nil {
+				return nil, err
+			}
+		}
+		
+		sampled = append(sampled, spread(issues, quota)...)
+	}
+	
+	log.Printf("Sampled %d %s issues from %s/%s (%d pages)", len(sampled), state, owner, repo, strata)
+	return sampled, nil
+}
+
+// spread picks up to n evenly spaced issues
+func spread(issues []*github.Issue, n int) []*github.Issue {
+	if n >= len(issues) {
+		return issues
+	}
+	
+	picked := make([]*github.Issue, 0, n)
+	for i := 0; i < n; i++ {
+		picked = append(picked, issues[i*len(issues)/n])
+	}
+	return picked
+}
+
+// GetIssueComments retrieves up to maxComments comments for an issue (0 = all)
+func (c *GitHubClient) GetIssueComments(ctx context.Context, owner, repo string, issueNumber int, maxComments int) ([]*github.IssueComment, error) {
+	var allComments []*github.IssueComment
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	
+	for {
+		comments, resp, err := c.client.Issues.ListComments(ctx, owner, repo, issueNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch comments for issue %d: %w", issueNumber, err)
+		}
+		
+		allComments = append(allComments, comments...)
+		
+		if maxComments > 0 && len(allComments) >= maxComments {
+			return allComments[:maxComments], nil
+		}
+		
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	
+	return allComments, nil
 }
 
 // GetIssueReactions retrieves reactions for an issue
@@ -92,6 +253,127 @@ func (c *GitHubClient) GetIssueReactions(ctx context.Context, owner, repo string
 	return reactions, nil
 }
 
+// GetIssueTimeline retrieves timeline events for an issue. With maxEvents > 0,
+// fetching stops with ErrTimelineTooLong once the timeline exceeds maxEvents.
+func (c *GitHubClient) GetIssueTimeline(ctx context.Context, owner, repo string, issueNumber int, maxEvents int) ([]*github.Timeline, error) {
+	var allEvents []*github.Timeline
+	opts := &github.ListOptions{PerPage: 100}
+	
+	for {
+		events, resp, err := c.client.Issues.ListIssueTimeline(ctx, owner, repo, issueNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch timeline for issue %d: %w", issueNumber, err)
+		}
+		
+		allEvents = append(allEvents, events...)
+		
+		if maxEvents > 0 && len(allEvents) > maxEvents {
+			return nil, fmt.Errorf("timeline for issue %d: %w", issueNumber, ErrTimelineTooLong)
+		}
+		
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	
+	return allEvents, nil
+}
+
+// GetClosedPullRequests retrieves up to maxPulls closed (including merged)
+// pull requests updated at or after since, most recently updated first
+func (c *GitHubClient) GetClosedPullRequests(ctx context.Context, owner, repo string, maxPulls int, since time.Time) ([]*github.PullRequest, error) {
+	var allPulls []*github.PullRequest
+	opts := &github.PullRequestListOptions{
+		State:       "closed",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	
+	for {
+		pulls, resp, err := c.client.PullRequests.List(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch pull requests: %w", err)
+		}
+		
+		for _, pull := range pulls {
+			if pull.GetUpdatedAt().Before(since) {
+				return allPulls, nil
+			}
+			allPulls = append(allPulls, pull)
+			if len(allPulls) >= maxPulls {
+				return allPulls, nil
+			}
+		}
+		
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	
+	return allPulls, nil
+}
+
+// GetReviewComments retrieves up to maxComments review comments on the diff
+// of a pull request (0 = all)
+func (c *GitHubClient) GetReviewComments(ctx context.Context, owner, repo string, number int, maxComments int) ([]*github.PullRequestComment, error) {
+	var allComments []*github.PullRequestComment
+	opts := &github.PullRequestListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	
+	for {
+		comments, resp, err := c.client.PullRequests.ListComments(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch review comments for pull request %d: %w", number, err)
+		}
+		
+		allComments = append(allComments, comments...)
+		
+		if maxComments > 0 && len(allComments) >= maxComments {
+			return allComments[:maxComments], nil
+		}
+		
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	
+	return allComments, nil
+}
+
+// GetIssue retrieves a single issue. GitHub redirects requests for a
+// transferred issue, so it is returned from its new repository; a deleted
+// issue yields ErrIssueGone.
+func (c *GitHubClient) GetIssue(ctx context.Context, owner, repo string, issueNumber int) (*github.Issue, error) {
+	issue, resp, err := c.client.Issues.Get(ctx, owner, repo, issueNumber)
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone) {
+			return nil, fmt.Errorf("issue %d: %w", issueNumber, ErrIssueGone)
+		}
+		return nil, fmt.Errorf("failed to fetch issue %d: %w", issueNumber, err)
+	}
+	
+	return issue, nil
+}
+
+// CountIssues returns the number of issues (not pull requests) in the given
+// state, using the search API's total count
+func (c *GitHubClient) CountIssues(ctx context.Context, owner, repo string, state string) (int, error) {
+	query := fmt.Sprintf("repo:%s/%s is:issue is:%s", owner, repo, state)
+	result, _, err := c.client.Search.Issues(ctx, query, &github.SearchOptions{
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count %s issues: %w", state, err)
+	}
+	
+	return result.GetTotal(), nil
+}
+
 // GetRepoInfo retrieves repository information
 func (c *GitHubClient) GetRepoInfo(ctx context.Context, owner, repo string) (*github.Repository, error) {
 	repoInfo, _, err := c.client.Repositories.Get(ctx, owner, repo)
@@ -100,4 +382,29 @@ func (c *GitHubClient) GetRepoInfo(ctx context.Context, owner, repo string) (*gi
 	}
 	
 	return repoInfo, nil
+}
+
+// GetRepoInfoIfChanged fetches repository info with a conditional request,
+// returning ErrNotModified when it is unchanged since etag. Unchanged
+// answers do not count against the rate limit. The returned ETag is for
+// the next call.
+func (c *GitHubClient) GetRepoInfoIfChanged(ctx context.Context, owner, repo, etag string) (*github.Repository, string, error) {
+	req, err := c.client.NewRequest(http.MethodGet, fmt.Sprintf("repos/%s/%s", owner, repo), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	
+	repoInfo := new(github.Repository)
+	resp, err := c.client.Do(ctx, req, repoInfo)
+	if resp != nil && resp.StatusCode == http.StatusNotModified {
+		return nil, etag, ErrNotModified
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch repository info: %w", err)
+	}
+	
+	return repoInfo, resp.Header.Get("ETag"), nil
 }
\ No newline at end of file
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultGitLabURL is the API host used when a GitLab repository has no base URL
+const DefaultGitLabURL = "https://gitlab.com"
+
+// GitLabClient is a minimal client for the GitLab issues API (v4)
+type GitLabClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// GitLabIssue is the subset of a GitLab issue the scraper uses
+type GitLabIssue struct {
+	ID             int64       `json:"id"`
+	IID            int         `json:"iid"`
+	Title          string      `json:"title"`
+	Description    string      `json:"description"`
+	State          string      `json:"state"`
+	WebURL         string      `json:"web_url"`
+	Labels         []string    `json:"labels"`
+	UserNotesCount int         `json:"user_notes_count"`
+	Upvotes        int         `json:"upvotes"`
+	Downvotes      int         `json:"downvotes"`
+	Author         GitLabUser  `json:"author"`
+	ClosedBy       *GitLabUser `json:"closed_by"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+// GitLabUser is a GitLab user reference
+type GitLabUser struct {
+	Username string `json:"username"`
+}
+
+// NewGitLabClient creates a client for gitlab.com or a self-hosted instance
+// sending its requests through transport (see NewPoliteTransport)
+func NewGitLabClient(baseURL, token string, transport http.RoundTripper) *GitLabClient {
+	if baseURL == "" {
+		baseURL = DefaultGitLabURL
+	}
+
+	return &GitLabClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Transport: transport},
+	}
+}
+
+// GetIssues retrieves up to maxIssues issues of a project (group/project path),
+// most recently updated first
+func (c *GitLabClient) GetIssues(ctx context.Context, project string, state string, maxIssues int) ([]GitLabIssue, error) {
+	var allIssues []GitLabIssue
+	page := "1"
+
+	for page != "" {
+		query := url.Values{
+			"state":    {state},
+			"order_by": {"updated_at"},
+			"sort":     {"desc"},
+			"per_page": {"100"},
+			"page":     {page},
+		}
+		endpoint := fmt.Sprintf("%s/api/v4/projects/%s/issues?%s", c.baseURL, url.PathEscape(project), query.Encode())
+
+		var issues []GitLabIssue
+		next, err := c.get(ctx, endpoint, &issues)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch issues: %w", err)
+		}
+
+		allIssues = append(allIssues, issues...)
+		if len(issues) == 0 || len(allIssues) >= maxIssues {
+			break
+		}
+		page = next
+	}
+
+	log.Printf("Retrieved %d issues from %s (%s)", len(allIssues), project, c.baseURL)
+	return allIssues, nil
+}
+
+// get decodes a JSON response into v and returns the next page number, if any
+func (c *GitLabClient) get(ctx context.Context, endpoint string, v interface{}) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s: %s", endpoint, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	next := resp.Header.Get("X-Next-Page")
+	if _, err := strconv.Atoi(next); err != nil {
+		next = ""
+	}
+	return next, nil
+}
@@ -0,0 +1,213 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// nvdAPI is the NVD CVE API endpoint
+const nvdAPI = "https://services.nvd.nist.gov/rest/json/cves/2.0"
+
+// NVD allows 5 requests per 30 seconds without an API key and 50 with one
+const (
+	nvdInterval        = 6 * time.Second
+	nvdIntervalWithKey = 600 * time.Millisecond
+)
+
+// NVDClient is a minimal client for the NVD CVE API
+type NVDClient struct {
+	key  string
+	http *http.Client
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// nvdResponse is the subset of an NVD CVE API response the scraper uses
+type nvdResponse struct {
+	Vulnerabilities []struct {
+		CVE struct {
+			ID           string `json:"id"`
+			Published    string `json:"published"`
+			Descriptions []struct {
+				Lang  string `json:"lang"`
+				Value string `json:"value"`
+			} `json:"descriptions"`
+			Metrics struct {
+				V31 []nvdMetric `json:"cvssMetricV31"`
+				V30 []nvdMetric `json:"cvssMetricV30"`
+				V2  []nvdMetric `json:"cvssMetricV2"`
+			} `json:"metrics"`
+			Configurations []struct {
+				Nodes []struct {
+					CPEMatch []struct {
+						Vulnerable            bool   `json:"vulnerable"`
+						Criteria              string `json:"criteria"`
+						VersionStartIncluding string `json:"versionStartIncluding"`
+						VersionStartExcluding string `json:"versionStartExcluding"`
+						VersionEndIncluding   string `json:"versionEndIncluding"`
+						VersionEndExcluding   string `json:"versionEndExcluding"`
+					} `json:"cpeMatch"`
+				} `json:"nodes"`
+			} `json:"configurations"`
+		} `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+// nvdMetric is a CVSS metric; CVSS v2 reports the severity outside cvssData
+type nvdMetric struct {
+	CVSSData struct {
+		BaseScore    float64 `json:"baseScore"`
+		BaseSeverity string  `json:"baseSeverity"`
+	} `json:"cvssData"`
+	BaseSeverity string `json:"baseSeverity"`
+}
+
+// NewNVDClient creates a client; key is an optional NVD API key raising the
+// rate limit
+func NewNVDClient(key string, transport http.RoundTripper) *NVDClient {
+	return &NVDClient{
+		key:  key,
+		http: &http.Client{Transport: transport},
+	}
+}
+
+// GetCVE fetches the metadata of a CVE. A CVE unknown to NVD is returned
+// with NotFound set.
+func (c *NVDClient) GetCVE(ctx context.Context, id string) (model.CVE, error) {
+	if err := c.wait(ctx); err != nil {
+		return model.CVE{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, nvdAPI+"?"+url.Values{"cveId": {id}}.Encode(), nil)
+	if err != nil {
+		return model.CVE{}, err
+	}
+	if c.key != "" {
+		req.Header.Set("apiKey", c.key)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return model.CVE{}, fmt.Errorf("failed to fetch %s: %w", id, err)
+	}
+	defer resp.Body.Close()
+
+	cve := model.CVE{ID: id, FetchedAt: time.Now()}
+	if resp.StatusCode == http.StatusNotFound {
+		cve.NotFound = true
+		return cve, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return model.CVE{}, fmt.Errorf("GET %s?cveId=%s: %s", nvdAPI, id, resp.Status)
+	}
+
+	var result nvdResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return model.CVE{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(result.Vulnerabilities) == 0 {
+		cve.NotFound = true
+		return cve, nil
+	}
+
+	data := result.Vulnerabilities[0].CVE
+	cve.Published = data.Published
+	for _, description := range data.Descriptions {
+		if description.Lang == "en" {
+			cve.Description = description.Value
+			break
+		}
+	}
+
+	// Prefer the newest CVSS version available
+	for _, metrics := range [][]nvdMetric{data.Metrics.V31, data.Metrics.V30, data.Metrics.V2} {
+		if len(metrics) == 0 {
+			continue
+		}
+		cve.Score = metrics[0].CVSSData.BaseScore
+		cve.Severity = strings.ToLower(metrics[0].CVSSData.BaseSeverity)
+		if cve.Severity == "" {
+			cve.Severity = strings.ToLower(metrics[0].BaseSeverity)
+		}
+		break
+	}
+
+	products := make(map[string]bool)
+	for _, configuration := range data.Configurations {
+		for _, node := range configuration.Nodes {
+			for _, match := range node.CPEMatch {
+				if !match.Vulnerable {
+					continue
+				}
+				// cpe:2.3:part:vendor:product:version:...
+				parts := strings.Split(match.Criteria, ":")
+				if len(parts) < 6 {
+					continue
+				}
+				product := parts[3] + "/" + parts[4]
+				if !products[product] {
+					products[product] = true
+					cve.Products = append(cve.Products, product)
+				}
+				if versions := versionRange(parts[5], match.VersionStartIncluding, match.VersionStartExcluding, match.VersionEndIncluding, match.VersionEndExcluding); versions != "" {
+					cve.AffectedVersions = append(cve.AffectedVersions, product+" "+versions)
+				}
+			}
+		}
+	}
+	return cve, nil
+}
+
+// versionRange formats an affected version range, e.g. ">=1.2.0 <1.4.1"
+func versionRange(exact, startIncluding, startExcluding, endIncluding, endExcluding string) string {
+	var bounds []string
+	if startIncluding != "" {
+		bounds = append(bounds, ">="+startIncluding)
+	}
+	if startExcluding != "" {
+		bounds = append(bounds, ">"+startExcluding)
+	}
+	if endIncluding != "" {
+		bounds = append(bounds, "<="+endIncluding)
+	}
+	if endExcluding != "" {
+		bounds = append(bounds, "<"+endExcluding)
+	}
+	if len(bounds) == 0 && exact != "*" && exact != "-" {
+		return exact
+	}
+	return strings.Join(bounds, " ")
+}
+
+// wait spaces requests to stay within the NVD rate limit
+func (c *NVDClient) wait(ctx context.Context) error {
+	interval := nvdInterval
+	if c.key != "" {
+		interval = nvdIntervalWithKey
+	}
+
+	c.mu.Lock()
+	next := c.last.Add(interval)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	c.last = next
+	c.mu.Unlock()
+
+	select {
+	case <-time.After(time.Until(next)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,277 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PolitenessConfig controls request pacing towards API hosts
+type PolitenessConfig struct {
+	MinIntervalMs     int `yaml:"min_interval_ms"`
+	MaxConcurrency    int `yaml:"max_concurrency"`
+	RequestsPerHour   int `yaml:"requests_per_hour"`
+	MaxBackoffSeconds int `yaml:"max_backoff_seconds"`
+	// GitHub only: retries of rate-limited responses, and the remaining
+	// requests at which to pause until the rate limit resets
+	MaxRetries       int `yaml:"max_retries"`
+	RateLimitReserve int `yaml:"rate_limit_reserve"`
+}
+
+// pacer is an http.RoundTripper that paces requests: at most MaxConcurrency
+// in flight and one every MinIntervalMs per host, no more than
+// RequestsPerHour in total, and an adaptive slow-down for hosts that answer
+// 403/429.
+type pacer struct {
+	base       http.RoundTripper
+	interval   time.Duration
+	maxBackoff time.Duration
+	slots      int
+	perHour    int
+
+	mu       sync.Mutex
+	hosts    map[string]*hostPace
+	sent     []time.Time
+	requests map[string]int
+}
+
+// hostPace is the pacing state of a single host
+type hostPace struct {
+	slots   chan struct{}
+	next    time.Time
+	backoff time.Duration
+	stats   PoolStats
+}
+
+// PoolStats describes how the requests to a host used its MaxConcurrency
+// slots: Waits counts requests that found every slot busy, WaitTime and
+// MaxWait their time waiting for one. Paced is the time spent waiting for
+// the host interval, backoff and hourly ceiling once a slot was free.
+type PoolStats struct {
+	Slots    int
+	Requests int
+	Waits    int
+	WaitTime time.Duration
+	MaxWait  time.Duration
+	Paced    time.Duration
+}
+
+// NewPoliteTransport returns an http.RoundTripper applying the politeness
+// controls. Clients sharing the transport share its hourly ceiling.
+func NewPoliteTransport(config PolitenessConfig) http.RoundTripper {
+	return newPacer(http.DefaultTransport, config)
+}
+
+// newPacer wraps base with the configured politeness controls
+func newPacer(base http.RoundTripper, config PolitenessConfig) *pacer {
+	slots := config.MaxConcurrency
+	if slots < 1 {
+		slots = 1
+	}
+	maxBackoff := time.Duration(config.MaxBackoffSeconds) * time.Second
+	if maxBackoff <= 0 {
+		maxBackoff = 5 * time.Minute
+	}
+
+	return &pacer{
+		base:       base,
+		interval:   time.Duration(config.MinIntervalMs) * time.Millisecond,
+		maxBackoff: maxBackoff,
+		slots:      slots,
+		perHour:    config.RequestsPerHour,
+		hosts:      make(map[string]*hostPace),
+		requests:   make(map[string]int),
+	}
+}
+
+// RoundTrip waits for a concurrency slot and the host's next send time, then
+// sends the request and adapts the host's pace to the response
+func (p *pacer) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := p.host(req.URL.Host)
+	ctx := req.Context()
+
+	select {
+	case host.slots <- struct{}{}:
+	default:
+		start := time.Now()
+		select {
+		case host.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		p.recordWait(host, time.Since(start))
+	}
+	defer func() { <-host.slots }()
+
+	if wait := time.Until(p.reserve(host)); wait > 0 {
+		p.recordPaced(host, wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+
+	p.count(req.URL.Host)
+	resp, err := p.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	p.adapt(host, resp)
+	return resp, nil
+}
+
+// RequestCounts returns the number of requests sent per host through a
+// transport created by NewPoliteTransport, or nil for other transports
+func RequestCounts(transport http.RoundTripper) map[string]int {
+	p, ok := transport.(*pacer)
+	if !ok {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	counts := make(map[string]int, len(p.requests))
+	for host, n := range p.requests {
+		counts[host] = n
+	}
+	return counts
+}
+
+// HostPoolStats returns the slot usage per host of a transport created by
+// NewPoliteTransport, or nil for other transports
+func HostPoolStats(transport http.RoundTripper) map[string]PoolStats {
+	p, ok := transport.(*pacer)
+	if !ok {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make(map[string]PoolStats, len(p.hosts))
+	for name, h := range p.hosts {
+		hostStats := h.stats
+		hostStats.Slots = p.slots
+		hostStats.Requests = p.requests[name]
+		stats[name] = hostStats
+	}
+	return stats
+}
+
+// count records a request sent to a host
+func (p *pacer) count(host string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests[host]++
+}
+
+// recordWait records a request's wait for a concurrency slot
+func (p *pacer) recordWait(h *hostPace, wait time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h.stats.Waits++
+	h.stats.WaitTime += wait
+	h.stats.MaxWait = max(h.stats.MaxWait, wait)
+}
+
+// recordPaced records a request's wait for its send time
+func (p *pacer) recordPaced(h *hostPace, wait time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h.stats.Paced += wait
+}
+
+// host returns the pacing state for a host, creating it on first use
+func (p *pacer) host(name string) *hostPace {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	h, ok := p.hosts[name]
+	if !ok {
+		h = &hostPace{slots: make(chan struct{}, p.slots)}
+		p.hosts[name] = h
+	}
+	return h
+}
+
+// reserve books the next send time for a host, honoring the host interval,
+// its current backoff and the global hourly ceiling
+func (p *pacer) reserve(h *hostPace) time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	at := now
+	if h.next.After(at) {
+		at = h.next
+	}
+
+	if p.perHour > 0 {
+		// Drop sends that left the one-hour window
+		cutoff := now.Add(-time.Hour)
+		i := 0
+		for i < len(p.sent) && p.sent[i].Before(cutoff) {
+			i++
+		}
+		p.sent = p.sent[i:]
+
+		if len(p.sent) >= p.perHour {
+			if free := p.sent[len(p.sent)-p.perHour].Add(time.Hour); free.After(at) {
+				at = free
+			}
+		}
+		p.sent = append(p.sent, at)
+	}
+
+	h.next = at.Add(p.interval + h.backoff)
+	return at
+}
+
+// adapt doubles a host's backoff when it signals rate limiting and halves it
+// again on success. A Retry-After header postpones the host's next request.
+func (p *pacer) adapt(h *hostPace, resp *http.Response) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !isRateLimited(resp) {
+		h.backoff /= 2
+		if h.backoff < time.Second {
+			h.backoff = 0
+		}
+		return
+	}
+
+	h.backoff *= 2
+	if h.backoff < time.Second {
+		h.backoff = time.Second
+	}
+	if h.backoff > p.maxBackoff {
+		h.backoff = p.maxBackoff
+	}
+
+	next := time.Now().Add(h.backoff)
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		if retry := time.Now().Add(time.Duration(seconds) * time.Second); retry.After(next) {
+			next = retry
+		}
+	}
+	if next.After(h.next) {
+		h.next = next
+	}
+}
+
+// isRateLimited reports whether a response asks the client to slow down.
+// GitHub answers secondary rate limits with 403, so a 403 only counts when
+// it carries rate-limit headers.
+func isRateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0"
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// githubDomains are the hosts GitHub API calls and their redirects use
+var githubDomains = []string{"github.com", "githubusercontent.com"}
+
+// IsGitHubHost reports whether host belongs to GitHub
+func IsGitHubHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, domain := range githubDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// githubOnly is an http.RoundTripper refusing requests to non-GitHub hosts
+type githubOnly struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends GitHub requests and refuses all others before any
+// connection is made
+func (g githubOnly) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !IsGitHubHost(req.URL.Hostname()) {
+		return nil, fmt.Errorf("privacy: strict mode blocks request to %s", req.URL.Hostname())
+	}
+	return g.base.RoundTrip(req)
+}
+
+// RestrictToGitHub makes http.DefaultTransport refuse every request to a
+// host other than GitHub, and returns a function restoring the previous
+// transport. The program's HTTP clients all go through the default
+// transport, so this enforces strict privacy for them in one place; it
+// must run before any client is created. Integrations that do not use
+// HTTP, such as sftp export destinations running the sftp binary, are not
+// covered and are rejected by Config.PrivacyViolations instead.
+func RestrictToGitHub() (restore func()) {
+	previous := http.DefaultTransport
+	if _, ok := previous.(githubOnly); !ok {
+		http.DefaultTransport = githubOnly{base: previous}
+	}
+	return func() { http.DefaultTransport = previous }
+}
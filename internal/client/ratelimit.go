@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimitTransport keeps GitHub requests within the token's rate limits.
+// Once the remaining requests of a rate-limit resource (core, search,
+// graphql) drop to the reserve, requests for it pause until the limit
+// resets; rate-limited (403/429) responses are retried with exponential
+// backoff and jitter instead of failing.
+type rateLimitTransport struct {
+	base       http.RoundTripper
+	reserve    int
+	maxRetries int
+	maxBackoff time.Duration
+
+	mu     sync.Mutex
+	paused map[string]time.Time
+}
+
+// newRateLimitTransport wraps base with rate-limit awareness configured by
+// the politeness settings
+func newRateLimitTransport(base http.RoundTripper, config PolitenessConfig) *rateLimitTransport {
+	maxBackoff := time.Duration(config.MaxBackoffSeconds) * time.Second
+	if maxBackoff <= 0 {
+		maxBackoff = 5 * time.Minute
+	}
+	return &rateLimitTransport{
+		base:       base,
+		reserve:    config.RateLimitReserve,
+		maxRetries: config.MaxRetries,
+		maxBackoff: maxBackoff,
+		paused:     make(map[string]time.Time),
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	resource := rateLimitResource(req.URL.Path)
+
+	for attempt := 0; ; attempt++ {
+		if err := sleepContext(ctx, time.Until(t.pausedUntil(resource))); err != nil {
+			return nil, err
+		}
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		t.observe(resource, resp)
+
+		// Requests whose body cannot be sent again are not retried
+		if !isRateLimited(resp) || attempt >= t.maxRetries || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+		wait := t.retryDelay(resp, attempt)
+		log.Printf("GitHub rate limit hit for %s %s, retrying in %s (%d/%d)", req.Method, req.URL.Path, wait.Round(time.Second), attempt+1, t.maxRetries)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// observe pauses a resource until its reset when the response shows its
+// remaining requests at or below the reserve
+func (t *rateLimitTransport) observe(resource string, resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining > t.reserve {
+		return
+	}
+	reset, ok := rateLimitReset(resp)
+	if !ok || !reset.After(time.Now()) {
+		return
+	}
+	if name := resp.Header.Get("X-RateLimit-Resource"); name != "" {
+		resource = name
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if reset.After(t.paused[resource]) {
+		t.paused[resource] = reset
+		log.Printf("GitHub rate limit nearly exhausted (%d %s requests left), pausing until %s", remaining, resource, reset.Format("15:04:05"))
+	}
+}
+
+// pausedUntil returns when requests for a resource may be sent again
+func (t *rateLimitTransport) pausedUntil(resource string) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.paused[resource]
+}
+
+// retryDelay returns how long to wait before retrying a rate-limited
+// response: exponential backoff with jitter, but no earlier than its
+// Retry-After or, with an exhausted limit, the reset
+func (t *rateLimitTransport) retryDelay(resp *http.Response, attempt int) time.Duration {
+	backoff := min(time.Second<<attempt, t.maxBackoff)
+	delay := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		delay = max(delay, time.Duration(seconds)*time.Second)
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, ok := rateLimitReset(resp); ok {
+			delay = max(delay, time.Until(reset))
+		}
+	}
+	return delay
+}
+
+// rateLimitReset reads the X-RateLimit-Reset header, in Unix seconds
+func rateLimitReset(resp *http.Response) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+// rateLimitResource guesses the rate-limit resource of a request before
+// its response names it
+func rateLimitResource(path string) string {
+	switch {
+	case strings.HasPrefix(path, "/search/"):
+		return "search"
+	case strings.HasPrefix(path, "/graphql"):
+		return "graphql"
+	default:
+		return "core"
+	}
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
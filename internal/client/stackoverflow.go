@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// stackExchangeAPI is the Stack Exchange API endpoint for questions
+const stackExchangeAPI = "https://api.stackexchange.com/2.3/questions"
+
+// StackOverflowClient is a minimal client for Stack Overflow questions
+type StackOverflowClient struct {
+	key  string
+	http *http.Client
+}
+
+// StackOverflowQuestion is the subset of a question the scraper uses
+type StackOverflowQuestion struct {
+	QuestionID       int                `json:"question_id"`
+	Title            string             `json:"title"`
+	Body             string             `json:"body"`
+	Link             string             `json:"link"`
+	Score            int                `json:"score"`
+	AnswerCount      int                `json:"answer_count"`
+	AcceptedAnswerID int                `json:"accepted_answer_id"`
+	Tags             []string           `json:"tags"`
+	Owner            StackOverflowOwner `json:"owner"`
+	CreationDate     int64              `json:"creation_date"`
+	LastActivityDate int64              `json:"last_activity_date"`
+}
+
+// StackOverflowOwner is the author of a question
+type StackOverflowOwner struct {
+	DisplayName string `json:"display_name"`
+}
+
+// stackExchangeResponse is the common Stack Exchange response wrapper
+type stackExchangeResponse struct {
+	Items   []StackOverflowQuestion `json:"items"`
+	HasMore bool                    `json:"has_more"`
+	Backoff int                     `json:"backoff"`
+}
+
+// NewStackOverflowClient creates a client; key is an optional Stack Exchange
+// API key raising the daily quota
+func NewStackOverflowClient(key string, transport http.RoundTripper) *StackOverflowClient {
+	return &StackOverflowClient{
+		key:  key,
+		http: &http.Client{Transport: transport},
+	}
+}
+
+// GetTopQuestions retrieves up to maxQuestions of the highest-voted questions for a tag
+func (c *StackOverflowClient) GetTopQuestions(ctx context.Context, tag string, maxQuestions int) ([]StackOverflowQuestion, error) {
+	var allQuestions []StackOverflowQuestion
+
+	for page := 1; ; page++ {
+		query := url.Values{
+			"site":     {"stackoverflow"},
+			"tagged":   {tag},
+			"sort":     {"votes"},
+			"order":    {"desc"},
+			"filter":   {"withbody"},
+			"pagesize": {"100"},
+			"page":     {strconv.Itoa(page)},
+		}
+		if c.key != "" {
+			query.Set("key", c.key)
+		}
+
+		var result stackExchangeResponse
+		if err := c.get(ctx, stackExchangeAPI+"?"+query.Encode(), &result); err != nil {
+			return nil, fmt.Errorf("failed to fetch questions for tag %s: %w", tag, err)
+		}
+
+		allQuestions = append(allQuestions, result.Items...)
+		if !result.HasMore || len(allQuestions) >= maxQuestions {
+			break
+		}
+
+		// The API asks clients to wait before the next request of the same kind
+		if result.Backoff > 0 {
+			select {
+			case <-time.After(time.Duration(result.Backoff) * time.Second):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	if maxQuestions > 0 && len(allQuestions) > maxQuestions {
+		allQuestions = allQuestions[:maxQuestions]
+	}
+
+	log.Printf("Retrieved %d questions tagged %s from Stack Overflow", len(allQuestions), tag)
+	return allQuestions, nil
+}
+
+// get decodes a JSON response into v
+func (c *StackOverflowClient) get(ctx context.Context, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", stackExchangeAPI, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Config lists the destinations finished exports and reports are uploaded
+// to, and the declarative feeds built from the stored issues
+type Config struct {
+	Destinations []string     `yaml:"destinations"`
+	Retries      int          `yaml:"retries"`
+	Feeds        []FeedConfig `yaml:"feeds"`
+}
+
+// Destination uploads a local file under a slash-separated name and returns
+// the URL it can be fetched from
+type Destination interface {
+	Upload(ctx context.Context, localPath, name string) (string, error)
+}
+
+// Result records the outcome of uploading one file to one destination
+type Result struct {
+	File        string    `json:"file"`
+	Destination string    `json:"destination"`
+	URL         string    `json:"url,omitempty"`
+	Attempts    int       `json:"attempts"`
+	Error       string    `json:"error,omitempty"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+}
+
+// Supported destination URL schemes
+const (
+	SchemeS3          = "s3"
+	SchemeGCS         = "gs"
+	SchemeSFTP        = "sftp"
+	SchemeWebDAV      = "webdav+http"
+	SchemeWebDAVHTTPS = "webdav+https"
+)
+
+// ValidSchemes lists the accepted destination URL schemes
+var ValidSchemes = []string{SchemeS3, SchemeGCS, SchemeSFTP, SchemeWebDAV, SchemeWebDAVHTTPS}
+
+// NewDestination parses a destination URL such as s3://bucket/prefix,
+// gs://bucket/prefix, sftp://user@host/dir or webdav+https://host/dir.
+// Credentials are read from the environment.
+func NewDestination(rawURL string) (Destination, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination %q: %w", rawURL, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("destination %q has no bucket or host", rawURL)
+	}
+	prefix := strings.Trim(u.Path, "/")
+
+	switch u.Scheme {
+	case SchemeS3:
+		return newS3Destination(u.Host, prefix), nil
+	case SchemeGCS:
+		return newGCSDestination(u.Host, prefix), nil
+	case SchemeSFTP:
+		return newSFTPDestination(u), nil
+	case SchemeWebDAV, SchemeWebDAVHTTPS:
+		return newWebDAVDestination(u), nil
+	default:
+		return nil, fmt.Errorf("destination %q: scheme must be one of: %v", rawURL, ValidSchemes)
+	}
+}
+
+// Uploader uploads files to every configured destination
+type Uploader struct {
+	destinations map[string]Destination
+	order        []string
+	retries      int
+}
+
+// NewUploader creates an uploader for the configured destinations
+func NewUploader(config Config) (*Uploader, error) {
+	uploader := &Uploader{
+		destinations: make(map[string]Destination),
+		retries:      config.Retries,
+	}
+	for _, rawURL := range config.Destinations {
+		destination, err := NewDestination(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		uploader.destinations[rawURL] = destination
+		uploader.order = append(uploader.order, rawURL)
+	}
+	return uploader, nil
+}
+
+// UploadAll uploads files, named by their path relative to baseDir, to every
+// destination. Failed uploads are retried with exponential backoff; the
+// result of every file and destination is returned.
+func (u *Uploader) UploadAll(ctx context.Context, baseDir string, files []string) []Result {
+	var results []Result
+	for _, rawURL := range u.order {
+		for _, file := range files {
+			name, err := filepath.Rel(baseDir, file)
+			if err != nil {
+				name = filepath.Base(file)
+			}
+			results = append(results, u.upload(ctx, rawURL, file, filepath.ToSlash(name)))
+		}
+	}
+	return results
+}
+
+func (u *Uploader) upload(ctx context.Context, rawURL, file, name string) Result {
+	result := Result{File: file, Destination: rawURL}
+	backoff := time.Second
+
+	for result.Attempts = 1; ; result.Attempts++ {
+		uploaded, err := u.destinations[rawURL].Upload(ctx, file, name)
+		if err == nil {
+			result.URL = uploaded
+			result.Error = ""
+			result.UploadedAt = time.Now()
+			return result
+		}
+		result.Error = err.Error()
+
+		if result.Attempts > u.retries {
+			return result
+		}
+		log.Printf("Upload of %s to %s failed (attempt %d): %v", name, rawURL, result.Attempts, err)
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			result.Error = ctx.Err().Error()
+			return result
+		}
+	}
+}
+
+// objectName joins a destination prefix and a file name
+func objectName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return path.Join(prefix, name)
+}
@@ -0,0 +1,357 @@
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/query"
+)
+
+// Feed formats
+const (
+	FeedCSV    = "csv"
+	FeedJSON   = "json"
+	FeedNDJSON = "ndjson"
+)
+
+// FeedFormats lists the accepted feed formats
+var FeedFormats = []string{FeedCSV, FeedJSON, FeedNDJSON}
+
+// DefaultFeedColumns are the columns of a feed without a select stage
+var DefaultFeedColumns = []string{"repository", "number", "title", "url", "state", "category", "priority", "score", "author", "created_at"}
+
+// FeedConfig declares a downstream feed: the issues matching Filter go
+// through the stages in order, are written to Output in Format and
+// uploaded to Destinations
+type FeedConfig struct {
+	Name         string        `yaml:"name"`
+	Filter       string        `yaml:"filter"` // search query syntax, empty for all issues
+	Stages       []StageConfig `yaml:"stages"`
+	Format       string        `yaml:"format"`
+	Output       string        `yaml:"output"`
+	Destinations []string      `yaml:"destinations"`
+}
+
+// StageConfig is one transform stage of a feed; exactly one field is set
+type StageConfig struct {
+	Select      []string          `yaml:"select"`       // keep these columns, in this order
+	Rename      map[string]string `yaml:"rename"`       // column -> new name
+	MaskAuthors bool              `yaml:"mask_authors"` // replace authors with stable pseudonyms
+	Limit       int               `yaml:"limit"`        // keep the first rows
+}
+
+// Table is the data flowing through a feed's stages
+type Table struct {
+	Columns []string
+	Rows    []map[string]interface{}
+}
+
+// project keeps the table's columns of a row
+func (t Table) project(row map[string]interface{}) map[string]interface{} {
+	projected := make(map[string]interface{}, len(t.Columns))
+	for _, column := range t.Columns {
+		projected[column] = row[column]
+	}
+	return projected
+}
+
+// stage transforms a table
+type stage func(Table) (Table, error)
+
+// Feed is a configured feed ready to run
+type Feed struct {
+	config FeedConfig
+	search query.AdvancedSearch
+	stages []stage
+}
+
+// NewFeed validates a feed configuration and builds its stages
+func NewFeed(config FeedConfig) (*Feed, error) {
+	if config.Name == "" {
+		return nil, fmt.Errorf("feed without name")
+	}
+	feed := &Feed{config: config}
+
+	search, err := query.Parse(config.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("feed %s: invalid filter: %w", config.Name, err)
+	}
+	feed.search = search
+
+	for i, stageConfig := range config.Stages {
+		s, err := newStage(stageConfig)
+		if err != nil {
+			return nil, fmt.Errorf("feed %s: stage %d: %w", config.Name, i+1, err)
+		}
+		feed.stages = append(feed.stages, s)
+	}
+
+	if !containsString(FeedFormats, config.Format) {
+		return nil, fmt.Errorf("feed %s: format must be one of: %v", config.Name, FeedFormats)
+	}
+	if config.Output == "" {
+		return nil, fmt.Errorf("feed %s: output is required", config.Name)
+	}
+	for _, destination := range config.Destinations {
+		if _, err := NewDestination(destination); err != nil {
+			return nil, fmt.Errorf("feed %s: %w", config.Name, err)
+		}
+	}
+	return feed, nil
+}
+
+// Name returns the feed name
+func (f *Feed) Name() string {
+	return f.config.Name
+}
+
+// Build selects the matching issues, oldest repository and number first,
+// and runs them through the stages
+func (f *Feed) Build(issues map[string][]model.Issue) (Table, error) {
+	matched := f.search.Filter(issues)
+	sort.Slice(matched, func(i, j int) bool {
+		if matched[i].Repository != matched[j].Repository {
+			return matched[i].Repository < matched[j].Repository
+		}
+		return matched[i].Number < matched[j].Number
+	})
+
+	table, err := issueTable(matched)
+	if err != nil {
+		return Table{}, err
+	}
+	for _, s := range f.stages {
+		if table, err = s(table); err != nil {
+			return Table{}, fmt.Errorf("feed %s: %w", f.config.Name, err)
+		}
+	}
+	return table, nil
+}
+
+// Run builds the feed, writes it to its output file and uploads it to its
+// destinations, retrying failed uploads retries times
+func (f *Feed) Run(ctx context.Context, issues map[string][]model.Issue, retries int) (int, []Result, error) {
+	table, err := f.Build(issues)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.config.Output), 0755); err != nil {
+		return 0, nil, err
+	}
+	file, err := os.Create(f.config.Output)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := WriteTable(file, table, f.config.Format); err != nil {
+		file.Close()
+		return 0, nil, err
+	}
+	if err := file.Close(); err != nil {
+		return 0, nil, err
+	}
+
+	if len(f.config.Destinations) == 0 {
+		return len(table.Rows), nil, nil
+	}
+	uploader, err := NewUploader(Config{Destinations: f.config.Destinations, Retries: retries})
+	if err != nil {
+		return 0, nil, err
+	}
+	dir := filepath.Dir(f.config.Output)
+	return len(table.Rows), uploader.UploadAll(ctx, dir, []string{f.config.Output}), nil
+}
+
+// WriteTable writes a table as CSV, a JSON array or NDJSON
+func WriteTable(w io.Writer, table Table, format string) error {
+	switch format {
+	case FeedCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(table.Columns); err != nil {
+			return err
+		}
+		for _, row := range table.Rows {
+			record := make([]string, len(table.Columns))
+			for i, column := range table.Columns {
+				record[i] = cellString(row[column])
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case FeedJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		rows := make([]map[string]interface{}, len(table.Rows))
+		for i, row := range table.Rows {
+			rows[i] = table.project(row)
+		}
+		return encoder.Encode(rows)
+	case FeedNDJSON:
+		encoder := json.NewEncoder(w)
+		for _, row := range table.Rows {
+			if err := encoder.Encode(table.project(row)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown feed format %q", format)
+	}
+}
+
+// newStage builds the transform of a stage configuration
+func newStage(config StageConfig) (stage, error) {
+	set := 0
+	for _, isSet := range []bool{len(config.Select) > 0, len(config.Rename) > 0, config.MaskAuthors, config.Limit != 0} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("exactly one of select, rename, mask_authors or limit must be set")
+	}
+
+	switch {
+	case len(config.Select) > 0:
+		return selectColumns(config.Select), nil
+	case len(config.Rename) > 0:
+		return renameColumns(config.Rename), nil
+	case config.MaskAuthors:
+		return maskAuthors, nil
+	default:
+		if config.Limit < 0 {
+			return nil, fmt.Errorf("limit must not be negative")
+		}
+		return limitRows(config.Limit), nil
+	}
+}
+
+// issueTable turns issues into rows keyed by their JSON field names
+func issueTable(issues []model.Issue) (Table, error) {
+	table := Table{Columns: append([]string(nil), DefaultFeedColumns...)}
+	for _, issue := range issues {
+		data, err := json.Marshal(issue)
+		if err != nil {
+			return Table{}, err
+		}
+		row := make(map[string]interface{})
+		if err := json.Unmarshal(data, &row); err != nil {
+			return Table{}, err
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table, nil
+}
+
+// selectColumns keeps the given columns; unknown columns stay empty
+func selectColumns(columns []string) stage {
+	return func(table Table) (Table, error) {
+		for i, row := range table.Rows {
+			selected := make(map[string]interface{}, len(columns))
+			for _, column := range columns {
+				if value, ok := row[column]; ok {
+					selected[column] = value
+				}
+			}
+			table.Rows[i] = selected
+		}
+		table.Columns = columns
+		return table, nil
+	}
+}
+
+// renameColumns renames columns, keeping their position
+func renameColumns(names map[string]string) stage {
+	return func(table Table) (Table, error) {
+		columns := make([]string, len(table.Columns))
+		for i, column := range table.Columns {
+			columns[i] = column
+			if name, ok := names[column]; ok {
+				columns[i] = name
+			}
+		}
+		for old := range names {
+			if !containsString(table.Columns, old) {
+				return Table{}, fmt.Errorf("rename: unknown column %q", old)
+			}
+		}
+		for _, row := range table.Rows {
+			values := make(map[string]interface{}, len(names))
+			for old := range names {
+				if value, ok := row[old]; ok {
+					values[old] = value
+					delete(row, old)
+				}
+			}
+			for old, value := range values {
+				row[names[old]] = value
+			}
+		}
+		table.Columns = columns
+		return table, nil
+	}
+}
+
+// maskAuthors replaces author names with pseudonyms that are stable across
+// feeds, so rows of one author can still be grouped
+func maskAuthors(table Table) (Table, error) {
+	for _, row := range table.Rows {
+		if author, ok := row["author"].(string); ok && author != "" {
+			sum := sha256.Sum256([]byte(author))
+			row["author"] = "user-" + hex.EncodeToString(sum[:4])
+		}
+	}
+	return table, nil
+}
+
+// limitRows keeps the first n rows
+func limitRows(n int) stage {
+	return func(table Table) (Table, error) {
+		if len(table.Rows) > n {
+			table.Rows = table.Rows[:n]
+		}
+		return table, nil
+	}
+}
+
+// cellString formats a row value for CSV
+func cellString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// gcsUploadAPI is the Cloud Storage JSON API media upload endpoint
+const gcsUploadAPI = "https://storage.googleapis.com/upload/storage/v1/b/"
+
+// gcsDestination uploads to a Cloud Storage bucket with the OAuth access
+// token in GCS_ACCESS_TOKEN (e.g. from `gcloud auth print-access-token`)
+type gcsDestination struct {
+	bucket string
+	prefix string
+	http   *http.Client
+}
+
+func newGCSDestination(bucket, prefix string) gcsDestination {
+	return gcsDestination{
+		bucket: bucket,
+		prefix: prefix,
+		http:   &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+func (d gcsDestination) Upload(ctx context.Context, localPath, name string) (string, error) {
+	token := os.Getenv("GCS_ACCESS_TOKEN")
+	if token == "" {
+		return "", fmt.Errorf("GCS_ACCESS_TOKEN must be set")
+	}
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	object := objectName(d.prefix, name)
+	endpoint := gcsUploadAPI + url.PathEscape(d.bucket) + "/o?" + url.Values{
+		"uploadType": {"media"},
+		"name":       {object},
+	}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, file)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("upload %s: %s %s", object, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return fmt.Sprintf("gs://%s/%s", d.bucket, object), nil
+}
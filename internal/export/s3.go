@@ -0,0 +1,132 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// s3Destination uploads to an S3 bucket (or an S3-compatible endpoint set in
+// AWS_ENDPOINT_URL) using the AWS_* credentials from the environment
+type s3Destination struct {
+	bucket string
+	prefix string
+	region string
+	http   *http.Client
+}
+
+func newS3Destination(bucket, prefix string) s3Destination {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+	return s3Destination{
+		bucket: bucket,
+		prefix: prefix,
+		region: region,
+		http:   &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+func (d s3Destination) Upload(ctx context.Context, localPath, name string) (string, error) {
+	accessKey, secretKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return "", fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return "", err
+	}
+
+	key := objectName(d.prefix, name)
+	endpoint := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", d.bucket, d.region, escapePath(key))
+	if custom := strings.TrimRight(os.Getenv("AWS_ENDPOINT_URL"), "/"); custom != "" {
+		endpoint = fmt.Sprintf("%s/%s/%s", custom, d.bucket, escapePath(key))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	signV4(req, data, d.region, accessKey, secretKey, os.Getenv("AWS_SESSION_TOKEN"), time.Now().UTC())
+
+	resp, err := d.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("PUT %s: %s %s", key, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return fmt.Sprintf("s3://%s/%s", d.bucket, key), nil
+}
+
+// signV4 signs an S3 request with AWS Signature Version 4
+func signV4(req *http.Request, payload []byte, region, accessKey, secretKey, sessionToken string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n", req.URL.Host, payloadHash, amzDate)
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+		signedHeaders += ";x-amz-security-token"
+		canonicalHeaders += "x-amz-security-token:" + sessionToken + "\n"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, region)
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath escapes each segment of a slash-separated object key
+func escapePath(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,75 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// sftpDestination uploads with the system sftp client in batch mode, so
+// authentication must not prompt: use an SSH agent or the key file in
+// SFTP_IDENTITY_FILE
+type sftpDestination struct {
+	target string
+	port   string
+	dir    string
+}
+
+func newSFTPDestination(u *url.URL) sftpDestination {
+	target := u.Hostname()
+	if u.User != nil {
+		target = u.User.Username() + "@" + target
+	}
+	return sftpDestination{
+		target: target,
+		port:   u.Port(),
+		dir:    u.Path,
+	}
+}
+
+func (d sftpDestination) Upload(ctx context.Context, localPath, name string) (string, error) {
+	remote := path.Join(d.dir, name)
+	if d.dir == "" {
+		remote = name
+	}
+
+	// A leading "-" lets sftp continue when the directory already exists
+	var batch strings.Builder
+	for _, dir := range parentDirs(remote) {
+		fmt.Fprintf(&batch, "-mkdir %s\n", quoteSFTP(dir))
+	}
+	fmt.Fprintf(&batch, "put %s %s\n", quoteSFTP(localPath), quoteSFTP(remote))
+
+	args := []string{"-b", "-", "-o", "BatchMode=yes"}
+	if d.port != "" {
+		args = append(args, "-P", d.port)
+	}
+	if identity := os.Getenv("SFTP_IDENTITY_FILE"); identity != "" {
+		args = append(args, "-i", identity)
+	}
+	args = append(args, d.target)
+
+	cmd := exec.CommandContext(ctx, "sftp", args...)
+	cmd.Stdin = strings.NewReader(batch.String())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("sftp put %s: %w: %s", remote, err, strings.TrimSpace(stderr.String()))
+	}
+
+	host := d.target
+	if d.port != "" {
+		host += ":" + d.port
+	}
+	return fmt.Sprintf("sftp://%s/%s", host, strings.TrimPrefix(remote, "/")), nil
+}
+
+// quoteSFTP quotes a path for an sftp batch command
+func quoteSFTP(p string) string {
+	return `"` + strings.ReplaceAll(p, `"`, `\"`) + `"`
+}
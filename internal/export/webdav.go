@@ -0,0 +1,84 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// webdavDestination uploads with HTTP PUT to a WebDAV collection, using the
+// WEBDAV_USERNAME and WEBDAV_PASSWORD credentials when set
+type webdavDestination struct {
+	base *url.URL
+	http *http.Client
+}
+
+func newWebDAVDestination(u *url.URL) webdavDestination {
+	base := *u
+	base.Scheme = strings.TrimPrefix(u.Scheme, "webdav+")
+	base.Path = strings.TrimRight(u.Path, "/")
+	return webdavDestination{
+		base: &base,
+		http: &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+func (d webdavDestination) Upload(ctx context.Context, localPath, name string) (string, error) {
+	// Create missing parent collections; existing ones answer 405
+	for _, dir := range parentDirs(name) {
+		resp, err := d.do(ctx, "MKCOL", dir+"/", nil)
+		if err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+	}
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	resp, err := d.do(ctx, http.MethodPut, name, file)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("PUT %s: %s", name, resp.Status)
+	}
+	return d.url(name), nil
+}
+
+func (d webdavDestination) do(ctx context.Context, method, name string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, d.url(name), body)
+	if err != nil {
+		return nil, err
+	}
+	if username := os.Getenv("WEBDAV_USERNAME"); username != "" {
+		req.SetBasicAuth(username, os.Getenv("WEBDAV_PASSWORD"))
+	}
+	return d.http.Do(req)
+}
+
+func (d webdavDestination) url(name string) string {
+	target := *d.base
+	target.Path = d.base.Path + "/" + name
+	return target.String()
+}
+
+// parentDirs returns the parent directories of a slash-separated name, outermost first
+func parentDirs(name string) []string {
+	var dirs []string
+	for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		dirs = append([]string{dir}, dirs...)
+	}
+	return dirs
+}
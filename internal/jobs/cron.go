@@ -0,0 +1,147 @@
+package jobs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros are the shorthand schedules accepted by ParseCron
+var cronMacros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// cronFieldBounds are the value ranges of the five cron fields
+var cronFieldBounds = [5][2]int{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 6},  // day of week, Sunday = 0 (7 is accepted as Sunday too)
+}
+
+// Cron is a parsed five-field cron schedule: minute, hour, day of month,
+// month and day of week
+type Cron struct {
+	spec   string
+	fields [5]map[int]bool
+	// When both days are restricted, either may match as in cron(8)
+	anyDay bool
+}
+
+// ParseCron parses a schedule such as "0 */6 * * *". Fields accept *,
+// values, ranges (1-5), steps (*/15, 0-30/10) and lists (1,15); the
+// @hourly, @daily, @weekly and @monthly shorthands are accepted too.
+func ParseCron(spec string) (Cron, error) {
+	expanded := strings.TrimSpace(spec)
+	if macro, ok := cronMacros[expanded]; ok {
+		expanded = macro
+	}
+	parts := strings.Fields(expanded)
+	if len(parts) != 5 {
+		return Cron{}, fmt.Errorf("cron schedule %q must have 5 fields (minute hour day month weekday)", spec)
+	}
+
+	cron := Cron{spec: spec}
+	for i, part := range parts {
+		values, err := parseCronField(part, cronFieldBounds[i][0], cronFieldBounds[i][1], i == 4)
+		if err != nil {
+			return Cron{}, fmt.Errorf("cron schedule %q: %w", spec, err)
+		}
+		cron.fields[i] = values
+	}
+	cron.anyDay = parts[2] != "*" && parts[4] != "*"
+	return cron, nil
+}
+
+// parseCronField expands one field into the set of values it matches
+func parseCronField(field string, low, high int, weekday bool) (map[int]bool, error) {
+	values := make(map[int]bool)
+	for _, item := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step in %q", item)
+			}
+		}
+
+		start, end := low, high
+		if rangePart != "*" {
+			first, last, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = strconv.Atoi(first); err != nil {
+				return nil, fmt.Errorf("invalid value in %q", item)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(last); err != nil {
+					return nil, fmt.Errorf("invalid range in %q", item)
+				}
+			} else if hasStep {
+				end = high
+			}
+		}
+		if weekday && start == 7 && end == 7 {
+			start, end = 0, 0
+		}
+		if weekday && end == 7 {
+			end = 6
+			values[0] = true
+		}
+		if start < low || end > high || start > end {
+			return nil, fmt.Errorf("%q is out of range %d-%d", item, low, high)
+		}
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+// String returns the schedule as written
+func (c Cron) String() string {
+	return c.spec
+}
+
+// Matches reports whether the schedule fires in the minute of t
+func (c Cron) Matches(t time.Time) bool {
+	return c.fields[0][t.Minute()] && c.fields[1][t.Hour()] && c.fields[3][int(t.Month())] && c.dayMatches(t)
+}
+
+// Next returns the first minute after t, in t's location, at which the
+// schedule fires, or the zero time when it never fires within five years
+// (such as on February 30th)
+func (c Cron) Next(t time.Time) time.Time {
+	next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, t.Location())
+	limit := next.AddDate(5, 0, 0)
+	for next.Before(limit) {
+		switch {
+		case !c.fields[3][int(next.Month())]:
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+		case !c.dayMatches(next):
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+		case !c.fields[1][next.Hour()]:
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+		case !c.fields[0][next.Minute()]:
+			next = next.Add(time.Minute)
+		default:
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches reports whether the schedule fires on t's day
+func (c Cron) dayMatches(t time.Time) bool {
+	dayOfMonth, dayOfWeek := c.fields[2][t.Day()], c.fields[4][int(t.Weekday())]
+	if c.anyDay {
+		return dayOfMonth || dayOfWeek
+	}
+	return dayOfMonth && dayOfWeek
+}
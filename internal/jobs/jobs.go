@@ -0,0 +1,136 @@
+package jobs
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Job statuses
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusFailed    = "failed"
+	StatusDone      = "done"
+	StatusCancelled = "cancelled"
+)
+
+// KindExport regenerates and uploads the reports; every other kind is a
+// pipeline step name
+const KindExport = "export"
+
+// Job is a persisted long-running task. Running jobs update UpdatedAt as they
+// make progress, so a job whose worker died can be detected and re-queued.
+type Job struct {
+	ID              int       `json:"id"`
+	Kind            string    `json:"kind"`
+	Tier            string    `json:"tier,omitempty"`
+	Status          string    `json:"status"`
+	Progress        int       `json:"progress"`
+	Total           int       `json:"total"`
+	Owner           string    `json:"owner,omitempty"`
+	Attempts        int       `json:"attempts"`
+	CancelRequested bool      `json:"cancel_requested,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	Note            string    `json:"note,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+	StartedAt       time.Time `json:"started_at,omitempty"`
+	FinishedAt      time.Time `json:"finished_at,omitempty"`
+}
+
+// Finished reports whether the job has reached a final status
+func (j Job) Finished() bool {
+	return j.Status == StatusDone || j.Status == StatusFailed || j.Status == StatusCancelled
+}
+
+// Owner identifies the current process as host:pid
+func Owner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// Enqueue appends a queued job and returns the updated list and the new job.
+// An empty tier selects the kind's default tier.
+func Enqueue(jobs []Job, kind, tier string, now time.Time) ([]Job, Job) {
+	if tier == "" {
+		tier = DefaultTier(kind)
+	}
+
+	id := 1
+	for _, job := range jobs {
+		if job.ID >= id {
+			id = job.ID + 1
+		}
+	}
+
+	job := Job{ID: id, Kind: kind, Tier: tier, Status: StatusQueued, CreatedAt: now, UpdatedAt: now}
+	return append(jobs, job), job
+}
+
+// Find returns the index of the job with the given ID, or -1
+func Find(jobs []Job, id int) int {
+	for i, job := range jobs {
+		if job.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Cancel cancels a queued job immediately and asks a running job to stop
+// after its current unit of work
+func Cancel(job *Job, now time.Time) error {
+	switch job.Status {
+	case StatusQueued:
+		job.Status = StatusCancelled
+		job.FinishedAt = now
+	case StatusRunning:
+		job.CancelRequested = true
+	default:
+		return fmt.Errorf("job %d is already %s", job.ID, job.Status)
+	}
+	job.UpdatedAt = now
+	return nil
+}
+
+// Retry queues a failed or cancelled job again
+func Retry(job *Job, now time.Time) error {
+	if job.Status != StatusFailed && job.Status != StatusCancelled {
+		return fmt.Errorf("job %d is %s; only failed or cancelled jobs can be retried", job.ID, job.Status)
+	}
+	job.Status = StatusQueued
+	job.Progress = 0
+	job.Owner = ""
+	job.Error = ""
+	job.CancelRequested = false
+	job.FinishedAt = time.Time{}
+	job.UpdatedAt = now
+	return nil
+}
+
+// RequeueStale puts running jobs that have not reported progress within
+// staleAfter back in the queue, e.g. after the worker process was restarted,
+// and returns how many were re-queued
+func RequeueStale(jobs []Job, staleAfter time.Duration, now time.Time) int {
+	requeued := 0
+	for i := range jobs {
+		if jobs[i].Status != StatusRunning || now.Sub(jobs[i].UpdatedAt) < staleAfter {
+			continue
+		}
+		if jobs[i].CancelRequested {
+			jobs[i].Status = StatusCancelled
+			jobs[i].FinishedAt = now
+		} else {
+			jobs[i].Status = StatusQueued
+			jobs[i].Progress = 0
+			jobs[i].Owner = ""
+			requeued++
+		}
+		jobs[i].UpdatedAt = now
+	}
+	return requeued
+}
@@ -0,0 +1,60 @@
+package jobs
+
+// Scheduling tiers. Interactive jobs (small queries and exports someone is
+// waiting for) are always started before queued background jobs.
+const (
+	TierInteractive = "interactive"
+	TierBackground  = "background"
+)
+
+// Tiers lists the scheduling tiers, highest priority first
+var Tiers = []string{TierInteractive, TierBackground}
+
+// Config limits how many jobs of each tier run at the same time
+type Config struct {
+	InteractiveConcurrency int `yaml:"interactive_concurrency"`
+	BackgroundConcurrency  int `yaml:"background_concurrency"`
+}
+
+// Limit returns the concurrency limit of a tier
+func (c Config) Limit(tier string) int {
+	if tier == TierInteractive {
+		return c.InteractiveConcurrency
+	}
+	return c.BackgroundConcurrency
+}
+
+// DefaultTier returns the tier a job kind runs in unless one is given:
+// exports are interactive, bulk reprocessing steps are background work
+func DefaultTier(kind string) string {
+	if kind == KindExport {
+		return TierInteractive
+	}
+	return TierBackground
+}
+
+// TierName returns the job's tier, defaulting by kind for jobs queued
+// before tiers were recorded
+func (j Job) TierName() string {
+	if j.Tier == "" {
+		return DefaultTier(j.Kind)
+	}
+	return j.Tier
+}
+
+// Pick returns the index of the next job to start given the number of
+// running jobs per tier, or -1 when nothing can start. Tiers are tried in
+// priority order and jobs within a tier oldest first.
+func Pick(jobs []Job, running map[string]int, config Config) int {
+	for _, tier := range Tiers {
+		if running[tier] >= config.Limit(tier) {
+			continue
+		}
+		for i, job := range jobs {
+			if job.Status == StatusQueued && job.TierName() == tier {
+				return i
+			}
+		}
+	}
+	return -1
+}
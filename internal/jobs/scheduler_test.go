@@ -0,0 +1,68 @@
+package jobs
+
+import "testing"
+
+func TestPick(t *testing.T) {
+	queue := []Job{
+		{ID: 1, Kind: "classify", Tier: TierBackground, Status: StatusDone},
+		{ID: 2, Kind: "classify", Tier: TierBackground, Status: StatusQueued},
+		{ID: 3, Kind: "score", Status: StatusQueued},    // queued before tiers: background
+		{ID: 4, Kind: KindExport, Status: StatusQueued}, // queued before tiers: interactive
+		{ID: 5, Kind: "dedup", Tier: TierInteractive, Status: StatusQueued},
+	}
+	config := Config{InteractiveConcurrency: 1, BackgroundConcurrency: 2}
+
+	tests := []struct {
+		name    string
+		running map[string]int
+		config  Config
+		picked  int // job ID, 0 for none
+	}{
+		{"interactive first, oldest first", nil, config, 4},
+		{"background once interactive is full", map[string]int{TierInteractive: 1}, config, 2},
+		{"background limit", map[string]int{TierInteractive: 1, TierBackground: 2}, config, 0},
+		{"interactive despite full background", map[string]int{TierBackground: 2}, config, 4},
+		{"no slots configured", nil, Config{}, 0},
+	}
+	for _, test := range tests {
+		picked := 0
+		if i := Pick(queue, test.running, test.config); i >= 0 {
+			picked = queue[i].ID
+		}
+		if picked != test.picked {
+			t.Errorf("%s: expected job %d, got %d", test.name, test.picked, picked)
+		}
+	}
+}
+
+// TestPickDrainsByPriority starts jobs until the limits are reached
+func TestPickDrainsByPriority(t *testing.T) {
+	queue := []Job{
+		{ID: 1, Kind: "classify", Status: StatusQueued},
+		{ID: 2, Kind: KindExport, Status: StatusQueued},
+		{ID: 3, Kind: "score", Status: StatusQueued},
+		{ID: 4, Kind: KindExport, Status: StatusQueued},
+		{ID: 5, Kind: "dedup", Status: StatusQueued},
+	}
+	running := make(map[string]int)
+	var order []int
+	for {
+		i := Pick(queue, running, Config{InteractiveConcurrency: 2, BackgroundConcurrency: 1})
+		if i < 0 {
+			break
+		}
+		queue[i].Status = StatusRunning
+		running[queue[i].TierName()]++
+		order = append(order, queue[i].ID)
+	}
+
+	expected := []int{2, 4, 1}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected jobs %v started, got %v", expected, order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected jobs %v started, got %v", expected, order)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+)
+
+// ProtocolVersion is the MCP revision this server implements
+const ProtocolVersion = "2024-11-05"
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool is a function exposed to MCP clients
+type Tool struct {
+	Name        string                                                               `json:"name"`
+	Description string                                                               `json:"description"`
+	InputSchema map[string]interface{}                                               `json:"inputSchema"`
+	Handler     func(ctx context.Context, args json.RawMessage) (interface{}, error) `json:"-"`
+}
+
+// Server is an MCP server speaking newline-delimited JSON-RPC over stdio
+type Server struct {
+	name    string
+	version string
+	tools   []Tool
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// NewServer creates a server exposing tools
+func NewServer(name, version string, tools []Tool) *Server {
+	return &Server{name: name, version: version, tools: tools}
+}
+
+// Serve handles requests from r until it is closed or ctx is cancelled,
+// writing responses to w. Logs must not go to w, which carries the protocol.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+	write := func(resp response) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := encoder.Encode(resp); err != nil {
+			log.Printf("Error writing MCP response: %v", err)
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			write(response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: err.Error()}})
+			continue
+		}
+		// Notifications carry no id and get no response
+		if len(req.ID) == 0 {
+			continue
+		}
+
+		result, rpcErr := s.handle(ctx, req)
+		write(response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr})
+	}
+	return scanner.Err()
+}
+
+// handle dispatches one request
+func (s *Server) handle(ctx context.Context, req request) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": s.name, "version": s.version},
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": s.tools}, nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+}
+
+// callTool runs a tool, reporting tool failures as error results so the
+// client's model can see and react to them
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (interface{}, *rpcError) {
+	var call struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	for _, tool := range s.tools {
+		if tool.Name != call.Name {
+			continue
+		}
+		args := call.Arguments
+		if len(args) == 0 {
+			args = json.RawMessage("{}")
+		}
+
+		result, err := tool.Handler(ctx, args)
+		if err != nil {
+			return map[string]interface{}{
+				"content": []content{{Type: "text", Text: err.Error()}},
+				"isError": true,
+			}, nil
+		}
+		text, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		return map[string]interface{}{"content": []content{{Type: "text", Text: string(text)}}}, nil
+	}
+	return nil, &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf("unknown tool %q", call.Name)}
+}
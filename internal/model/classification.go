@@ -0,0 +1,86 @@
+package model
+
+import "time"
+
+// ClassificationChange records a stored issue's previous category and
+// priority when they change, with the classification versions involved
+type ClassificationChange struct {
+	Repository       string    `json:"repository"`
+	IssueNumber      int       `json:"issue_number"`
+	PreviousCategory string    `json:"previous_category"`
+	PreviousPriority string    `json:"previous_priority,omitempty"`
+	PreviousVersion  string    `json:"previous_version,omitempty"`
+	Category         string    `json:"category"`
+	Priority         string    `json:"priority,omitempty"`
+	Version          string    `json:"version,omitempty"`
+	ChangedAt        time.Time `json:"changed_at"`
+	// Author is set for manual changes
+	Author string `json:"author,omitempty"`
+}
+
+// TaxonomyChange reports whether the change came with new classification
+// rules rather than from the issue itself changing
+func (c ClassificationChange) TaxonomyChange() bool {
+	return c.PreviousVersion != c.Version
+}
+
+// ClassificationChanged compares a previously stored copy of an issue with
+// its new copy and returns the change, if category or priority differ
+func ClassificationChanged(previous, current Issue, now time.Time) (ClassificationChange, bool) {
+	if previous.Category == "" || (previous.Category == current.Category && previous.Priority == current.Priority) {
+		return ClassificationChange{}, false
+	}
+	return ClassificationChange{
+		IssueNumber:      current.Number,
+		PreviousCategory: previous.Category,
+		PreviousPriority: previous.Priority,
+		PreviousVersion:  previous.ClassificationVersion,
+		Category:         current.Category,
+		Priority:         current.Priority,
+		Version:          current.ClassificationVersion,
+		ChangedAt:        now,
+	}, true
+}
+
+// Classification sources
+const (
+	ClassifiedByRules      = "rules"
+	ClassifiedByCorrection = "correction"
+)
+
+// ClassificationResult explains an issue's category: every keyword rule
+// that matched, in evaluation order (the first one wins), or the manual
+// correction that overrides them
+type ClassificationResult struct {
+	Category    string      `json:"category"`
+	Source      string      `json:"source"`
+	Version     string      `json:"version,omitempty"`
+	Matches     []RuleMatch `json:"matches,omitempty"`
+	CorrectedBy string      `json:"corrected_by,omitempty"`
+	// Confidence is the winning rule's share of all matched keywords, 0
+	// when no rule matched; it describes the rules even when corrected
+	Confidence float64 `json:"confidence"`
+}
+
+// RuleCategory returns the category the rules chose, before corrections
+func (r ClassificationResult) RuleCategory() string {
+	if len(r.Matches) == 0 {
+		return "other"
+	}
+	return r.Matches[0].Category
+}
+
+// RuleMatch lists the keywords of one category rule found in an issue
+type RuleMatch struct {
+	Category string   `json:"category"`
+	Keywords []string `json:"keywords"`
+}
+
+// ClassificationRecord is the classification result of one issue in one
+// run, kept so results can be compared across runs
+type ClassificationRecord struct {
+	Repository  string               `json:"repository"`
+	IssueNumber int                  `json:"issue_number"`
+	RunAt       time.Time            `json:"run_at"`
+	Result      ClassificationResult `json:"result"`
+}
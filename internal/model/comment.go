@@ -0,0 +1,31 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Comment is a comment on an issue
+type Comment struct {
+	ID        int64     `json:"id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	URL       string    `json:"url"`
+	Reactions int       `json:"reactions"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IssueComments are the stored comments of one issue
+type IssueComments struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Comments  []Comment `json:"comments"`
+}
+
+// DiscussionText joins the bodies of the comments attached to an issue
+func (i Issue) DiscussionText() string {
+	bodies := make([]string, len(i.Discussion))
+	for j, comment := range i.Discussion {
+		bodies[j] = comment.Body
+	}
+	return strings.Join(bodies, "\n")
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// CategoryCorrection is a manual fix of an issue's category or priority.
+// Corrections override the classification rules and serve as feedback to
+// improve them.
+type CategoryCorrection struct {
+	Repository  string `json:"repository"`
+	IssueNumber int    `json:"issue_number"`
+	// Predicted is the category the rules assigned when the correction was made
+	Predicted string `json:"predicted"`
+	// Category or Priority is empty when only the other one is corrected
+	Category    string    `json:"category,omitempty"`
+	Priority    string    `json:"priority,omitempty"`
+	Author      string    `json:"author,omitempty"`
+	CorrectedAt time.Time `json:"corrected_at"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// EmptyScrape records a repository whose last scrape found no issue
+// matching the keywords and filters it was scraped with
+type EmptyScrape struct {
+	Repository string `json:"repository"`
+	// Filters fingerprints the keywords and filters of the scrape; a
+	// record only applies to scrapes with the same fingerprint
+	Filters   string    `json:"filters"`
+	Keywords  []string  `json:"keywords,omitempty"`
+	MinScore  float64   `json:"min_score"`
+	Scanned   int       `json:"scanned"` // issues fetched, none of which matched
+	ScrapedAt time.Time `json:"scraped_at"`
+}
+
+// Coverage summarizes how many scanned repositories had matching issues
+type Coverage struct {
+	Scanned int           `json:"scanned"`
+	Empty   []EmptyScrape `json:"empty"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// CVE is the NVD metadata of a vulnerability referenced by issues
+type CVE struct {
+	ID               string    `json:"id"`
+	Severity         string    `json:"severity,omitempty"`
+	Score            float64   `json:"score,omitempty"`
+	Description      string    `json:"description,omitempty"`
+	Products         []string  `json:"products,omitempty"`
+	AffectedVersions []string  `json:"affected_versions,omitempty"`
+	Published        string    `json:"published,omitempty"`
+	FetchedAt        time.Time `json:"fetched_at"`
+	NotFound         bool      `json:"not_found,omitempty"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// DecayScore scales a raw score by the time since the issue was last
+// updated, halving it every halfLife. A non-positive halfLife disables decay.
+func DecayScore(raw float64, updatedAt, now time.Time, halfLife time.Duration) float64 {
+	if halfLife <= 0 || updatedAt.IsZero() || !now.After(updatedAt) {
+		return raw
+	}
+	return raw * math.Pow(0.5, float64(now.Sub(updatedAt))/float64(halfLife))
+}
+
+// ApplyScoreDecay recomputes Score from RawScore as of now for all issues in
+// place. Issues stored before raw scores were kept use their score as raw score.
+func ApplyScoreDecay(issues map[string][]Issue, halfLife time.Duration, now time.Time) {
+	for _, repoIssues := range issues {
+		for i := range repoIssues {
+			if repoIssues[i].RawScore == 0 {
+				repoIssues[i].RawScore = repoIssues[i].Score
+			}
+			repoIssues[i].Score = DecayScore(repoIssues[i].RawScore, repoIssues[i].UpdatedAt, now, halfLife)
+		}
+	}
+}
+
+// BaseScore returns Score without the share earned by reporter reputation,
+// so reputation can be derived from scores without feeding on itself
+func (i Issue) BaseScore() float64 {
+	raw := i.RawScore
+	if raw <= 0 {
+		raw = i.Score
+	}
+	if raw <= 0 || i.ReputationScore <= 0 {
+		return i.Score
+	}
+	return i.Score * math.Max(0, raw-i.ReputationScore) / raw
+}
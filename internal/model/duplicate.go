@@ -0,0 +1,217 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DuplicateLink records that an issue duplicates a canonical issue
+type DuplicateLink struct {
+	Repository          string    `json:"repository"`
+	IssueNumber         int       `json:"issue_number"`
+	CanonicalRepository string    `json:"canonical_repository"`
+	CanonicalNumber     int       `json:"canonical_number"`
+	Source              string    `json:"source"`
+	LinkedAt            time.Time `json:"linked_at"`
+}
+
+// Duplicate link sources
+const (
+	// DuplicateSourceUpstream marks links taken from upstream "closed as duplicate" events
+	DuplicateSourceUpstream = "upstream"
+	// DuplicateSourceCrossSource marks similar issues found on different sources
+	DuplicateSourceCrossSource = "cross_source"
+)
+
+// Verdicts of a duplicate review
+const (
+	DuplicateConfirmed = "confirmed"
+	DuplicateRejected  = "rejected"
+)
+
+// DuplicateReview records a reviewer's verdict on a suggested duplicate
+// link. A rejected pair is unlinked and not suggested again.
+type DuplicateReview struct {
+	Repository          string    `json:"repository"`
+	IssueNumber         int       `json:"issue_number"`
+	CanonicalRepository string    `json:"canonical_repository"`
+	CanonicalNumber     int       `json:"canonical_number"`
+	Verdict             string    `json:"verdict"`
+	Reviewer            string    `json:"reviewer,omitempty"`
+	ReviewedAt          time.Time `json:"reviewed_at"`
+}
+
+// Suggested reports whether the link was found by similarity rather than
+// marked upstream or by hand, and so needs review
+func (l DuplicateLink) Suggested() bool {
+	return l.Source == DuplicateSourceCrossSource || l.Source == DuplicateSourceContentHash
+}
+
+// PairKey identifies the linked pair of issues in either direction
+func (l DuplicateLink) PairKey() string {
+	return duplicatePairKey(IssueRef(l.Repository, l.IssueNumber), IssueRef(l.CanonicalRepository, l.CanonicalNumber))
+}
+
+// PairKey identifies the reviewed pair of issues in either direction
+func (r DuplicateReview) PairKey() string {
+	return duplicatePairKey(IssueRef(r.Repository, r.IssueNumber), IssueRef(r.CanonicalRepository, r.CanonicalNumber))
+}
+
+func duplicatePairKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + " " + b
+}
+
+// IssueRef formats an issue reference as owner/repo#number. Repository
+// names are case-insensitive upstream, so references use the lower-case
+// name and can be compared directly.
+func IssueRef(repository string, number int) string {
+	return fmt.Sprintf("%s#%d", strings.ToLower(repository), number)
+}
+
+// ParseIssueRef splits an owner/repo#number reference
+func ParseIssueRef(ref string) (string, int, bool) {
+	repository, num, ok := strings.Cut(ref, "#")
+	if !ok || repository == "" {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(num)
+	if err != nil {
+		return "", 0, false
+	}
+	return repository, number, true
+}
+
+// DuplicateGroups joins linked issues into groups of issue references
+// (owner/repo#number), using only links of the given sources (all links when
+// none are given). Every group has at least two members.
+func DuplicateGroups(links []DuplicateLink, sources ...string) [][]string {
+	parent := make(map[string]string)
+	var find func(ref string) string
+	find = func(ref string) string {
+		if parent[ref] == ref {
+			return ref
+		}
+		root := find(parent[ref])
+		parent[ref] = root
+		return root
+	}
+
+	var order []string
+	for _, link := range links {
+		if len(sources) > 0 && !containsString(sources, link.Source) {
+			continue
+		}
+		a := IssueRef(link.Repository, link.IssueNumber)
+		b := IssueRef(link.CanonicalRepository, link.CanonicalNumber)
+		for _, ref := range []string{a, b} {
+			if _, ok := parent[ref]; !ok {
+				parent[ref] = ref
+				order = append(order, ref)
+			}
+		}
+		parent[find(a)] = find(b)
+	}
+
+	members := make(map[string][]string)
+	var roots []string
+	for _, ref := range order {
+		root := find(ref)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], ref)
+	}
+
+	groups := make([][]string, 0, len(roots))
+	for _, root := range roots {
+		groups = append(groups, members[root])
+	}
+	return groups
+}
+
+// Duplicate handling modes at export time
+const (
+	// DuplicatesInclude keeps every issue
+	DuplicatesInclude = "include"
+	// DuplicatesExclude keeps only the master of each duplicate group
+	DuplicatesExclude = "exclude"
+	// DuplicatesCollapse keeps the master with the group's members listed on it
+	DuplicatesCollapse = "collapse"
+)
+
+// DuplicateModes lists the accepted duplicate handling modes
+var DuplicateModes = []string{DuplicatesInclude, DuplicatesExclude, DuplicatesCollapse}
+
+// ApplyDuplicateMode returns issues with duplicate groups handled per mode.
+// The master of a group is its highest-scoring member present in issues.
+// Collapsing sets MemberCount and DuplicateMembers on the master, counting
+// all linked members like the duplicate groups report does.
+func ApplyDuplicateMode(issues map[string][]Issue, links []DuplicateLink, mode string) map[string][]Issue {
+	if mode == DuplicatesInclude || mode == "" {
+		return issues
+	}
+
+	present := make(map[string]Issue)
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			present[IssueRef(repoName, issue.Number)] = issue
+		}
+	}
+
+	// Members to drop, and the group of every master
+	dropped := make(map[string]bool)
+	groups := make(map[string][]string)
+	for _, group := range DuplicateGroups(links) {
+		master := ""
+		for _, ref := range group {
+			issue, ok := present[ref]
+			if !ok {
+				continue
+			}
+			if master == "" || issue.Score > present[master].Score || (issue.Score == present[master].Score && ref < master) {
+				master = ref
+			}
+		}
+		if master == "" {
+			continue
+		}
+		for _, ref := range group {
+			if ref != master {
+				dropped[ref] = true
+			}
+		}
+		groups[master] = group
+	}
+
+	result := make(map[string][]Issue, len(issues))
+	for repoName, repoIssues := range issues {
+		kept := make([]Issue, 0, len(repoIssues))
+		for _, issue := range repoIssues {
+			ref := IssueRef(repoName, issue.Number)
+			if dropped[ref] {
+				continue
+			}
+			if group, ok := groups[ref]; ok && mode == DuplicatesCollapse {
+				issue.MemberCount = len(group)
+				issue.DuplicateMembers = group
+			}
+			kept = append(kept, issue)
+		}
+		result[repoName] = kept
+	}
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
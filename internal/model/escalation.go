@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Kinds of escalation
+const (
+	EscalationPriority  = "priority"  // a severity label raised the priority
+	EscalationReactions = "reactions" // reactions spiked since the last scrape
+	EscalationReopened  = "reopened"  // a closed issue was reopened
+)
+
+// Escalation records a stored issue that became more severe between scrapes
+type Escalation struct {
+	Repository  string    `json:"repository"`
+	IssueNumber int       `json:"issue_number"`
+	Title       string    `json:"title"`
+	URL         string    `json:"url"`
+	Kind        string    `json:"kind"`
+	From        string    `json:"from"` // the previous priority, reaction count or state
+	To          string    `json:"to"`
+	Team        string    `json:"team,omitempty"` // the issue's assigned team
+	DetectedAt  time.Time `json:"detected_at"`
+}
@@ -0,0 +1,102 @@
+package model
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Content hash algorithms. Each one bakes in a set of normalization rules,
+// so hashes are only comparable between issues hashed with the same algorithm.
+const (
+	// HashV1 hashes the lower-cased title and body with whitespace collapsed
+	HashV1 = "v1"
+	// HashV2 additionally drops code blocks, URLs, numbers and markdown
+	// punctuation, so reports differing only in versions or logs collide
+	HashV2 = "v2"
+
+	// DefaultHashAlgorithm is used for newly stored issues
+	DefaultHashAlgorithm = HashV2
+)
+
+// HashAlgorithms lists the supported content hash algorithms
+var HashAlgorithms = []string{HashV1, HashV2}
+
+// DuplicateSourceContentHash marks links between issues with the same content hash
+const DuplicateSourceContentHash = "content_hash"
+
+var (
+	codeBlockPattern   = regexp.MustCompile("(?s)```.*?```")
+	urlPattern         = regexp.MustCompile(`https?://\S+`)
+	numberPattern      = regexp.MustCompile(`[0-9]+`)
+	punctuationPattern = regexp.MustCompile("[`*_#>\\[\\]()~|.,:;!?\"'-]+")
+)
+
+// ContentHash hashes an issue's title and body after normalizing them with
+// the given algorithm
+func ContentHash(issue Issue, algorithm string) (string, error) {
+	text := issue.Title + "\n" + issue.Body
+
+	switch algorithm {
+	case HashV1:
+	case HashV2:
+		text = codeBlockPattern.ReplaceAllString(text, " ")
+		text = urlPattern.ReplaceAllString(text, " ")
+		text = numberPattern.ReplaceAllString(text, " ")
+		text = punctuationPattern.ReplaceAllString(text, " ")
+	default:
+		return "", fmt.Errorf("unknown hash algorithm %q (available: %v)", algorithm, HashAlgorithms)
+	}
+
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	sum := sha256.Sum256([]byte(normalized))
+	return fmt.Sprintf("%x", sum[:16]), nil
+}
+
+// ContentHashLinks links every issue to the first stored issue (by
+// reference) with the same algorithm and content hash
+func ContentHashLinks(issues map[string][]Issue, now time.Time) []DuplicateLink {
+	groups := make(map[string][]Issue)
+	for _, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			if issue.ContentHash == "" {
+				continue
+			}
+			key := issue.HashAlgorithm + ":" + issue.ContentHash
+			groups[key] = append(groups[key], issue)
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var links []DuplicateLink
+	for _, key := range keys {
+		members := groups[key]
+		if len(members) < 2 {
+			continue
+		}
+		sort.Slice(members, func(i, j int) bool {
+			return IssueRef(members[i].Repository, members[i].Number) < IssueRef(members[j].Repository, members[j].Number)
+		})
+
+		canonical := members[0]
+		for _, member := range members[1:] {
+			links = append(links, DuplicateLink{
+				Repository:          member.Repository,
+				IssueNumber:         member.Number,
+				CanonicalRepository: canonical.Repository,
+				CanonicalNumber:     canonical.Number,
+				Source:              DuplicateSourceContentHash,
+				LinkedAt:            now,
+			})
+		}
+	}
+	return links
+}
@@ -8,20 +8,88 @@ type Issue struct {
 	Number      int       `json:"number"`
 	Title       string    `json:"title"`
 	Body        string    `json:"body"`
+	// PlainText is Body without HTML, set only when the body has HTML
+	PlainText   string    `json:"plain_text,omitempty"`
+	StructuredFields map[string]string `json:"structured_fields,omitempty"`
 	URL         string    `json:"url"`
 	State       string    `json:"state"`
+	StateReason string    `json:"state_reason,omitempty"`
+	ClosedBy    string    `json:"closed_by,omitempty"`
+	FixedBy     string    `json:"fixed_by,omitempty"`
+	DuplicateOf string    `json:"duplicate_of,omitempty"`
+	// Set on request at export time
+	Classification *ClassificationResult `json:"classification,omitempty"`
+	// Set on the master row when duplicates are collapsed at export time
+	MemberCount      int      `json:"member_count,omitempty"`
+	DuplicateMembers []string `json:"duplicate_members,omitempty"`
+	AlsoReportedOn []string `json:"also_reported_on,omitempty"`
+	Workaround  *Workaround `json:"workaround,omitempty"`
+	// Discussion holds comments attached from the comments store; it is
+	// not saved with the issue
+	Discussion  []Comment `json:"discussion,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	Labels      []Label   `json:"labels"`
 	Comments    int       `json:"comments"`
 	Reactions   int       `json:"reactions"`
+	Author      string    `json:"author"`
 	
 	// Scoring information
 	Score       float64   `json:"score"`
+	RawScore    float64   `json:"raw_score,omitempty"`
 	ScoreReason []string  `json:"score_reason"`
+	// ReputationScore is the part of RawScore earned by reporter reputation
+	ReputationScore float64 `json:"reputation_score,omitempty"`
+	
+	// Classification information
+	Category     string   `json:"category"`
+	// Type is IssueTypeQuestion for support questions, empty otherwise
+	Type         string   `json:"type,omitempty"`
+	Priority     string   `json:"priority,omitempty"`
+	AssignedTeam string   `json:"assigned_team,omitempty"`
+	Portfolios   []string `json:"portfolios,omitempty"`
+	IsAbandoned  bool     `json:"is_abandoned"`
+	Platforms    []string `json:"platforms,omitempty"`
+	CVEs         []string `json:"cves,omitempty"`
+	CWEs         []string `json:"cwes,omitempty"`
+	OWASP        []string `json:"owasp,omitempty"`
+	ClassificationVersion string `json:"classification_version,omitempty"`
+	ScoreVersion          string `json:"score_version,omitempty"`
+	PlatformsVersion      string `json:"platforms_version,omitempty"`
+	ContentHash           string `json:"content_hash,omitempty"`
+	HashAlgorithm         string `json:"hash_algorithm,omitempty"`
 	
 	// Repository information
 	Repository  string    `json:"repository"`
+	Source      string    `json:"source,omitempty"`
+	RepoRisks   []string  `json:"repo_risks,omitempty"`
+	
+	// Local tracking information
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	Notes       []Note    `json:"notes,omitempty"`
+	DeletedUpstream bool   `json:"deleted_upstream,omitempty"`
+	TransferredFrom string `json:"transferred_from,omitempty"`
+}
+
+// Issue sources
+const (
+	SourceGitHub = "github"
+	SourceGitLab = "gitlab"
+	SourceGitea  = "gitea"
+
+	SourceStackOverflow = "stackoverflow"
+)
+
+// IssueTypeQuestion is the type of support questions
+const IssueTypeQuestion = "question"
+
+// SourceName returns the tracker an issue came from. Issues stored before
+// sources were recorded are GitHub issues.
+func (i Issue) SourceName() string {
+	if i.Source == "" {
+		return SourceGitHub
+	}
+	return i.Source
 }
 
 // Label represents a GitHub label
@@ -29,4 +97,31 @@ type Label struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Color       string `json:"color"`
-}
\ No newline at end of file
+}
+
+// Workaround is the best workaround candidate found in an issue's comments
+type Workaround struct {
+	Author     string  `json:"author"`
+	URL        string  `json:"url"`
+	Body       string  `json:"body"`
+	Confidence float64 `json:"confidence"`
+}
+
+// ExcludeDeletedUpstream returns issues without those deleted upstream and
+// how many were left out
+func ExcludeDeletedUpstream(issues map[string][]Issue) (map[string][]Issue, int) {
+	live := make(map[string][]Issue, len(issues))
+	excluded := 0
+	for repoName, repoIssues := range issues {
+		kept := make([]Issue, 0, len(repoIssues))
+		for _, issue := range repoIssues {
+			if issue.DeletedUpstream {
+				excluded++
+				continue
+			}
+			kept = append(kept, issue)
+		}
+		live[repoName] = kept
+	}
+	return live, excluded
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// Note is a team's own finding attached to a scraped issue
+type Note struct {
+	ID          int       `json:"id"`
+	Repository  string    `json:"repository"`
+	IssueNumber int       `json:"issue_number"`
+	Author      string    `json:"author"`
+	Text        string    `json:"text"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlCommentPattern   = regexp.MustCompile(`(?s)<!--.*?-->`)
+	htmlBreakPattern     = regexp.MustCompile(`(?i)<(?:br|/p|/tr|/li|/h[1-6]|/div)\b[^>]*>`)
+	htmlCellPattern      = regexp.MustCompile(`(?i)</t[dh]\s*>`)
+	htmlTagPattern       = regexp.MustCompile(`(?s)<[a-zA-Z/][^>]*>`)
+	markdownImagePattern = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	blankLinesPattern    = regexp.MustCompile(`\n\s*\n+`)
+)
+
+// PlainText strips the HTML that issue templates put in bodies (comments,
+// tables, images, line breaks) and decodes entities, leaving Markdown text
+// as it is. Images keep only their alt text.
+func PlainText(body string) string {
+	text := htmlCommentPattern.ReplaceAllString(body, " ")
+	text = markdownImagePattern.ReplaceAllString(text, "$1")
+	text = htmlBreakPattern.ReplaceAllString(text, "\n")
+	text = htmlCellPattern.ReplaceAllString(text, " ")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	return collapseWhitespace(html.UnescapeString(text))
+}
+
+// collapseWhitespace collapses runs of spaces within lines and of blank lines
+func collapseWhitespace(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// SetPlainText stores the plain text of an issue's body when stripping
+// changes more than whitespace, so plain bodies are not stored twice
+func (i *Issue) SetPlainText() {
+	i.PlainText = ""
+	if plain := PlainText(i.Body); plain != collapseWhitespace(i.Body) {
+		i.PlainText = plain
+	}
+}
+
+// SearchText returns the body text searches match against: the plain
+// text when the body has HTML, otherwise the body itself
+func (i Issue) SearchText() string {
+	if i.PlainText != "" {
+		return i.PlainText
+	}
+	return i.Body
+}
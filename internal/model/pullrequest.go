@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// PullRequest is a closed or merged pull request, kept for the pitfall
+// discussion in its description and review comments
+type PullRequest struct {
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"`
+	URL        string    `json:"url"`
+	Author     string    `json:"author"`
+	Labels     []Label   `json:"labels"`
+	Merged     bool      `json:"merged"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	ClosedAt   time.Time `json:"closed_at"`
+	Repository string    `json:"repository"`
+	// LinkedIssues are the issues the pull request closes, as owner/repo#number
+	LinkedIssues   []string        `json:"linked_issues,omitempty"`
+	ReviewComments []ReviewComment `json:"review_comments,omitempty"`
+}
+
+// ReviewComment is a comment on the diff of a pull request
+type ReviewComment struct {
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	Path      string    `json:"path,omitempty"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // Repository represents a GitHub repository to scrape
 type Repository struct {
 	Name     string `json:"name"`
@@ -14,4 +16,60 @@ type Repository struct {
 	// Statistics
 	IssuesScraped int     `json:"issues_scraped"`
 	HighValueCount int    `json:"high_value_count"`
-}
\ No newline at end of file
+}
+
+// RepoSnapshot records repository health metrics at a point in time
+type RepoSnapshot struct {
+	Repository    string    `json:"repository"`
+	CapturedAt    time.Time `json:"captured_at"`
+	Stars         int       `json:"stars"`
+	Forks         int       `json:"forks"`
+	OpenIssues    int       `json:"open_issues"`
+	PitfallCount  int       `json:"pitfall_count"`
+	License       string    `json:"license,omitempty"`
+	Archived      bool      `json:"archived,omitempty"`
+	DefaultBranch string    `json:"default_branch,omitempty"`
+}
+
+// Repository risks: problems in such repositories will not be fixed upstream
+const (
+	RepoRiskArchived   = "archived"
+	RepoRiskUnlicensed = "unlicensed"
+)
+
+// NoLicense is the snapshot license of a repository without a license;
+// snapshots taken before licenses were recorded have an empty license
+const NoLicense = "none"
+
+// Risks returns the upstream risks recorded in a snapshot
+func (s RepoSnapshot) Risks() []string {
+	var risks []string
+	if s.Archived {
+		risks = append(risks, RepoRiskArchived)
+	}
+	if s.License == NoLicense {
+		risks = append(risks, RepoRiskUnlicensed)
+	}
+	return risks
+}
+
+// MarkRepoRisks sets RepoRisks on issues from the latest snapshot of their
+// repository; issues of repositories without snapshots are left unflagged
+func MarkRepoRisks(issues map[string][]Issue, snapshots []RepoSnapshot) {
+	latest := make(map[string]RepoSnapshot)
+	for _, snapshot := range snapshots {
+		if current, ok := latest[snapshot.Repository]; !ok || !snapshot.CapturedAt.Before(current.CapturedAt) {
+			latest[snapshot.Repository] = snapshot
+		}
+	}
+
+	for repoName, repoIssues := range issues {
+		snapshot, ok := latest[repoName]
+		for i := range repoIssues {
+			repoIssues[i].RepoRisks = nil
+			if ok {
+				repoIssues[i].RepoRisks = snapshot.Risks()
+			}
+		}
+	}
+}
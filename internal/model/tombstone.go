@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// Tombstone reasons
+const (
+	// TombstoneDuplicate marks duplicates deleted by the dedup policy
+	TombstoneDuplicate = "duplicate"
+)
+
+// Tombstone records the deletion of a stored issue, so incremental exports
+// and downstream mirrors can delete the same record
+type Tombstone struct {
+	ID         int       `json:"id"`
+	Repository string    `json:"repository"`
+	Number     int       `json:"number"`
+	Reason     string    `json:"reason"`
+	DeletedAt  time.Time `json:"deleted_at"`
+}
+
+// NewTombstones records the deletion of issues for a reason
+func NewTombstones(issues []Issue, reason string, now time.Time) []Tombstone {
+	tombstones := make([]Tombstone, len(issues))
+	for i, issue := range issues {
+		tombstones[i] = Tombstone{ID: issue.ID, Repository: issue.Repository, Number: issue.Number, Reason: reason, DeletedAt: now}
+	}
+	return tombstones
+}
+
+// TombstonesSince returns the latest tombstone of each issue deleted after
+// since, leaving out issues that are stored again (live references, see
+// IssueRef), e.g. duplicates re-scraped after their deletion
+func TombstonesSince(tombstones []Tombstone, since time.Time, live map[string]bool) []Tombstone {
+	latest := make(map[string]int)
+	var selected []Tombstone
+	for _, tombstone := range tombstones {
+		ref := IssueRef(tombstone.Repository, tombstone.Number)
+		if !tombstone.DeletedAt.After(since) || live[ref] {
+			continue
+		}
+		if i, ok := latest[ref]; ok {
+			selected[i] = tombstone
+			continue
+		}
+		latest[ref] = len(selected)
+		selected = append(selected, tombstone)
+	}
+	return selected
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// ProviderUsage counts the API requests sent to a provider host in a month
+type ProviderUsage struct {
+	Month     string    `json:"month"`
+	Provider  string    `json:"provider"`
+	Requests  int       `json:"requests"`
+	Runs      int       `json:"runs"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UsageMonth returns the calendar month usage is accounted in, e.g. "2026-10"
+func UsageMonth(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// MonthlyRequests returns the requests sent to all providers in a month
+func MonthlyRequests(usage []ProviderUsage, month string) int {
+	total := 0
+	for _, u := range usage {
+		if u.Month == month {
+			total += u.Requests
+		}
+	}
+	return total
+}
+
+// PoolSample records how the requests of one scrape to an API host used
+// the host's concurrency slots (politeness.max_concurrency)
+type PoolSample struct {
+	Host           string    `json:"host"`
+	SampledAt      time.Time `json:"sampled_at"`
+	Slots          int       `json:"slots"`
+	Requests       int       `json:"requests"`
+	Waits          int       `json:"waits"`            // requests that found every slot busy
+	WaitSeconds    float64   `json:"wait_seconds"`     // their total wait for a slot
+	MaxWaitSeconds float64   `json:"max_wait_seconds"` // the longest wait for a slot
+	PacedSeconds   float64   `json:"paced_seconds"`    // waits for the interval, backoff and hourly ceiling
+}
+
+// LatestPoolSamples returns the latest sample of each host, by host
+func LatestPoolSamples(samples []PoolSample) []PoolSample {
+	latest := make(map[string]PoolSample)
+	for _, sample := range samples {
+		if current, ok := latest[sample.Host]; !ok || !sample.SampledAt.Before(current.SampledAt) {
+			latest[sample.Host] = sample
+		}
+	}
+	result := make([]PoolSample, 0, len(latest))
+	for _, sample := range latest {
+		result = append(result, sample)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Host < result[j].Host })
+	return result
+}
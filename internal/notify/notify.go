@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Config configures chat notifications. Webhooks are Slack-compatible
+// incoming webhook URLs (Slack, Mattermost, Rocket.Chat).
+type Config struct {
+	Webhooks     []string `yaml:"webhooks"`
+	DigestPeriod string   `yaml:"digest_period"`
+	DigestFormat string   `yaml:"digest_format"`
+	Alerts       []Alert  `yaml:"alerts"`
+}
+
+// Alert is a saved search whose newly matching issues are posted as they
+// are scraped. Without webhooks of its own it posts to Config.Webhooks.
+type Alert struct {
+	Name     string   `yaml:"name"`
+	Query    string   `yaml:"query"`
+	Webhooks []string `yaml:"webhooks"`
+}
+
+// Sent records the state of an issue when an alert last notified about it
+type Sent struct {
+	Priority   string    `json:"priority,omitempty"`
+	State      string    `json:"state,omitempty"`
+	NotifiedAt time.Time `json:"notified_at"`
+}
+
+// DigestState records when the last automatic digest was posted
+type DigestState struct {
+	LastPostedAt time.Time `json:"last_posted_at"`
+}
+
+// Enabled reports whether any webhook is configured
+func (c Config) Enabled() bool {
+	return len(c.Webhooks) > 0
+}
+
+// AlertsEnabled reports whether any alert has a webhook to post to
+func (c Config) AlertsEnabled() bool {
+	for _, alert := range c.Alerts {
+		if c.AlertConfig(alert).Enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// AlertConfig returns the notification config an alert posts with
+func (c Config) AlertConfig(alert Alert) Config {
+	if len(alert.Webhooks) == 0 {
+		return c
+	}
+	alertConfig := c
+	alertConfig.Webhooks = alert.Webhooks
+	return alertConfig
+}
+
+// Post sends a JSON payload to every configured webhook, returning the
+// first error after trying all of them
+func Post(ctx context.Context, config Config, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	var firstErr error
+	for _, webhook := range config.Webhooks {
+		if err := post(ctx, client, webhook, body); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func post(ctx context.Context, client *http.Client, webhook string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to %s: %w", redact(webhook), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("POST %s: %s: %s", redact(webhook), resp.Status, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// redact hides the secret path of a webhook URL in errors
+func redact(webhook string) string {
+	if i := strings.Index(webhook, "://"); i >= 0 {
+		if j := strings.Index(webhook[i+3:], "/"); j >= 0 {
+			return webhook[:i+3+j] + "/..."
+		}
+	}
+	return webhook
+}
@@ -0,0 +1,157 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// Export is a full export of the stored issues, usable as the base of a
+// later diff
+type Export struct {
+	SchemaVersion int           `json:"schema_version"`
+	GeneratedAt   time.Time     `json:"generated_at"`
+	Issues        []model.Issue `json:"issues"`
+}
+
+// ExportDelta holds only the records that changed since a base export.
+// Removed lists owner/repo#number references no longer exported, for any
+// reason; Tombstones are the issues deleted from the store since the base,
+// with the reason, which mirrors should delete too.
+type ExportDelta struct {
+	SchemaVersion int               `json:"schema_version"`
+	GeneratedAt   time.Time         `json:"generated_at"`
+	BaseAt        time.Time         `json:"base_generated_at"`
+	Added         []model.Issue     `json:"added"`
+	Changed       []model.Issue     `json:"changed"`
+	Removed       []string          `json:"removed"`
+	Tombstones    []model.Tombstone `json:"tombstones"`
+}
+
+// ChangeManifest lists the references in a delta so consumers can check
+// what to apply without reading the records
+type ChangeManifest struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Base        string    `json:"base"`
+	BaseAt      time.Time `json:"base_generated_at"`
+	Added       []string  `json:"added"`
+	Changed     []string  `json:"changed"`
+	Removed     []string  `json:"removed"`
+	Deleted     []string  `json:"deleted"`
+	Unchanged   int       `json:"unchanged"`
+}
+
+// ReadExport reads a previous full export after validating it against its
+// declared schema version. A plain JSON array of issues is accepted as a
+// schema 1 export.
+func ReadExport(path string) (Export, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Export{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		data = append(append([]byte(`{"issues":`), trimmed...), '}')
+	}
+
+	version, err := validateExport(data)
+	if err != nil {
+		return Export{}, fmt.Errorf("invalid export %s: %w", path, err)
+	}
+
+	var export Export
+	if err := json.Unmarshal(data, &export); err != nil {
+		return Export{}, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	export.SchemaVersion = version
+	return export, nil
+}
+
+// NewExport collects issues into a full export sorted by reference
+func NewExport(issues map[string][]model.Issue, now time.Time) Export {
+	var all []model.Issue
+	for _, repoIssues := range issues {
+		all = append(all, repoIssues...)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return model.IssueRef(all[i].Repository, all[i].Number) < model.IssueRef(all[j].Repository, all[j].Number)
+	})
+	return Export{SchemaVersion: ExportSchemaVersion, GeneratedAt: now, Issues: all}
+}
+
+// DiffExport compares the current export with a base export. Records are
+// matched by reference and count as changed when any field known to the
+// base's schema version differs. The tombstones of deletions since the
+// base (see model.TombstonesSince) are passed on to the delta.
+func DiffExport(base, current Export, tombstones []model.Tombstone) (ExportDelta, ChangeManifest) {
+	version := base.SchemaVersion
+	if version == 0 {
+		version = 1
+	}
+
+	previous := make(map[string][]byte, len(base.Issues))
+	for _, issue := range base.Issues {
+		previous[model.IssueRef(issue.Repository, issue.Number)] = encodeIssue(issue, version)
+	}
+
+	delta := ExportDelta{SchemaVersion: current.SchemaVersion, GeneratedAt: current.GeneratedAt, BaseAt: base.GeneratedAt}
+	manifest := ChangeManifest{GeneratedAt: current.GeneratedAt, BaseAt: base.GeneratedAt}
+	seen := make(map[string]bool, len(current.Issues))
+	for _, issue := range current.Issues {
+		ref := model.IssueRef(issue.Repository, issue.Number)
+		seen[ref] = true
+
+		old, existed := previous[ref]
+		data := encodeIssue(issue, version)
+		switch {
+		case !existed:
+			delta.Added = append(delta.Added, issue)
+			manifest.Added = append(manifest.Added, ref)
+		case !bytes.Equal(old, data):
+			delta.Changed = append(delta.Changed, issue)
+			manifest.Changed = append(manifest.Changed, ref)
+		default:
+			manifest.Unchanged++
+		}
+	}
+
+	for ref := range previous {
+		if !seen[ref] {
+			delta.Removed = append(delta.Removed, ref)
+		}
+	}
+	sort.Strings(delta.Removed)
+	manifest.Removed = delta.Removed
+
+	delta.Tombstones = tombstones
+	for _, tombstone := range tombstones {
+		manifest.Deleted = append(manifest.Deleted, model.IssueRef(tombstone.Repository, tombstone.Number))
+	}
+	return delta, manifest
+}
+
+// encodeIssue encodes an issue with only the fields of a schema version
+func encodeIssue(issue model.Issue, version int) []byte {
+	converted, err := ConvertExport(Export{Issues: []model.Issue{issue}}, version)
+	if err != nil {
+		return nil
+	}
+	data, _ := json.Marshal(converted["issues"])
+	return data
+}
+
+// WriteJSONFile writes v as indented JSON
+func WriteJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
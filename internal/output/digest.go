@@ -0,0 +1,107 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/analytics"
+)
+
+// Digest formats
+const (
+	DigestMarkdown = "markdown"
+	DigestSlack    = "slack"
+)
+
+// DigestFormats lists the supported digest formats
+var DigestFormats = []string{DigestMarkdown, DigestSlack}
+
+// FormatDigest renders a digest as a chat message payload: {"text": ...}
+// for Markdown and {"text": ..., "blocks": [...]} for Slack
+func FormatDigest(digest analytics.Digest, format string) (map[string]interface{}, error) {
+	switch format {
+	case DigestMarkdown:
+		return map[string]interface{}{"text": digestMarkdown(digest)}, nil
+	case DigestSlack:
+		return map[string]interface{}{
+			"text":   digestHeadline(digest),
+			"blocks": digestBlocks(digest),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported digest format %q (supported: %v)", format, DigestFormats)
+	}
+}
+
+func digestHeadline(digest analytics.Digest) string {
+	return fmt.Sprintf("踩坑周报 %s ~ %s: 新增 %d 个问题 (问题库共 %d 个)",
+		digest.From.Format("01-02"), digest.To.Format("01-02"), digest.NewCount, digest.Corpus)
+}
+
+func digestMarkdown(digest analytics.Digest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### 📬 %s\n", digestHeadline(digest))
+
+	if len(digest.Top) > 0 {
+		b.WriteString("\n**最值得关注**\n")
+		for i, issue := range digest.Top {
+			fmt.Fprintf(&b, "%d. [%s](%s) — %s, 评分 %.1f\n", i+1, issue.Title, issue.URL, issue.Repository, issue.Score)
+		}
+	}
+
+	if len(digest.Trends) > 0 {
+		b.WriteString("\n**趋势变化**\n")
+		for _, trend := range digest.Trends {
+			fmt.Fprintf(&b, "- %s\n", trendText(trend))
+		}
+	}
+	return b.String()
+}
+
+func digestBlocks(digest analytics.Digest) []map[string]interface{} {
+	section := func(text string) map[string]interface{} {
+		return map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": text},
+		}
+	}
+
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]string{"type": "plain_text", "text": "📬 " + digestHeadline(digest)},
+		},
+	}
+
+	if len(digest.Top) > 0 {
+		var b strings.Builder
+		b.WriteString("*最值得关注*\n")
+		for i, issue := range digest.Top {
+			fmt.Fprintf(&b, "%d. <%s|%s> — %s, 评分 %.1f\n", i+1, issue.URL, slackEscape(issue.Title), issue.Repository, issue.Score)
+		}
+		blocks = append(blocks, section(b.String()))
+	}
+
+	if len(digest.Trends) > 0 {
+		var b strings.Builder
+		b.WriteString("*趋势变化*\n")
+		for _, trend := range digest.Trends {
+			fmt.Fprintf(&b, "• %s\n", trendText(trend))
+		}
+		blocks = append(blocks, section(b.String()))
+	}
+	return blocks
+}
+
+// trendText describes a category trend change
+func trendText(trend analytics.TrendChange) string {
+	name := categoryName(trend.Category)
+	if trend.Previous == 0 {
+		return fmt.Sprintf("%s: 新出现 %d 个", name, trend.Current)
+	}
+	return fmt.Sprintf("%s: %d → %d (%+.0f%%)", name, trend.Previous, trend.Current, trend.Change)
+}
+
+// slackEscape escapes the characters Slack treats as control sequences
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
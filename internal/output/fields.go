@@ -0,0 +1,189 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// fieldAliases maps GitHub API field names to the issue fields they mean
+var fieldAliases = map[string]string{
+	"html_url": "url",
+	"user":     "author",
+}
+
+// issueFieldIndex maps JSON field names of model.Issue to struct fields
+var issueFieldIndex = func() map[string]int {
+	index := make(map[string]int)
+	t := reflect.TypeOf(model.Issue{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			index[name] = i
+		}
+	}
+	return index
+}()
+
+// IssueFields lists the field names accepted by ParseFields, in model order
+func IssueFields() []string {
+	var fields []string
+	t := reflect.TypeOf(model.Issue{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// ParseFields parses a comma-separated field list such as
+// "number,title,score,category,html_url", rejecting unknown fields
+func ParseFields(spec string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if _, ok := issueFieldIndex[resolveField(field)]; !ok {
+			return nil, fmt.Errorf("unknown field %q (available: %s)", field, strings.Join(IssueFields(), ", "))
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields given")
+	}
+	return fields, nil
+}
+
+// resolveField returns the issue field an alias stands for
+func resolveField(field string) string {
+	if resolved, ok := fieldAliases[field]; ok {
+		return resolved
+	}
+	return field
+}
+
+// ProjectIssue returns only the given fields of an issue, keyed by the
+// requested names
+func ProjectIssue(issue model.Issue, fields []string) map[string]interface{} {
+	value := reflect.ValueOf(issue)
+	projected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		name := resolveField(field)
+		if name == "source" {
+			projected[field] = issue.SourceName()
+			continue
+		}
+		projected[field] = value.Field(issueFieldIndex[name]).Interface()
+	}
+	return projected
+}
+
+// ProjectIssues projects every issue to the given fields
+func ProjectIssues(issues []model.Issue, fields []string) []map[string]interface{} {
+	projected := make([]map[string]interface{}, len(issues))
+	for i, issue := range issues {
+		projected[i] = ProjectIssue(issue, fields)
+	}
+	return projected
+}
+
+// ProjectExport keeps only the given fields of the issue records of a
+// converted export document. Records keep the schema's field names, so
+// aliases are stored under the fields they stand for, and the required
+// fields identifying an issue are always kept so deltas can still be
+// applied.
+func ProjectExport(document map[string]interface{}, fields []string) {
+	keep := append(append([]string(nil), requiredFields...), fields...)
+	for _, key := range recordLists {
+		records, _ := document[key].([]interface{})
+		for i, record := range records {
+			values, ok := record.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			projected := make(map[string]interface{}, len(keep))
+			for _, field := range keep {
+				name := resolveField(field)
+				if value, ok := values[name]; ok {
+					projected[name] = value
+				}
+			}
+			records[i] = projected
+		}
+	}
+}
+
+// WriteCSVFields streams issues as CSV with only the given columns. Lists
+// are joined with ";" as in WriteCSV.
+func WriteCSVFields(w io.Writer, issues []model.Issue, fields []string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(fields); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, issue := range issues {
+		projected := ProjectIssue(issue, fields)
+		record := make([]string, len(fields))
+		for i, field := range fields {
+			record[i] = csvCell(projected[field])
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteNDJSONFields streams issues as NDJSON with only the given fields
+func WriteNDJSONFields(w io.Writer, issues []model.Issue, fields []string) error {
+	encoder := json.NewEncoder(w)
+	for _, issue := range issues {
+		if err := encoder.Encode(ProjectIssue(issue, fields)); err != nil {
+			return fmt.Errorf("failed to write NDJSON record: %w", err)
+		}
+	}
+	return nil
+}
+
+// csvCell formats a projected field value for CSV
+func csvCell(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', 1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ";")
+	case []model.Label:
+		names := make([]string, len(v))
+		for i, label := range v {
+			names[i] = label.Name
+		}
+		return strings.Join(names, ";")
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
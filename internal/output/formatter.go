@@ -0,0 +1,600 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/analytics"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// maxBodyLength limits the issue description shown in Markdown reports
+const maxBodyLength = 1500
+
+// Markdown reports show the most-reacted comments of an issue, shortened
+const (
+	maxReportComments = 3
+	maxCommentLength  = 300
+)
+
+// categoryNames maps category keys to their display names
+var categoryNames = map[string]string{
+	"performance":   "性能问题",
+	"gpu_memory":    "GPU内存问题",
+	"distributed":   "分布式训练",
+	"model_serving": "模型推理",
+	"crashes":       "崩溃错误",
+	"memory_issues": "内存泄漏",
+	"security":      "安全问题",
+	"other":         "其他",
+}
+
+// Formatter writes filtered issues to disk as Markdown or JSON
+type Formatter struct {
+	generatedAt time.Time
+	location    *time.Location
+	sections    []Section
+	duplicates  []model.DuplicateLink
+	files       []string
+}
+
+// Section is an extra block appended to the summary report
+type Section struct {
+	Key      string
+	Title    string
+	Markdown string
+	Data     interface{}
+}
+
+// RepoReport is the JSON document written for a single repository
+type RepoReport struct {
+	Repository  string        `json:"repository"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	IssueCount  int           `json:"issue_count"`
+	AvgScore    float64       `json:"avg_score"`
+	Issues      []model.Issue `json:"issues"`
+}
+
+// Summary is the JSON document written for the whole run
+type Summary struct {
+	GeneratedAt     time.Time              `json:"generated_at"`
+	TotalRepos      int                    `json:"total_repos"`
+	TotalIssues     int                    `json:"total_issues"`
+	RepositoryStats map[string]RepoStats   `json:"repository_stats"`
+	CategoryStats   map[string]int         `json:"category_stats"`
+	TeamStats       map[string]int         `json:"team_stats,omitempty"`
+	PlatformStats   map[string]int         `json:"platform_stats,omitempty"`
+	WeaknessStats   map[string]int         `json:"weakness_stats,omitempty"`
+	Sections        map[string]interface{} `json:"sections,omitempty"`
+}
+
+// RepoStats holds per-repository summary numbers
+type RepoStats struct {
+	IssueCount int     `json:"issue_count"`
+	AvgScore   float64 `json:"avg_score"`
+}
+
+// NewFormatter creates a new output formatter
+func NewFormatter() *Formatter {
+	return &Formatter{
+		generatedAt: time.Now(),
+		location:    time.Local,
+	}
+}
+
+// SetLocation sets the timezone report timestamps and dates are shown in
+func (f *Formatter) SetLocation(loc *time.Location) {
+	f.location = loc
+	f.generatedAt = f.generatedAt.In(loc)
+}
+
+// AddSection appends an extra section to the summary report
+func (f *Formatter) AddSection(section Section) {
+	f.sections = append(f.sections, section)
+}
+
+// SetDuplicateLinks sets the duplicate links used to group issues in the groups format
+func (f *Formatter) SetDuplicateLinks(links []model.DuplicateLink) {
+	f.duplicates = links
+}
+
+// Files returns the paths of all files written so far
+func (f *Formatter) Files() []string {
+	return f.files
+}
+
+// FormatIssues writes one report per repository plus a summary
+func (f *Formatter) FormatIssues(issues map[string][]model.Issue, format, outputDir string) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	switch format {
+	case "markdown":
+		return f.formatMarkdown(issues, outputDir)
+	case "json":
+		return f.formatJSON(issues, outputDir)
+	case "csv":
+		return f.formatStream(issues, filepath.Join(outputDir, "issues.csv"), WriteCSV)
+	case "ndjson":
+		return f.formatStream(issues, filepath.Join(outputDir, "issues.ndjson"), WriteNDJSON)
+	case "groups":
+		return f.formatGroups(issues, filepath.Join(outputDir, "duplicate_groups.csv"))
+	default:
+		return fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// FormatTeams writes one report per assigned team into a "teams" subdirectory
+func (f *Formatter) FormatTeams(issues map[string][]model.Issue, format, outputDir string) error {
+	byTeam := make(map[string]map[string][]model.Issue)
+
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			team := issue.AssignedTeam
+			if team == "" {
+				team = "unassigned"
+			}
+			if byTeam[team] == nil {
+				byTeam[team] = make(map[string][]model.Issue)
+			}
+			byTeam[team][repoName] = append(byTeam[team][repoName], issue)
+		}
+	}
+
+	for team, teamIssues := range byTeam {
+		teamDir := filepath.Join(outputDir, "teams", fileName(team))
+		if err := f.FormatIssues(teamIssues, format, teamDir); err != nil {
+			return fmt.Errorf("failed to format issues for team %s: %w", team, err)
+		}
+	}
+
+	return nil
+}
+
+// FormatPortfolios writes one report per portfolio under
+// outputDir/portfolios/<name>/; an issue appears in every portfolio it
+// belongs to
+func (f *Formatter) FormatPortfolios(issues map[string][]model.Issue, format, outputDir string) error {
+	byPortfolio := make(map[string]map[string][]model.Issue)
+
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			for _, portfolio := range issue.Portfolios {
+				if byPortfolio[portfolio] == nil {
+					byPortfolio[portfolio] = make(map[string][]model.Issue)
+				}
+				byPortfolio[portfolio][repoName] = append(byPortfolio[portfolio][repoName], issue)
+			}
+		}
+	}
+
+	for portfolio, portfolioIssues := range byPortfolio {
+		portfolioDir := filepath.Join(outputDir, "portfolios", fileName(portfolio))
+		if err := f.FormatIssues(portfolioIssues, format, portfolioDir); err != nil {
+			return fmt.Errorf("failed to format issues for portfolio %s: %w", portfolio, err)
+		}
+	}
+
+	return nil
+}
+
+// formatMarkdown writes Markdown reports
+func (f *Formatter) formatMarkdown(issues map[string][]model.Issue, outputDir string) error {
+	for _, repoName := range sortedRepos(issues) {
+		path := filepath.Join(outputDir, fileName(repoName)+".md")
+		if err := os.WriteFile(path, []byte(f.repoMarkdown(repoName, issues[repoName])), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		f.files = append(f.files, path)
+	}
+
+	path := filepath.Join(outputDir, "summary.md")
+	if err := os.WriteFile(path, []byte(f.summaryMarkdown(issues)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	f.files = append(f.files, path)
+
+	return nil
+}
+
+// formatJSON writes JSON reports
+func (f *Formatter) formatJSON(issues map[string][]model.Issue, outputDir string) error {
+	for _, repoName := range sortedRepos(issues) {
+		repoIssues := issues[repoName]
+		report := RepoReport{
+			Repository:  repoName,
+			GeneratedAt: f.generatedAt,
+			IssueCount:  len(repoIssues),
+			AvgScore:    averageScore(repoIssues),
+			Issues:      repoIssues,
+		}
+
+		path := filepath.Join(outputDir, fileName(repoName)+".json")
+		if err := f.writeJSON(path, report); err != nil {
+			return err
+		}
+	}
+
+	return f.writeJSON(filepath.Join(outputDir, "summary.json"), f.buildSummary(issues))
+}
+
+// formatStream writes all issues into a single file using a streaming serializer,
+// plus the JSON summary
+func (f *Formatter) formatStream(issues map[string][]model.Issue, path string, write func(io.Writer, []model.Issue) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var all []model.Issue
+	for _, repoName := range sortedRepos(issues) {
+		all = append(all, issues[repoName]...)
+	}
+	if err := write(file, all); err != nil {
+		return err
+	}
+	f.files = append(f.files, path)
+
+	return f.writeJSON(filepath.Join(filepath.Dir(path), "summary.json"), f.buildSummary(issues))
+}
+
+// formatGroups writes one CSV row per duplicate group, plus the JSON summary
+func (f *Formatter) formatGroups(issues map[string][]model.Issue, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := WriteGroupsCSV(file, analytics.SummarizeGroups(issues, f.duplicates)); err != nil {
+		return err
+	}
+	f.files = append(f.files, path)
+
+	return f.writeJSON(filepath.Join(filepath.Dir(path), "summary.json"), f.buildSummary(issues))
+}
+
+// buildSummary aggregates statistics for the summary report
+func (f *Formatter) buildSummary(issues map[string][]model.Issue) Summary {
+	summary := Summary{
+		GeneratedAt:     f.generatedAt,
+		TotalRepos:      len(issues),
+		RepositoryStats: make(map[string]RepoStats),
+		CategoryStats:   make(map[string]int),
+		TeamStats:       make(map[string]int),
+		PlatformStats:   make(map[string]int),
+		WeaknessStats:   make(map[string]int),
+	}
+
+	for repoName, repoIssues := range issues {
+		summary.TotalIssues += len(repoIssues)
+		summary.RepositoryStats[repoName] = RepoStats{
+			IssueCount: len(repoIssues),
+			AvgScore:   averageScore(repoIssues),
+		}
+
+		for _, issue := range repoIssues {
+			summary.CategoryStats[categoryKey(issue)]++
+			if issue.AssignedTeam != "" {
+				summary.TeamStats[issue.AssignedTeam]++
+			}
+			for _, platform := range issue.Platforms {
+				summary.PlatformStats[platform]++
+			}
+			for _, category := range issue.OWASP {
+				summary.WeaknessStats[category]++
+			}
+			for _, cwe := range issue.CWEs {
+				summary.WeaknessStats[cwe]++
+			}
+		}
+	}
+
+	if len(f.sections) > 0 {
+		summary.Sections = make(map[string]interface{})
+		for _, section := range f.sections {
+			summary.Sections[section.Key] = section.Data
+		}
+	}
+
+	return summary
+}
+
+// summaryMarkdown renders summary.md
+func (f *Formatter) summaryMarkdown(issues map[string][]model.Issue) string {
+	summary := f.buildSummary(issues)
+	var b strings.Builder
+
+	b.WriteString("# GitHub Issues 踩坑报告摘要\n\n")
+	b.WriteString("## 📊 统计概览\n\n")
+	fmt.Fprintf(&b, "- **抓取时间**: %s\n", f.generatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "- **总计问题数**: %d\n", summary.TotalIssues)
+	fmt.Fprintf(&b, "- **涉及仓库数**: %d\n\n", summary.TotalRepos)
+
+	b.WriteString("## 🏢 仓库统计\n\n")
+	repos := sortedRepos(issues)
+	sort.SliceStable(repos, func(i, j int) bool {
+		return len(issues[repos[i]]) > len(issues[repos[j]])
+	})
+	for _, repoName := range repos {
+		fmt.Fprintf(&b, "- **%s**: %d 个高价值问题\n", repoName, len(issues[repoName]))
+	}
+
+	b.WriteString("\n## 🎯 高价值问题类别分布\n\n")
+	for _, entry := range sortedCounts(summary.CategoryStats) {
+		fmt.Fprintf(&b, "- **%s**: %d 个问题\n", categoryName(entry.key), entry.count)
+	}
+
+	if len(summary.TeamStats) > 0 {
+		b.WriteString("\n## 👥 团队分布\n\n")
+		for _, entry := range sortedCounts(summary.TeamStats) {
+			fmt.Fprintf(&b, "- **%s**: %d 个问题\n", entry.key, entry.count)
+		}
+	}
+
+	if len(summary.PlatformStats) > 0 {
+		b.WriteString("\n## 💻 平台分布\n\n")
+		for _, entry := range sortedCounts(summary.PlatformStats) {
+			fmt.Fprintf(&b, "- **%s**: %d 个问题\n", entry.key, entry.count)
+		}
+	}
+
+	if len(summary.WeaknessStats) > 0 {
+		b.WriteString("\n## 🔐 安全弱点分布 (OWASP / CWE)\n\n")
+		for _, entry := range sortedCounts(summary.WeaknessStats) {
+			fmt.Fprintf(&b, "- **%s**: %d 个问题\n", entry.key, entry.count)
+		}
+	}
+
+	for _, section := range f.sections {
+		fmt.Fprintf(&b, "\n## %s\n\n", section.Title)
+		b.WriteString(section.Markdown)
+	}
+
+	b.WriteString("\n## 📋 详细报告\n\n")
+	for _, repoName := range repos {
+		fmt.Fprintf(&b, "- [%s](./%s.md)\n", fileName(repoName), fileName(repoName))
+	}
+
+	b.WriteString("\n---\n\n*报告由 gh-pitfall-scraper 自动生成*\n")
+	return b.String()
+}
+
+// repoMarkdown renders the report for a single repository
+func (f *Formatter) repoMarkdown(repoName string, issues []model.Issue) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s - 高价值工程问题报告\n\n", repoName)
+	b.WriteString("## 📈 问题概览\n\n")
+	fmt.Fprintf(&b, "- **生成时间**: %s\n", f.generatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "- **问题总数**: %d\n", len(issues))
+	fmt.Fprintf(&b, "- **平均评分**: %.1f\n\n", averageScore(issues))
+	if len(issues) > 0 && len(issues[0].RepoRisks) > 0 {
+		fmt.Fprintf(&b, "> ⚠️ **%s**: 此仓库中的问题可能不会在上游修复\n\n", riskNames(issues[0].RepoRisks))
+	}
+	b.WriteString("---\n\n")
+
+	for i, issue := range issues {
+		fmt.Fprintf(&b, "## %d. %s\n\n", i+1, issue.Title)
+		fmt.Fprintf(&b, "**链接**: [%s](%s)  \n", issue.URL, issue.URL)
+		if issue.RawScore > issue.Score {
+			fmt.Fprintf(&b, "**评分**: %.1f/100 (原始 %.1f)  \n", issue.Score, issue.RawScore)
+		} else {
+			fmt.Fprintf(&b, "**评分**: %.1f/100  \n", issue.Score)
+		}
+		fmt.Fprintf(&b, "**状态**: %s  \n", issue.State)
+		fmt.Fprintf(&b, "**类别**: %s  \n", categoryName(categoryKey(issue)))
+		if issue.Priority != "" {
+			fmt.Fprintf(&b, "**上游优先级**: %s  \n", issue.Priority)
+		}
+		if issue.AssignedTeam != "" {
+			fmt.Fprintf(&b, "**负责团队**: %s  \n", issue.AssignedTeam)
+		}
+		if len(issue.Platforms) > 0 {
+			fmt.Fprintf(&b, "**平台**: %s  \n", strings.Join(issue.Platforms, ", "))
+		}
+		if len(issue.RepoRisks) > 0 {
+			fmt.Fprintf(&b, "**上游风险**: %s  \n", riskNames(issue.RepoRisks))
+		}
+		if len(issue.CWEs) > 0 {
+			fmt.Fprintf(&b, "**安全弱点**: %s", strings.Join(issue.CWEs, ", "))
+			if len(issue.OWASP) > 0 {
+				fmt.Fprintf(&b, " (%s)", strings.Join(issue.OWASP, "; "))
+			}
+			b.WriteString("  \n")
+		}
+		if issue.DuplicateOf != "" {
+			fmt.Fprintf(&b, "**重复于**: %s  \n", issue.DuplicateOf)
+		}
+		if issue.TransferredFrom != "" {
+			fmt.Fprintf(&b, "**转移自**: %s  \n", issue.TransferredFrom)
+		}
+		if len(issue.AlsoReportedOn) > 0 {
+			fmt.Fprintf(&b, "**也报告于**: %s  \n", strings.Join(issue.AlsoReportedOn, ", "))
+		}
+		if issue.FixedBy != "" {
+			fmt.Fprintf(&b, "**修复**: [%s](%s)  \n", issue.FixedBy, issue.FixedBy)
+		}
+		if issue.IsAbandoned {
+			b.WriteString("**⚠️ 上游无人响应**  \n")
+		}
+		if issue.Type == model.IssueTypeQuestion {
+			b.WriteString("**❓ 支持类问题**  \n")
+		}
+		fmt.Fprintf(&b, "**创建时间**: %s  \n", issue.CreatedAt.In(f.location).Format("2006-01-02"))
+		fmt.Fprintf(&b, "**更新时间**: %s  \n\n", issue.UpdatedAt.In(f.location).Format("2006-01-02"))
+
+		if issue.Workaround != nil {
+			fmt.Fprintf(&b, "> 💡 **临时解决方案** (置信度 %.0f%%, 来自 [@%s](%s)):\n>\n",
+				issue.Workaround.Confidence*100, issue.Workaround.Author, issue.Workaround.URL)
+			for _, line := range strings.Split(truncate(issue.Workaround.Body, maxBodyLength), "\n") {
+				fmt.Fprintf(&b, "> %s\n", line)
+			}
+			b.WriteString("\n")
+		}
+
+		if len(issue.Labels) > 0 {
+			b.WriteString("**标签**:")
+			for _, label := range issue.Labels {
+				fmt.Fprintf(&b, " [%s](https://github.com/%s/labels/%s)", label.Name, repoName, label.Name)
+			}
+			b.WriteString("\n\n")
+		}
+
+		if len(issue.ScoreReason) > 0 {
+			b.WriteString("**评分理由**:\n")
+			for _, reason := range issue.ScoreReason {
+				fmt.Fprintf(&b, "- %s\n", reason)
+			}
+			b.WriteString("\n")
+		}
+
+		if len(issue.Notes) > 0 {
+			b.WriteString("**团队备注**:\n")
+			for _, note := range issue.Notes {
+				fmt.Fprintf(&b, "- %s (%s, %s)\n", note.Text, note.Author, note.CreatedAt.In(f.location).Format("2006-01-02"))
+			}
+			b.WriteString("\n")
+		}
+
+		if issue.Body != "" {
+			b.WriteString("**问题描述**:\n```\n")
+			b.WriteString(truncate(issue.Body, maxBodyLength))
+			b.WriteString("\n```\n\n")
+		}
+
+		if len(issue.Discussion) > 0 {
+			fmt.Fprintf(&b, "**讨论** (%d 条评论):\n", len(issue.Discussion))
+			for _, comment := range topComments(issue.Discussion, maxReportComments) {
+				text := strings.Join(strings.Fields(truncate(comment.Body, maxCommentLength)), " ")
+				fmt.Fprintf(&b, "- [@%s](%s) (👍 %d): %s\n", comment.Author, comment.URL, comment.Reactions, text)
+			}
+			b.WriteString("\n")
+		}
+
+		b.WriteString("---\n\n")
+	}
+
+	fmt.Fprintf(&b, "*报告由 gh-pitfall-scraper 生成于 %s*\n", f.generatedAt.Format("2006-01-02 15:04:05"))
+	return b.String()
+}
+
+// Helper functions
+
+type countEntry struct {
+	key   string
+	count int
+}
+
+// sortedCounts orders a count map by count (descending) then key
+func sortedCounts(counts map[string]int) []countEntry {
+	entries := make([]countEntry, 0, len(counts))
+	for key, count := range counts {
+		entries = append(entries, countEntry{key: key, count: count})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].key < entries[j].key
+	})
+	return entries
+}
+
+func sortedRepos(issues map[string][]model.Issue) []string {
+	repos := make([]string, 0, len(issues))
+	for repoName := range issues {
+		repos = append(repos, repoName)
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+func averageScore(issues []model.Issue) float64 {
+	if len(issues) == 0 {
+		return 0
+	}
+	var total float64
+	for _, issue := range issues {
+		total += issue.Score
+	}
+	return total / float64(len(issues))
+}
+
+func categoryKey(issue model.Issue) string {
+	if issue.Category == "" {
+		return "other"
+	}
+	return issue.Category
+}
+
+func categoryName(key string) string {
+	if name, ok := categoryNames[key]; ok {
+		return name
+	}
+	return key
+}
+
+// riskNames returns the display names of repository risks
+func riskNames(risks []string) string {
+	names := make([]string, len(risks))
+	for i, risk := range risks {
+		switch risk {
+		case model.RepoRiskArchived:
+			names[i] = "仓库已归档"
+		case model.RepoRiskUnlicensed:
+			names[i] = "无许可证"
+		default:
+			names[i] = risk
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func fileName(name string) string {
+	return strings.ReplaceAll(name, "/", "_")
+}
+
+// topComments returns up to n comments with the most reactions, in
+// discussion order
+func topComments(comments []model.Comment, n int) []model.Comment {
+	if len(comments) <= n {
+		return comments
+	}
+	indexes := make([]int, len(comments))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool { return comments[indexes[i]].Reactions > comments[indexes[j]].Reactions })
+	indexes = indexes[:n]
+	sort.Ints(indexes)
+
+	top := make([]model.Comment, n)
+	for i, index := range indexes {
+		top[i] = comments[index]
+	}
+	return top
+}
+
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max]) + "..."
+}
+
+func (f *Formatter) writeJSON(path string, v interface{}) error {
+	if err := WriteJSONFile(path, v); err != nil {
+		return err
+	}
+	f.files = append(f.files, path)
+	return nil
+}
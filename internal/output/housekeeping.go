@@ -0,0 +1,215 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GeneratedFile lists, relative to the output directory, the files the
+// tool wrote there. Housekeeping only removes files listed in it, so user
+// files and diff bases kept next to the reports are never touched.
+const GeneratedFile = ".generated.json"
+
+// RetentionConfig bounds how much the output directory may hold; zero
+// disables a limit
+type RetentionConfig struct {
+	MaxFiles   int `yaml:"max_files"`
+	MaxAgeDays int `yaml:"max_age_days"`
+	MaxTotalMB int `yaml:"max_total_mb"`
+}
+
+// Enabled reports whether any retention limit is set
+func (c RetentionConfig) Enabled() bool {
+	return c.MaxFiles > 0 || c.MaxAgeDays > 0 || c.MaxTotalMB > 0
+}
+
+// ExpiredFile is a file selected for removal by housekeeping
+type ExpiredFile struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+	Reason  string
+}
+
+// RecordGenerated adds files written under dir to its GeneratedFile list;
+// files outside dir are ignored
+func RecordGenerated(dir string, files []string) error {
+	generated, err := loadGenerated(dir)
+	if err != nil {
+		return err
+	}
+	for _, path := range files {
+		if rel, ok := relativeTo(dir, path); ok {
+			generated[rel] = true
+		}
+	}
+	return saveGenerated(dir, generated)
+}
+
+// GeneratedFiles lists the files under dir in its GeneratedFile list that
+// still exist, relative to dir in slash form
+func GeneratedFiles(dir string) ([]string, error) {
+	generated, err := loadGenerated(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(generated))
+	for rel := range generated {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(rel))); err == nil {
+			files = append(files, rel)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Housekeep removes generated files under dir, oldest first, until the
+// retention limits hold. Only files listed in GeneratedFile are counted or
+// removed, and files in keep (usually the ones just written) are never
+// removed. With dryRun, the files are only listed.
+func Housekeep(dir string, config RetentionConfig, keep []string, dryRun bool, now time.Time) ([]ExpiredFile, error) {
+	protected := make(map[string]bool, len(keep))
+	for _, path := range keep {
+		protected[filepath.Clean(path)] = true
+	}
+	generated, err := loadGenerated(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []ExpiredFile
+	var total int64
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		if rel, ok := relativeTo(dir, path); !ok || !generated[rel] {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, ExpiredFile{Path: path, Size: info.Size(), ModTime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Oldest first
+	sort.Slice(files, func(i, j int) bool { return files[i].ModTime.Before(files[j].ModTime) })
+
+	maxAge := time.Duration(config.MaxAgeDays) * 24 * time.Hour
+	maxTotal := int64(config.MaxTotalMB) * 1024 * 1024
+	remaining := len(files)
+
+	var expired []ExpiredFile
+	for _, file := range files {
+		if protected[filepath.Clean(file.Path)] {
+			continue
+		}
+
+		switch {
+		case config.MaxAgeDays > 0 && now.Sub(file.ModTime) > maxAge:
+			file.Reason = "max_age_days"
+		case config.MaxFiles > 0 && remaining > config.MaxFiles:
+			file.Reason = "max_files"
+		case config.MaxTotalMB > 0 && total > maxTotal:
+			file.Reason = "max_total_mb"
+		default:
+			continue
+		}
+
+		expired = append(expired, file)
+		remaining--
+		total -= file.Size
+	}
+
+	if dryRun {
+		return expired, nil
+	}
+	for _, file := range expired {
+		if err := os.Remove(file.Path); err != nil {
+			return expired, err
+		}
+		removeEmptyParents(filepath.Dir(file.Path), dir)
+	}
+
+	// Forget removed files, and listed files that are gone
+	for rel := range generated {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(rel))); os.IsNotExist(err) {
+			delete(generated, rel)
+		}
+	}
+	return expired, saveGenerated(dir, generated)
+}
+
+// relativeTo returns path relative to dir in slash form, and whether path
+// is inside dir
+func relativeTo(dir, path string) (string, bool) {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+func loadGenerated(dir string) (map[string]bool, error) {
+	generated := make(map[string]bool)
+	data, err := os.ReadFile(filepath.Join(dir, GeneratedFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return generated, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", GeneratedFile, err)
+	}
+	var files []string
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", GeneratedFile, err)
+	}
+	for _, rel := range files {
+		generated[rel] = true
+	}
+	return generated, nil
+}
+
+func saveGenerated(dir string, generated map[string]bool) error {
+	files := make([]string, 0, len(generated))
+	for rel := range generated {
+		files = append(files, rel)
+	}
+	sort.Strings(files)
+	data, err := json.MarshalIndent(files, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, GeneratedFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", GeneratedFile, err)
+	}
+	return nil
+}
+
+// removeEmptyParents removes now empty directories up to, but not including, root
+func removeEmptyParents(dir, root string) {
+	root = filepath.Clean(root)
+	for dir = filepath.Clean(dir); dir != root && len(dir) > len(root); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ChecksumsFile is the checksum manifest written next to the reports
+const ChecksumsFile = "SHA256SUMS"
+
+// SignatureSuffix is appended to a manifest's name for its minisign signature
+const SignatureSuffix = ".minisig"
+
+// IntegrityConfig configures signing of checksum manifests with minisign.
+// The secret key must not be password protected (minisign -G -W), since
+// signing runs unattended.
+type IntegrityConfig struct {
+	SignKey   string `yaml:"sign_key"`
+	PublicKey string `yaml:"public_key"`
+}
+
+// ChecksumResult is the outcome of verifying one file listed in a manifest
+type ChecksumResult struct {
+	File  string
+	OK    bool
+	Error string
+}
+
+// WriteChecksums writes a manifest in sha256sum format (so `sha256sum -c`
+// works too) listing files relative to the manifest's directory
+func WriteChecksums(manifest string, files []string) error {
+	dir := filepath.Dir(manifest)
+
+	var buf bytes.Buffer
+	for _, file := range files {
+		sum, err := fileSHA256(file)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, file)
+		if err != nil || strings.HasPrefix(name, "..") {
+			name = file
+		}
+		fmt.Fprintf(&buf, "%s  %s\n", sum, filepath.ToSlash(name))
+	}
+
+	if err := os.WriteFile(manifest, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", manifest, err)
+	}
+	return nil
+}
+
+// VerifyChecksums recomputes the hash of every file listed in a manifest
+func VerifyChecksums(manifest string) ([]ChecksumResult, error) {
+	file, err := os.Open(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", manifest, err)
+	}
+	defer file.Close()
+
+	dir := filepath.Dir(manifest)
+	var results []ChecksumResult
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		expected, name, ok := strings.Cut(text, "  ")
+		if !ok || len(expected) != sha256.Size*2 {
+			return nil, fmt.Errorf("%s:%d: malformed checksum line", manifest, line)
+		}
+
+		path := filepath.FromSlash(name)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		result := ChecksumResult{File: name}
+		actual, err := fileSHA256(path)
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+		case actual != strings.ToLower(expected):
+			result.Error = "checksum mismatch"
+		default:
+			result.OK = true
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", manifest, err)
+	}
+	return results, nil
+}
+
+// SignManifest signs a manifest with the configured minisign key, writing
+// manifest+SignatureSuffix. It returns the signature path, or "" when no
+// key is configured.
+func SignManifest(ctx context.Context, config IntegrityConfig, manifest string) (string, error) {
+	if config.SignKey == "" {
+		return "", nil
+	}
+
+	signature := manifest + SignatureSuffix
+	cmd := exec.CommandContext(ctx, "minisign", "-S", "-s", config.SignKey, "-m", manifest, "-x", signature)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("minisign sign %s: %w: %s", manifest, err, strings.TrimSpace(string(out)))
+	}
+	return signature, nil
+}
+
+// VerifySignature checks a manifest's minisign signature against a public
+// key file
+func VerifySignature(ctx context.Context, publicKey, manifest string) error {
+	signature := manifest + SignatureSuffix
+	if _, err := os.Stat(signature); err != nil {
+		return fmt.Errorf("no signature for %s: %w", manifest, err)
+	}
+
+	cmd := exec.CommandContext(ctx, "minisign", "-V", "-p", publicKey, "-m", manifest, "-x", signature)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("minisign verify %s: %w: %s", manifest, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// FindManifest returns the checksum manifest covering path: path itself if
+// it is a manifest, else path.sha256, else SHA256SUMS in its directory
+func FindManifest(path string) (string, error) {
+	if filepath.Base(path) == ChecksumsFile || strings.HasSuffix(path, ".sha256") {
+		return path, nil
+	}
+	for _, candidate := range []string{path + ".sha256", filepath.Join(filepath.Dir(path), ChecksumsFile)} {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no checksum manifest found for %s", path)
+}
+
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
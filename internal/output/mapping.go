@@ -0,0 +1,380 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// Column transforms of import mappings
+const (
+	TransformSplit  = "split"  // "a,b" -> ["a", "b"]
+	TransformJoin   = "join"   // ["a", "b"] -> "a,b"
+	TransformDate   = "date"   // parsed with the layout: a Go layout, "unix" or RFC 3339 by default
+	TransformNumber = "number" // "42" -> 42
+	TransformLower  = "lower"  // "Open" -> "open"
+)
+
+// Transforms lists the supported column transforms
+var Transforms = []string{TransformSplit, TransformJoin, TransformDate, TransformNumber, TransformLower}
+
+// FieldMapping maps one source column onto an issue field
+type FieldMapping struct {
+	Source    string `yaml:"source"`
+	Target    string `yaml:"target"`    // issue field, e.g. title or html_url
+	Transform string `yaml:"transform"` // optional, one of Transforms
+	Separator string `yaml:"separator"` // for split and join (default ",")
+	Layout    string `yaml:"layout"`    // for date
+}
+
+// ColumnMapping maps the columns of datasets exported by other versions or
+// tools onto issue fields. Unmapped columns named like issue fields are
+// kept unless Strict is set; Defaults fill fields a dataset lacks, such as
+// the repository of a single-repository export.
+type ColumnMapping struct {
+	Fields   []FieldMapping    `yaml:"fields"`
+	Strict   bool              `yaml:"strict"`
+	Defaults map[string]string `yaml:"defaults"`
+}
+
+// Validate checks that every mapping names a source column, a known issue
+// field and a supported transform, and that no field is mapped twice
+func (m ColumnMapping) Validate() error {
+	targets := make(map[string]bool)
+	for i, field := range m.Fields {
+		if field.Source == "" || field.Target == "" {
+			return fmt.Errorf("fields[%d]: source and target are required", i)
+		}
+		target := resolveField(field.Target)
+		if _, ok := issueFieldIndex[target]; !ok {
+			return fmt.Errorf("fields[%d]: unknown target field %q (available: %s)", i, field.Target, strings.Join(IssueFields(), ", "))
+		}
+		if targets[target] {
+			return fmt.Errorf("fields[%d]: field %q is mapped more than once", i, target)
+		}
+		targets[target] = true
+		if field.Transform != "" && !containsTransform(field.Transform) {
+			return fmt.Errorf("fields[%d]: unknown transform %q (expected one of: %s)", i, field.Transform, strings.Join(Transforms, ", "))
+		}
+	}
+	for name := range m.Defaults {
+		if _, ok := issueFieldIndex[resolveField(name)]; !ok {
+			return fmt.Errorf("defaults: unknown field %q", name)
+		}
+	}
+	return nil
+}
+
+func containsTransform(transform string) bool {
+	for _, t := range Transforms {
+		if t == transform {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyMapping converts dataset records into issues. The whole dataset is
+// checked before any issue is returned: every mapped source column must
+// occur in it, and every record must convert and identify its issue by
+// repository and number.
+func ApplyMapping(records []map[string]interface{}, mapping ColumnMapping) ([]model.Issue, error) {
+	if err := mapping.Validate(); err != nil {
+		return nil, err
+	}
+	columns := make(map[string]bool)
+	for _, record := range records {
+		for column := range record {
+			columns[column] = true
+		}
+	}
+	for _, field := range mapping.Fields {
+		if !columns[field.Source] {
+			return nil, fmt.Errorf("column %q is not in the dataset (columns: %s)", field.Source, strings.Join(sortedKeys(columns), ", "))
+		}
+	}
+
+	issues := make([]model.Issue, 0, len(records))
+	for i, record := range records {
+		issue, err := mapRecord(record, mapping)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		if issue.Repository == "" || issue.Number <= 0 {
+			return nil, fmt.Errorf("record %d: no repository and number (map or default them)", i+1)
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+// mapRecord converts one record into an issue
+func mapRecord(record map[string]interface{}, mapping ColumnMapping) (model.Issue, error) {
+	values := make(map[string]interface{})
+	if !mapping.Strict {
+		mapped := make(map[string]bool, len(mapping.Fields))
+		for _, field := range mapping.Fields {
+			mapped[field.Source] = true
+		}
+		for column, value := range record {
+			name := resolveField(column)
+			if _, ok := issueFieldIndex[name]; ok && !mapped[column] {
+				values[name] = value
+			}
+		}
+	}
+	for _, field := range mapping.Fields {
+		value, ok := record[field.Source]
+		if !ok || value == nil {
+			continue
+		}
+		value, err := transformValue(value, field)
+		if err != nil {
+			return model.Issue{}, fmt.Errorf("column %q: %w", field.Source, err)
+		}
+		values[resolveField(field.Target)] = value
+	}
+	for name, value := range mapping.Defaults {
+		if current, ok := values[resolveField(name)]; !ok || current == nil || current == "" {
+			values[resolveField(name)] = value
+		}
+	}
+
+	issueType := reflect.TypeOf(model.Issue{})
+	for name, value := range values {
+		coerced, err := coerceValue(value, issueType.Field(issueFieldIndex[name]).Type)
+		if err != nil {
+			return model.Issue{}, fmt.Errorf("field %q: %w", name, err)
+		}
+		values[name] = coerced
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return model.Issue{}, err
+	}
+	var issue model.Issue
+	if err := json.Unmarshal(data, &issue); err != nil {
+		return model.Issue{}, err
+	}
+	return issue, nil
+}
+
+// transformValue applies the transform of a field mapping to a value
+func transformValue(value interface{}, field FieldMapping) (interface{}, error) {
+	separator := field.Separator
+	if separator == "" {
+		separator = ","
+	}
+	switch field.Transform {
+	case TransformSplit:
+		text, ok := value.(string)
+		if !ok {
+			return value, nil
+		}
+		return splitList(text, separator), nil
+	case TransformJoin:
+		list, ok := value.([]interface{})
+		if !ok {
+			return value, nil
+		}
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, separator), nil
+	case TransformDate:
+		return parseDate(value, field.Layout)
+	case TransformNumber:
+		if text, ok := value.(string); ok {
+			return strconv.ParseFloat(strings.TrimSpace(text), 64)
+		}
+		return value, nil
+	case TransformLower:
+		if text, ok := value.(string); ok {
+			return strings.ToLower(text), nil
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
+}
+
+// parseDate parses a date with a Go layout, as Unix seconds with "unix",
+// or as RFC 3339 without a layout
+func parseDate(value interface{}, layout string) (time.Time, error) {
+	if layout == "unix" {
+		switch v := value.(type) {
+		case float64:
+			return time.Unix(int64(v), 0).UTC(), nil
+		case string:
+			seconds, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("invalid unix time %q", v)
+			}
+			return time.Unix(seconds, 0).UTC(), nil
+		}
+		return time.Time{}, fmt.Errorf("invalid unix time %v", value)
+	}
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	text, ok := value.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("expected a date string, got %v", value)
+	}
+	parsed, err := time.Parse(layout, strings.TrimSpace(text))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q for layout %q", text, layout)
+	}
+	return parsed, nil
+}
+
+// coerceValue converts text values, as read from CSV, into the kind of
+// the issue field. Lists in text are separated by ";" as in WriteCSV, and
+// labels may be given by name.
+func coerceValue(value interface{}, fieldType reflect.Type) (interface{}, error) {
+	text, isText := value.(string)
+	switch {
+	case fieldType == reflect.TypeOf([]model.Label{}):
+		var names []interface{}
+		switch v := value.(type) {
+		case string:
+			for _, name := range splitList(v, ";") {
+				names = append(names, name)
+			}
+		case []string:
+			for _, name := range v {
+				names = append(names, name)
+			}
+		case []interface{}:
+			names = v
+		default:
+			return value, nil
+		}
+		labels := make([]interface{}, len(names))
+		for i, name := range names {
+			if s, ok := name.(string); ok {
+				labels[i] = map[string]string{"name": s}
+			} else {
+				labels[i] = name
+			}
+		}
+		return labels, nil
+	case !isText:
+		return value, nil
+	case fieldType.Kind() == reflect.Int:
+		if text == "" {
+			return 0, nil
+		}
+		return strconv.Atoi(strings.TrimSpace(text))
+	case fieldType.Kind() == reflect.Float64:
+		if text == "" {
+			return 0.0, nil
+		}
+		return strconv.ParseFloat(strings.TrimSpace(text), 64)
+	case fieldType.Kind() == reflect.Bool:
+		if text == "" {
+			return false, nil
+		}
+		return strconv.ParseBool(strings.TrimSpace(text))
+	case fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() == reflect.String:
+		return splitList(text, ";"), nil
+	case fieldType == reflect.TypeOf(time.Time{}) && text == "":
+		return time.Time{}, nil
+	}
+	return value, nil
+}
+
+// splitList splits text into trimmed, non-empty items
+func splitList(text, separator string) []string {
+	items := []string{}
+	for _, item := range strings.Split(text, separator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ReadRecords reads the records of a dataset to import: CSV with a header
+// row (.csv), one JSON object per line (.ndjson, .jsonl), or JSON holding
+// an array of records or an export with an issues array
+func ReadRecords(path string) ([]map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if len(rows) == 0 {
+			return nil, nil
+		}
+		records := make([]map[string]interface{}, 0, len(rows)-1)
+		for _, row := range rows[1:] {
+			record := make(map[string]interface{}, len(row))
+			for i, column := range rows[0] {
+				if i < len(row) {
+					record[strings.TrimSpace(column)] = row[i]
+				}
+			}
+			records = append(records, record)
+		}
+		return records, nil
+	case ".ndjson", ".jsonl":
+		var records []map[string]interface{}
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			var record map[string]interface{}
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				return nil, fmt.Errorf("failed to parse %s line %d: %w", path, line, err)
+			}
+			records = append(records, record)
+		}
+		return records, scanner.Err()
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var records []map[string]interface{}
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return records, nil
+	}
+	var document struct {
+		Issues []map[string]interface{} `json:"issues"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return document.Issues, nil
+}
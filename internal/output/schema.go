@@ -0,0 +1,121 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ExportSchemaVersion is the schema version of exports written with the
+// current Issue model. Bump it and record the new fields in schemaFields
+// (or document keys in schemaDocumentFields) whenever exported issue fields
+// are added, renamed or removed.
+const ExportSchemaVersion = 13
+
+// schemaFields lists the issue fields each schema version added. Converting
+// to an older version drops the fields added after it.
+var schemaFields = map[int][]string{
+	2: {
+		"content_hash", "hash_algorithm",
+		"classification_version", "score_version", "platforms_version",
+		"deleted_upstream", "transferred_from",
+	},
+	3:  {"cwes", "owasp"},
+	4:  {"cves"},
+	5:  {"repo_risks"},
+	6:  {"member_count", "duplicate_members"},
+	7:  {"classification"},
+	9:  {"plain_text"},
+	10: {"discussion"},
+	11: {"type"},
+	12: {"portfolios"},
+	13: {"reputation_score"},
+}
+
+// schemaDocumentFields lists the document keys each schema version added
+var schemaDocumentFields = map[int][]string{
+	8: {"tombstones"},
+}
+
+// requiredFields must be present in every exported issue of any version
+var requiredFields = []string{"repository", "number"}
+
+// recordLists are the document keys that hold issue records
+var recordLists = []string{"issues", "added", "changed"}
+
+// ConvertExport encodes an export document (Export or ExportDelta) in the
+// given schema version, stamping schema_version and dropping issue fields
+// the version does not know
+func ConvertExport(document interface{}, version int) (map[string]interface{}, error) {
+	if version < 1 || version > ExportSchemaVersion {
+		return nil, fmt.Errorf("unsupported export schema %d (supported: 1-%d)", version, ExportSchemaVersion)
+	}
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+	var converted map[string]interface{}
+	if err := json.Unmarshal(data, &converted); err != nil {
+		return nil, err
+	}
+	converted["schema_version"] = version
+	for v := version + 1; v <= ExportSchemaVersion; v++ {
+		for _, key := range schemaDocumentFields[v] {
+			delete(converted, key)
+		}
+	}
+
+	for _, key := range recordLists {
+		records, _ := converted[key].([]interface{})
+		for _, record := range records {
+			fields, ok := record.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for v := version + 1; v <= ExportSchemaVersion; v++ {
+				for _, field := range schemaFields[v] {
+					delete(fields, field)
+				}
+			}
+		}
+	}
+	return converted, nil
+}
+
+// validateExport checks an export document against its declared schema
+// version: the version must be supported and every issue must have the
+// required fields and no fields added in later versions. Documents without
+// a schema_version predate versioning and are schema 1.
+func validateExport(data []byte) (int, error) {
+	var document struct {
+		SchemaVersion int                          `json:"schema_version"`
+		Issues        []map[string]json.RawMessage `json:"issues"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return 0, err
+	}
+
+	version := document.SchemaVersion
+	if version == 0 {
+		version = 1
+	}
+	if version > ExportSchemaVersion {
+		return 0, fmt.Errorf("export schema %d is newer than the supported schema %d", version, ExportSchemaVersion)
+	}
+
+	for i, issue := range document.Issues {
+		for _, field := range requiredFields {
+			if _, ok := issue[field]; !ok {
+				return 0, fmt.Errorf("issue %d has no %q field", i, field)
+			}
+		}
+		for v := version + 1; v <= ExportSchemaVersion; v++ {
+			for _, field := range schemaFields[v] {
+				if _, ok := issue[field]; ok {
+					return 0, fmt.Errorf("issue %d has field %q from schema %d, but the export declares schema %d", i, field, v, version)
+				}
+			}
+		}
+	}
+	return version, nil
+}
@@ -0,0 +1,131 @@
+package output
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// TestConvertExportType checks that the issue type, added in schema 11, is
+// kept in schema 11 exports and dropped from older ones
+func TestConvertExportType(t *testing.T) {
+	issue := model.Issue{Repository: "acme/infer", Number: 1, Type: model.IssueTypeQuestion}
+	export := NewExport(map[string][]model.Issue{"acme/infer": {issue}}, time.Now())
+
+	for version, kept := range map[int]bool{10: false, 11: true} {
+		converted, err := ConvertExport(export, version)
+		if err != nil {
+			t.Fatalf("Failed to convert to schema %d: %v", version, err)
+		}
+		fields := converted["issues"].([]interface{})[0].(map[string]interface{})
+		if _, ok := fields["type"]; ok != kept {
+			t.Errorf("Expected type kept=%v in schema %d, got %v", kept, version, fields)
+		}
+	}
+}
+
+// TestConvertExportVersions converts an export using every versioned field
+// to each schema and validates the result against it
+func TestConvertExportVersions(t *testing.T) {
+	issue := model.Issue{
+		Repository: "acme/infer", Number: 1,
+		ContentHash: "abc", HashAlgorithm: "sha256", TransferredFrom: "acme/old#1",
+		CWEs: []string{"CWE-400"}, OWASP: []string{"A05"},
+		CVEs:             []string{"CVE-2024-0001"},
+		RepoRisks:        []string{"archived"},
+		MemberCount:      2,
+		DuplicateMembers: []string{"acme/infer#2"},
+		Classification:   &model.ClassificationResult{Category: "crashes"},
+		PlainText:        "text",
+		Discussion:       []model.Comment{{ID: 1, Body: "same here"}},
+		Type:             model.IssueTypeQuestion,
+		Portfolios:       []string{"serving"},
+		ReputationScore:  2.5,
+	}
+	export := NewExport(map[string][]model.Issue{"acme/infer": {issue}}, time.Now())
+	delta := ExportDelta{SchemaVersion: ExportSchemaVersion, Added: []model.Issue{issue}, Tombstones: []model.Tombstone{{ID: 3}}}
+
+	tests := []struct {
+		version    int
+		newest     string // the newest field the version keeps
+		next       string // the first field it drops
+		tombstones bool
+	}{
+		{1, "number", "content_hash", false},
+		{2, "transferred_from", "cwes", false},
+		{3, "owasp", "cves", false},
+		{4, "cves", "repo_risks", false},
+		{5, "repo_risks", "member_count", false},
+		{6, "duplicate_members", "classification", false},
+		{7, "classification", "plain_text", false},
+		{8, "classification", "plain_text", true},
+		{9, "plain_text", "discussion", true},
+		{10, "discussion", "type", true},
+		{11, "type", "portfolios", true},
+		{12, "portfolios", "reputation_score", true},
+		{13, "reputation_score", "", true},
+	}
+	if last := tests[len(tests)-1].version; last != ExportSchemaVersion {
+		t.Fatalf("Expected the table to end at schema %d, got %d", ExportSchemaVersion, last)
+	}
+
+	for _, test := range tests {
+		converted, err := ConvertExport(export, test.version)
+		if err != nil {
+			t.Fatalf("Failed to convert to schema %d: %v", test.version, err)
+		}
+		fields := converted["issues"].([]interface{})[0].(map[string]interface{})
+		if _, ok := fields[test.newest]; !ok {
+			t.Errorf("Expected schema %d to keep %q", test.version, test.newest)
+		}
+		if _, ok := fields[test.next]; ok {
+			t.Errorf("Expected schema %d to drop %q", test.version, test.next)
+		}
+
+		data, err := json.Marshal(converted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version, err := validateExport(data); err != nil || version != test.version {
+			t.Errorf("Expected the converted export to validate as schema %d, got %d (%v)", test.version, version, err)
+		}
+
+		convertedDelta, err := ConvertExport(delta, test.version)
+		if err != nil {
+			t.Fatalf("Failed to convert the delta to schema %d: %v", test.version, err)
+		}
+		if _, ok := convertedDelta["tombstones"]; ok != test.tombstones {
+			t.Errorf("Expected tombstones=%v in schema %d deltas", test.tombstones, test.version)
+		}
+	}
+
+	for _, version := range []int{0, ExportSchemaVersion + 1} {
+		if _, err := ConvertExport(export, version); err == nil {
+			t.Errorf("Expected schema %d to be unsupported", version)
+		}
+	}
+}
+
+// TestValidateExport rejects exports with fields newer than their schema
+func TestValidateExport(t *testing.T) {
+	tests := []struct {
+		document string
+		version  int
+		valid    bool
+	}{
+		{`{"issues":[{"repository":"acme/infer","number":1}]}`, 1, true},
+		{`{"schema_version":9,"issues":[{"repository":"acme/infer","number":1,"plain_text":"text"}]}`, 9, true},
+		{`{"schema_version":8,"issues":[{"repository":"acme/infer","number":1,"plain_text":"text"}]}`, 0, false},
+		{`{"schema_version":1,"issues":[{"repository":"acme/infer","number":1,"cwes":["CWE-400"]}]}`, 0, false},
+		{`{"schema_version":13,"issues":[{"repository":"acme/infer"}]}`, 0, false},
+		{`{"schema_version":99,"issues":[]}`, 0, false},
+	}
+	for _, test := range tests {
+		version, err := validateExport([]byte(test.document))
+		if (err == nil) != test.valid || version != test.version {
+			t.Errorf("Expected %s to validate=%v as schema %d, got %d (%v)", test.document, test.valid, test.version, version, err)
+		}
+	}
+}
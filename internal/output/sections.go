@@ -0,0 +1,369 @@
+package output
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/analytics"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// maxSectionRows limits the rows rendered in summary section tables
+const maxSectionRows = 20
+
+// SLASection renders the issue aging and SLA breach report
+func SLASection(report analytics.SLAReport) Section {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "- **本地未分派**: %d 个问题 (平均等待 %.1f 天)\n", report.Untriaged, analytics.Days(report.AvgUntriagedAge))
+	fmt.Fprintf(&b, "- **上游无回复**: %d 个问题\n", report.Unanswered)
+	fmt.Fprintf(&b, "- **超出 SLA**: %d 项\n\n", len(report.Breaches))
+
+	if len(report.Breaches) > 0 {
+		b.WriteString("| 仓库 | 问题 | 类型 | 已等待 (天) | SLA (天) |\n")
+		b.WriteString("|------|------|------|------------|----------|\n")
+		for i, breach := range report.Breaches {
+			if i == maxSectionRows {
+				fmt.Fprintf(&b, "\n*另有 %d 项未列出*\n", len(report.Breaches)-maxSectionRows)
+				break
+			}
+			fmt.Fprintf(&b, "| %s | [#%d %s](%s) | %s | %.1f | %.0f |\n",
+				breach.Issue.Repository, breach.Issue.Number, tableCell(breach.Issue.Title), breach.Issue.URL,
+				breachKindName(breach.Kind), analytics.Days(breach.Age), analytics.Days(breach.Limit))
+		}
+	}
+
+	return Section{
+		Key:      "sla",
+		Title:    "⏱️ 问题老化与 SLA",
+		Markdown: b.String(),
+		Data:     report,
+	}
+}
+
+func breachKindName(kind string) string {
+	switch kind {
+	case analytics.BreachUntriaged:
+		return "本地未分派"
+	case analytics.BreachUnanswered:
+		return "上游无回复"
+	default:
+		return kind
+	}
+}
+
+// tableCell escapes text for use inside a Markdown table cell
+func tableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
+// RepoHealthSection renders repository health trends next to pitfall trends
+func RepoHealthSection(snapshots []model.RepoSnapshot) Section {
+	byRepo := make(map[string][]model.RepoSnapshot)
+	var repos []string
+	for _, snapshot := range snapshots {
+		if _, ok := byRepo[snapshot.Repository]; !ok {
+			repos = append(repos, snapshot.Repository)
+		}
+		byRepo[snapshot.Repository] = append(byRepo[snapshot.Repository], snapshot)
+	}
+	sort.Strings(repos)
+
+	var b strings.Builder
+	b.WriteString("| 仓库 | Stars | Forks | Open Issues | 踩坑问题 | Stars 趋势 | 踩坑趋势 | 风险 |\n")
+	b.WriteString("|------|-------|-------|-------------|----------|-----------|----------|------|\n")
+
+	for _, repoName := range repos {
+		history := byRepo[repoName]
+		first, last := history[0], history[len(history)-1]
+
+		var stars, pitfalls []int
+		for _, snapshot := range history {
+			stars = append(stars, snapshot.Stars)
+			pitfalls = append(pitfalls, snapshot.PitfallCount)
+		}
+
+		// A stagnating project with rising pitfalls is the key risk signal
+		var risks []string
+		if len(history) > 1 && last.Stars <= first.Stars && last.PitfallCount > first.PitfallCount {
+			risks = append(risks, "⚠️")
+		}
+		if upstream := last.Risks(); len(upstream) > 0 {
+			risks = append(risks, riskNames(upstream))
+		}
+		risk := strings.Join(risks, " ")
+
+		fmt.Fprintf(&b, "| %s | %d (%+d) | %d (%+d) | %d (%+d) | %d (%+d) | %s | %s | %s |\n",
+			repoName,
+			last.Stars, last.Stars-first.Stars,
+			last.Forks, last.Forks-first.Forks,
+			last.OpenIssues, last.OpenIssues-first.OpenIssues,
+			last.PitfallCount, last.PitfallCount-first.PitfallCount,
+			sparkline(stars), sparkline(pitfalls), risk)
+	}
+
+	return Section{
+		Key:      "repo_health",
+		Title:    "💓 仓库健康趋势",
+		Markdown: b.String(),
+		Data:     byRepo,
+	}
+}
+
+// sparkline renders a series as a compact unicode chart
+func sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	bars := []rune("▁▂▃▄▅▆▇█")
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		idx := 0
+		if max > min {
+			idx = (v - min) * (len(bars) - 1) / (max - min)
+		}
+		b.WriteRune(bars[idx])
+	}
+	return b.String()
+}
+
+// AbandonedSection renders known pitfalls that never got an upstream acknowledgment
+func AbandonedSection(issues map[string][]model.Issue) Section {
+	var abandoned []model.Issue
+	for _, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			if issue.IsAbandoned {
+				abandoned = append(abandoned, issue)
+			}
+		}
+	}
+	sort.SliceStable(abandoned, func(i, j int) bool {
+		return abandoned[i].Score > abandoned[j].Score
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "以下 %d 个问题在上游无人响应或被 stale 机器人关闭，采用相关组件时需格外谨慎。\n\n", len(abandoned))
+
+	if len(abandoned) > 0 {
+		b.WriteString("| 仓库 | 问题 | 评分 | 状态 | 创建时间 |\n")
+		b.WriteString("|------|------|------|------|----------|\n")
+		for i, issue := range abandoned {
+			if i == maxSectionRows {
+				fmt.Fprintf(&b, "\n*另有 %d 项未列出*\n", len(abandoned)-maxSectionRows)
+				break
+			}
+			state := issue.State
+			if issue.StateReason != "" {
+				state += " (" + issue.StateReason + ")"
+			}
+			fmt.Fprintf(&b, "| %s | [#%d %s](%s) | %.1f | %s | %s |\n",
+				issue.Repository, issue.Number, tableCell(issue.Title), issue.URL,
+				issue.Score, state, issue.CreatedAt.Format("2006-01-02"))
+		}
+	}
+
+	return Section{
+		Key:      "abandoned",
+		Title:    "🚧 无人响应的已知坑",
+		Markdown: b.String(),
+		Data:     abandoned,
+	}
+}
+
+// FixStatusSection renders fixed vs unfixed pitfall statistics
+func FixStatusSection(issues map[string][]model.Issue) Section {
+	type fixStats struct {
+		Open          int `json:"open"`
+		ClosedFixed   int `json:"closed_fixed"`
+		ClosedUnfixed int `json:"closed_unfixed"`
+	}
+	stats := make(map[string]*fixStats)
+	total := &fixStats{}
+
+	for repoName, repoIssues := range issues {
+		repoStats := &fixStats{}
+		for _, issue := range repoIssues {
+			switch {
+			case issue.State == "open":
+				repoStats.Open++
+			case issue.FixedBy != "":
+				repoStats.ClosedFixed++
+			default:
+				repoStats.ClosedUnfixed++
+			}
+		}
+		stats[repoName] = repoStats
+		total.Open += repoStats.Open
+		total.ClosedFixed += repoStats.ClosedFixed
+		total.ClosedUnfixed += repoStats.ClosedUnfixed
+	}
+
+	var b strings.Builder
+	b.WriteString("| 仓库 | 未关闭 | 已修复 (有修复链接) | 已关闭 (无修复链接) |\n")
+	b.WriteString("|------|--------|---------------------|---------------------|\n")
+	for _, repoName := range sortedRepos(issues) {
+		s := stats[repoName]
+		fmt.Fprintf(&b, "| %s | %d | %d | %d |\n", repoName, s.Open, s.ClosedFixed, s.ClosedUnfixed)
+	}
+	fmt.Fprintf(&b, "| **总计** | %d | %d | %d |\n", total.Open, total.ClosedFixed, total.ClosedUnfixed)
+
+	stats["total"] = total
+	return Section{
+		Key:      "fix_status",
+		Title:    "🔧 修复情况",
+		Markdown: b.String(),
+		Data:     stats,
+	}
+}
+
+// CoverageSection lists the scanned repositories without any issue
+// matching the keywords and filters
+func CoverageSection(coverage model.Coverage) Section {
+	var b strings.Builder
+	fmt.Fprintf(&b, "共扫描 %d 个仓库, 其中 %d 个没有匹配关键词和过滤条件的问题。\n\n", coverage.Scanned, len(coverage.Empty))
+
+	b.WriteString("| 仓库 | 扫描问题数 | 关键词 | 最后抓取 |\n")
+	b.WriteString("|------|------------|--------|----------|\n")
+	for i, record := range coverage.Empty {
+		if i == maxSectionRows {
+			fmt.Fprintf(&b, "\n*另有 %d 项未列出*\n", len(coverage.Empty)-maxSectionRows)
+			break
+		}
+		fmt.Fprintf(&b, "| %s | %d | %s | %s |\n", record.Repository, record.Scanned,
+			tableCell(strings.Join(record.Keywords, ", ")), record.ScrapedAt.Format("2006-01-02"))
+	}
+
+	return Section{
+		Key:      "coverage",
+		Title:    "🕳️ 扫描覆盖",
+		Markdown: b.String(),
+		Data:     coverage,
+	}
+}
+
+// ClassificationQualitySection renders how well the classification rules
+// agree with manual category corrections
+func ClassificationQualitySection(report analytics.ConfusionReport) Section {
+	var b strings.Builder
+	fmt.Fprintf(&b, "基于 %d 条人工分类修正, 当前规则准确率 %.1f%%。\n\n", report.Total, report.Accuracy()*100)
+
+	b.WriteString("| 类别 | 规则预测 | 人工标注 | 精确率 | 召回率 |\n")
+	b.WriteString("|------|----------|----------|--------|--------|\n")
+	for _, quality := range report.Quality {
+		fmt.Fprintf(&b, "| %s | %d | %d | %.1f%% | %.1f%% |\n", categoryName(quality.Category),
+			quality.Predicted, quality.Actual, quality.Precision*100, quality.Recall*100)
+	}
+
+	b.WriteString("\n**混淆矩阵** (行: 规则预测, 列: 人工标注)\n\n")
+	b.WriteString("| 预测 \\ 标注 |")
+	for _, category := range report.Categories {
+		fmt.Fprintf(&b, " %s |", categoryName(category))
+	}
+	b.WriteString("\n|------|" + strings.Repeat("------|", len(report.Categories)) + "\n")
+	for _, predicted := range report.Categories {
+		fmt.Fprintf(&b, "| %s |", categoryName(predicted))
+		for _, actual := range report.Categories {
+			count := report.Matrix[predicted][actual]
+			switch {
+			case count == 0:
+				b.WriteString(" · |")
+			case predicted == actual:
+				fmt.Fprintf(&b, " **%d** |", count)
+			default:
+				fmt.Fprintf(&b, " %d |", count)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if len(report.Confidence) > 0 {
+		b.WriteString("\n**按置信度** (已记录的分类结果)\n\n")
+		b.WriteString("| 置信度 | 问题数 | 准确率 |\n")
+		b.WriteString("|--------|--------|--------|\n")
+		for _, band := range report.Confidence {
+			label := fmt.Sprintf("%.2f–%.2f", band.Min, band.Max)
+			if band.Min == band.Max {
+				label = fmt.Sprintf("%.2f", band.Min)
+			}
+			fmt.Fprintf(&b, "| %s | %d | %.1f%% |\n", label, band.Total, band.Accuracy*100)
+		}
+	}
+
+	return Section{
+		Key:      "classification_quality",
+		Title:    "🎯 分类质量",
+		Markdown: b.String(),
+		Data:     report,
+	}
+}
+
+// PortfolioSection renders the per-portfolio rollup of the corpus
+func PortfolioSection(rollup []analytics.PortfolioStats) Section {
+	var b strings.Builder
+	b.WriteString("| Portfolio | 仓库 | 问题 | 未关闭 | 高优先级 | 平均评分 | 主要类别 |\n")
+	b.WriteString("|-----------|------|------|--------|----------|----------|----------|\n")
+	for _, stats := range rollup {
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %.1f | %s |\n",
+			tableCell(stats.Name), stats.Repositories, stats.Issues, stats.Open, stats.HighPriority, stats.AverageScore, stats.TopCategory)
+	}
+
+	return Section{
+		Key:      "portfolios",
+		Title:    "🗂️ Portfolio 概览",
+		Markdown: b.String(),
+		Data:     rollup,
+	}
+}
+
+// riskTrendNames describe risk trend directions
+var riskTrendNames = map[string]string{
+	analytics.TrendRising:  "↑ 上升",
+	analytics.TrendFalling: "↓ 下降",
+	analytics.TrendStable:  "→ 持平",
+}
+
+// PortfolioRiskSection renders the risk score of each portfolio, charted
+// over its recorded history followed by the current score
+func PortfolioRiskSection(risks, history []analytics.PortfolioRisk) Section {
+	byName := make(map[string][]int)
+	for _, risk := range history {
+		byName[risk.Name] = append(byName[risk.Name], int(math.Round(risk.Score)))
+	}
+
+	var b strings.Builder
+	b.WriteString("风险分 (0-100) 综合未解决的高严重性踩坑、依赖健康 (归档仓库、上游无响应) 和新增趋势。\n\n")
+	b.WriteString("| Portfolio | 风险分 | 变化 | 历史 | 未解决高严重性 | 归档仓库 | 上游无响应 | 新增趋势 |\n")
+	b.WriteString("|-----------|--------|------|------|----------------|----------|------------|----------|\n")
+	for _, risk := range risks {
+		scores := append(byName[risk.Name], int(math.Round(risk.Score)))
+		change := "-"
+		if len(scores) > 1 {
+			change = fmt.Sprintf("%+d", scores[len(scores)-1]-scores[len(scores)-2])
+		}
+		fmt.Fprintf(&b, "| %s | %.0f | %s | %s | %d | %d/%d | %.0f%% | %s (%d → %d) |\n",
+			tableCell(risk.Name), risk.Score, change, sparkline(scores), risk.Unresolved,
+			risk.Archived, risk.Repositories, risk.Unresponsive*100,
+			riskTrendNames[risk.Trend], risk.Previous, risk.Recent)
+	}
+
+	return Section{
+		Key:      "portfolio_risk",
+		Title:    "🎯 Portfolio 风险分",
+		Markdown: b.String(),
+		Data:     risks,
+	}
+}
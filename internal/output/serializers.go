@@ -0,0 +1,112 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/analytics"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// csvColumns are the columns written by WriteCSV
+var csvColumns = []string{
+	"repository", "source", "number", "title", "state", "category", "assigned_team",
+	"score", "comments", "reactions", "author", "labels",
+	"created_at", "updated_at", "url", "fixed_by", "is_abandoned", "also_reported_on",
+	"cwes", "owasp", "repo_risks",
+}
+
+// WriteCSV streams issues as CSV with a header row
+func WriteCSV(w io.Writer, issues []model.Issue) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, issue := range issues {
+		labels := make([]string, len(issue.Labels))
+		for i, label := range issue.Labels {
+			labels[i] = label.Name
+		}
+
+		record := []string{
+			issue.Repository,
+			issue.SourceName(),
+			strconv.Itoa(issue.Number),
+			issue.Title,
+			issue.State,
+			issue.Category,
+			issue.AssignedTeam,
+			strconv.FormatFloat(issue.Score, 'f', 1, 64),
+			strconv.Itoa(issue.Comments),
+			strconv.Itoa(issue.Reactions),
+			issue.Author,
+			strings.Join(labels, ";"),
+			issue.CreatedAt.Format(time.RFC3339),
+			issue.UpdatedAt.Format(time.RFC3339),
+			issue.URL,
+			issue.FixedBy,
+			strconv.FormatBool(issue.IsAbandoned),
+			strings.Join(issue.AlsoReportedOn, ";"),
+			strings.Join(issue.CWEs, ";"),
+			strings.Join(issue.OWASP, ";"),
+			strings.Join(issue.RepoRisks, ";"),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// groupColumns are the columns written by WriteGroupsCSV
+var groupColumns = []string{
+	"master", "master_title", "master_url", "member_count", "members",
+	"repositories", "reactions", "best_workaround", "score",
+}
+
+// WriteGroupsCSV writes one row per duplicate group with a header row
+func WriteGroupsCSV(w io.Writer, groups []analytics.GroupSummary) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(groupColumns); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, group := range groups {
+		record := []string{
+			group.Master,
+			group.MasterTitle,
+			group.MasterURL,
+			strconv.Itoa(group.MemberCount),
+			strings.Join(group.Members, ";"),
+			strings.Join(group.Repositories, ";"),
+			strconv.Itoa(group.Reactions),
+			group.BestWorkaround,
+			strconv.FormatFloat(group.Score, 'f', 1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteNDJSON streams issues as newline-delimited JSON, one issue per line
+func WriteNDJSON(w io.Writer, issues []model.Issue) error {
+	encoder := json.NewEncoder(w)
+	for _, issue := range issues {
+		if err := encoder.Encode(issue); err != nil {
+			return fmt.Errorf("failed to write NDJSON record: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package pipeline
+
+import "time"
+
+// RunRecord is the persisted outcome of one pipeline run
+type RunRecord struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Results    []Result  `json:"results"`
+}
+
+// StepStats aggregates the results of one step across runs
+type StepStats struct {
+	Step          string
+	Runs          int
+	Failures      int
+	Skipped       int
+	Items         int
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+	LastDuration  time.Duration
+	LastItems     int
+}
+
+// AvgDuration is the mean duration of the runs that executed the step
+func (s StepStats) AvgDuration() time.Duration {
+	if s.Runs == 0 {
+		return 0
+	}
+	return s.TotalDuration / time.Duration(s.Runs)
+}
+
+// ItemsPerSecond is the step's overall throughput
+func (s StepStats) ItemsPerSecond() float64 {
+	if s.TotalDuration <= 0 {
+		return 0
+	}
+	return float64(s.Items) / s.TotalDuration.Seconds()
+}
+
+// Summarize aggregates per-step metrics over runs in the order steps first
+// appear. Steps resumed from an earlier run did no work and are not counted.
+func Summarize(runs []RunRecord) []StepStats {
+	index := make(map[string]int)
+	var stats []StepStats
+
+	for _, run := range runs {
+		for _, result := range run.Results {
+			if result.Status == StatusResumed {
+				continue
+			}
+
+			i, ok := index[result.Step]
+			if !ok {
+				i = len(stats)
+				index[result.Step] = i
+				stats = append(stats, StepStats{Step: result.Step})
+			}
+			step := &stats[i]
+
+			if result.Status == StatusSkipped {
+				step.Skipped++
+				continue
+			}
+
+			step.Runs++
+			if result.Status == StatusFailed {
+				step.Failures++
+			}
+			step.Items += result.Items
+			step.TotalDuration += result.Duration
+			if result.Duration > step.MaxDuration {
+				step.MaxDuration = result.Duration
+			}
+			step.LastDuration = result.Duration
+			step.LastItems = result.Items
+		}
+	}
+	return stats
+}
@@ -0,0 +1,194 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Step statuses reported in results
+const (
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+	StatusResumed = "resumed"
+)
+
+// Step is a named post-ingest processing step. Run returns the number of
+// items it processed.
+type Step struct {
+	Name     string
+	Requires []string
+	Run      func(ctx context.Context) (int, error)
+}
+
+// Result is the outcome of one step in a run
+type Result struct {
+	Step     string        `json:"step"`
+	Status   string        `json:"status"`
+	Items    int           `json:"items"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// State records the progress of the latest run so an interrupted or failed
+// run can be resumed
+type State struct {
+	StartedAt time.Time `json:"started_at"`
+	Steps     []string  `json:"steps"`
+	Completed []string  `json:"completed"`
+	Finished  bool      `json:"finished"`
+}
+
+// Runner runs registered steps in registration order, which must be a
+// dependency order
+type Runner struct {
+	steps []Step
+}
+
+// NewRunner registers steps; a step may only require steps registered before it
+func NewRunner(steps ...Step) (*Runner, error) {
+	known := make(map[string]bool)
+	for _, step := range steps {
+		for _, required := range step.Requires {
+			if !known[required] {
+				return nil, fmt.Errorf("step %s requires %s, which is not registered before it", step.Name, required)
+			}
+		}
+		known[step.Name] = true
+	}
+	return &Runner{steps: steps}, nil
+}
+
+// StepNames returns the registered step names in dependency order
+func (r *Runner) StepNames() []string {
+	names := make([]string, len(r.steps))
+	for i, step := range r.steps {
+		names[i] = step.Name
+	}
+	return names
+}
+
+// Plan returns the selected steps in dependency order (all steps when none are selected)
+func (r *Runner) Plan(selected []string) ([]string, error) {
+	if len(selected) == 0 {
+		return r.StepNames(), nil
+	}
+
+	want := make(map[string]bool)
+	for _, name := range selected {
+		if r.step(name) == nil {
+			return nil, fmt.Errorf("unknown step %q (available: %v)", name, r.StepNames())
+		}
+		want[name] = true
+	}
+
+	var plan []string
+	for _, step := range r.steps {
+		if want[step.Name] {
+			plan = append(plan, step.Name)
+		}
+	}
+	return plan, nil
+}
+
+// Run executes the planned steps. A failing step does not stop the run, but
+// steps that require it are skipped, as are all steps once ctx is cancelled. Steps already completed in state are
+// not run again; state is updated and passed to save after every step.
+func (r *Runner) Run(ctx context.Context, plan []string, state *State, save func(State) error) []Result {
+	completed := make(map[string]bool)
+	for _, name := range state.Completed {
+		completed[name] = true
+	}
+
+	failed := make(map[string]bool)
+	var results []Result
+	for i, name := range plan {
+		step := r.step(name)
+
+		if completed[name] {
+			log.Printf("[%d/%d] %s: already completed, skipping", i+1, len(plan), name)
+			results = append(results, Result{Step: name, Status: StatusResumed})
+			continue
+		}
+
+		if blocker := firstFailed(step.Requires, failed); blocker != "" {
+			log.Printf("[%d/%d] %s: skipped, required step %s failed", i+1, len(plan), name, blocker)
+			failed[name] = true
+			results = append(results, Result{Step: name, Status: StatusSkipped, Error: "requires failed step " + blocker})
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			log.Printf("[%d/%d] %s: skipped, run cancelled", i+1, len(plan), name)
+			failed[name] = true
+			results = append(results, Result{Step: name, Status: StatusSkipped, Error: err.Error()})
+			continue
+		}
+
+		log.Printf("[%d/%d] %s: running", i+1, len(plan), name)
+		result := runStep(ctx, *step)
+		results = append(results, result)
+
+		if result.Status == StatusFailed {
+			failed[name] = true
+			log.Printf("[%d/%d] %s: failed after %s: %s", i+1, len(plan), name, result.Duration.Round(time.Millisecond), result.Error)
+			continue
+		}
+
+		log.Printf("[%d/%d] %s: %d items in %s", i+1, len(plan), name, result.Items, result.Duration.Round(time.Millisecond))
+		state.Completed = append(state.Completed, name)
+		if err := save(*state); err != nil {
+			log.Printf("Error saving pipeline state: %v", err)
+		}
+	}
+
+	state.Finished = len(failed) == 0
+	if err := save(*state); err != nil {
+		log.Printf("Error saving pipeline state: %v", err)
+	}
+	return results
+}
+
+// runStep runs a single step, turning a panic into a failure
+func runStep(ctx context.Context, step Step) (result Result) {
+	result.Step = step.Name
+	start := time.Now()
+
+	defer func() {
+		result.Duration = time.Since(start)
+		if recovered := recover(); recovered != nil {
+			result.Status = StatusFailed
+			result.Error = fmt.Sprintf("panic: %v", recovered)
+		}
+	}()
+
+	items, err := step.Run(ctx)
+	result.Items = items
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		return result
+	}
+	result.Status = StatusDone
+	return result
+}
+
+func (r *Runner) step(name string) *Step {
+	for i := range r.steps {
+		if r.steps[i].Name == name {
+			return &r.steps[i]
+		}
+	}
+	return nil
+}
+
+func firstFailed(names []string, failed map[string]bool) string {
+	for _, name := range names {
+		if failed[name] {
+			return name
+		}
+	}
+	return ""
+}
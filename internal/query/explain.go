@@ -0,0 +1,124 @@
+package query
+
+import (
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// How a clause combines with the rest of the search
+const (
+	// ClauseRequired must hold for an issue to match
+	ClauseRequired = "required"
+	// ClauseExcluded must not hold for an issue to match
+	ClauseExcluded = "excluded"
+	// ClauseAnyOf must hold for at least one clause of the same group
+	ClauseAnyOf = "any_of"
+)
+
+// ClauseStat is one clause of a search and how many scanned issues it
+// matches on its own
+type ClauseStat struct {
+	Clause  string `json:"clause"`
+	Kind    string `json:"kind"`
+	Group   string `json:"group,omitempty"`
+	Matched int    `json:"matched"`
+}
+
+// Explanation breaks a search down into its clauses and reports how
+// selective each one is over the scanned issues
+type Explanation struct {
+	Mode     string        `json:"mode"`
+	Clauses  []ClauseStat  `json:"clauses"`
+	Scanned  int           `json:"scanned"`
+	Matched  int           `json:"matched"`
+	Duration time.Duration `json:"duration"`
+}
+
+// String formats the condition in query syntax
+func (c Condition) String() string {
+	op := c.Op
+	if op == ":" {
+		op = ""
+	}
+	value := c.Value
+	if strings.ContainsAny(value, " \t") {
+		value = `"` + value + `"`
+	}
+	prefix := ""
+	if c.Negate {
+		prefix = "-"
+	}
+	return prefix + c.Field + ":" + op + value
+}
+
+// Explain evaluates every clause of the search separately over issues, in
+// the order Match applies them, and times the full match
+func (s AdvancedSearch) Explain(issues map[string][]model.Issue) Explanation {
+	type clause struct {
+		stat  ClauseStat
+		match func(model.Issue, string) bool
+	}
+
+	mode := s.Mode
+	if mode == "" {
+		mode = MatchAny
+	}
+	var clauses []clause
+	if len(s.Terms) > 0 {
+		terms := AdvancedSearch{Terms: s.Terms, Mode: s.Mode}
+		clauses = append(clauses, clause{
+			stat:  ClauseStat{Clause: strings.Join(s.Terms, " "), Kind: ClauseRequired, Group: "text:" + mode},
+			match: func(_ model.Issue, text string) bool { return terms.matchTerms(text) },
+		})
+	}
+	for _, phrase := range s.Phrases {
+		phrase := strings.ToLower(phrase)
+		clauses = append(clauses, clause{
+			stat:  ClauseStat{Clause: `"` + phrase + `"`, Kind: ClauseRequired},
+			match: func(_ model.Issue, text string) bool { return strings.Contains(text, phrase) },
+		})
+	}
+	for _, excluded := range s.Excluded {
+		excluded := strings.ToLower(excluded)
+		clauses = append(clauses, clause{
+			stat:  ClauseStat{Clause: "-" + excluded, Kind: ClauseExcluded},
+			match: func(_ model.Issue, text string) bool { return strings.Contains(text, excluded) },
+		})
+	}
+	for _, condition := range s.Conditions {
+		condition := condition
+		stat := ClauseStat{Clause: condition.String(), Kind: ClauseRequired}
+		if condition.Negate {
+			stat.Kind = ClauseExcluded
+		} else if !numericFields[condition.Field] && (!multiValueFields[condition.Field] || s.Mode == MatchAny) {
+			stat.Kind, stat.Group = ClauseAnyOf, condition.Field
+		}
+		clauses = append(clauses, clause{
+			stat:  stat,
+			match: func(issue model.Issue, _ string) bool { return condition.matches(issue) },
+		})
+	}
+
+	explanation := Explanation{Mode: mode}
+	for _, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			explanation.Scanned++
+			text := strings.ToLower(issue.Title + " " + issue.SearchText())
+			for i := range clauses {
+				if clauses[i].match(issue, text) {
+					clauses[i].stat.Matched++
+				}
+			}
+		}
+	}
+	for _, c := range clauses {
+		explanation.Clauses = append(explanation.Clauses, c.stat)
+	}
+
+	start := time.Now()
+	explanation.Matched = len(s.Filter(issues))
+	explanation.Duration = time.Since(start)
+	return explanation
+}
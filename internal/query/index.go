@@ -0,0 +1,146 @@
+package query
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// BM25 parameters: term frequency saturation and document length normalization
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Index holds the corpus statistics for ranking free-text matches by BM25
+// over issue titles, bodies and attached comments, title tokens counting
+// twice. It is built from the corpus a search runs on, so it always
+// reflects the stored issues; the statistics are computed on first use.
+type Index struct {
+	corpus    map[string][]model.Issue
+	once      sync.Once
+	docs      int
+	avgLength float64
+	docFreq   map[string]int
+}
+
+// NewIndex creates an index of a corpus
+func NewIndex(issues map[string][]model.Issue) *Index {
+	return &Index{corpus: issues}
+}
+
+// build computes the document frequencies and average length
+func (x *Index) build() {
+	x.docFreq = make(map[string]int)
+	total := 0
+	for _, repoIssues := range x.corpus {
+		for _, issue := range repoIssues {
+			tokens := issueTokens(issue)
+			seen := make(map[string]bool, len(tokens))
+			for _, token := range tokens {
+				if !seen[token] {
+					seen[token] = true
+					x.docFreq[token]++
+				}
+			}
+			x.docs++
+			total += len(tokens)
+		}
+	}
+	if x.docs > 0 {
+		x.avgLength = float64(total) / float64(x.docs)
+	}
+}
+
+// Sort orders matched issues like AdvancedSearch.Sort, but ranks the text
+// component of relevance by BM25, relative to the best matching issue
+func (x *Index) Sort(s AdvancedSearch, issues []model.Issue, sortBy string, weights RelevanceWeights, now time.Time) {
+	if sortBy != SortRelevance || !s.HasText() {
+		s.Sort(issues, sortBy, weights, now)
+		return
+	}
+
+	x.once.Do(x.build)
+	needles := append(append([]string{}, s.Terms...), s.Phrases...)
+	scores := make([]float64, len(issues))
+	best := 0.0
+	for i, issue := range issues {
+		scores[i] = x.bm25(issue, needles)
+		best = math.Max(best, scores[i])
+	}
+	keys := make([]float64, len(issues))
+	for i, issue := range issues {
+		text := 0.0
+		if best > 0 {
+			text = scores[i] / best
+		}
+		keys[i] = s.relevance(issue, text, weights, now)
+	}
+	sort.Stable(byKey{issues: issues, keys: keys})
+}
+
+// bm25 scores an issue for the needles. Like matching, a single-word needle
+// counts every token containing it, so "oom" also scores "oomkilled".
+func (x *Index) bm25(issue model.Issue, needles []string) float64 {
+	tokens := issueTokens(issue)
+	length := float64(len(tokens))
+	norm := bm25K1
+	if x.avgLength > 0 {
+		norm = bm25K1 * (1 - bm25B + bm25B*length/x.avgLength)
+	}
+
+	var score float64
+	for _, needle := range needles {
+		needle = strings.ToLower(needle)
+		var tf float64
+		if words := textTokens(needle); len(words) == 1 && words[0] == needle {
+			for _, token := range tokens {
+				if strings.Contains(token, needle) {
+					tf++
+				}
+			}
+		} else {
+			tf = float64(strings.Count(strings.Join(tokens, " "), strings.Join(words, " ")))
+		}
+		if tf == 0 {
+			continue
+		}
+		score += x.idf(needle) * tf * (bm25K1 + 1) / (tf + norm)
+	}
+	return score
+}
+
+// idf returns the inverse document frequency of a needle; the documents of
+// all tokens containing it are counted, as an upper bound
+func (x *Index) idf(needle string) float64 {
+	df, ok := x.docFreq[needle]
+	if !ok {
+		for token, n := range x.docFreq {
+			if strings.Contains(token, needle) {
+				df += n
+			}
+		}
+	}
+	df = min(df, x.docs)
+	return math.Log(1 + (float64(x.docs)-float64(df)+0.5)/(float64(df)+0.5))
+}
+
+// issueTokens returns the indexed tokens of an issue, the title twice
+func issueTokens(issue model.Issue) []string {
+	title := textTokens(issue.Title)
+	tokens := append(append([]string{}, title...), title...)
+	tokens = append(tokens, textTokens(issue.SearchText())...)
+	return append(tokens, textTokens(issue.DiscussionText())...)
+}
+
+// textTokens splits lowercased text into runs of letters and digits
+func textTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
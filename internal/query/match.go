@@ -0,0 +1,230 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// multiValueFields are fields where an issue can hold several values;
+// repeating such a qualifier requires all values unless the match mode is
+// any. Repeating any other qualifier (repo:a repo:b) matches either value.
+var multiValueFields = map[string]bool{
+	"label":     true,
+	"platform":  true,
+	"cwe":       true,
+	"owasp":     true,
+	"portfolio": true,
+	"is":        true,
+}
+
+// Match reports whether an issue satisfies the search
+func (s AdvancedSearch) Match(issue model.Issue) bool {
+	text := strings.ToLower(issue.Title + " " + issue.SearchText() + " " + issue.DiscussionText())
+
+	if !s.matchTerms(text) {
+		return false
+	}
+	for _, phrase := range s.Phrases {
+		if !strings.Contains(text, strings.ToLower(phrase)) {
+			return false
+		}
+	}
+	for _, excluded := range s.Excluded {
+		if strings.Contains(text, strings.ToLower(excluded)) {
+			return false
+		}
+	}
+
+	anyOf := make(map[string]bool)
+	anyOfMatched := make(map[string]bool)
+
+	for _, condition := range s.Conditions {
+		matched := condition.matches(issue)
+
+		switch {
+		case condition.Negate:
+			if matched {
+				return false
+			}
+		case numericFields[condition.Field] || (multiValueFields[condition.Field] && s.Mode != MatchAny):
+			if !matched {
+				return false
+			}
+		default:
+			anyOf[condition.Field] = true
+			if matched {
+				anyOfMatched[condition.Field] = true
+			}
+		}
+	}
+
+	for field := range anyOf {
+		if !anyOfMatched[field] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchTerms applies the match mode to the free-text terms. Without a mode,
+// any term matches.
+func (s AdvancedSearch) matchTerms(text string) bool {
+	if len(s.Terms) == 0 {
+		return true
+	}
+
+	switch s.Mode {
+	case MatchAll:
+		for _, term := range s.Terms {
+			if !strings.Contains(text, strings.ToLower(term)) {
+				return false
+			}
+		}
+		return true
+	case MatchPhrase:
+		return strings.Contains(text, strings.ToLower(strings.Join(s.Terms, " ")))
+	default:
+		for _, term := range s.Terms {
+			if strings.Contains(text, strings.ToLower(term)) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Filter returns the issues matching the search
+func (s AdvancedSearch) Filter(issues map[string][]model.Issue) []model.Issue {
+	var matched []model.Issue
+	for _, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			if s.Match(issue) {
+				matched = append(matched, issue)
+			}
+		}
+	}
+	return matched
+}
+
+// matches evaluates a single condition, ignoring negation
+func (c Condition) matches(issue model.Issue) bool {
+	if numericFields[c.Field] {
+		return compare(numericValue(issue, c.Field), c.Op, c.Value)
+	}
+
+	if key, ok := strings.CutPrefix(c.Field, formFieldPrefix); ok {
+		value, exists := issue.StructuredFields[key]
+		return exists && strings.Contains(strings.ToLower(value), strings.ToLower(c.Value))
+	}
+
+	for _, value := range fieldValues(issue, c.Field) {
+		if matchValue(value, c.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchValue compares a field value with a qualifier value ignoring case.
+// A "*" in the qualifier matches any run of characters, so repo:vllm-project/*
+// matches a prefix and author:*bot* a substring.
+func matchValue(value, pattern string) bool {
+	if !strings.Contains(pattern, "*") {
+		return strings.EqualFold(value, pattern)
+	}
+
+	value, pattern = strings.ToLower(value), strings.ToLower(pattern)
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
+
+// fieldValues returns the string values of an issue field
+func fieldValues(issue model.Issue, field string) []string {
+	switch field {
+	case "repo":
+		return []string{issue.Repository}
+	case "category":
+		return []string{issue.Category}
+	case "state":
+		return []string{issue.State}
+	case "team":
+		return []string{issue.AssignedTeam}
+	case "author":
+		return []string{issue.Author}
+	case "priority":
+		return []string{issue.Priority}
+	case "source":
+		return []string{issue.SourceName()}
+	case "platform":
+		return issue.Platforms
+	case "cwe":
+		return issue.CWEs
+	case "owasp":
+		return issue.OWASP
+	case "type":
+		return []string{issue.Type}
+	case "portfolio":
+		return issue.Portfolios
+	case "is":
+		var flags []string
+		if issue.IsAbandoned {
+			flags = append(flags, "abandoned")
+		}
+		return flags
+	case "label":
+		labels := make([]string, len(issue.Labels))
+		for i, label := range issue.Labels {
+			labels[i] = label.Name
+		}
+		return labels
+	}
+	return nil
+}
+
+// numericValue returns the numeric value of an issue field
+func numericValue(issue model.Issue, field string) float64 {
+	switch field {
+	case "score":
+		return issue.Score
+	case "comments":
+		return float64(issue.Comments)
+	case "reactions":
+		return float64(issue.Reactions)
+	}
+	return 0
+}
+
+func compare(actual float64, op, value string) bool {
+	expected, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+
+	switch op {
+	case ">":
+		return actual > expected
+	case ">=":
+		return actual >= expected
+	case "<":
+		return actual < expected
+	case "<=":
+		return actual <= expected
+	default:
+		return actual == expected
+	}
+}
@@ -0,0 +1,228 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Condition is a single field qualifier such as label:regression or score:>20
+type Condition struct {
+	Field  string
+	Op     string // ":", ">", ">=", "<", "<="
+	Value  string
+	Negate bool
+}
+
+// AdvancedSearch is a parsed search query
+type AdvancedSearch struct {
+	Conditions []Condition
+	Terms      []string
+	Phrases    []string
+	Excluded   []string
+	Mode       string
+}
+
+// Match modes for free-text terms and repeated label/platform qualifiers
+const (
+	// MatchAll requires every term and every repeated label/platform
+	MatchAll = "all"
+	// MatchAny requires at least one term and one of the repeated labels/platforms
+	MatchAny = "any"
+	// MatchPhrase requires the terms to appear together, in order
+	MatchPhrase = "phrase"
+)
+
+// MatchModes lists the accepted match modes
+var MatchModes = []string{MatchAll, MatchAny, MatchPhrase}
+
+// ValidMatchMode reports whether mode is a known match mode
+func ValidMatchMode(mode string) bool {
+	for _, known := range MatchModes {
+		if mode == known {
+			return true
+		}
+	}
+	return false
+}
+
+// numericFields are fields compared with comparison operators
+var numericFields = map[string]bool{
+	"score":     true,
+	"comments":  true,
+	"reactions": true,
+}
+
+// textFields are fields matched against string values
+var textFields = map[string]bool{
+	"repo":      true,
+	"category":  true,
+	"label":     true,
+	"state":     true,
+	"team":      true,
+	"author":    true,
+	"platform":  true,
+	"priority":  true,
+	"source":    true,
+	"cwe":       true,
+	"owasp":     true,
+	"type":      true,
+	"portfolio": true,
+	"is":        true,
+}
+
+// issueFlags are the values of the is: qualifier
+var issueFlags = map[string]bool{
+	"abandoned": true,
+}
+
+// formFieldPrefix prefixes qualifiers on issue-form fields, e.g. form.version:0.4
+const formFieldPrefix = "form."
+
+// Parse parses the compact query syntax, e.g.
+//
+//	repo:golang/go category:performance score:>20 label:regression "memory leak" -label:question
+//
+// Free-text terms match in the mode set with match:all|any|phrase; by
+// default any term matches and relevance ranking favours issues with more.
+// Qualifier values match case-insensitively and may use "*" wildcards for
+// prefix or substring matches (repo:vllm-project/* author:*bot*).
+// Issue-form fields are matched with form.<field>:value, where value is a
+// case-insensitive substring of the field (form.operating_system:ubuntu).
+// is:abandoned matches issues flagged as abandoned.
+// Bare words and quoted phrases match title and body text; a leading "-"
+// negates a qualifier, word or phrase.
+func Parse(input string) (AdvancedSearch, error) {
+	var search AdvancedSearch
+
+	tokens, err := tokenize(input)
+	if err != nil {
+		return search, err
+	}
+
+	for _, tok := range tokens {
+		if tok.quoted {
+			if tok.negate {
+				search.Excluded = append(search.Excluded, tok.text)
+			} else {
+				search.Phrases = append(search.Phrases, tok.text)
+			}
+			continue
+		}
+
+		field, value, ok := strings.Cut(tok.text, ":")
+		if !ok || field == "" {
+			if tok.negate {
+				search.Excluded = append(search.Excluded, tok.text)
+			} else {
+				search.Terms = append(search.Terms, tok.text)
+			}
+			continue
+		}
+
+		if strings.EqualFold(field, "match") && !tok.negate {
+			if !ValidMatchMode(strings.ToLower(value)) {
+				return search, fmt.Errorf("invalid match mode %q (expected one of %v)", value, MatchModes)
+			}
+			search.Mode = strings.ToLower(value)
+			continue
+		}
+
+		condition, err := parseCondition(strings.ToLower(field), value, tok.negate)
+		if err != nil {
+			return search, err
+		}
+		search.Conditions = append(search.Conditions, condition)
+	}
+
+	return search, nil
+}
+
+// parseCondition validates a field qualifier
+func parseCondition(field, value string, negate bool) (Condition, error) {
+	condition := Condition{Field: field, Op: ":", Value: value, Negate: negate}
+
+	switch {
+	case numericFields[field]:
+		for _, op := range []string{">=", "<=", ">", "<", "="} {
+			if strings.HasPrefix(value, op) {
+				condition.Op = op
+				condition.Value = strings.TrimPrefix(value, op)
+				break
+			}
+		}
+		if condition.Op == "=" {
+			condition.Op = ":"
+		}
+		if _, err := strconv.ParseFloat(condition.Value, 64); err != nil {
+			return condition, fmt.Errorf("invalid number for %s: %q", field, condition.Value)
+		}
+	case textFields[field] || (strings.HasPrefix(field, formFieldPrefix) && len(field) > len(formFieldPrefix)):
+		if value == "" {
+			return condition, fmt.Errorf("missing value for %s", field)
+		}
+		if field == "is" && !issueFlags[strings.ToLower(value)] {
+			return condition, fmt.Errorf("unknown value for is: %q (expected abandoned)", value)
+		}
+	default:
+		return condition, fmt.Errorf("unknown search field: %s", field)
+	}
+
+	return condition, nil
+}
+
+type token struct {
+	text   string
+	quoted bool
+	negate bool
+}
+
+// tokenize splits input on whitespace, keeping quoted phrases together.
+// A quoted value directly after a qualifier (label:"good first issue")
+// stays part of that qualifier.
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		tok := token{}
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			tok.negate = true
+			i++
+		}
+
+		var b strings.Builder
+		for i < len(runes) && !unicode.IsSpace(runes[i]) {
+			if runes[i] == '"' {
+				end := i + 1
+				for end < len(runes) && runes[end] != '"' {
+					end++
+				}
+				if end == len(runes) {
+					return nil, fmt.Errorf("unterminated quote in query")
+				}
+				if b.Len() == 0 {
+					tok.quoted = true
+				}
+				b.WriteString(string(runes[i+1 : end]))
+				i = end + 1
+				continue
+			}
+			b.WriteRune(runes[i])
+			i++
+		}
+
+		tok.text = b.String()
+		if tok.text != "" {
+			tokens = append(tokens, tok)
+		}
+	}
+
+	return tokens, nil
+}
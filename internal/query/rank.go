@@ -0,0 +1,126 @@
+package query
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// Sort orders accepted by Sort
+const (
+	SortRelevance = "relevance"
+	SortScore     = "score"
+	SortUpdated   = "updated"
+	SortCreated   = "created"
+)
+
+// recencyHalfLife is the age at which the recency component halves
+const recencyHalfLife = 90 * 24 * time.Hour
+
+// RelevanceWeights controls how relevance ranking combines its components
+type RelevanceWeights struct {
+	Text    float64
+	Score   float64
+	Recency float64
+	Phrase  float64
+}
+
+// DefaultRelevanceWeights favours text matches, then pitfall score
+var DefaultRelevanceWeights = RelevanceWeights{Text: 0.5, Score: 0.3, Recency: 0.1, Phrase: 0.1}
+
+// HasText reports whether the search contains free-text terms or phrases
+func (s AdvancedSearch) HasText() bool {
+	return len(s.Terms) > 0 || len(s.Phrases) > 0
+}
+
+// Relevance computes a 0-1 relevance of an issue for the search
+func (s AdvancedSearch) Relevance(issue model.Issue, weights RelevanceWeights, now time.Time) float64 {
+	title := strings.ToLower(issue.Title)
+	body := strings.ToLower(issue.SearchText() + " " + issue.DiscussionText())
+
+	// Text: saturated term frequency, title hits counting double
+	var text float64
+	needles := append(append([]string{}, s.Terms...), s.Phrases...)
+	for _, needle := range needles {
+		needle = strings.ToLower(needle)
+		tf := float64(2*strings.Count(title, needle) + strings.Count(body, needle))
+		text += tf / (tf + 1)
+	}
+	if len(needles) > 0 {
+		text /= float64(len(needles))
+	}
+	return s.relevance(issue, text, weights, now)
+}
+
+// relevance combines a 0-1 text relevance with the phrase, score and
+// recency components
+func (s AdvancedSearch) relevance(issue model.Issue, text float64, weights RelevanceWeights, now time.Time) float64 {
+	title := strings.ToLower(issue.Title)
+	body := strings.ToLower(issue.SearchText() + " " + issue.DiscussionText())
+
+	// Phrase: the whole free-text query appears verbatim
+	var phrase float64
+	if len(s.Terms) > 1 {
+		joined := strings.ToLower(strings.Join(s.Terms, " "))
+		if strings.Contains(title, joined) {
+			phrase = 1
+		} else if strings.Contains(body, joined) {
+			phrase = 0.5
+		}
+	} else if len(s.Phrases) > 0 {
+		for _, p := range s.Phrases {
+			if strings.Contains(title, strings.ToLower(p)) {
+				phrase = 1
+				break
+			}
+		}
+	}
+
+	score := math.Min(issue.Score/100, 1)
+
+	var recency float64
+	if !issue.UpdatedAt.IsZero() {
+		age := now.Sub(issue.UpdatedAt)
+		recency = math.Pow(0.5, float64(age)/float64(recencyHalfLife))
+	}
+
+	total := weights.Text + weights.Score + weights.Recency + weights.Phrase
+	if total <= 0 {
+		return 0
+	}
+	return (weights.Text*text + weights.Score*score + weights.Recency*recency + weights.Phrase*phrase) / total
+}
+
+// Sort orders matched issues in place
+func (s AdvancedSearch) Sort(issues []model.Issue, sortBy string, weights RelevanceWeights, now time.Time) {
+	switch sortBy {
+	case SortRelevance:
+		keys := make([]float64, len(issues))
+		for i := range issues {
+			keys[i] = s.Relevance(issues[i], weights, now)
+		}
+		sort.Stable(byKey{issues: issues, keys: keys})
+	case SortUpdated:
+		sort.SliceStable(issues, func(i, j int) bool { return issues[i].UpdatedAt.After(issues[j].UpdatedAt) })
+	case SortCreated:
+		sort.SliceStable(issues, func(i, j int) bool { return issues[i].CreatedAt.After(issues[j].CreatedAt) })
+	default:
+		sort.SliceStable(issues, func(i, j int) bool { return issues[i].Score > issues[j].Score })
+	}
+}
+
+// byKey sorts issues by a parallel slice of keys, descending
+type byKey struct {
+	issues []model.Issue
+	keys   []float64
+}
+
+func (b byKey) Len() int           { return len(b.issues) }
+func (b byKey) Less(i, j int) bool { return b.keys[i] > b.keys[j] }
+func (b byKey) Swap(i, j int) {
+	b.issues[i], b.issues[j] = b.issues[j], b.issues[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
@@ -0,0 +1,60 @@
+package scraper
+
+import (
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// AbandonedConfig represents abandoned-issue detection configuration
+type AbandonedConfig struct {
+	NoResponseDays int      `yaml:"no_response_days"`
+	StaleBots      []string `yaml:"stale_bots"`
+}
+
+// AbandonedDetector flags issues that never got an upstream acknowledgment
+type AbandonedDetector struct {
+	noResponse time.Duration
+	staleBots  []string
+}
+
+// NewAbandonedDetector creates a detector from configuration
+func NewAbandonedDetector(config AbandonedConfig) *AbandonedDetector {
+	return &AbandonedDetector{
+		noResponse: time.Duration(config.NoResponseDays) * 24 * time.Hour,
+		staleBots:  config.StaleBots,
+	}
+}
+
+// IsAbandoned reports whether an issue was left without acknowledgment:
+// open with no comments for longer than the no-response window, or closed
+// as not planned by a stale bot or with a stale label.
+func (d *AbandonedDetector) IsAbandoned(issue model.Issue) bool {
+	if issue.State == "open" {
+		return d.noResponse > 0 && issue.Comments == 0 && time.Since(issue.CreatedAt) > d.noResponse
+	}
+
+	if issue.StateReason != "not_planned" {
+		return false
+	}
+
+	if containsFold(d.staleBots, issue.ClosedBy) {
+		return true
+	}
+
+	for _, label := range issue.Labels {
+		if strings.Contains(strings.ToLower(label.Name), "stale") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MarkIssues sets IsAbandoned on every issue in place
+func (d *AbandonedDetector) MarkIssues(issues []model.Issue) {
+	for i := range issues {
+		issues[i].IsAbandoned = d.IsAbandoned(issues[i])
+	}
+}
@@ -0,0 +1,178 @@
+package scraper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// calibrationBudget is the total points the calibrated component weights
+// share, as the default weights do, so tuned scores keep their 0-100 range
+const calibrationBudget = 100.0
+
+// RelevanceLabel is a human judgment of whether an issue is a pitfall worth keeping
+type RelevanceLabel struct {
+	Repository string
+	Number     int
+	Relevant   bool
+}
+
+// CalibrationSample is a labeled issue broken into score components
+type CalibrationSample struct {
+	Shares   ScoreComponents
+	Fixed    float64
+	Relevant bool
+}
+
+// Evaluation is the confusion matrix of a score threshold against labels
+type Evaluation struct {
+	TruePositives  int
+	FalsePositives int
+	FalseNegatives int
+	TrueNegatives  int
+}
+
+// Precision is the share of kept issues labeled relevant
+func (e Evaluation) Precision() float64 {
+	return ratio(e.TruePositives, e.TruePositives+e.FalsePositives)
+}
+
+// Recall is the share of relevant issues kept
+func (e Evaluation) Recall() float64 {
+	return ratio(e.TruePositives, e.TruePositives+e.FalseNegatives)
+}
+
+// F1 is the harmonic mean of precision and recall
+func (e Evaluation) F1() float64 {
+	p, r := e.Precision(), e.Recall()
+	if p+r == 0 {
+		return 0
+	}
+	return 2 * p * r / (p + r)
+}
+
+// ReadRelevanceLabels reads a CSV with a header naming the columns
+// repository, number and relevant (1/0, true/false, yes/no)
+func ReadRelevanceLabels(r io.Reader) ([]RelevanceLabel, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"repository", "number", "relevant"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing %q column", name)
+		}
+	}
+
+	var labels []RelevanceLabel
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		number, err := strconv.Atoi(strings.TrimSpace(record[columns["number"]]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid number %q", line, record[columns["number"]])
+		}
+		relevant, err := parseJudgment(record[columns["relevant"]])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		labels = append(labels, RelevanceLabel{
+			Repository: strings.TrimSpace(record[columns["repository"]]),
+			Number:     number,
+			Relevant:   relevant,
+		})
+	}
+	return labels, nil
+}
+
+// Evaluate scores the samples with weights and compares keeping those at
+// or above threshold with the labels
+func Evaluate(samples []CalibrationSample, weights ScoreComponents, threshold float64) Evaluation {
+	var e Evaluation
+	for _, sample := range samples {
+		kept := sample.Shares.Dot(weights)+sample.Fixed >= threshold
+		switch {
+		case kept && sample.Relevant:
+			e.TruePositives++
+		case kept:
+			e.FalsePositives++
+		case sample.Relevant:
+			e.FalseNegatives++
+		default:
+			e.TrueNegatives++
+		}
+	}
+	return e
+}
+
+// Calibrate grid-searches component weights in multiples of step that
+// share a 100-point budget, returning the weights with the best F1 at
+// threshold. Ties go to the weights closest to current, so calibration
+// only moves weights the labels give a reason to move.
+func Calibrate(samples []CalibrationSample, current ScoreComponents, threshold, step float64) (ScoreComponents, Evaluation) {
+	best := current
+	bestEval := Evaluate(samples, current, threshold)
+	bestDistance := 0.0
+
+	units := int(calibrationBudget / step)
+	for k := 0; k <= units; k++ {
+		for p := 0; k+p <= units; p++ {
+			for l := 0; k+p+l <= units; l++ {
+				for s := 0; k+p+l+s <= units; s++ {
+					a := units - k - p - l - s
+					weights := ScoreComponents{
+						Keyword:  float64(k) * step,
+						Pattern:  float64(p) * step,
+						Label:    float64(l) * step,
+						Status:   float64(s) * step,
+						Activity: float64(a) * step,
+					}
+					eval := Evaluate(samples, weights, threshold)
+					distance := weightDistance(weights, current)
+					if f1, bestF1 := eval.F1(), bestEval.F1(); f1 > bestF1+1e-9 || (math.Abs(f1-bestF1) <= 1e-9 && distance < bestDistance) {
+						best, bestEval, bestDistance = weights, eval, distance
+					}
+				}
+			}
+		}
+	}
+	return best, bestEval
+}
+
+// parseJudgment parses a relevance judgment
+func parseJudgment(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "1", "true", "yes", "y", "relevant":
+		return true, nil
+	case "0", "false", "no", "n", "irrelevant":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid relevance judgment %q", value)
+}
+
+func weightDistance(a, b ScoreComponents) float64 {
+	return math.Abs(a.Keyword-b.Keyword) + math.Abs(a.Pattern-b.Pattern) + math.Abs(a.Label-b.Label) +
+		math.Abs(a.Status-b.Status) + math.Abs(a.Activity-b.Activity)
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
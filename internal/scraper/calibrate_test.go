@@ -0,0 +1,83 @@
+package scraper
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestReadRelevanceLabels(t *testing.T) {
+	labels, err := ReadRelevanceLabels(strings.NewReader("Relevant, Number, repository, note\nyes, 12, acme/infer, crash\n0, 7, acme/train,\nrelevant, 3, acme/infer, \n"))
+	if err != nil {
+		t.Fatalf("Failed to read labels: %v", err)
+	}
+	expected := []RelevanceLabel{{"acme/infer", 12, true}, {"acme/train", 7, false}, {"acme/infer", 3, true}}
+	if len(labels) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, labels)
+	}
+	for i := range expected {
+		if labels[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], labels[i])
+		}
+	}
+
+	for input, message := range map[string]string{
+		"repository,number\nacme/infer,1\n":                `missing "relevant" column`,
+		"repository,number,relevant\nacme/infer,x,1\n":     `line 2: invalid number "x"`,
+		"repository,number,relevant\nacme/infer,1,maybe\n": `line 2: invalid relevance judgment "maybe"`,
+	} {
+		if _, err := ReadRelevanceLabels(strings.NewReader(input)); err == nil || err.Error() != message {
+			t.Errorf("Expected %q reading %q, got %v", message, input, err)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	samples := []CalibrationSample{
+		{Shares: ScoreComponents{Keyword: 1, Pattern: 1}, Relevant: true},  // 55: kept
+		{Shares: ScoreComponents{Keyword: 1}, Fixed: 25, Relevant: true},   // 55 with fixed points: kept
+		{Shares: ScoreComponents{Label: 1}, Relevant: true},                // 20: missed
+		{Shares: ScoreComponents{Pattern: 1, Label: 1}, Relevant: false},   // 45: kept
+		{Shares: ScoreComponents{Status: 1, Activity: 1}, Relevant: false}, // 25: dropped
+	}
+	e := Evaluate(samples, DefaultScoreWeights, 45)
+	if e != (Evaluation{TruePositives: 2, FalsePositives: 1, FalseNegatives: 1, TrueNegatives: 1}) {
+		t.Fatalf("Unexpected evaluation %+v", e)
+	}
+	if math.Abs(e.Precision()-2.0/3) > 1e-9 || math.Abs(e.Recall()-2.0/3) > 1e-9 || math.Abs(e.F1()-2.0/3) > 1e-9 {
+		t.Errorf("Expected precision, recall and F1 of 2/3, got %.3f, %.3f and %.3f", e.Precision(), e.Recall(), e.F1())
+	}
+	if f1 := (Evaluation{TrueNegatives: 3}).F1(); f1 != 0 {
+		t.Errorf("Expected F1 0 without positives, got %.3f", f1)
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	// Labels are what tells pitfalls apart here, keywords are noise
+	samples := []CalibrationSample{
+		{Shares: ScoreComponents{Label: 1}, Relevant: true},
+		{Shares: ScoreComponents{Label: 0.8, Keyword: 0.2}, Relevant: true},
+		{Shares: ScoreComponents{Keyword: 1}, Relevant: false},
+		{Shares: ScoreComponents{Keyword: 0.8}, Relevant: false},
+	}
+	if f1 := Evaluate(samples, DefaultScoreWeights, 40).F1(); f1 != 0 {
+		t.Fatalf("Expected the default weights to miss every pitfall, got F1 %.3f", f1)
+	}
+
+	weights, e := Calibrate(samples, DefaultScoreWeights, 40, 10)
+	if e.F1() != 1 {
+		t.Errorf("Expected calibrated weights to separate the samples, got %+v with %+v", weights, e)
+	}
+	total := weights.Keyword + weights.Pattern + weights.Label + weights.Status + weights.Activity
+	if math.Abs(total-calibrationBudget) > 1e-9 {
+		t.Errorf("Expected the weights to share %.0f points, got %.1f", calibrationBudget, total)
+	}
+	if weights.Label <= DefaultScoreWeights.Label || weights.Keyword > DefaultScoreWeights.Keyword {
+		t.Errorf("Expected more weight on labels and none added to keywords, got %+v", weights)
+	}
+
+	// Weights the labels give no reason to change are kept
+	if kept, _ := Calibrate(samples, weights, 40, 10); kept != weights {
+		t.Errorf("Expected the calibrated weights %+v kept, got %+v", weights, kept)
+	}
+}
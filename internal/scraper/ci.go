@@ -0,0 +1,19 @@
+package scraper
+
+import "github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+
+// CIConfig decides which pitfalls the ci command reports and when it fails
+type CIConfig struct {
+	MinScore    float64 `yaml:"min_score"`    // pitfalls scoring at least this are high severity
+	MinPriority string  `yaml:"min_priority"` // as are pitfalls with at least this upstream priority
+	MaxNew      int     `yaml:"max_new"`      // fail above this many new high-severity pitfalls
+}
+
+// HighSeverity reports whether an issue is severe enough for CI
+func (c CIConfig) HighSeverity(issue model.Issue) bool {
+	if issue.Score >= c.MinScore {
+		return true
+	}
+	rank := priorityRank(issue.Priority)
+	return rank >= 0 && c.MinPriority != "" && rank <= priorityRank(c.MinPriority)
+}
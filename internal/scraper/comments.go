@@ -0,0 +1,108 @@
+package scraper
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// CommentConfig configures scraping of issue comments
+type CommentConfig struct {
+	Enabled           bool `yaml:"enabled"`
+	MaxPerIssue       int  `yaml:"max_per_issue"`       // 0 = all
+	RequestsPerMinute int  `yaml:"requests_per_minute"` // comment fetches only; 0 = unlimited
+}
+
+// commentLimiter spaces comment fetches evenly, independently of the
+// politeness limits of other requests
+type commentLimiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	next     time.Time
+}
+
+// newCommentLimiter allows perMinute fetches per minute (0 = unlimited)
+func newCommentLimiter(perMinute int) *commentLimiter {
+	if perMinute <= 0 {
+		return &commentLimiter{}
+	}
+	return &commentLimiter{interval: time.Minute / time.Duration(perMinute)}
+}
+
+// wait blocks until the next fetch is allowed
+func (l *commentLimiter) wait(ctx context.Context) error {
+	if l.interval <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	start := l.next
+	if now := time.Now(); start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	select {
+	case <-time.After(time.Until(start)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// FetchComments attaches comments to issues in place. Comments of GitHub
+// issues are fetched when the stored copy predates the issue's last update;
+// otherwise the stored comments are attached. It returns the fetched
+// comments keyed by issue reference.
+func (s *Scraper) FetchComments(ctx context.Context, issues map[string][]model.Issue, stored map[string]model.IssueComments) map[string]model.IssueComments {
+	fetched := make(map[string]model.IssueComments)
+	for repoName, repoIssues := range issues {
+		parts := parseRepoName(repoName)
+		fromGitHub := len(parts) == 2 && s.isGitHubRepo(repoName)
+
+		for i := range repoIssues {
+			issue := &repoIssues[i]
+			ref := model.IssueRef(repoName, issue.Number)
+			cached, ok := stored[ref]
+			issue.Discussion = cached.Comments
+			if !fromGitHub || issue.Comments == 0 || (ok && !cached.FetchedAt.Before(issue.UpdatedAt)) {
+				continue
+			}
+
+			if err := s.commentLimiter.wait(ctx); err != nil {
+				return fetched
+			}
+			comments, err := s.githubClient.GetIssueComments(ctx, parts[0], parts[1], issue.Number, s.comments.MaxPerIssue)
+			if err != nil {
+				log.Printf("Error fetching comments for %s: %v", ref, err)
+				s.recordFailure("comments", ref, err)
+				continue
+			}
+			entry := model.IssueComments{FetchedAt: time.Now(), Comments: convertComments(comments)}
+			fetched[ref] = entry
+			issue.Discussion = entry.Comments
+		}
+	}
+	return fetched
+}
+
+// convertComments converts GitHub API comments to our model
+func convertComments(ghComments []*github.IssueComment) []model.Comment {
+	comments := make([]model.Comment, 0, len(ghComments))
+	for _, comment := range ghComments {
+		comments = append(comments, model.Comment{
+			ID:        comment.GetID(),
+			Author:    comment.GetUser().GetLogin(),
+			Body:      comment.GetBody(),
+			URL:       comment.GetHTMLURL(),
+			Reactions: comment.GetReactions().GetTotalCount(),
+			CreatedAt: comment.GetCreatedAt().Time,
+		})
+	}
+	return comments
+}
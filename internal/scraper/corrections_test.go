@@ -0,0 +1,45 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+func TestUseCorrections(t *testing.T) {
+	s := NewScraper(Config{})
+	s.UseCorrections([]model.CategoryCorrection{
+		{Repository: "acme/infer", IssueNumber: 1, Predicted: "crashes", Category: "performance", Author: "alice"},
+		{Repository: "acme/infer", IssueNumber: 2, Priority: "P1", Author: "bob"},
+	})
+	issues := []model.Issue{
+		{Repository: "acme/infer", Number: 1, Title: "Server crash under load", Category: "crashes", Priority: "P2"},
+		{Repository: "acme/infer", Number: 2, Title: "Server crash under load", Category: "crashes"},
+		{Repository: "acme/train", Number: 1, Title: "Server crash under load", Category: "crashes"},
+	}
+
+	// Corrections override only what they correct, and only their issue
+	s.applyCorrections(issues)
+	for i, expected := range [][2]string{{"performance", "P2"}, {"crashes", "P1"}, {"crashes", ""}} {
+		if issues[i].Category != expected[0] || issues[i].Priority != expected[1] {
+			t.Errorf("Expected %s#%d as %v, got %s/%s", issues[i].Repository, issues[i].Number, expected, issues[i].Category, issues[i].Priority)
+		}
+	}
+
+	s.ExplainClassification(issues)
+	if result := issues[0].Classification; result.Source != model.ClassifiedByCorrection || result.Category != "performance" || result.CorrectedBy != "alice" {
+		t.Errorf("Expected the corrected category explained by alice's correction, got %+v", result)
+	}
+	for _, issue := range issues[1:] {
+		if issue.Classification.Source == model.ClassifiedByCorrection {
+			t.Errorf("Expected %s#%d explained by the rules, got %+v", issue.Repository, issue.Number, issue.Classification)
+		}
+	}
+
+	// Loading corrections again replaces the earlier ones
+	s.UseCorrections(nil)
+	s.ExplainClassification(issues[:1])
+	if issues[0].Classification.Source == model.ClassifiedByCorrection {
+		t.Errorf("Expected the withdrawn correction no longer applied, got %+v", issues[0].Classification)
+	}
+}
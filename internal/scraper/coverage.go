@@ -0,0 +1,67 @@
+package scraper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// target returns the scrape target named repoName
+func (c Config) target(repoName string) (RepositoryConfig, bool) {
+	for _, target := range c.scrapeTargets() {
+		if strings.EqualFold(target.Name, repoName) {
+			return target, true
+		}
+	}
+	return RepositoryConfig{}, false
+}
+
+// FilterFingerprint identifies the keywords and filters repoName is
+// scraped with, so an empty result is only reused for identical scrapes
+func (c Config) FilterFingerprint(repoName string) string {
+	target, _ := c.target(repoName)
+	keywords := append([]string(nil), target.Keywords...)
+	sort.Strings(keywords)
+	filter := c.Filter
+	filter.SkipKnownEmpty = false
+	data, _ := json.Marshal(struct {
+		Keywords  []string
+		MaxIssues int
+		Filter    FilterConfig
+		Sample    int
+	}{keywords, target.MaxIssues, filter, c.Sample})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// EmptyScrape records that scanned issues of repoName were fetched and
+// none matched
+func (c Config) EmptyScrape(repoName string, scanned int, now time.Time) model.EmptyScrape {
+	target, _ := c.target(repoName)
+	return model.EmptyScrape{
+		Repository: repoName,
+		Filters:    c.FilterFingerprint(repoName),
+		Keywords:   target.Keywords,
+		MinScore:   c.Filter.MinScore,
+		Scanned:    scanned,
+		ScrapedAt:  now,
+	}
+}
+
+// KnownEmpty returns the enabled repositories whose last scrape with the
+// current filters found nothing
+func (c Config) KnownEmpty(empty map[string]model.EmptyScrape) []model.EmptyScrape {
+	var known []model.EmptyScrape
+	for _, target := range c.Repositories {
+		record, ok := empty[target.Name]
+		if target.Enabled && ok && record.Filters == c.FilterFingerprint(target.Name) {
+			known = append(known, record)
+		}
+	}
+	return known
+}
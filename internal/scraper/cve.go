@@ -0,0 +1,59 @@
+package scraper
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/client"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// NVDConfig configures CVE metadata lookups
+type NVDConfig struct {
+	APIKey string `yaml:"api_key"`
+}
+
+// ReferencedCVEs returns the CVE identifiers cited by issues, sorted
+func ReferencedCVEs(issues map[string][]model.Issue) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			for _, id := range issue.CVEs {
+				if !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// FetchCVEs fetches NVD metadata for the CVEs cited by issues that are not
+// in known yet (all of them with refresh), adding them to known. It returns
+// the number fetched; lookups that fail are logged and retried next time.
+func FetchCVEs(ctx context.Context, config NVDConfig, issues map[string][]model.Issue, known map[string]model.CVE, refresh bool) (int, error) {
+	nvd := client.NewNVDClient(config.APIKey, nil)
+
+	fetched := 0
+	for _, id := range ReferencedCVEs(issues) {
+		if _, ok := known[id]; ok && !refresh {
+			continue
+		}
+
+		cve, err := nvd.GetCVE(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fetched, ctx.Err()
+			}
+			log.Printf("Error fetching %s from NVD: %v", id, err)
+			continue
+		}
+		known[id] = cve
+		fetched++
+	}
+	return fetched, nil
+}
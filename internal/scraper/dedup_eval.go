@@ -0,0 +1,148 @@
+package scraper
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/similarity"
+)
+
+// TruthPair is a human judgment of whether two issues are duplicates
+type TruthPair struct {
+	A         string
+	B         string
+	Duplicate bool
+}
+
+// PairOutcome is how the automatic duplicate detectors judged a truth pair
+type PairOutcome struct {
+	Pair       TruthPair
+	Similarity float64
+	Predicted  bool
+	// CrossSource is set when the issues come from different sources
+	CrossSource bool
+	// Reason names the detector that linked the pair: content_hash or cross_source
+	Reason string
+}
+
+// DedupEvaluation is the result of evaluating duplicate detection against truth pairs
+type DedupEvaluation struct {
+	Evaluation
+	Outcomes []PairOutcome
+	// Missing lists pairs naming issues that are not in the corpus
+	Missing []TruthPair
+}
+
+// ReadTruthPairs reads a CSV with a header naming the columns a and b
+// (owner/repo#number references) and duplicate (1/0, true/false, yes/no)
+func ReadTruthPairs(r io.Reader) ([]TruthPair, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"a", "b", "duplicate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing %q column", name)
+		}
+	}
+
+	var pairs []TruthPair
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var refs [2]string
+		for i, column := range []string{"a", "b"} {
+			repository, number, ok := model.ParseIssueRef(strings.TrimSpace(record[columns[column]]))
+			if !ok {
+				return nil, fmt.Errorf("line %d: invalid issue reference %q (expected owner/repo#number)", line, record[columns[column]])
+			}
+			refs[i] = model.IssueRef(repository, number)
+		}
+		duplicate, err := parseJudgment(record[columns["duplicate"]])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		pairs = append(pairs, TruthPair{A: refs[0], B: refs[1], Duplicate: duplicate})
+	}
+	return pairs, nil
+}
+
+// EvaluateDedup judges every truth pair the way the dedup step links
+// duplicates automatically (identical content hashes, and cross-source
+// pairs reaching the similarity threshold when enabled) and compares the
+// judgments with the truth. Upstream "closed as duplicate" links are human
+// judgments themselves and are not evaluated.
+func EvaluateDedup(issues map[string][]model.Issue, pairs []TruthPair, config DedupConfig) DedupEvaluation {
+	byRef := make(map[string]model.Issue)
+	for _, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			byRef[model.IssueRef(issue.Repository, issue.Number)] = issue
+		}
+	}
+	engine := similarity.NewEngine(issues)
+
+	var result DedupEvaluation
+	for _, pair := range pairs {
+		a, okA := byRef[pair.A]
+		b, okB := byRef[pair.B]
+		if !okA || !okB {
+			result.Missing = append(result.Missing, pair)
+			continue
+		}
+
+		outcome := PairOutcome{
+			Pair:        pair,
+			Similarity:  engine.Similarity(a, b),
+			CrossSource: config.CrossSource && a.SourceName() != b.SourceName(),
+		}
+		if a.ContentHash != "" && a.ContentHash == b.ContentHash && a.HashAlgorithm == b.HashAlgorithm {
+			outcome.Predicted, outcome.Reason = true, model.DuplicateSourceContentHash
+		} else if outcome.linkedAt(config.CrossSourceMinSimilarity) {
+			outcome.Predicted, outcome.Reason = true, model.DuplicateSourceCrossSource
+		}
+		result.Outcomes = append(result.Outcomes, outcome)
+	}
+	result.Evaluation = result.AtThreshold(config.CrossSourceMinSimilarity)
+	return result
+}
+
+// AtThreshold re-evaluates the judged pairs with a different cross-source
+// similarity threshold
+func (r DedupEvaluation) AtThreshold(minSimilarity float64) Evaluation {
+	var e Evaluation
+	for _, outcome := range r.Outcomes {
+		predicted := outcome.Reason == model.DuplicateSourceContentHash || outcome.linkedAt(minSimilarity)
+		switch {
+		case predicted && outcome.Pair.Duplicate:
+			e.TruePositives++
+		case predicted:
+			e.FalsePositives++
+		case outcome.Pair.Duplicate:
+			e.FalseNegatives++
+		default:
+			e.TrueNegatives++
+		}
+	}
+	return e
+}
+
+// linkedAt reports whether cross-source linking joins the pair at a threshold
+func (o PairOutcome) linkedAt(minSimilarity float64) bool {
+	return o.CrossSource && o.Similarity > 0 && o.Similarity >= minSimilarity
+}
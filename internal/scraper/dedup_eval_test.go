@@ -0,0 +1,80 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+func TestReadTruthPairs(t *testing.T) {
+	pairs, err := ReadTruthPairs(strings.NewReader("duplicate,a,b\nyes,acme/infer#1, acme/infer#2\nno,acme/infer#3,stackoverflow/pytorch#10\n"))
+	if err != nil {
+		t.Fatalf("Failed to read pairs: %v", err)
+	}
+	expected := []TruthPair{{"acme/infer#1", "acme/infer#2", true}, {"acme/infer#3", "stackoverflow/pytorch#10", false}}
+	if len(pairs) != len(expected) || pairs[0] != expected[0] || pairs[1] != expected[1] {
+		t.Errorf("Expected %v, got %v", expected, pairs)
+	}
+
+	for input, message := range map[string]string{
+		"a,b\nacme/infer#1,acme/infer#2\n":                   `missing "duplicate" column`,
+		"a,b,duplicate\nacme/infer#1,acme/infer,1\n":         `line 2: invalid issue reference "acme/infer" (expected owner/repo#number)`,
+		"a,b,duplicate\nacme/infer#1,acme/infer#2,perhaps\n": `line 2: invalid relevance judgment "perhaps"`,
+	} {
+		if _, err := ReadTruthPairs(strings.NewReader(input)); err == nil || err.Error() != message {
+			t.Errorf("Expected %q reading %q, got %v", message, input, err)
+		}
+	}
+}
+
+func TestEvaluateDedup(t *testing.T) {
+	issues := map[string][]model.Issue{
+		"acme/infer": {
+			{Repository: "acme/infer", Number: 1, Title: "Server crash on startup", ContentHash: "h1", HashAlgorithm: "sha256"},
+			{Repository: "acme/infer", Number: 2, Title: "Server crash on startup", ContentHash: "h1", HashAlgorithm: "sha256"},
+			{Repository: "acme/infer", Number: 3, Title: "NCCL deadlock in all-reduce with eight GPUs", Body: "Training hangs in all-reduce"},
+		},
+		"stackoverflow/pytorch": {
+			{Repository: "stackoverflow/pytorch", Number: 10, Source: model.SourceStackOverflow, Title: "NCCL deadlock during all-reduce on eight GPUs", Body: "Training hangs in all-reduce"},
+			{Repository: "stackoverflow/pytorch", Number: 11, Source: model.SourceStackOverflow, Title: "How to format dates in pandas", Body: "Question about strftime"},
+		},
+	}
+	pairs := []TruthPair{
+		{"acme/infer#1", "acme/infer#2", true},
+		{"acme/infer#3", "stackoverflow/pytorch#10", true},
+		{"acme/infer#3", "stackoverflow/pytorch#11", false},
+		{"acme/infer#1", "acme/infer#3", false},
+		{"acme/infer#1", "acme/infer#99", true},
+	}
+
+	// Without cross-source linking only identical content is linked
+	result := EvaluateDedup(issues, pairs, DedupConfig{CrossSourceMinSimilarity: 0.1})
+	if len(result.Missing) != 1 || result.Missing[0] != pairs[4] {
+		t.Errorf("Expected the pair naming a missing issue reported, got %v", result.Missing)
+	}
+	if result.Evaluation != (Evaluation{TruePositives: 1, FalseNegatives: 1, TrueNegatives: 2}) {
+		t.Errorf("Unexpected evaluation without cross-source linking: %+v", result.Evaluation)
+	}
+	if outcome := result.Outcomes[0]; !outcome.Predicted || outcome.Reason != model.DuplicateSourceContentHash {
+		t.Errorf("Expected identical content linked by hash, got %+v", outcome)
+	}
+
+	result = EvaluateDedup(issues, pairs, DedupConfig{CrossSource: true, CrossSourceMinSimilarity: 1.1})
+	similar, unrelated := result.Outcomes[1], result.Outcomes[2]
+	if !similar.CrossSource || similar.Predicted || similar.Similarity <= unrelated.Similarity {
+		t.Fatalf("Expected the similar cross-source pair unlinked at 1.1 but closer than the unrelated one, got %+v and %+v", similar, unrelated)
+	}
+	if result.Outcomes[3].CrossSource {
+		t.Error("Expected issues of the same source not to be a cross-source pair")
+	}
+
+	// Lowering the threshold to the similar pair links it and nothing else
+	if e := result.AtThreshold(similar.Similarity); e != (Evaluation{TruePositives: 2, TrueNegatives: 2}) {
+		t.Errorf("Unexpected evaluation at similarity %.3f: %+v", similar.Similarity, e)
+	}
+	result = EvaluateDedup(issues, pairs, DedupConfig{CrossSource: true, CrossSourceMinSimilarity: similar.Similarity})
+	if outcome := result.Outcomes[1]; !outcome.Predicted || outcome.Reason != model.DuplicateSourceCrossSource {
+		t.Errorf("Expected the similar pair linked across sources, got %+v", outcome)
+	}
+}
@@ -0,0 +1,96 @@
+package scraper
+
+import (
+	"sort"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// Duplicate policies decide what happens to issues closed upstream as duplicates
+const (
+	// DuplicatePolicyMarkOnly keeps duplicates and only records the link
+	DuplicatePolicyMarkOnly = "mark-only"
+	// DuplicatePolicyDeleteLowScore deletes a duplicate scoring no higher than its stored canonical issue
+	DuplicatePolicyDeleteLowScore = "delete-low-score"
+	// DuplicatePolicyDeleteAfterDays deletes duplicates linked more than delete_after_days ago
+	DuplicatePolicyDeleteAfterDays = "delete-after-days"
+)
+
+// ValidDuplicatePolicies lists the accepted values of dedup.policy
+var ValidDuplicatePolicies = []string{DuplicatePolicyMarkOnly, DuplicatePolicyDeleteLowScore, DuplicatePolicyDeleteAfterDays}
+
+// DuplicatesToPrune returns the upstream duplicates the configured policy
+// removes from issues, sorted by reference. Nothing is selected unless a
+// delete policy is explicitly configured; cross-source links never delete.
+func DuplicatesToPrune(issues map[string][]model.Issue, links []model.DuplicateLink, config DedupConfig, now time.Time) []model.Issue {
+	if config.Policy == "" || config.Policy == DuplicatePolicyMarkOnly {
+		return nil
+	}
+
+	stored := make(map[string]model.Issue)
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			issue.Repository = repoName
+			stored[model.IssueRef(repoName, issue.Number)] = issue
+		}
+	}
+
+	remove := make(map[string]bool)
+	for _, link := range links {
+		if link.Source != model.DuplicateSourceUpstream {
+			continue
+		}
+		ref := model.IssueRef(link.Repository, link.IssueNumber)
+		issue, ok := stored[ref]
+		if !ok {
+			continue
+		}
+
+		switch config.Policy {
+		case DuplicatePolicyDeleteLowScore:
+			canonical, ok := stored[model.IssueRef(link.CanonicalRepository, link.CanonicalNumber)]
+			if ok && issue.Score <= canonical.Score {
+				remove[ref] = true
+			}
+		case DuplicatePolicyDeleteAfterDays:
+			if config.DeleteAfterDays > 0 && now.Sub(link.LinkedAt) > time.Duration(config.DeleteAfterDays)*24*time.Hour {
+				remove[ref] = true
+			}
+		}
+	}
+
+	refs := make([]string, 0, len(remove))
+	for ref := range remove {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	duplicates := make([]model.Issue, len(refs))
+	for i, ref := range refs {
+		duplicates[i] = stored[ref]
+	}
+	return duplicates
+}
+
+// RemoveIssues removes the given issues, matched by repository and number,
+// and returns the number removed
+func RemoveIssues(issues map[string][]model.Issue, remove []model.Issue) int {
+	refs := make(map[string]bool, len(remove))
+	for _, issue := range remove {
+		refs[model.IssueRef(issue.Repository, issue.Number)] = true
+	}
+
+	removed := 0
+	for repoName, repoIssues := range issues {
+		kept := repoIssues[:0]
+		for _, issue := range repoIssues {
+			if refs[model.IssueRef(repoName, issue.Number)] {
+				removed++
+				continue
+			}
+			kept = append(kept, issue)
+		}
+		issues[repoName] = kept
+	}
+	return removed
+}
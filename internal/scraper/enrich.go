@@ -0,0 +1,195 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/client"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// EnrichConfig selects optional per-issue enrichment that costs extra API calls
+type EnrichConfig struct {
+	FixLinks          bool `yaml:"fix_links"`
+	Workarounds       bool `yaml:"workarounds"`
+	Duplicates        bool `yaml:"duplicates"`
+	Concurrency       int  `yaml:"concurrency"`
+	MaxComments       int  `yaml:"max_comments"`
+	MaxTimelineEvents int  `yaml:"max_timeline_events"`
+}
+
+// duplicateComment matches GitHub's "Duplicate of #123" marker comment,
+// also in its cross-repository and full URL forms
+var duplicateComment = regexp.MustCompile(`(?i)duplicate of\s+(?:https://github\.com/([\w.-]+/[\w.-]+)/issues/(\d+)|([\w.-]+/[\w.-]+)?#(\d+))`)
+
+// EnrichIssues runs the configured per-issue enrichment on filtered issues.
+// Only filtered GitHub issues are enriched to keep the number of API calls bounded;
+// up to Concurrency issues are enriched at the same time.
+func (s *Scraper) EnrichIssues(ctx context.Context, filteredIssues map[string][]model.Issue) {
+	jobs := make(chan enrichJob)
+
+	workers := s.enrich.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				s.enrichIssue(ctx, job)
+			}
+		}()
+	}
+
+	for repoName, issues := range filteredIssues {
+		parts := parseRepoName(repoName)
+		if len(parts) != 2 || !s.isGitHubRepo(repoName) {
+			continue
+		}
+
+		for i := range issues {
+			jobs <- enrichJob{repoName: repoName, owner: parts[0], repo: parts[1], issue: &issues[i]}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// enrichJob is a single issue waiting for enrichment
+type enrichJob struct {
+	repoName    string
+	owner, repo string
+	issue       *model.Issue
+}
+
+// enrichIssue fetches the timeline and comments of one issue as configured,
+// staying within the comment and timeline budgets
+func (s *Scraper) enrichIssue(ctx context.Context, job enrichJob) {
+	issue := job.issue
+	ref := model.IssueRef(job.repoName, issue.Number)
+	s.safely("enrich", ref, func() {
+		if err := s.enrichTimelineAndComments(ctx, job, s.enrich); err != nil {
+			s.recordFailure("enrich", ref, err)
+		}
+	})
+}
+
+// enrichTimelineAndComments does the work of enrichIssue with the given
+// enrichment, returning the first fetch error
+func (s *Scraper) enrichTimelineAndComments(ctx context.Context, job enrichJob, enrich EnrichConfig) error {
+	issue := job.issue
+
+	if (enrich.FixLinks || enrich.Duplicates) && issue.State == "closed" {
+		events, err := s.githubClient.GetIssueTimeline(ctx, job.owner, job.repo, issue.Number, enrich.MaxTimelineEvents)
+		switch {
+		case errors.Is(err, client.ErrTimelineTooLong):
+			log.Printf("Skipping timeline for %s#%d: more than %d events", job.repoName, issue.Number, enrich.MaxTimelineEvents)
+		case err != nil:
+			log.Printf("Error fetching timeline for %s#%d: %v", job.repoName, issue.Number, err)
+			return err
+		default:
+			if enrich.FixLinks {
+				issue.FixedBy = extractFixedBy(events, job.owner, job.repo)
+			}
+			if enrich.Duplicates {
+				issue.DuplicateOf = extractDuplicateOf(events, issue.StateReason, job.repoName)
+			}
+		}
+	}
+
+	if enrich.Workarounds && issue.Comments > 0 {
+		comments, err := s.githubClient.GetIssueComments(ctx, job.owner, job.repo, issue.Number, enrich.MaxComments)
+		if err != nil {
+			log.Printf("Error fetching comments for %s#%d: %v", job.repoName, issue.Number, err)
+			return err
+		}
+		issue.Workaround = DetectWorkaround(comments)
+	}
+	return nil
+}
+
+// extractFixedBy finds the commit or pull request that closed an issue.
+// A commit referenced by the closing event wins; otherwise the most recent
+// merged pull request that cross-referenced the issue is used.
+func extractFixedBy(events []*github.Timeline, owner, repo string) string {
+	var closingCommit, mergedPR string
+
+	for _, event := range events {
+		switch event.GetEvent() {
+		case "closed":
+			if sha := event.GetCommitID(); sha != "" {
+				closingCommit = fmt.Sprintf("https://github.com/%s/%s/commit/%s", owner, repo, sha)
+			}
+		case "cross-referenced":
+			source := event.GetSource().GetIssue()
+			if source.IsPullRequest() && !source.GetPullRequestLinks().GetMergedAt().IsZero() {
+				mergedPR = source.GetHTMLURL()
+			}
+		}
+	}
+
+	if closingCommit != "" {
+		return closingCommit
+	}
+	return mergedPR
+}
+
+// extractDuplicateOf returns the canonical issue (owner/repo#number) of an
+// issue closed as a duplicate upstream, or an empty string. The canonical
+// issue comes from the "Duplicate of #N" comment GitHub uses to mark duplicates;
+// such comments only count when the issue was actually marked or closed as
+// a duplicate.
+func extractDuplicateOf(events []*github.Timeline, stateReason, repoName string) string {
+	marked := stateReason == "duplicate"
+	canonical := ""
+
+	for _, event := range events {
+		switch event.GetEvent() {
+		case "marked_as_duplicate":
+			marked = true
+		case "unmarked_as_duplicate":
+			marked = stateReason == "duplicate"
+		case "commented":
+			if ref := parseDuplicateComment(event.GetBody(), repoName); ref != "" {
+				canonical = ref
+			}
+		}
+	}
+
+	if !marked {
+		return ""
+	}
+	return canonical
+}
+
+// parseDuplicateComment extracts the canonical issue reference from a comment body
+func parseDuplicateComment(body, repoName string) string {
+	match := duplicateComment.FindStringSubmatch(body)
+	if match == nil {
+		return ""
+	}
+
+	repository, num := match[1], match[2]
+	if num == "" {
+		repository, num = match[3], match[4]
+	}
+	if repository == "" {
+		repository = repoName
+	}
+
+	number, err := strconv.Atoi(num)
+	if err != nil {
+		return ""
+	}
+	return model.IssueRef(repository, number)
+}
@@ -0,0 +1,65 @@
+package scraper
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// EscalationConfig decides when a re-scraped issue counts as escalated
+type EscalationConfig struct {
+	ReactionSpike int     `yaml:"reaction_spike"` // reactions gained since the last scrape; 0 disables
+	ReactionRatio float64 `yaml:"reaction_ratio"` // and the factor the reaction count grew by
+}
+
+// Escalations compares a repository's re-scraped issues with their stored
+// versions and returns the escalations. Issues not stored before are new,
+// not escalated.
+func (c EscalationConfig) Escalations(stored, current []model.Issue, now time.Time) []model.Escalation {
+	previous := make(map[int]model.Issue, len(stored))
+	for _, issue := range stored {
+		previous[issue.Number] = issue
+	}
+
+	var escalations []model.Escalation
+	for _, issue := range current {
+		before, ok := previous[issue.Number]
+		if !ok {
+			continue
+		}
+		escalation := func(kind, from, to string) {
+			escalations = append(escalations, model.Escalation{
+				Repository:  issue.Repository,
+				IssueNumber: issue.Number,
+				Title:       issue.Title,
+				URL:         issue.URL,
+				Kind:        kind,
+				From:        from,
+				To:          to,
+				Team:        issue.AssignedTeam,
+				DetectedAt:  now,
+			})
+		}
+
+		if Escalated(before.Priority, issue.Priority) {
+			escalation(model.EscalationPriority, before.Priority, issue.Priority)
+		}
+		if c.reactionSpike(before.Reactions, issue.Reactions) {
+			escalation(model.EscalationReactions, strconv.Itoa(before.Reactions), strconv.Itoa(issue.Reactions))
+		}
+		if before.State == "closed" && issue.State == "open" {
+			escalation(model.EscalationReopened, before.State, issue.State)
+		}
+	}
+	return escalations
+}
+
+// reactionSpike reports whether reactions grew by at least ReactionSpike
+// and by at least ReactionRatio times
+func (c EscalationConfig) reactionSpike(before, after int) bool {
+	if c.ReactionSpike <= 0 || after-before < c.ReactionSpike {
+		return false
+	}
+	return before == 0 || float64(after) >= float64(before)*c.ReactionRatio
+}
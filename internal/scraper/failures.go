@@ -0,0 +1,58 @@
+package scraper
+
+import (
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Failure records one repository or issue that could not be processed, so a
+// single bad item does not abort the rest of a run
+type Failure struct {
+	Stage string
+	Item  string
+	Error string
+}
+
+// failureLog collects failures from concurrent workers
+type failureLog struct {
+	mu       sync.Mutex
+	failures []Failure
+}
+
+// recordFailure adds a failure for an item at a processing stage
+func (s *Scraper) recordFailure(stage, item string, err error) {
+	s.failures.mu.Lock()
+	defer s.failures.mu.Unlock()
+	s.failures.failures = append(s.failures.failures, Failure{Stage: stage, Item: item, Error: err.Error()})
+}
+
+// Failures returns the failures recorded so far
+func (s *Scraper) Failures() []Failure {
+	s.failures.mu.Lock()
+	defer s.failures.mu.Unlock()
+	return append([]Failure(nil), s.failures.failures...)
+}
+
+// safely runs fn for one item. A panic is recorded as a failure of the item
+// instead of crashing the run; safely reports whether fn completed.
+func (s *Scraper) safely(stage, item string, fn func()) (ok bool) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("Recovered from panic during %s of %s: %v", stage, item, recovered)
+			s.recordFailure(stage, item, fmt.Errorf("panic: %v", recovered))
+			ok = false
+		}
+	}()
+	fn()
+	return true
+}
+
+// CountFailures counts failures by stage
+func CountFailures(failures []Failure) map[string]int {
+	counts := make(map[string]int)
+	for _, failure := range failures {
+		counts[failure.Stage]++
+	}
+	return counts
+}
@@ -14,6 +14,7 @@ type Filter struct {
 	MaxAge        time.Duration
 	RequiredState string
 	MaxIssues     int
+	Abandoned     string
 }
 
 // FilterConfig represents filtering configuration
@@ -23,6 +24,10 @@ type FilterConfig struct {
 	MaxAge        string          `yaml:"max_age"`
 	RequiredState string          `yaml:"required_state"`
 	MaxIssues     int             `yaml:"max_issues"`
+	Abandoned     string          `yaml:"abandoned"` // "include", "exclude" or "only"
+	// SkipKnownEmpty skips repositories whose last scrape with the same
+	// keywords and filters found no matching issue
+	SkipKnownEmpty bool `yaml:"skip_known_empty"`
 }
 
 // NewFilter creates a new issue filter
@@ -31,6 +36,7 @@ func NewFilter(config FilterConfig) *Filter {
 		MinScore:      config.MinScore,
 		RequiredState: config.RequiredState,
 		MaxIssues:     config.MaxIssues,
+		Abandoned:     config.Abandoned,
 	}
 	
 	// Parse age durations
@@ -59,8 +65,12 @@ func (f *Filter) FilterIssues(issues []model.Issue, scorer *Scorer) []model.Issu
 			score, reasons := scorer.ScoreIssue(&issue)
 			issue.Score = score
 			issue.ScoreReason = reasons
+			issue.Category = f.Categorize(issue)
 			
-			// Apply minimum score filter
+			// Rank by the decayed score
+			scorer.ApplyDecay(&issue, time.Now())
+			
+			// Apply minimum score filter on the raw score
 			if score >= f.MinScore {
 				filtered = append(filtered, issue)
 			}
@@ -80,11 +90,23 @@ func (f *Filter) FilterIssues(issues []model.Issue, scorer *Scorer) []model.Issu
 
 // shouldInclude determines if an issue should be included
 func (f *Filter) shouldInclude(issue model.Issue) bool {
-	// Check state
-	if f.RequiredState != "" && issue.State != f.RequiredState {
+	// Check state ("all" disables the check)
+	if f.RequiredState != "" && f.RequiredState != "all" && issue.State != f.RequiredState {
 		return false
 	}
 	
+	// Check abandonment
+	switch f.Abandoned {
+	case "exclude":
+		if issue.IsAbandoned {
+			return false
+		}
+	case "only":
+		if !issue.IsAbandoned {
+			return false
+		}
+	}
+	
 	// Check age
 	age := time.Since(issue.CreatedAt)
 	
@@ -140,42 +162,86 @@ func (f *Filter) GetHighValueIssues(issues []model.Issue, threshold float64) []m
 	return highValue
 }
 
-// CategorizeIssues categorizes issues by type
-func (f *Filter) CategorizeIssues(issues []model.Issue) map[string][]model.Issue {
-	categories := make(map[string][]model.Issue)
-	
-	// Define categories based on keywords and patterns
-	categoryRules := map[string][]string{
-		"performance":     {"performance", "speed", "slow", "optimization", "throughput", "latency"},
-		"gpu_memory":      {"gpu", "cuda", "oom", "memory", "fragmentation"},
-		"distributed":     {"distributed", "nccl", "multi-gpu", "multi-node", "deadlock"},
-		"model_serving":   {"inference", "serving", "kv cache", "prefill", "decode"},
-		"crashes":         {"crash", "error", "exception", "kernel", "timeout"},
-		"memory_issues":   {"memory leak", "leak", "overflow", "allocation"},
+// categoryRule maps a category to the keywords that identify it
+type categoryRule struct {
+	name     string
+	keywords []string
+}
+
+// categoryRules are evaluated in order, the first matching rule wins
+var categoryRules = []categoryRule{
+	{SecurityCategory, []string{"security", "vulnerability", "vulnerable to", "cve-", "cwe-", "sql injection", "command injection", "prompt injection", "xss", "cross-site", "csrf", "ssrf", "path traversal", "directory traversal", "remote code execution", "arbitrary code execution", "unsafe deserialization", "privilege escalation"}},
+	{"performance", []string{"performance", "speed", "slow", "optimization", "throughput", "latency"}},
+	{"gpu_memory", []string{"gpu", "cuda", "oom", "memory", "fragmentation"}},
+	{"distributed", []string{"distributed", "nccl", "multi-gpu", "multi-node", "deadlock"}},
+	{"model_serving", []string{"inference", "serving", "kv cache", "prefill", "decode"}},
+	{"crashes", []string{"crash", "error", "exception", "kernel", "timeout"}},
+	{"memory_issues", []string{"memory leak", "leak", "overflow", "allocation"}},
+}
+
+// Categories returns the category names the rules assign, in rule order
+func Categories() []string {
+	names := make([]string, 0, len(categoryRules)+1)
+	for _, rule := range categoryRules {
+		names = append(names, rule.name)
 	}
+	return append(names, "other")
+}
+
+// Categorize returns the category of a single issue, or "other"
+func (f *Filter) Categorize(issue model.Issue) string {
+	text := strings.ToLower(issue.Title + " " + issue.Body + " " + issue.DiscussionText())
 	
-	for _, issue := range issues {
-		text := issue.Title + " " + issue.Body
-		text = strings.ToLower(text)
-		
-		assigned := false
-		for category, keywords := range categoryRules {
-			for _, keyword := range keywords {
-				if contains(text, keyword) {
-					categories[category] = append(categories[category], issue)
-					assigned = true
-					break
-				}
+	for _, rule := range categoryRules {
+		for _, keyword := range rule.keywords {
+			if contains(text, keyword) {
+				return rule.name
 			}
-			if assigned {
-				break
+		}
+	}
+	
+	return "other"
+}
+
+// Classify explains the rule-based category of an issue with every rule
+// whose keywords it contains
+func (f *Filter) Classify(issue model.Issue) model.ClassificationResult {
+	text := strings.ToLower(issue.Title + " " + issue.Body + " " + issue.DiscussionText())
+	result := model.ClassificationResult{Category: "other", Source: model.ClassifiedByRules}
+
+	for _, rule := range categoryRules {
+		var keywords []string
+		for _, keyword := range rule.keywords {
+			if contains(text, keyword) {
+				keywords = append(keywords, keyword)
 			}
 		}
-		
-		// If no category assigned, put in "other"
-		if !assigned {
-			categories["other"] = append(categories["other"], issue)
+		if len(keywords) == 0 {
+			continue
+		}
+		if len(result.Matches) == 0 {
+			result.Category = rule.name
 		}
+		result.Matches = append(result.Matches, model.RuleMatch{Category: rule.name, Keywords: keywords})
+	}
+
+	if len(result.Matches) > 0 {
+		total := 0
+		for _, match := range result.Matches {
+			total += len(match.Keywords)
+		}
+		result.Confidence = float64(len(result.Matches[0].Keywords)) / float64(total)
+	}
+	return result
+}
+
+// CategorizeIssues categorizes issues by type
+func (f *Filter) CategorizeIssues(issues []model.Issue) map[string][]model.Issue {
+	categories := make(map[string][]model.Issue)
+	
+	for _, issue := range issues {
+		category := f.Categorize(issue)
+		categories[category] = append(categories[category], issue)
 	}
 	
 	return categories
@@ -0,0 +1,62 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+)
+
+// formHeading matches the "### Field" headings rendered by GitHub issue forms
+var formHeading = regexp.MustCompile(`(?m)^###[ \t]+(.+?)[ \t]*$`)
+
+// formKeyCleaner collapses anything that is not a letter or digit in a field key
+var formKeyCleaner = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ParseIssueForm extracts the fields of an issue-form body, keyed by the
+// normalized heading ("### Steps to reproduce" -> "steps_to_reproduce").
+// Fields left empty or answered with "_No response_" are omitted; nil is
+// returned when the body has no form headings.
+func ParseIssueForm(body string) map[string]string {
+	headings := formHeading.FindAllStringSubmatchIndex(body, -1)
+	if len(headings) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]string)
+	for i, heading := range headings {
+		key := FormFieldKey(body[heading[2]:heading[3]])
+		if key == "" {
+			continue
+		}
+
+		end := len(body)
+		if i+1 < len(headings) {
+			end = headings[i+1][0]
+		}
+
+		value := strings.TrimSpace(body[heading[1]:end])
+		if value == "" || value == "_No response_" {
+			continue
+		}
+		fields[key] = value
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// FormFieldKey normalizes an issue-form heading into a field key
+func FormFieldKey(heading string) string {
+	return strings.Trim(formKeyCleaner.ReplaceAllString(strings.ToLower(heading), "_"), "_")
+}
+
+// HasReproSteps reports whether the form fields include reproduction steps
+func HasReproSteps(fields map[string]string) bool {
+	for key := range fields {
+		if strings.Contains(key, "reproduc") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package scraper
+
+import (
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// UseIncremental makes GitHub repositories fetch only the issues updated
+// since their last scrape. Samples and other sources are always fetched in
+// full.
+func (s *Scraper) UseIncremental(lastScraped map[string]time.Time) {
+	s.since = lastScraped
+}
+
+// FetchedSince reports whether the last fetch of repoName only returned
+// issues updated since its previous scrape
+func (s *Scraper) FetchedSince(repoName string) bool {
+	s.fetchedMu.Lock()
+	defer s.fetchedMu.Unlock()
+	return s.fetchedSince[repoName]
+}
+
+// MergeIncremental combines the stored issues of a repository with an
+// incremental fetch: fetched issues replace their stored copies, dropping
+// those no longer passing the filter (filtered holds the ones that do), and
+// the result is ranked and capped like a full scrape
+func (s *Scraper) MergeIncremental(stored, fetched, filtered []model.Issue) []model.Issue {
+	refetched := make(map[int]bool, len(fetched))
+	for _, issue := range fetched {
+		refetched[issue.Number] = true
+	}
+
+	merged := append([]model.Issue(nil), filtered...)
+	for _, issue := range stored {
+		if !refetched[issue.Number] {
+			merged = append(merged, issue)
+		}
+	}
+	s.filter.sortByScore(merged)
+	if s.filter.MaxIssues > 0 && len(merged) > s.filter.MaxIssues {
+		merged = merged[:s.filter.MaxIssues]
+	}
+	return merged
+}
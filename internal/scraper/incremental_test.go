@@ -0,0 +1,104 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/client"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// handlerTransport answers requests with a handler instead of the network
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	t.handler.ServeHTTP(recorder, req)
+	return recorder.Result(), nil
+}
+
+// issuePages serves total issues of acme/infer, 100 per page
+func issuePages(total int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 1 {
+			page = 1
+		}
+		if page*100 < total {
+			next := *r.URL
+			query := next.Query()
+			query.Set("page", strconv.Itoa(page+1))
+			next.RawQuery = query.Encode()
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+		}
+
+		var issues []string
+		for number := (page-1)*100 + 1; number <= total && number <= page*100; number++ {
+			issues = append(issues, fmt.Sprintf(`{"id":%d,"number":%d,"title":"Issue %d","state":"open","updated_at":"2024-05-01T00:00:00Z"}`, number, number, number))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, "[%s]", strings.Join(issues, ","))
+	})
+}
+
+func TestIncrementalFetchIsNotCapped(t *testing.T) {
+	s := NewScraper(Config{})
+	s.githubClient = client.NewGitHubClient("", client.AttributionConfig{}, client.PolitenessConfig{}, handlerTransport{issuePages(250)})
+	repoConfig := RepositoryConfig{Name: "acme/infer", MaxIssues: 50}
+
+	full, err := s.scrapeRepository(context.Background(), repoConfig)
+	if err != nil {
+		t.Fatalf("Failed to scrape: %v", err)
+	}
+	if len(full) != 100 {
+		t.Errorf("Expected a full scrape to stop after the page reaching max_issues, got %d issues", len(full))
+	}
+
+	// Every issue updated since the last scrape is fetched, since the
+	// last scrape time moves past all of them
+	s.UseIncremental(map[string]time.Time{"acme/infer": time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)})
+	since, err := s.scrapeRepository(context.Background(), repoConfig)
+	if err != nil {
+		t.Fatalf("Failed to scrape: %v", err)
+	}
+	if len(since) != 250 || !s.FetchedSince("acme/infer") {
+		t.Errorf("Expected an incremental scrape of all 250 updated issues, got %d", len(since))
+	}
+}
+
+func TestMergeIncremental(t *testing.T) {
+	s := NewScraper(Config{})
+	stored := []model.Issue{{Number: 1, Score: 50}, {Number: 2, Score: 70}, {Number: 3, Score: 30}}
+	// #2 was updated and no longer passes the filter, #3 was updated and
+	// #4 is new
+	fetched := []model.Issue{{Number: 2, Score: 10}, {Number: 3, Score: 80}, {Number: 4, Score: 60}}
+	filtered := []model.Issue{{Number: 3, Score: 80}, {Number: 4, Score: 60}}
+
+	numbers := func(issues []model.Issue) string {
+		var numbers []string
+		for _, issue := range issues {
+			numbers = append(numbers, strconv.Itoa(issue.Number))
+		}
+		return strings.Join(numbers, ",")
+	}
+	if merged := numbers(s.MergeIncremental(stored, fetched, filtered)); merged != "3,4,1" {
+		t.Errorf("Expected the fetched issues to replace their stored copies, ranked by score, got %s", merged)
+	}
+
+	s.filter.MaxIssues = 2
+	if merged := numbers(s.MergeIncremental(stored, fetched, filtered)); merged != "3,4" {
+		t.Errorf("Expected the merge capped at max_issues, got %s", merged)
+	}
+
+	if merged := numbers(s.MergeIncremental(stored, nil, nil)); merged != "2,1" {
+		t.Errorf("Expected the stored issues kept when nothing was updated, got %s", merged)
+	}
+}
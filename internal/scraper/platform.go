@@ -0,0 +1,28 @@
+package scraper
+
+import (
+	"regexp"
+	"sort"
+)
+
+// platformPatterns map a normalized platform name to the phrases that mention it
+var platformPatterns = map[string]*regexp.Regexp{
+	"linux":   regexp.MustCompile(`(?i)\b(linux|ubuntu|debian|centos|rhel|fedora|rocky|alpine|wsl2?)\b`),
+	"macos":   regexp.MustCompile(`(?i)\b(macos|mac os|os x|osx|darwin|apple silicon)\b`),
+	"windows": regexp.MustCompile(`(?i)\b(windows|win32|win64|win1[01])\b`),
+	"arm64":   regexp.MustCompile(`(?i)\b(arm64|aarch64|armv8|apple silicon|graviton)\b`),
+	"amd64":   regexp.MustCompile(`(?i)\b(amd64|x86_64|x86-64|x64)\b`),
+}
+
+// DetectPlatforms returns the operating systems and architectures mentioned in
+// an issue, sorted by name
+func DetectPlatforms(text string) []string {
+	var platforms []string
+	for name, pattern := range platformPatterns {
+		if pattern.MatchString(text) {
+			platforms = append(platforms, name)
+		}
+	}
+	sort.Strings(platforms)
+	return platforms
+}
@@ -0,0 +1,132 @@
+package scraper
+
+import (
+	"strings"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/notify"
+)
+
+// PortfolioConfig groups repositories into a named system, such as
+// "payments-stack", with saved searches and webhooks of its own
+type PortfolioConfig struct {
+	Name         string        `yaml:"name"`
+	Repositories []string      `yaml:"repositories"` // owner/name, or owner/* for a whole owner
+	Searches     []SavedSearch `yaml:"searches"`
+	Webhooks     []string      `yaml:"webhooks"`
+}
+
+// SavedSearch is a named query within a portfolio
+type SavedSearch struct {
+	Name  string `yaml:"name"`
+	Query string `yaml:"query"`
+}
+
+// PortfolioRiskConfig weighs the signals of the portfolio risk score
+type PortfolioRiskConfig struct {
+	UnresolvedWeight float64 `yaml:"unresolved_weight"` // open high-severity pitfalls
+	HealthWeight     float64 `yaml:"health_weight"`     // archived repositories and issues without upstream response
+	TrendWeight      float64 `yaml:"trend_weight"`      // new high-severity pitfalls, recent vs previous window
+	TrendDays        int     `yaml:"trend_days"`        // length of the trend windows
+}
+
+// Portfolios are the configured portfolios
+type Portfolios []PortfolioConfig
+
+// Contains reports whether a repository belongs to the portfolio
+func (p PortfolioConfig) Contains(repoName string) bool {
+	for _, pattern := range p.Repositories {
+		if owner, ok := strings.CutSuffix(pattern, "/*"); ok {
+			if repoOwner, _, found := strings.Cut(repoName, "/"); found && strings.EqualFold(repoOwner, owner) {
+				return true
+			}
+		} else if strings.EqualFold(pattern, repoName) {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchQuery returns the query of a saved search, limited to the
+// portfolio's repositories
+func (p PortfolioConfig) SearchQuery(search SavedSearch) string {
+	return "portfolio:" + p.Name + " " + search.Query
+}
+
+// Names returns the names of the portfolios in configuration order
+func (ps Portfolios) Names() []string {
+	names := make([]string, len(ps))
+	for i, portfolio := range ps {
+		names[i] = portfolio.Name
+	}
+	return names
+}
+
+// Find returns the portfolio with the given name
+func (ps Portfolios) Find(name string) (PortfolioConfig, bool) {
+	for _, portfolio := range ps {
+		if strings.EqualFold(portfolio.Name, name) {
+			return portfolio, true
+		}
+	}
+	return PortfolioConfig{}, false
+}
+
+// Of returns the names of the portfolios a repository belongs to
+func (ps Portfolios) Of(repoName string) []string {
+	var names []string
+	for _, portfolio := range ps {
+		if portfolio.Contains(repoName) {
+			names = append(names, portfolio.Name)
+		}
+	}
+	return names
+}
+
+// MarkIssues sets Portfolios on every issue in place
+func (ps Portfolios) MarkIssues(issues []model.Issue) {
+	for i := range issues {
+		issues[i].Portfolios = ps.Of(issues[i].Repository)
+	}
+}
+
+// MarkCorpus sets Portfolios on every issue of a corpus in place, so
+// issues stored before a portfolio was configured match it too
+func (ps Portfolios) MarkCorpus(issues map[string][]model.Issue) {
+	for repoName, repoIssues := range issues {
+		for i := range repoIssues {
+			repoIssues[i].Portfolios = ps.Of(repoName)
+		}
+	}
+}
+
+// Alerts returns the saved searches of all portfolios as alerts named
+// <portfolio>/<search>, posting to the portfolio's webhooks
+func (ps Portfolios) Alerts() []notify.Alert {
+	var alerts []notify.Alert
+	for _, portfolio := range ps {
+		for _, search := range portfolio.Searches {
+			alerts = append(alerts, notify.Alert{
+				Name:     portfolio.Name + "/" + search.Name,
+				Query:    portfolio.SearchQuery(search),
+				Webhooks: portfolio.Webhooks,
+			})
+		}
+	}
+	return alerts
+}
+
+// Alerts returns notify.alerts followed by the saved searches of portfolios
+func (c Config) Alerts() []notify.Alert {
+	return append(append([]notify.Alert(nil), c.Notify.Alerts...), c.Portfolios.Alerts()...)
+}
+
+// AlertsEnabled reports whether any alert has a webhook to post to
+func (c Config) AlertsEnabled() bool {
+	for _, alert := range c.Alerts() {
+		if c.Notify.AlertConfig(alert).Enabled() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package scraper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// Privacy modes
+const (
+	// PrivacyStandard allows every configured integration
+	PrivacyStandard = "standard"
+	// PrivacyStrict allows no network traffic except GitHub API calls
+	PrivacyStrict = "strict"
+)
+
+// PrivacyModes lists the valid privacy modes
+var PrivacyModes = []string{PrivacyStandard, PrivacyStrict}
+
+// PrivacyViolations lists the configured settings that would send data to
+// hosts other than GitHub, which strict privacy forbids
+func (c Config) PrivacyViolations() []string {
+	var violations []string
+	for _, target := range c.scrapeTargets() {
+		if source := target.sourceName(); source != model.SourceGitHub {
+			violations = append(violations, fmt.Sprintf("repository %s uses source %s", target.Name, source))
+		}
+	}
+	// Every export destination is remote; sftp runs the sftp binary, which
+	// the transport restricting HTTP clients to GitHub cannot stop
+	for _, destination := range c.Export.Destinations {
+		scheme, _, _ := strings.Cut(destination, "://")
+		violations = append(violations, fmt.Sprintf("export destination %s://...", scheme))
+	}
+	for _, feed := range c.Export.Feeds {
+		for _, destination := range feed.Destinations {
+			scheme, _, _ := strings.Cut(destination, "://")
+			violations = append(violations, fmt.Sprintf("feed %s destination %s://...", feed.Name, scheme))
+		}
+	}
+	if len(c.Notify.Webhooks) > 0 {
+		violations = append(violations, "notify.webhooks is set")
+	}
+	for _, alert := range c.Notify.Alerts {
+		if len(alert.Webhooks) > 0 {
+			violations = append(violations, fmt.Sprintf("notify alert %s webhooks are set", alert.Name))
+		}
+	}
+	for _, portfolio := range c.Portfolios {
+		if len(portfolio.Webhooks) > 0 {
+			violations = append(violations, fmt.Sprintf("portfolio %s webhooks are set", portfolio.Name))
+		}
+	}
+	for _, team := range c.Teams {
+		if len(team.Webhooks) > 0 {
+			violations = append(violations, fmt.Sprintf("team %s webhooks are set", team.Name))
+		}
+	}
+	if c.NVD.APIKey != "" {
+		violations = append(violations, "nvd.api_key is set")
+	}
+	return violations
+}
@@ -0,0 +1,136 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// PullRequestConfig configures scraping of closed pull requests
+type PullRequestConfig struct {
+	Enabled           bool `yaml:"enabled"`
+	MaxPulls          int  `yaml:"max_pulls"`           // per repository and scrape
+	MaxReviewComments int  `yaml:"max_review_comments"` // per pull request (0 = all)
+}
+
+// closingReference matches GitHub's closing keywords, e.g. "Fixes #123",
+// also in their cross-repository and full URL forms
+var closingReference = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?):?\s+(?:https://github\.com/([\w.-]+/[\w.-]+)/issues/(\d+)|([\w.-]+/[\w.-]+)?#(\d+))`)
+
+// ScrapePullRequests fetches the closed pull requests of a GitHub
+// repository updated since its last scrape, keeping those that close an
+// issue or mention one of the repository's keywords, with their review
+// comments
+func (s *Scraper) ScrapePullRequests(ctx context.Context, repoConfig RepositoryConfig) ([]model.PullRequest, error) {
+	parts := parseRepoName(repoConfig.Name)
+	if len(parts) != 2 || repoConfig.sourceName() != model.SourceGitHub {
+		return nil, fmt.Errorf("%s is not a GitHub repository", repoConfig.Name)
+	}
+	owner, repo := parts[0], parts[1]
+
+	ghPulls, err := s.githubClient.GetClosedPullRequests(ctx, owner, repo, s.pulls.MaxPulls, s.since[repoConfig.Name])
+	if err != nil {
+		return nil, err
+	}
+
+	var pulls []model.PullRequest
+	for _, ghPull := range ghPulls {
+		pull := convertPullRequest(ghPull, repoConfig.Name)
+		if len(pull.LinkedIssues) == 0 && !mentionsKeyword(pull, repoConfig.Keywords) {
+			continue
+		}
+
+		comments, err := s.githubClient.GetReviewComments(ctx, owner, repo, pull.Number, s.pulls.MaxReviewComments)
+		if err != nil {
+			log.Printf("Error fetching review comments for %s#%d: %v", repoConfig.Name, pull.Number, err)
+			s.recordFailure("pulls", model.IssueRef(repoConfig.Name, pull.Number), err)
+		}
+		for _, comment := range comments {
+			pull.ReviewComments = append(pull.ReviewComments, model.ReviewComment{
+				Author:    comment.GetUser().GetLogin(),
+				Body:      comment.GetBody(),
+				Path:      comment.GetPath(),
+				URL:       comment.GetHTMLURL(),
+				CreatedAt: comment.GetCreatedAt().Time,
+			})
+		}
+		pulls = append(pulls, pull)
+	}
+
+	log.Printf("Kept %d of %d closed pull requests from %s", len(pulls), len(ghPulls), repoConfig.Name)
+	return pulls, nil
+}
+
+// convertPullRequest converts a GitHub API pull request to our model
+func convertPullRequest(ghPull *github.PullRequest, repoName string) model.PullRequest {
+	var labels []model.Label
+	for _, label := range ghPull.Labels {
+		labels = append(labels, model.Label{
+			Name:        label.GetName(),
+			Description: label.GetDescription(),
+			Color:       label.GetColor(),
+		})
+	}
+	return model.PullRequest{
+		Number:       ghPull.GetNumber(),
+		Title:        ghPull.GetTitle(),
+		Body:         ghPull.GetBody(),
+		URL:          ghPull.GetHTMLURL(),
+		Author:       ghPull.GetUser().GetLogin(),
+		Labels:       labels,
+		Merged:       !ghPull.GetMergedAt().IsZero(),
+		CreatedAt:    ghPull.GetCreatedAt().Time,
+		UpdatedAt:    ghPull.GetUpdatedAt().Time,
+		ClosedAt:     ghPull.GetClosedAt().Time,
+		Repository:   repoName,
+		LinkedIssues: LinkedIssues(ghPull.GetTitle()+"\n"+ghPull.GetBody(), repoName),
+	}
+}
+
+// LinkedIssues returns the issues text closes with GitHub's closing
+// keywords, as owner/repo#number; bare #123 references are in repoName
+func LinkedIssues(text, repoName string) []string {
+	var refs []string
+	seen := make(map[string]bool)
+	for _, match := range closingReference.FindAllStringSubmatch(text, -1) {
+		repository, number := match[1], match[2]
+		if number == "" {
+			repository, number = match[3], match[4]
+		}
+		if repository == "" {
+			repository = repoName
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			continue
+		}
+		ref := model.IssueRef(repository, n)
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// mentionsKeyword reports whether a pull request's title or description
+// mentions one of keywords; without keywords every pull request does
+func mentionsKeyword(pull model.PullRequest, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	text := strings.ToLower(pull.Title + "\n" + pull.Body)
+	for _, keyword := range keywords {
+		if strings.Contains(text, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
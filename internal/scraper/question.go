@@ -0,0 +1,81 @@
+package scraper
+
+import (
+	"strings"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// questionPrefixes open the titles of support questions
+var questionPrefixes = []string{
+	"how to ", "how do i ", "how can i ", "how should i ", "is it possible", "is there a way",
+	"can i ", "can someone ", "question:", "[question]", "[usage]", "[help]", "help:",
+}
+
+// questionLabels mark support questions upstream
+var questionLabels = []string{"question", "usage", "support", "how-to", "help wanted by reporter"}
+
+// helpPhrases ask for help in the body of a support question
+var helpPhrases = []string{
+	"any help would be appreciated", "any help is appreciated", "can anyone help", "can someone help",
+	"please help", "need help", "help me", "any suggestions", "any advice", "thanks in advance",
+}
+
+// defectSignals show that a question reports a defect after all
+var defectSignals = []string{
+	"traceback", "stack trace", "stacktrace", "segfault", "segmentation fault", "panic:",
+	"steps to reproduce", "expected behavior", "expected behaviour", "regression",
+}
+
+// IsQuestion reports whether an issue asks for support rather than
+// reporting a defect or gotcha: it carries a question label, or its title
+// reads as a question ("How to ...", or ending in "?") or its body asks for
+// help, without a bug label or defect signals such as a traceback.
+func IsQuestion(issue model.Issue) bool {
+	for _, label := range issue.Labels {
+		name := strings.ToLower(label.Name)
+		if name == "bug" || strings.Contains(name, "regression") {
+			return false
+		}
+	}
+	for _, label := range issue.Labels {
+		if containsFold(questionLabels, label.Name) {
+			return true
+		}
+	}
+
+	body := strings.ToLower(issue.SearchText())
+	for _, signal := range defectSignals {
+		if strings.Contains(body, signal) {
+			return false
+		}
+	}
+
+	title := strings.ToLower(strings.TrimSpace(issue.Title))
+	for _, prefix := range questionPrefixes {
+		if strings.HasPrefix(title, prefix) {
+			return true
+		}
+	}
+	signals := 0
+	if strings.HasSuffix(title, "?") {
+		signals++
+	}
+	for _, phrase := range helpPhrases {
+		if strings.Contains(body, phrase) {
+			signals++
+			break
+		}
+	}
+	return signals == 2
+}
+
+// markQuestions sets the type of support questions in place
+func markQuestions(issues []model.Issue) {
+	for i := range issues {
+		issues[i].Type = ""
+		if IsQuestion(issues[i]) {
+			issues[i].Type = model.IssueTypeQuestion
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// RefreshIssue re-fetches one GitHub issue with its timeline and comments,
+// whatever enrichment is configured, and classifies and scores it like a
+// scraped issue. The issue is not filtered, so it is returned even when it
+// no longer matches the keywords or minimum score.
+func (s *Scraper) RefreshIssue(ctx context.Context, repoName string, number int) (model.Issue, error) {
+	parts := parseRepoName(repoName)
+	if len(parts) != 2 || !s.isGitHubRepo(repoName) {
+		return model.Issue{}, fmt.Errorf("%s is not a GitHub repository", repoName)
+	}
+	ghIssue, err := s.githubClient.GetIssue(ctx, parts[0], parts[1], number)
+	if err != nil {
+		return model.Issue{}, err
+	}
+	if ghIssue.IsPullRequest() {
+		return model.Issue{}, fmt.Errorf("%s is a pull request", model.IssueRef(repoName, number))
+	}
+
+	issues := []model.Issue{s.convertGitHubIssue(ghIssue, repoName)}
+	enrich := s.enrich
+	enrich.FixLinks, enrich.Workarounds, enrich.Duplicates = true, true, true
+	if err := s.enrichTimelineAndComments(ctx, enrichJob{repoName: repoName, owner: parts[0], repo: parts[1], issue: &issues[0]}, enrich); err != nil {
+		return model.Issue{}, err
+	}
+
+	s.classify(issues)
+	s.score(issues, time.Now())
+	s.stampVersions(issues)
+	return issues[0], nil
+}
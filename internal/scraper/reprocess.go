@@ -0,0 +1,114 @@
+package scraper
+
+import (
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// ClassifyIssues re-derives platforms, plain text, upstream priority, abandonment,
+// category and team of already scraped issues in place. An issue that
+// fails is recorded in Failures and left as it was.
+func (s *Scraper) ClassifyIssues(issues map[string][]model.Issue) int {
+	count := 0
+	for repoName, repoIssues := range issues {
+		for i := range repoIssues {
+			issue := repoIssues[i : i+1]
+			if s.safely("classify", model.IssueRef(repoName, issue[0].Number), func() {
+				s.detectPlatforms(issue)
+				s.classify(issue)
+			}) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// ScoreIssues recomputes the raw and decayed scores of already scraped
+// issues in place. An issue that fails is recorded in Failures.
+func (s *Scraper) ScoreIssues(issues map[string][]model.Issue) int {
+	now := time.Now()
+	count := 0
+	for repoName, repoIssues := range issues {
+		for i := range repoIssues {
+			issue := repoIssues[i : i+1]
+			if s.safely("score", model.IssueRef(repoName, issue[0].Number), func() { s.score(issue, now) }) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// ReprocessOutdated recomputes only the derived fields that older versions
+// produced and returns how many issues were updated. Issues that are
+// reclassified are also rescored, since scores depend on priority.
+func (s *Scraper) ReprocessOutdated(issues map[string][]model.Issue) int {
+	now := time.Now()
+	count := 0
+	for repoName, repoIssues := range issues {
+		for i := range repoIssues {
+			issue := repoIssues[i : i+1]
+			classification, score, platforms := s.versions.Outdated(issue[0])
+			if !classification && !score && !platforms {
+				continue
+			}
+			if s.safely("reprocess", model.IssueRef(repoName, issue[0].Number), func() {
+				if platforms {
+					s.detectPlatforms(issue)
+				}
+				if classification {
+					s.classify(issue)
+				}
+				if classification || score {
+					s.score(issue, now)
+				}
+			}) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// detectPlatforms re-detects the platforms mentioned by issues
+func (s *Scraper) detectPlatforms(issues []model.Issue) {
+	for i := range issues {
+		issues[i].Platforms = DetectPlatforms(issues[i].Title + "\n" + issues[i].Body)
+		issues[i].PlatformsVersion = s.versions.Platforms
+	}
+}
+
+// classify re-derives the plain text, priority, abandonment, category and
+// team of issues
+func (s *Scraper) classify(issues []model.Issue) {
+	markPlainText(issues)
+	markQuestions(issues)
+	s.severity.MarkIssues(issues)
+	s.abandoned.MarkIssues(issues)
+	for i := range issues {
+		issues[i].Category = s.filter.Categorize(issues[i])
+		issues[i].ClassificationVersion = s.versions.Classification
+	}
+	s.applyCorrections(issues)
+	markWeaknesses(issues)
+	s.teams.AssignIssues(issues)
+	s.portfolios.MarkIssues(issues)
+}
+
+// markPlainText stores the HTML-free text searches use on issues
+func markPlainText(issues []model.Issue) {
+	for i := range issues {
+		issues[i].SetPlainText()
+	}
+}
+
+// score recomputes the raw and decayed scores of issues
+func (s *Scraper) score(issues []model.Issue, now time.Time) {
+	for i := range issues {
+		issues[i].Score, issues[i].ScoreReason = s.scorer.ScoreIssue(&issues[i])
+		s.scorer.ApplyDecay(&issues[i], now)
+		issues[i].ScoreVersion = s.versions.Score
+	}
+}
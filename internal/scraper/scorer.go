@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
 )
@@ -18,6 +19,39 @@ type Scorer struct {
 	
 	// Priority labels that indicate high value
 	priorityLabels []string
+	
+	// Reporter reputation (0-1) and the maximum points it can add
+	reputation       map[string]float64
+	reputationWeight float64
+	
+	// Age at which a score is halved (0 = no decay)
+	decayHalfLife time.Duration
+	
+	// Maximum points of the configurable components
+	weights ScoreComponents
+	
+	// Share of the score support questions lose
+	questionDeboost float64
+}
+
+// ScoreComponents holds one value per configurable score component: either
+// the maximum points of each component (weights) or the share of them an
+// issue earns (0-1)
+type ScoreComponents struct {
+	Keyword  float64 `json:"keyword"`
+	Pattern  float64 `json:"pattern"`
+	Label    float64 `json:"label"`
+	Status   float64 `json:"status"`
+	Activity float64 `json:"activity"`
+}
+
+// DefaultScoreWeights are the component weights scoring was designed with
+var DefaultScoreWeights = ScoreComponents{Keyword: 30, Pattern: 25, Label: 20, Status: 10, Activity: 15}
+
+// Dot returns the points earned by shares c under weights
+func (c ScoreComponents) Dot(weights ScoreComponents) float64 {
+	return c.Keyword*weights.Keyword + c.Pattern*weights.Pattern + c.Label*weights.Label +
+		c.Status*weights.Status + c.Activity*weights.Activity
 }
 
 // NewScorer creates a new issue scorer
@@ -70,52 +104,156 @@ func NewScorer() *Scorer {
 			"documentation", "good first issue", "help wanted",
 			"high priority", "urgent", "regression",
 		},
+		
+		weights: DefaultScoreWeights,
 	}
 }
 
-// ScoreIssue calculates a score for an issue based on multiple factors
+// ScoreIssue calculates a score for an issue based on multiple factors. The
+// points earned by reporter reputation are kept in issue.ReputationScore.
 func (s *Scorer) ScoreIssue(issue *model.Issue) (float64, []string) {
 	var score float64
 	var reasons []string
+	shares, _ := s.Components(issue)
 	
-	// 1. Keyword matching (30 points max)
-	keywordScore := s.scoreKeywords(issue)
+	// 1. Keyword matching (30 points max by default)
+	keywordScore := shares.Keyword * s.weights.Keyword
 	score += keywordScore
 	if keywordScore > 0 {
 		reasons = append(reasons, fmt.Sprintf("关键词匹配: %.1f分", keywordScore))
 	}
 	
-	// 2. Pattern matching (25 points max)
-	patternScore := s.scorePatterns(issue)
+	// 2. Pattern matching (25 points max by default)
+	patternScore := shares.Pattern * s.weights.Pattern
 	score += patternScore
 	if patternScore > 0 {
 		reasons = append(reasons, fmt.Sprintf("模式匹配: %.1f分", patternScore))
 	}
 	
-	// 3. Label scoring (20 points max)
-	labelScore := s.scoreLabels(issue)
+	// 3. Label scoring (20 points max by default)
+	labelScore := shares.Label * s.weights.Label
 	score += labelScore
 	if labelScore > 0 {
 		reasons = append(reasons, fmt.Sprintf("标签匹配: %.1f分", labelScore))
 	}
 	
-	// 4. Status scoring (10 points max)
-	statusScore := s.scoreStatus(issue)
+	// 4. Upstream priority (10 points max)
+	priorityScore := priorityPoints[issue.Priority]
+	score += priorityScore
+	if priorityScore > 0 {
+		reasons = append(reasons, fmt.Sprintf("上游优先级 (%s): %.1f分", issue.Priority, priorityScore))
+	}
+	
+	// 5. Status scoring (10 points max by default)
+	statusScore := shares.Status * s.weights.Status
 	score += statusScore
 	if statusScore > 0 {
 		reasons = append(reasons, fmt.Sprintf("状态评分: %.1f分", statusScore))
 	}
 	
-	// 5. Activity scoring (15 points max)
-	activityScore := s.scoreActivity(issue)
+	// 6. Activity scoring (15 points max by default)
+	activityScore := shares.Activity * s.weights.Activity
 	score += activityScore
 	if activityScore > 0 {
 		reasons = append(reasons, fmt.Sprintf("活跃度评分: %.1f分", activityScore))
 	}
 	
+	// 7. Report quality (5 points max)
+	qualityScore := s.scoreQuality(issue)
+	score += qualityScore
+	if qualityScore > 0 {
+		reasons = append(reasons, fmt.Sprintf("复现步骤: %.1f分", qualityScore))
+	}
+	
+	// 8. Reporter reputation (optional)
+	reputationScore := s.scoreReputation(issue)
+	score += reputationScore
+	if reputationScore > 0 {
+		reasons = append(reasons, fmt.Sprintf("报告者信誉: %.1f分", reputationScore))
+	}
+	
+	// 9. Support questions lose part of their score
+	if issue.Type == model.IssueTypeQuestion && s.questionDeboost > 0 {
+		score *= 1 - s.questionDeboost
+		reputationScore *= 1 - s.questionDeboost
+		reasons = append(reasons, fmt.Sprintf("支持类问题: ×%.2f", 1-s.questionDeboost))
+	}
+	
+	issue.ReputationScore = reputationScore
 	return score, reasons
 }
 
+// Components returns the share (0-1) of each configurable component an
+// issue earns, and the points of the fixed components (upstream priority,
+// report quality, reporter reputation)
+func (s *Scorer) Components(issue *model.Issue) (ScoreComponents, float64) {
+	shares := ScoreComponents{
+		Keyword:  s.scoreKeywords(issue) / DefaultScoreWeights.Keyword,
+		Pattern:  s.scorePatterns(issue) / DefaultScoreWeights.Pattern,
+		Label:    s.scoreLabels(issue) / DefaultScoreWeights.Label,
+		Status:   s.scoreStatus(issue) / DefaultScoreWeights.Status,
+		Activity: s.scoreActivity(issue) / DefaultScoreWeights.Activity,
+	}
+	fixed := priorityPoints[issue.Priority] + s.scoreQuality(issue) + s.scoreReputation(issue)
+	return shares, fixed
+}
+
+// SetWeights sets the maximum points of the configurable components
+func (s *Scorer) SetWeights(weights ScoreComponents) {
+	s.weights = weights
+}
+
+// SetReporterReputation enables reporter reputation scoring with up to weight points
+func (s *Scorer) SetReporterReputation(reputation map[string]float64, weight float64) {
+	s.reputation = reputation
+	s.reputationWeight = weight
+}
+
+// SetQuestionDeboost sets the share of the score support questions lose
+func (s *Scorer) SetQuestionDeboost(deboost float64) {
+	s.questionDeboost = deboost
+}
+
+// SetDecayHalfLife enables time decay of scores with the given half-life
+func (s *Scorer) SetDecayHalfLife(halfLife time.Duration) {
+	s.decayHalfLife = halfLife
+}
+
+// ApplyDecay keeps the issue's score as raw score and replaces the score
+// with its time-decayed value
+func (s *Scorer) ApplyDecay(issue *model.Issue, now time.Time) {
+	issue.RawScore = issue.Score
+	issue.Score = model.DecayScore(issue.RawScore, issue.UpdatedAt, now, s.decayHalfLife)
+	if issue.Score < issue.RawScore {
+		issue.ScoreReason = append(issue.ScoreReason, fmt.Sprintf("时间衰减: ×%.2f", issue.Score/issue.RawScore))
+	}
+}
+
+// scoreReputation scores based on the reporter's track record
+func (s *Scorer) scoreReputation(issue *model.Issue) float64 {
+	if s.reputationWeight <= 0 || issue.Author == "" {
+		return 0
+	}
+	return s.reputation[issue.Author] * s.reputationWeight
+}
+
+// scoreQuality scores issue-form reports that include reproduction steps
+func (s *Scorer) scoreQuality(issue *model.Issue) float64 {
+	if HasReproSteps(issue.StructuredFields) {
+		return 5.0
+	}
+	return 0
+}
+
+// Points per matching keyword or label; the keyword and label components
+// cap at 30 and 20 points
+const (
+	highValueKeywordPoints = 3.0
+	keywordPoints          = 1.0
+	priorityLabelPoints    = 2.0
+	issueLabelPoints       = 1.5 // bug, error or performance labels
+)
+
 // scoreKeywords scores based on keyword matches
 func (s *Scorer) scoreKeywords(issue *model.Issue) float64 {
 	var score float64
@@ -130,14 +268,14 @@ func (s *Scorer) scoreKeywords(issue *model.Issue) float64 {
 	
 	for _, keyword := range highValueKeywords {
 		if strings.Contains(text, keyword) {
-			score += 3.0
+			score += highValueKeywordPoints
 		}
 	}
 	
 	// Medium-value keywords
 	for _, keyword := range s.keywords {
 		if strings.Contains(text, keyword) {
-			score += 1.0
+			score += keywordPoints
 		}
 	}
 	
@@ -177,13 +315,13 @@ func (s *Scorer) scoreLabels(issue *model.Issue) float64 {
 		
 		switch {
 		case containsSlice(s.priorityLabels, labelName):
-			score += 2.0
+			score += priorityLabelPoints
 		case strings.Contains(labelName, "bug") || strings.Contains(labelName, "error"):
-			score += 1.5
+			score += issueLabelPoints
 		case strings.Contains(labelName, "performance"):
-			score += 1.5
+			score += issueLabelPoints
 		case strings.Contains(labelName, "critical") || strings.Contains(labelName, "urgent"):
-			score += 2.0
+			score += priorityLabelPoints
 		}
 	}
 	
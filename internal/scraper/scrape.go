@@ -4,26 +4,137 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"sync"
 	"time"
 	
 	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/client"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/export"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/jobs"
 	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/notify"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/server"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/output"
 	"github.com/google/go-github/v67/github"
 )
 
 // Scraper handles the main scraping logic
 type Scraper struct {
 	githubClient *client.GitHubClient
+	transport    http.RoundTripper
 	filter       *Filter
 	scorer       *Scorer
+	teams        *TeamAssigner
+	portfolios   Portfolios
+	abandoned    *AbandonedDetector
+	severity     *SeverityMapper
+	enrich       EnrichConfig
+	pulls        PullRequestConfig
+	comments     CommentConfig
+	// commentLimiter paces comment fetches
+	commentLimiter *commentLimiter
+	sample       int
+	sources      map[string]Source
+	repoSources  map[string]string
+	versions     Versions
+	failures     failureLog
+	corrections  map[string]model.CategoryCorrection
+	// correctionsMu guards corrections, which serve mode reloads while
+	// other requests classify
+	correctionsMu sync.RWMutex
+	// since and fetchedSince track incremental fetches per repository;
+	// fetchedMu guards fetchedSince against parallel fetches
+	since        map[string]time.Time
+	fetchedSince map[string]bool
+	fetchedMu    sync.Mutex
 }
 
 // Config represents scraper configuration
 type Config struct {
 	GitHubToken  string            `yaml:"github_token"`
+	GitLabToken  string            `yaml:"gitlab_token"`
+	GiteaToken   string            `yaml:"gitea_token"`
 	Repositories []RepositoryConfig `yaml:"repositories"`
 	Filter       FilterConfig      `yaml:"filter"`
 	Output       OutputConfig      `yaml:"output"`
+	Teams        []TeamConfig      `yaml:"teams"`
+	Storage      StorageConfig     `yaml:"storage"`
+	SLA          SLAConfig         `yaml:"sla"`
+	Scoring      ScoringConfig     `yaml:"scoring"`
+	Abandoned    AbandonedConfig   `yaml:"abandoned"`
+	Enrich       EnrichConfig      `yaml:"enrich"`
+	Search       SearchConfig      `yaml:"search"`
+	Severity     SeverityConfig    `yaml:"severity"`
+	Sample       int               `yaml:"sample"`
+	Politeness   client.PolitenessConfig `yaml:"politeness"`
+	StackOverflow StackOverflowConfig    `yaml:"stackoverflow"`
+	Dedup        DedupConfig       `yaml:"dedup"`
+	Export       export.Config     `yaml:"export"`
+	Jobs         jobs.Config       `yaml:"jobs"`
+	Integrity    output.IntegrityConfig `yaml:"integrity"`
+	NVD          NVDConfig         `yaml:"nvd"`
+	Notify       notify.Config     `yaml:"notify"`
+	Serve        server.Config     `yaml:"serve"`
+	Budget       BudgetConfig      `yaml:"budget"`
+	Privacy      string            `yaml:"privacy"`
+	Attribution  client.AttributionConfig `yaml:"attribution"`
+	Timezone     string            `yaml:"timezone"`
+	Trends       TrendsConfig      `yaml:"trends"`
+	CI           CIConfig          `yaml:"ci"`
+	Escalation   EscalationConfig  `yaml:"escalation"`
+	PullRequests PullRequestConfig `yaml:"pull_requests"`
+	App          AppConfig         `yaml:"app"`
+	Portfolios   Portfolios        `yaml:"portfolios"`
+	PortfolioRisk PortfolioRiskConfig `yaml:"portfolio_risk"`
+	Maintenance  MaintenanceConfig `yaml:"maintenance"`
+	Daemon       DaemonConfig      `yaml:"daemon"`
+	PoolAlerts   PoolAlertConfig   `yaml:"pool_alerts"`
+	Comments     CommentConfig     `yaml:"comments"`
+}
+
+// AppConfig controls how repositories are scraped in parallel
+type AppConfig struct {
+	MaxWorkers  int `yaml:"max_workers"`  // repositories fetched at the same time
+	WorkerQueue int `yaml:"worker_queue"` // fetched repositories waiting to be processed
+}
+
+// MaintenanceConfig guards destructive maintenance: deleting more items
+// than ConfirmThreshold needs explicit confirmation (0 = never)
+type MaintenanceConfig struct {
+	ConfirmThreshold int `yaml:"confirm_threshold"`
+}
+
+// PoolAlertConfig sets when the concurrency slots of an API host count as
+// undersized: more than MaxWaits requests of a scrape, or more than
+// MaxWaitSeconds of their total time, waiting for a slot (0 = off)
+type PoolAlertConfig struct {
+	MaxWaits       int     `yaml:"max_waits"`
+	MaxWaitSeconds float64 `yaml:"max_wait_seconds"`
+}
+
+// Exceeded reports whether a sample is over the thresholds
+func (c PoolAlertConfig) Exceeded(sample model.PoolSample) bool {
+	return (c.MaxWaits > 0 && sample.Waits > c.MaxWaits) || (c.MaxWaitSeconds > 0 && sample.WaitSeconds > c.MaxWaitSeconds)
+}
+
+// DaemonConfig schedules the work of --daemon mode with cron expressions:
+// repositories without their own schedule are re-scraped on Schedule, and
+// Tasks maps maintenance and analytics tasks to their schedules
+type DaemonConfig struct {
+	Schedule string            `yaml:"schedule"`
+	Tasks    map[string]string `yaml:"tasks"`
+}
+
+// TrendsConfig controls normalization of issue-rate trends for calendar
+// effects: fewer issues are opened on weekends and holidays
+type TrendsConfig struct {
+	Normalize bool     `yaml:"normalize"`
+	Holidays  []string `yaml:"holidays"` // YYYY-MM-DD
+}
+
+// BudgetConfig limits API usage per calendar month (0 = unlimited)
+type BudgetConfig struct {
+	MonthlyRequests int `yaml:"monthly_requests"`
 }
 
 // RepositoryConfig represents repository scraping configuration
@@ -33,6 +144,10 @@ type RepositoryConfig struct {
 	Keywords  []string `yaml:"keywords"`
 	MinScore  float64  `yaml:"min_score"`
 	MaxIssues int      `yaml:"max_issues"`
+	Source    string   `yaml:"source"`   // "github" (default), "gitlab" or "gitea"
+	BaseURL   string   `yaml:"base_url"` // API host for self-hosted sources
+	Token     string   `yaml:"token"`    // Overrides the source's global token
+	Schedule  string   `yaml:"schedule"` // Cron schedule in --daemon mode (default daemon.schedule)
 }
 
 // OutputConfig represents output configuration
@@ -41,15 +156,136 @@ type OutputConfig struct {
 	OutputDir  string `yaml:"output_dir"`
 	SortBy     string `yaml:"sort_by"`
 	IncludeRaw bool   `yaml:"include_raw"`
+	SplitByTeam bool  `yaml:"split_by_team"`
+	SplitByPortfolio bool `yaml:"split_by_portfolio"`
+	IncludeDeleted bool `yaml:"include_deleted"`
+	Retention   output.RetentionConfig `yaml:"retention"`
+}
+
+// StorageConfig represents local storage configuration
+type StorageConfig struct {
+	Dir         string `yaml:"dir"`
+	WriteBuffer int    `yaml:"write_buffer"`
+	WriteBatch  int    `yaml:"write_batch"`
+	MaxSizeMB   int    `yaml:"max_size_mb"` // alert above this size (0 = no limit)
+}
+
+// SLAConfig represents triage SLA windows in days (0 disables a check)
+type SLAConfig struct {
+	UntriagedDays  int `yaml:"untriaged_days"`
+	UnansweredDays int `yaml:"unanswered_days"`
+}
+
+// ScoringConfig represents optional scoring signals
+type ScoringConfig struct {
+	KeywordWeight            float64 `yaml:"keyword_weight"`
+	PatternWeight            float64 `yaml:"pattern_weight"`
+	LabelWeight              float64 `yaml:"label_weight"`
+	StatusWeight             float64 `yaml:"status_weight"`
+	ActivityWeight           float64 `yaml:"activity_weight"`
+	ReporterReputationWeight float64 `yaml:"reporter_reputation_weight"`
+	DecayHalfLifeDays        int     `yaml:"decay_half_life_days"`
+	// QuestionDeboost is the share of the score support questions lose
+	// (0 = only tag them type:question)
+	QuestionDeboost float64 `yaml:"question_deboost"`
+}
+
+// Weights returns the configured maximum points of the score components,
+// or the defaults when none are configured
+func (c ScoringConfig) Weights() ScoreComponents {
+	weights := ScoreComponents{
+		Keyword:  c.KeywordWeight,
+		Pattern:  c.PatternWeight,
+		Label:    c.LabelWeight,
+		Status:   c.StatusWeight,
+		Activity: c.ActivityWeight,
+	}
+	if weights == (ScoreComponents{}) {
+		return DefaultScoreWeights
+	}
+	return weights
+}
+
+// DecayHalfLife returns the score decay half-life (0 = no decay)
+func (c ScoringConfig) DecayHalfLife() time.Duration {
+	return time.Duration(c.DecayHalfLifeDays) * 24 * time.Hour
+}
+
+// SearchConfig represents search ranking configuration
+type SearchConfig struct {
+	RelevanceWeights RelevanceWeightsConfig `yaml:"relevance_weights"`
+}
+
+// DedupConfig represents duplicate linking configuration
+type DedupConfig struct {
+	CrossSource              bool    `yaml:"cross_source"`
+	CrossSourceMinSimilarity float64 `yaml:"cross_source_min_similarity"`
+	Policy                   string  `yaml:"policy"`
+	DeleteAfterDays          int     `yaml:"delete_after_days"`
+}
+
+// RelevanceWeightsConfig weights the components of relevance ranking
+type RelevanceWeightsConfig struct {
+	Text    float64 `yaml:"text"`
+	Score   float64 `yaml:"score"`
+	Recency float64 `yaml:"recency"`
+	Phrase  float64 `yaml:"phrase"`
+}
+
+// Location returns the configured timezone used for day boundaries and
+// report timestamps, the system's local zone when unset or invalid
+func (c Config) Location() *time.Location {
+	if c.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// scrapeTargets returns the configured repositories followed by the
+// Stack Overflow tags, which are scraped as pseudo repositories
+func (c Config) scrapeTargets() []RepositoryConfig {
+	targets := make([]RepositoryConfig, 0, len(c.Repositories))
+	targets = append(targets, c.Repositories...)
+	return append(targets, c.StackOverflow.repositories()...)
 }
 
 // NewScraper creates a new scraper instance
 func NewScraper(config Config) *Scraper {
+	transport := client.NewPoliteTransport(config.Politeness)
 	scraper := &Scraper{
-		githubClient: client.NewGitHubClient(config.GitHubToken),
+		githubClient: client.NewGitHubClient(config.GitHubToken, config.Attribution, config.Politeness, transport),
+		transport:    transport,
 		filter:       NewFilter(config.Filter),
 		scorer:       NewScorer(),
+		teams:        NewTeamAssigner(config.Teams),
+		portfolios:   config.Portfolios,
+		abandoned:    NewAbandonedDetector(config.Abandoned),
+		severity:     NewSeverityMapper(config.Severity),
+		enrich:       config.Enrich,
+		pulls:        config.PullRequests,
+		comments:     config.Comments,
+		commentLimiter: newCommentLimiter(config.Comments.RequestsPerMinute),
+		sample:       config.Sample,
+		repoSources:  make(map[string]string),
+		fetchedSince: make(map[string]bool),
+		versions:     deriveVersions(config),
 	}
+	scraper.sources = map[string]Source{
+		model.SourceGitHub: githubSource{scraper: scraper},
+		model.SourceGitLab: gitlabSource{token: config.GitLabToken, transport: transport},
+		model.SourceGitea:  giteaSource{token: config.GiteaToken, transport: transport},
+		model.SourceStackOverflow: newStackOverflowSource(config.StackOverflow.Key, transport),
+	}
+	for _, repoConfig := range config.scrapeTargets() {
+		scraper.repoSources[repoConfig.Name] = repoConfig.sourceName()
+	}
+	scraper.scorer.SetDecayHalfLife(config.Scoring.DecayHalfLife())
+	scraper.scorer.SetWeights(config.Scoring.Weights())
+	scraper.scorer.SetQuestionDeboost(config.Scoring.QuestionDeboost)
 	
 	return scraper
 }
@@ -57,36 +293,99 @@ func NewScraper(config Config) *Scraper {
 // ScrapeRepositories scrapes issues from configured repositories
 func (s *Scraper) ScrapeRepositories(ctx context.Context, config Config) (map[string][]model.Issue, error) {
 	allIssues := make(map[string][]model.Issue)
-	
-	log.Printf("Starting to scrape %d repositories...", len(config.Repositories))
-	
-	for i, repoConfig := range config.Repositories {
+	err := s.ScrapeEach(ctx, config, func(repoName string, issues []model.Issue) {
+		allIssues[repoName] = issues
+	})
+	return allIssues, err
+}
+
+// ScrapeEach scrapes configured repositories with up to app.max_workers
+// fetching in parallel and hands each repository's issues to handle as soon
+// as they are fetched. handle runs on the calling goroutine, one repository
+// at a time, in the order fetches complete; fetching pauses while
+// app.worker_queue fetched repositories wait for it.
+func (s *Scraper) ScrapeEach(ctx context.Context, config Config, handle func(repoName string, issues []model.Issue)) error {
+	var targets []RepositoryConfig
+	for _, repoConfig := range config.scrapeTargets() {
 		if !repoConfig.Enabled {
 			log.Printf("Skipping disabled repository: %s", repoConfig.Name)
 			continue
 		}
-		
-		log.Printf("Scraping repository %d/%d: %s", i+1, len(config.Repositories), repoConfig.Name)
-		
-		issues, err := s.scrapeRepository(ctx, repoConfig)
-		if err != nil {
-			log.Printf("Error scraping %s: %v", repoConfig.Name, err)
+		if _, ok := s.sources[repoConfig.sourceName()]; !ok {
+			log.Printf("Skipping %s: unknown source %q", repoConfig.Name, repoConfig.Source)
 			continue
 		}
-		
-		allIssues[repoConfig.Name] = issues
-		log.Printf("Successfully scraped %d issues from %s", len(issues), repoConfig.Name)
-		
-		// Rate limiting between repositories
-		if i < len(config.Repositories)-1 {
-			time.Sleep(500 * time.Millisecond)
+		targets = append(targets, repoConfig)
+	}
+	
+	workers := min(max(config.App.MaxWorkers, 1), max(len(targets), 1))
+	log.Printf("Starting to scrape %d repositories with %d workers...", len(targets), workers)
+	
+	jobs := make(chan RepositoryConfig)
+	results := make(chan scrapeResult, max(config.App.WorkerQueue, 0))
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repoConfig := range jobs {
+				results <- s.fetchRepository(ctx, repoConfig)
+			}
+		}()
+	}
+	go func() {
+		defer close(results)
+		defer wg.Wait()
+		defer close(jobs)
+		for _, repoConfig := range targets {
+			jobs <- repoConfig
 		}
+	}()
+	
+	done, failed := 0, 0
+	for result := range results {
+		done++
+		if result.err != nil {
+			failed++
+			log.Printf("[%d/%d] Failed to scrape %s after %s: %v", done, len(targets), result.name, result.elapsed.Round(time.Millisecond), result.err)
+			continue
+		}
+		log.Printf("[%d/%d] Scraped %d issues from %s in %s", done, len(targets), len(result.issues), result.name, result.elapsed.Round(time.Millisecond))
+		// A panicking handler is recorded and the rest still run
+		s.safely("scrape", result.name, func() {
+			handle(result.name, result.issues)
+		})
 	}
 	
-	return allIssues, nil
+	log.Printf("Finished scraping: %d of %d repositories succeeded, %d failed", done-failed, len(targets), failed)
+	return nil
 }
 
-// scrapeRepository scrapes issues from a single repository
+// scrapeResult is the outcome of fetching one repository
+type scrapeResult struct {
+	name    string
+	issues  []model.Issue
+	err     error
+	elapsed time.Duration
+}
+
+// fetchRepository fetches the issues of one repository, recording a failure
+// or panic instead of aborting the run
+func (s *Scraper) fetchRepository(ctx context.Context, repoConfig RepositoryConfig) scrapeResult {
+	result := scrapeResult{name: repoConfig.Name}
+	start := time.Now()
+	if !s.safely("scrape", repoConfig.Name, func() {
+		result.issues, result.err = s.sources[repoConfig.sourceName()].FetchIssues(ctx, repoConfig)
+	}) {
+		result.err = fmt.Errorf("panic while fetching issues")
+	} else if result.err != nil {
+		s.recordFailure("scrape", repoConfig.Name, result.err)
+	}
+	result.elapsed = time.Since(start)
+	return result
+}
+
+// scrapeRepository scrapes issues from a single GitHub repository
 func (s *Scraper) scrapeRepository(ctx context.Context, repoConfig RepositoryConfig) ([]model.Issue, error) {
 	// Parse repository name (format: owner/repo)
 	parts := parseRepoName(repoConfig.Name)
@@ -97,7 +396,24 @@ func (s *Scraper) scrapeRepository(ctx context.Context, repoConfig RepositoryCon
 	owner, repo := parts[0], parts[1]
 	
 	// Fetch issues
-	githubIssues, err := s.githubClient.GetIssues(ctx, owner, repo, "all", repoConfig.MaxIssues)
+	var githubIssues []*github.Issue
+	var err error
+	since := s.since[repoConfig.Name]
+	if s.sample > 0 {
+		githubIssues, err = s.sampleIssues(ctx, owner, repo)
+	} else {
+		// The repository's last scrape time moves past every issue updated
+		// since the previous one, so incremental fetches are not capped:
+		// issues left out would never be fetched again
+		maxIssues := repoConfig.MaxIssues
+		if !since.IsZero() {
+			maxIssues = 0
+		}
+		githubIssues, err = s.githubClient.GetIssuesSince(ctx, owner, repo, "all", maxIssues, since)
+		s.fetchedMu.Lock()
+		s.fetchedSince[repoConfig.Name] = !since.IsZero()
+		s.fetchedMu.Unlock()
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch issues: %w", err)
 	}
@@ -107,14 +423,27 @@ func (s *Scraper) scrapeRepository(ctx context.Context, repoConfig RepositoryCon
 	for _, ghIssue := range githubIssues {
 		issue := s.convertGitHubIssue(ghIssue, repoConfig.Name)
 		issues = append(issues, issue)
-		
-		// Rate limiting between issues
-		time.Sleep(50 * time.Millisecond)
 	}
 	
 	return issues, nil
 }
 
+// sampleIssues draws the configured sample size from a repository, split
+// evenly between open and closed issues
+func (s *Scraper) sampleIssues(ctx context.Context, owner, repo string) ([]*github.Issue, error) {
+	open, err := s.githubClient.SampleIssues(ctx, owner, repo, "open", s.sample/2)
+	if err != nil {
+		return nil, err
+	}
+	
+	closed, err := s.githubClient.SampleIssues(ctx, owner, repo, "closed", s.sample-len(open))
+	if err != nil {
+		return nil, err
+	}
+	
+	return append(open, closed...), nil
+}
+
 // convertGitHubIssue converts GitHub API issue to our model
 func (s *Scraper) convertGitHubIssue(ghIssue *github.Issue, repoName string) model.Issue {
 	// Extract labels
@@ -185,30 +514,191 @@ func (s *Scraper) convertGitHubIssue(ghIssue *github.Issue, repoName string) mod
 		Number:      ghIssue.GetNumber(),
 		Title:       title,
 		Body:        body,
+		StructuredFields: ParseIssueForm(body),
 		URL:         url,
 		State:       state,
+		StateReason: ghIssue.GetStateReason(),
+		ClosedBy:    ghIssue.GetClosedBy().GetLogin(),
 		CreatedAt:   createdAt,
 		UpdatedAt:   updatedAt,
 		Labels:      labels,
 		Comments:    comments,
 		Reactions:   reactions,
+		Author:      ghIssue.GetUser().GetLogin(),
+		Platforms:   DetectPlatforms(title + "\n" + body),
 		Repository:  repoName,
+		Source:      model.SourceGitHub,
 		Score:       0, // Will be calculated later
 		ScoreReason: []string{},
 	}
 }
 
+// SnapshotRepositories captures stars, forks, open issue counts, license and
+// archive status for scraped GitHub repositories
+func (s *Scraper) SnapshotRepositories(ctx context.Context, filteredIssues map[string][]model.Issue) []model.RepoSnapshot {
+	var snapshots []model.RepoSnapshot
+	now := time.Now()
+	
+	for repoName, issues := range filteredIssues {
+		parts := parseRepoName(repoName)
+		if len(parts) != 2 || !s.isGitHubRepo(repoName) {
+			continue
+		}
+		
+		info, err := s.githubClient.GetRepoInfo(ctx, parts[0], parts[1])
+		if err != nil {
+			log.Printf("Error fetching repository info for %s: %v", repoName, err)
+			continue
+		}
+		
+		snapshot := repoSnapshot(repoName, info, now)
+		snapshot.PitfallCount = len(issues)
+		snapshots = append(snapshots, snapshot)
+	}
+	
+	return snapshots
+}
+
+// RefreshRepository fetches the metadata of a GitHub repository with a
+// conditional request on etag, returning client.ErrNotModified when it is
+// unchanged, and the ETag for the next refresh. The snapshot's pitfall
+// count is left for the caller.
+func (s *Scraper) RefreshRepository(ctx context.Context, repoName, etag string) (model.RepoSnapshot, string, error) {
+	parts := parseRepoName(repoName)
+	if len(parts) != 2 {
+		return model.RepoSnapshot{}, "", fmt.Errorf("invalid repository name %s", repoName)
+	}
+	info, etag, err := s.githubClient.GetRepoInfoIfChanged(ctx, parts[0], parts[1], etag)
+	if err != nil {
+		return model.RepoSnapshot{}, etag, err
+	}
+	return repoSnapshot(repoName, info, time.Now()), etag, nil
+}
+
+// repoSnapshot records the health metrics of repository info
+func repoSnapshot(repoName string, info *github.Repository, now time.Time) model.RepoSnapshot {
+	license := info.GetLicense().GetSPDXID()
+	if license == "" {
+		license = model.NoLicense
+	}
+	return model.RepoSnapshot{
+		Repository:    repoName,
+		CapturedAt:    now,
+		Stars:         info.GetStargazersCount(),
+		Forks:         info.GetForksCount(),
+		OpenIssues:    info.GetOpenIssuesCount(),
+		License:       license,
+		Archived:      info.GetArchived(),
+		DefaultBranch: info.GetDefaultBranch(),
+	}
+}
+
+// isGitHubRepo reports whether a scraped repository came from GitHub
+func (s *Scraper) isGitHubRepo(repoName string) bool {
+	source, ok := s.repoSources[repoName]
+	return !ok || source == model.SourceGitHub
+}
+
+// RequestCounts returns the API requests sent so far per host
+func (s *Scraper) RequestCounts() map[string]int {
+	return client.RequestCounts(s.transport)
+}
+
+// PoolStats returns the concurrency slot usage so far per API host
+func (s *Scraper) PoolStats() map[string]client.PoolStats {
+	return client.HostPoolStats(s.transport)
+}
+
+// GitHubRequests returns the label of the GitHub token in use and the
+// number of GitHub API requests sent with it so far
+func (s *Scraper) GitHubRequests() (string, int) {
+	return s.githubClient.TokenLabel(), s.githubClient.Requests()
+}
+
+// UseReporterReputation enables reporter reputation as a scoring signal
+func (s *Scraper) UseReporterReputation(reputation map[string]float64, weight float64) {
+	s.scorer.SetReporterReputation(reputation, weight)
+}
+
+// UseCorrections makes manual category corrections override the
+// classification rules
+func (s *Scraper) UseCorrections(corrections []model.CategoryCorrection) {
+	byRef := make(map[string]model.CategoryCorrection, len(corrections))
+	for _, correction := range corrections {
+		byRef[model.IssueRef(correction.Repository, correction.IssueNumber)] = correction
+	}
+	s.correctionsMu.Lock()
+	s.corrections = byRef
+	s.correctionsMu.Unlock()
+}
+
+// correction returns the manual correction of an issue, if any
+func (s *Scraper) correction(issue model.Issue) (model.CategoryCorrection, bool) {
+	s.correctionsMu.RLock()
+	defer s.correctionsMu.RUnlock()
+	correction, ok := s.corrections[model.IssueRef(issue.Repository, issue.Number)]
+	return correction, ok
+}
+
+// ExplainClassification sets Classification on issues: the rule matches
+// of the current rules, or the manual correction overriding them
+func (s *Scraper) ExplainClassification(issues []model.Issue) {
+	for i := range issues {
+		result := s.filter.Classify(issues[i])
+		result.Version = s.versions.Classification
+		if correction, ok := s.correction(issues[i]); ok && correction.Category != "" {
+			result.Category = correction.Category
+			result.Source = model.ClassifiedByCorrection
+			result.CorrectedBy = correction.Author
+		}
+		issues[i].Classification = &result
+	}
+}
+
+// applyCorrections replaces the category and priority of manually
+// corrected issues
+func (s *Scraper) applyCorrections(issues []model.Issue) {
+	for i := range issues {
+		correction, ok := s.correction(issues[i])
+		if !ok {
+			continue
+		}
+		if correction.Category != "" {
+			issues[i].Category = correction.Category
+		}
+		if correction.Priority != "" {
+			issues[i].Priority = correction.Priority
+		}
+	}
+}
+
 // FilterAndScoreIssues filters and scores all collected issues
 func (s *Scraper) FilterAndScoreIssues(allIssues map[string][]model.Issue, config Config) map[string][]model.Issue {
 	filteredIssues := make(map[string][]model.Issue)
 	
 	for repoName, issues := range allIssues {
-		// Apply filtering and scoring
-		filtered := s.filter.FilterIssues(issues, s.scorer)
-		filteredIssues[repoName] = filtered
-		
-		log.Printf("Repository %s: %d issues filtered from %d total", 
-			repoName, len(filtered), len(issues))
+		s.safely("filter", repoName, func() {
+			// Flag abandoned issues before filtering so filters can use the flag
+			s.abandoned.MarkIssues(issues)
+			markPlainText(issues)
+			markQuestions(issues)
+			
+			// Reuse upstream severity labels and manual priorities before scoring
+			s.severity.MarkIssues(issues)
+			s.applyCorrections(issues)
+			
+			// Apply filtering and scoring
+			filtered := s.filter.FilterIssues(issues, s.scorer)
+			s.applyCorrections(filtered)
+			markWeaknesses(filtered)
+			s.teams.AssignIssues(filtered)
+			s.portfolios.MarkIssues(filtered)
+			s.stampVersions(filtered)
+			filteredIssues[repoName] = filtered
+			
+			log.Printf("Repository %s: %d issues filtered from %d total", 
+				repoName, len(filtered), len(issues))
+		})
 	}
 	
 	return filteredIssues
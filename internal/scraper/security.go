@@ -0,0 +1,132 @@
+package scraper
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// SecurityCategory is the category of issues reporting vulnerabilities
+const SecurityCategory = "security"
+
+// OWASP Top 10 (2021) categories
+const (
+	OWASPBrokenAccessControl = "A01:2021-Broken Access Control"
+	OWASPCryptographic       = "A02:2021-Cryptographic Failures"
+	OWASPInjection           = "A03:2021-Injection"
+	OWASPMisconfiguration    = "A05:2021-Security Misconfiguration"
+	OWASPAuthentication      = "A07:2021-Identification and Authentication Failures"
+	OWASPIntegrity           = "A08:2021-Software and Data Integrity Failures"
+	OWASPSSRF                = "A10:2021-Server-Side Request Forgery"
+)
+
+// cvePattern finds CVE identifiers cited in an issue
+var cvePattern = regexp.MustCompile(`(?i)\bCVE-(\d{4})-(\d{4,7})\b`)
+
+// cwePattern finds CWE identifiers cited in an issue
+var cwePattern = regexp.MustCompile(`(?i)\bCWE[- ]?(\d{1,4})\b`)
+
+// weaknessRule infers a CWE from phrases describing the weakness
+type weaknessRule struct {
+	cwe     int
+	pattern *regexp.Regexp
+}
+
+// weaknessRules infer CWEs for issues that describe a weakness without citing it
+var weaknessRules = []weaknessRule{
+	{89, regexp.MustCompile(`(?i)\bsql injection\b`)},
+	{78, regexp.MustCompile(`(?i)\b(command|shell|os command) injection\b`)},
+	{77, regexp.MustCompile(`(?i)\bprompt injection\b`)},
+	{79, regexp.MustCompile(`(?i)\b(xss|cross[- ]site scripting)\b`)},
+	{352, regexp.MustCompile(`(?i)\b(csrf|cross[- ]site request forgery)\b`)},
+	{22, regexp.MustCompile(`(?i)\b(path|directory) traversal\b`)},
+	{918, regexp.MustCompile(`(?i)\b(ssrf|server[- ]side request forgery)\b`)},
+	{502, regexp.MustCompile(`(?i)\b(unsafe|insecure|untrusted) (deserialization|unpickling)\b|\bpickle\.loads?\b`)},
+	{94, regexp.MustCompile(`(?i)\b(remote code execution|arbitrary code execution|trust_remote_code)\b`)},
+	{611, regexp.MustCompile(`(?i)\b(xxe|xml external entit(y|ies))\b`)},
+	{798, regexp.MustCompile(`(?i)\bhard[- ]?coded (credentials?|passwords?|secrets?|tokens?)\b`)},
+	{306, regexp.MustCompile(`(?i)\b(missing|no|without) authentication\b|\bunauthenticated (access|endpoint)\b`)},
+	{327, regexp.MustCompile(`(?i)\b(weak|broken|insecure) (cipher|crypto|encryption|hash)\b`)},
+	{120, regexp.MustCompile(`(?i)\bbuffer overflow\b`)},
+	{400, regexp.MustCompile(`(?i)\b(resource exhaustion|redos|denial of service)\b`)},
+}
+
+// cweOWASP maps CWEs to the OWASP Top 10 category they belong to
+var cweOWASP = map[int]string{
+	22:  OWASPBrokenAccessControl,
+	352: OWASPBrokenAccessControl,
+	327: OWASPCryptographic,
+	77:  OWASPInjection,
+	78:  OWASPInjection,
+	79:  OWASPInjection,
+	89:  OWASPInjection,
+	94:  OWASPInjection,
+	611: OWASPMisconfiguration,
+	306: OWASPAuthentication,
+	798: OWASPAuthentication,
+	502: OWASPIntegrity,
+	918: OWASPSSRF,
+}
+
+// DetectWeaknesses returns the CWE identifiers cited in or inferred from
+// text and the OWASP categories they map to, each sorted
+func DetectWeaknesses(text string) (cwes []string, owasp []string) {
+	found := make(map[int]bool)
+	for _, match := range cwePattern.FindAllStringSubmatch(text, -1) {
+		if id, err := strconv.Atoi(match[1]); err == nil && id > 0 {
+			found[id] = true
+		}
+	}
+	for _, rule := range weaknessRules {
+		if rule.pattern.MatchString(text) {
+			found[rule.cwe] = true
+		}
+	}
+
+	ids := make([]int, 0, len(found))
+	for id := range found {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	categories := make(map[string]bool)
+	for _, id := range ids {
+		cwes = append(cwes, "CWE-"+strconv.Itoa(id))
+		if category, ok := cweOWASP[id]; ok && !categories[category] {
+			categories[category] = true
+			owasp = append(owasp, category)
+		}
+	}
+	sort.Strings(owasp)
+	return cwes, owasp
+}
+
+// DetectCVEs returns the CVE identifiers cited in text, upper-cased and sorted
+func DetectCVEs(text string) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, match := range cvePattern.FindAllStringSubmatch(text, -1) {
+		id := "CVE-" + match[1] + "-" + match[2]
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// markWeaknesses records the CVEs cited by issues and sets the CWE and OWASP
+// fields of security issues, clearing them on all others
+func markWeaknesses(issues []model.Issue) {
+	for i := range issues {
+		issues[i].CVEs = DetectCVEs(issues[i].Title + "\n" + issues[i].Body)
+		if issues[i].Category != SecurityCategory {
+			issues[i].CWEs, issues[i].OWASP = nil, nil
+			continue
+		}
+		issues[i].CWEs, issues[i].OWASP = DetectWeaknesses(issues[i].Title + "\n" + issues[i].Body)
+	}
+}
@@ -0,0 +1,130 @@
+package scraper
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// Priorities are the normalized upstream priorities, most severe first
+var Priorities = []string{"critical", "high", "medium", "low"}
+
+// priorityPoints are the score points added for each upstream priority
+var priorityPoints = map[string]float64{
+	"critical": 10.0,
+	"high":     6.0,
+	"medium":   3.0,
+	"low":      0.0,
+}
+
+// SeverityConfig maps upstream severity labels to priorities.
+// Repository mappings extend and override the global label mapping.
+type SeverityConfig struct {
+	Labels       map[string]string    `yaml:"labels"`
+	Repositories []RepoSeverityConfig `yaml:"repositories"`
+}
+
+// RepoSeverityConfig is the label mapping for a single repository
+type RepoSeverityConfig struct {
+	Name   string            `yaml:"name"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+// Validate checks that every mapping targets a known priority
+func (c SeverityConfig) Validate() error {
+	check := func(labels map[string]string) error {
+		for label, priority := range labels {
+			if priorityRank(priority) < 0 {
+				return fmt.Errorf("severity label %q maps to unknown priority %q (expected one of %v)", label, priority, Priorities)
+			}
+		}
+		return nil
+	}
+
+	if err := check(c.Labels); err != nil {
+		return err
+	}
+	for _, repo := range c.Repositories {
+		if err := check(repo.Labels); err != nil {
+			return fmt.Errorf("%s: %w", repo.Name, err)
+		}
+	}
+	return nil
+}
+
+// SeverityMapper resolves an issue's upstream priority from its labels
+type SeverityMapper struct {
+	global map[string]string
+	repos  map[string]map[string]string
+}
+
+// NewSeverityMapper creates a severity mapper from configuration
+func NewSeverityMapper(config SeverityConfig) *SeverityMapper {
+	mapper := &SeverityMapper{
+		global: lowerKeys(config.Labels),
+		repos:  make(map[string]map[string]string),
+	}
+	for _, repo := range config.Repositories {
+		mapper.repos[strings.ToLower(repo.Name)] = lowerKeys(repo.Labels)
+	}
+	return mapper
+}
+
+// Priority returns the most severe priority mapped from the issue's labels,
+// or an empty string if none of its labels are mapped
+func (m *SeverityMapper) Priority(issue model.Issue) string {
+	repoLabels := m.repos[strings.ToLower(issue.Repository)]
+
+	best := ""
+	for _, label := range issue.Labels {
+		name := strings.ToLower(label.Name)
+		priority, ok := repoLabels[name]
+		if !ok {
+			priority, ok = m.global[name]
+		}
+		if !ok {
+			continue
+		}
+		if best == "" || priorityRank(priority) < priorityRank(best) {
+			best = strings.ToLower(priority)
+		}
+	}
+	return best
+}
+
+// MarkIssues sets Priority on every issue in place
+func (m *SeverityMapper) MarkIssues(issues []model.Issue) {
+	for i := range issues {
+		issues[i].Priority = m.Priority(issues[i])
+	}
+}
+
+// Escalated reports whether current is a more severe priority than
+// previous; unknown priorities rank below all known ones
+func Escalated(previous, current string) bool {
+	rank := priorityRank(current)
+	if rank < 0 {
+		return false
+	}
+	return priorityRank(previous) < 0 || rank < priorityRank(previous)
+}
+
+// priorityRank returns the index of a priority in Priorities, or -1 if unknown
+func priorityRank(priority string) int {
+	for i, p := range Priorities {
+		if strings.EqualFold(p, priority) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lowerKeys copies a label mapping with lowercased label names
+func lowerKeys(labels map[string]string) map[string]string {
+	lowered := make(map[string]string, len(labels))
+	for label, priority := range labels {
+		lowered[strings.ToLower(label)] = priority
+	}
+	return lowered
+}
@@ -0,0 +1,164 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/client"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// Source fetches the issues of one configured repository from an issue tracker
+type Source interface {
+	FetchIssues(ctx context.Context, repoConfig RepositoryConfig) ([]model.Issue, error)
+}
+
+// ValidSources lists the accepted values of a repository's source setting
+var ValidSources = []string{model.SourceGitHub, model.SourceGitLab, model.SourceGitea}
+
+// sourceName returns the configured source of a repository, GitHub by default
+func (r RepositoryConfig) sourceName() string {
+	if r.Source == "" {
+		return model.SourceGitHub
+	}
+	return r.Source
+}
+
+// githubSource fetches GitHub issues through the scraper's GitHub client
+type githubSource struct {
+	scraper *Scraper
+}
+
+func (g githubSource) FetchIssues(ctx context.Context, repoConfig RepositoryConfig) ([]model.Issue, error) {
+	return g.scraper.scrapeRepository(ctx, repoConfig)
+}
+
+// gitlabSource fetches issues from gitlab.com or a self-hosted GitLab
+type gitlabSource struct {
+	token     string
+	transport http.RoundTripper
+}
+
+func (g gitlabSource) FetchIssues(ctx context.Context, repoConfig RepositoryConfig) ([]model.Issue, error) {
+	token := g.token
+	if repoConfig.Token != "" {
+		token = repoConfig.Token
+	}
+	gitlab := client.NewGitLabClient(repoConfig.BaseURL, token, g.transport)
+
+	gitlabIssues, err := gitlab.GetIssues(ctx, repoConfig.Name, "all", repoConfig.MaxIssues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+
+	issues := make([]model.Issue, 0, len(gitlabIssues))
+	for _, gitlabIssue := range gitlabIssues {
+		issues = append(issues, convertGitLabIssue(gitlabIssue, repoConfig.Name))
+	}
+	return issues, nil
+}
+
+// convertGitLabIssue maps a GitLab issue onto the issue model. Notes count as
+// comments and votes as reactions.
+func convertGitLabIssue(gitlabIssue client.GitLabIssue, repoName string) model.Issue {
+	labels := make([]model.Label, 0, len(gitlabIssue.Labels))
+	for _, name := range gitlabIssue.Labels {
+		labels = append(labels, model.Label{Name: name})
+	}
+
+	state := gitlabIssue.State
+	if state == "opened" {
+		state = "open"
+	}
+
+	closedBy := ""
+	if gitlabIssue.ClosedBy != nil {
+		closedBy = gitlabIssue.ClosedBy.Username
+	}
+
+	return model.Issue{
+		ID:               int(gitlabIssue.ID),
+		Number:           gitlabIssue.IID,
+		Title:            gitlabIssue.Title,
+		Body:             gitlabIssue.Description,
+		StructuredFields: ParseIssueForm(gitlabIssue.Description),
+		URL:              gitlabIssue.WebURL,
+		State:            state,
+		ClosedBy:         closedBy,
+		CreatedAt:        gitlabIssue.CreatedAt,
+		UpdatedAt:        gitlabIssue.UpdatedAt,
+		Labels:           labels,
+		Comments:         gitlabIssue.UserNotesCount,
+		Reactions:        gitlabIssue.Upvotes + gitlabIssue.Downvotes,
+		Author:           gitlabIssue.Author.Username,
+		Platforms:        DetectPlatforms(gitlabIssue.Title + "\n" + gitlabIssue.Description),
+		Repository:       repoName,
+		Source:           model.SourceGitLab,
+		ScoreReason:      []string{},
+	}
+}
+
+// giteaSource fetches issues from a Gitea or Forgejo instance
+type giteaSource struct {
+	token     string
+	transport http.RoundTripper
+}
+
+func (g giteaSource) FetchIssues(ctx context.Context, repoConfig RepositoryConfig) ([]model.Issue, error) {
+	parts := parseRepoName(repoConfig.Name)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid repository name format: %s (expected owner/repo)", repoConfig.Name)
+	}
+	if repoConfig.BaseURL == "" {
+		return nil, fmt.Errorf("gitea repository %s needs a base_url", repoConfig.Name)
+	}
+
+	token := g.token
+	if repoConfig.Token != "" {
+		token = repoConfig.Token
+	}
+	gitea := client.NewGiteaClient(repoConfig.BaseURL, token, g.transport)
+
+	giteaIssues, err := gitea.GetIssues(ctx, parts[0], parts[1], "all", repoConfig.MaxIssues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch issues: %w", err)
+	}
+
+	issues := make([]model.Issue, 0, len(giteaIssues))
+	for _, giteaIssue := range giteaIssues {
+		issues = append(issues, convertGiteaIssue(giteaIssue, repoConfig.Name))
+	}
+	return issues, nil
+}
+
+// convertGiteaIssue maps a Gitea/Forgejo issue onto the issue model
+func convertGiteaIssue(giteaIssue client.GiteaIssue, repoName string) model.Issue {
+	labels := make([]model.Label, 0, len(giteaIssue.Labels))
+	for _, label := range giteaIssue.Labels {
+		labels = append(labels, model.Label{
+			Name:        label.Name,
+			Description: label.Description,
+			Color:       label.Color,
+		})
+	}
+
+	return model.Issue{
+		ID:               int(giteaIssue.ID),
+		Number:           giteaIssue.Number,
+		Title:            giteaIssue.Title,
+		Body:             giteaIssue.Body,
+		StructuredFields: ParseIssueForm(giteaIssue.Body),
+		URL:              giteaIssue.HTMLURL,
+		State:            giteaIssue.State,
+		CreatedAt:        giteaIssue.CreatedAt,
+		UpdatedAt:        giteaIssue.UpdatedAt,
+		Labels:           labels,
+		Comments:         giteaIssue.Comments,
+		Author:           giteaIssue.User.Login,
+		Platforms:        DetectPlatforms(giteaIssue.Title + "\n" + giteaIssue.Body),
+		Repository:       repoName,
+		Source:           model.SourceGitea,
+		ScoreReason:      []string{},
+	}
+}
@@ -0,0 +1,115 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/client"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// StackOverflowConfig selects Stack Overflow tags whose top questions join the corpus
+type StackOverflowConfig struct {
+	Enabled      bool     `yaml:"enabled"`
+	Tags         []string `yaml:"tags"`
+	Keywords     []string `yaml:"keywords"`
+	MaxQuestions int      `yaml:"max_questions"`
+	Key          string   `yaml:"key"`
+}
+
+// stackOverflowPrefix prefixes the pseudo repository name of a tag, e.g. stackoverflow/gorm
+const stackOverflowPrefix = "stackoverflow/"
+
+// htmlTag matches HTML tags in question bodies
+var htmlTag = regexp.MustCompile(`<[^>]+>`)
+
+// repositories returns one pseudo repository per configured tag so tags are
+// scraped, filtered and stored like repositories
+func (c StackOverflowConfig) repositories() []RepositoryConfig {
+	if !c.Enabled {
+		return nil
+	}
+
+	repos := make([]RepositoryConfig, 0, len(c.Tags))
+	for _, tag := range c.Tags {
+		repos = append(repos, RepositoryConfig{
+			Name:      stackOverflowPrefix + tag,
+			Enabled:   true,
+			Keywords:  c.Keywords,
+			MaxIssues: c.MaxQuestions,
+			Source:    model.SourceStackOverflow,
+		})
+	}
+	return repos
+}
+
+// stackOverflowSource fetches the top questions of a tag
+type stackOverflowSource struct {
+	client *client.StackOverflowClient
+}
+
+func newStackOverflowSource(key string, transport http.RoundTripper) stackOverflowSource {
+	return stackOverflowSource{client: client.NewStackOverflowClient(key, transport)}
+}
+
+func (s stackOverflowSource) FetchIssues(ctx context.Context, repoConfig RepositoryConfig) ([]model.Issue, error) {
+	tag := strings.TrimPrefix(repoConfig.Name, stackOverflowPrefix)
+	questions, err := s.client.GetTopQuestions(ctx, tag, repoConfig.MaxIssues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch questions: %w", err)
+	}
+
+	issues := make([]model.Issue, 0, len(questions))
+	for _, question := range questions {
+		issues = append(issues, convertStackOverflowQuestion(question, repoConfig.Name))
+	}
+	return issues, nil
+}
+
+// convertStackOverflowQuestion maps a question onto the issue model. A
+// question with an accepted answer counts as closed, answers count as
+// comments and the vote score as reactions.
+func convertStackOverflowQuestion(question client.StackOverflowQuestion, repoName string) model.Issue {
+	labels := make([]model.Label, 0, len(question.Tags))
+	for _, tag := range question.Tags {
+		labels = append(labels, model.Label{Name: tag})
+	}
+
+	state, stateReason := "open", ""
+	if question.AcceptedAnswerID != 0 {
+		state, stateReason = "closed", "completed"
+	}
+
+	reactions := question.Score
+	if reactions < 0 {
+		reactions = 0
+	}
+
+	title := html.UnescapeString(question.Title)
+	body := html.UnescapeString(htmlTag.ReplaceAllString(question.Body, ""))
+
+	return model.Issue{
+		ID:          question.QuestionID,
+		Number:      question.QuestionID,
+		Title:       title,
+		Body:        body,
+		URL:         question.Link,
+		State:       state,
+		StateReason: stateReason,
+		CreatedAt:   time.Unix(question.CreationDate, 0),
+		UpdatedAt:   time.Unix(question.LastActivityDate, 0),
+		Labels:      labels,
+		Comments:    question.AnswerCount,
+		Reactions:   reactions,
+		Author:      question.Owner.DisplayName,
+		Platforms:   DetectPlatforms(title + "\n" + body),
+		Repository:  repoName,
+		Source:      model.SourceStackOverflow,
+		ScoreReason: []string{},
+	}
+}
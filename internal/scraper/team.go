@@ -0,0 +1,64 @@
+package scraper
+
+import (
+	"strings"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// TeamConfig maps categories and repositories to an owning team. Alerts
+// and escalations about the team's issues are also posted to its webhooks.
+type TeamConfig struct {
+	Name         string   `yaml:"name"`
+	Categories   []string `yaml:"categories"`
+	Repositories []string `yaml:"repositories"`
+	Webhooks     []string `yaml:"webhooks"`
+}
+
+// TeamAssigner resolves the team responsible for an issue
+type TeamAssigner struct {
+	teams []TeamConfig
+}
+
+// NewTeamAssigner creates a team assigner from configuration
+func NewTeamAssigner(teams []TeamConfig) *TeamAssigner {
+	return &TeamAssigner{teams: teams}
+}
+
+// Assign returns the team for an issue, or an empty string if no team matches.
+// Repository mappings are more specific and take precedence over categories.
+func (a *TeamAssigner) Assign(issue model.Issue) string {
+	for _, team := range a.teams {
+		if containsFold(team.Repositories, issue.Repository) {
+			return team.Name
+		}
+	}
+
+	for _, team := range a.teams {
+		if containsFold(team.Categories, issue.Category) {
+			return team.Name
+		}
+	}
+
+	return ""
+}
+
+// AssignIssues sets AssignedTeam on every issue in place
+func (a *TeamAssigner) AssignIssues(issues []model.Issue) {
+	for i := range issues {
+		issues[i].AssignedTeam = a.Assign(issues[i])
+	}
+}
+
+// containsFold checks if a slice contains a string, ignoring case
+func containsFold(slice []string, item string) bool {
+	if item == "" {
+		return false
+	}
+	for _, s := range slice {
+		if strings.EqualFold(s, item) {
+			return true
+		}
+	}
+	return false
+}
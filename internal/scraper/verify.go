@@ -0,0 +1,134 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/client"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// maxVerifyProbes bounds the single-issue lookups of one reconciliation
+const maxVerifyProbes = 50
+
+// Reconciliation compares the stored issues of a repository with GitHub
+type Reconciliation struct {
+	Repository   string
+	StoredOpen   int
+	StoredClosed int
+	LiveOpen     int
+	LiveClosed   int
+	// Window is how many issues a scrape fetches (max_issues); MissedIssues
+	// is how many live issues fall outside it
+	Window       int
+	MissedIssues int
+	// StateChanged lists stored issues whose state differs upstream
+	StateChanged []int
+	// Deleted lists stored issues that no longer exist upstream
+	Deleted []int
+	// Transferred maps stored issues to their new owner/repo#number
+	Transferred map[int]string
+	// Unchecked lists stored issues outside the scrape window that were
+	// not looked up because the probe budget ran out
+	Unchecked []int
+}
+
+// HasDiscrepancies reports whether the stored issues are out of date
+func (r Reconciliation) HasDiscrepancies() bool {
+	return r.MissedIssues > 0 || len(r.StateChanged) > 0 || len(r.Deleted) > 0 || len(r.Transferred) > 0
+}
+
+// Reconcile compares stored issues of a GitHub repository with the live
+// repository. Stored issues missing from the current scrape window are
+// looked up one by one to tell deleted and transferred issues apart.
+func (s *Scraper) Reconcile(ctx context.Context, repoConfig RepositoryConfig, stored []model.Issue) (Reconciliation, error) {
+	result := Reconciliation{
+		Repository:  repoConfig.Name,
+		Window:      repoConfig.MaxIssues,
+		Transferred: make(map[int]string),
+	}
+
+	parts := parseRepoName(repoConfig.Name)
+	if len(parts) != 2 {
+		return result, fmt.Errorf("invalid repository name format: %s (expected owner/repo)", repoConfig.Name)
+	}
+	if !s.isGitHubRepo(repoConfig.Name) {
+		return result, fmt.Errorf("%s is not a GitHub repository", repoConfig.Name)
+	}
+	owner, repo := parts[0], parts[1]
+
+	for _, issue := range stored {
+		if issue.State == "closed" {
+			result.StoredClosed++
+		} else {
+			result.StoredOpen++
+		}
+	}
+
+	var err error
+	if result.LiveOpen, err = s.githubClient.CountIssues(ctx, owner, repo, "open"); err != nil {
+		return result, err
+	}
+	if result.LiveClosed, err = s.githubClient.CountIssues(ctx, owner, repo, "closed"); err != nil {
+		return result, err
+	}
+	if live := result.LiveOpen + result.LiveClosed; result.Window > 0 && live > result.Window {
+		result.MissedIssues = live - result.Window
+	}
+
+	window, err := s.githubClient.GetIssues(ctx, owner, repo, "all", repoConfig.MaxIssues)
+	if err != nil {
+		return result, err
+	}
+	liveStates := make(map[int]string, len(window))
+	for _, issue := range window {
+		liveStates[issue.GetNumber()] = issue.GetState()
+	}
+
+	probes := 0
+	for _, issue := range stored {
+		state, ok := liveStates[issue.Number]
+		if !ok {
+			if probes >= maxVerifyProbes {
+				result.Unchecked = append(result.Unchecked, issue.Number)
+				continue
+			}
+			probes++
+
+			live, err := s.githubClient.GetIssue(ctx, owner, repo, issue.Number)
+			if errors.Is(err, client.ErrIssueGone) {
+				result.Deleted = append(result.Deleted, issue.Number)
+				continue
+			}
+			if err != nil {
+				log.Printf("Error checking %s#%d: %v", repoConfig.Name, issue.Number, err)
+				result.Unchecked = append(result.Unchecked, issue.Number)
+				continue
+			}
+			if moved := issueRepository(live.GetRepositoryURL()); moved != "" && !strings.EqualFold(moved, repoConfig.Name) {
+				result.Transferred[issue.Number] = model.IssueRef(moved, live.GetNumber())
+				continue
+			}
+			state = live.GetState()
+		}
+
+		if state != issue.State {
+			result.StateChanged = append(result.StateChanged, issue.Number)
+		}
+	}
+
+	return result, nil
+}
+
+// issueRepository extracts owner/repo from an API repository URL such as
+// https://api.github.com/repos/owner/repo
+func issueRepository(repositoryURL string) string {
+	_, repo, ok := strings.Cut(repositoryURL, "/repos/")
+	if !ok {
+		return ""
+	}
+	return repo
+}
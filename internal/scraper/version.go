@@ -0,0 +1,66 @@
+package scraper
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// Rule versions are bumped whenever the built-in rules behind a derived
+// field change
+const (
+	ClassificationRules = 5
+	ScoringRules        = 2
+	PlatformRules       = 1
+)
+
+// Versions identifies the rules and configuration that derived fields are
+// computed with
+type Versions struct {
+	Classification string
+	Score          string
+	Platforms      string
+}
+
+// deriveVersions computes the current versions from the built-in rule
+// versions and the configuration each derived field depends on
+func deriveVersions(config Config) Versions {
+	return Versions{
+		Classification: ruleVersion(ClassificationRules, config.Severity),
+		Score:          ruleVersion(ScoringRules, config.Scoring),
+		Platforms:      fmt.Sprint(PlatformRules),
+	}
+}
+
+// ruleVersion combines a rule version with a hash of its configuration, e.g. "1-3fa2c1d0"
+func ruleVersion(rules int, config interface{}) string {
+	data, _ := json.Marshal(config)
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%d-%x", rules, sum[:4])
+}
+
+// Versions returns the versions issues derived by this scraper are stamped with
+func (s *Scraper) Versions() Versions {
+	return s.versions
+}
+
+// Outdated reports which derived fields of an issue were produced by older versions
+func (v Versions) Outdated(issue model.Issue) (classification, score, platforms bool) {
+	return issue.ClassificationVersion != v.Classification,
+		issue.ScoreVersion != v.Score,
+		issue.PlatformsVersion != v.Platforms
+}
+
+// stampVersions records the current versions of all derived fields on issues
+// and hashes their content with the default algorithm
+func (s *Scraper) stampVersions(issues []model.Issue) {
+	for i := range issues {
+		issues[i].ContentHash, _ = model.ContentHash(issues[i], model.DefaultHashAlgorithm)
+		issues[i].HashAlgorithm = model.DefaultHashAlgorithm
+		issues[i].ClassificationVersion = s.versions.Classification
+		issues[i].ScoreVersion = s.versions.Score
+		issues[i].PlatformsVersion = s.versions.Platforms
+	}
+}
@@ -0,0 +1,100 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// minWorkaroundConfidence is the confidence a comment needs to be reported
+const minWorkaroundConfidence = 0.4
+
+// workaroundPhrases are phrases that usually introduce a workaround, with their weight
+var workaroundPhrases = map[string]float64{
+	"as a workaround":     0.5,
+	"workaround":          0.35,
+	"temporary fix":       0.4,
+	"temp fix":            0.35,
+	"quick fix":           0.3,
+	"i fixed it by":       0.4,
+	"fixed it by":         0.35,
+	"solved it by":        0.35,
+	"resolved by":         0.25,
+	"you can try":         0.2,
+	"try setting":         0.25,
+	"downgrading to":      0.3,
+	"downgrade to":        0.3,
+	"pinning":             0.2,
+	"this works for me":   0.3,
+	"works for me":        0.2,
+	"in the meantime":     0.2,
+	"until this is fixed": 0.3,
+}
+
+// codeAfterPhrase matches a workaround phrase followed by a code block or inline code
+var codeAfterPhrase = regexp.MustCompile("(?is)(workaround|temporary fix|temp fix|fixed it by|try setting).{0,200}(```|`[^`]+`)")
+
+// maintainerAssociations are author associations that carry maintainer authority
+var maintainerAssociations = []string{"OWNER", "MEMBER", "COLLABORATOR"}
+
+// DetectWorkaround returns the most likely workaround among an issue's comments,
+// or nil if no comment looks like one
+func DetectWorkaround(comments []*github.IssueComment) *model.Workaround {
+	var best *model.Workaround
+
+	for _, comment := range comments {
+		confidence := workaroundConfidence(comment)
+		if confidence < minWorkaroundConfidence {
+			continue
+		}
+		if best == nil || confidence > best.Confidence {
+			best = &model.Workaround{
+				Author:     comment.GetUser().GetLogin(),
+				URL:        comment.GetHTMLURL(),
+				Body:       comment.GetBody(),
+				Confidence: confidence,
+			}
+		}
+	}
+
+	return best
+}
+
+// workaroundConfidence scores how likely a comment contains a workaround (0-1)
+func workaroundConfidence(comment *github.IssueComment) float64 {
+	body := comment.GetBody()
+	text := strings.ToLower(body)
+
+	var confidence float64
+	for phrase, weight := range workaroundPhrases {
+		if strings.Contains(text, phrase) {
+			confidence += weight
+		}
+	}
+	if confidence == 0 {
+		return 0
+	}
+
+	// Concrete instructions right after the phrase are a strong signal
+	if codeAfterPhrase.MatchString(body) {
+		confidence += 0.3
+	}
+
+	// Maintainer-suggested workarounds are more trustworthy
+	if containsFold(maintainerAssociations, comment.GetAuthorAssociation()) {
+		confidence += 0.15
+	}
+
+	// Community confirmation
+	if reactions := comment.GetReactions(); reactions != nil {
+		confidence += 0.05 * float64(reactions.GetPlusOne()+reactions.GetHeart()+reactions.GetHooray())
+	}
+
+	if confidence > 1 {
+		confidence = 1
+	}
+	return confidence
+}
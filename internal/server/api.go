@@ -0,0 +1,280 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/output"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/query"
+)
+
+// Page sizes of the list APIs; serve.max_page_size overrides the maximum
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+// listResponse is the body of GET /api/issues and /api/search
+type listResponse struct {
+	Total  int                       `json:"total"`
+	Offset int                       `json:"offset"`
+	Limit  int                       `json:"limit"`
+	Facets map[string]map[string]int `json:"facets,omitempty"`
+	Issues interface{}               `json:"issues"`
+}
+
+// facetFields are the fields whose values the issue lists can count
+var facetFields = map[string]func(model.Issue) []string{
+	"repo":     func(issue model.Issue) []string { return []string{issue.Repository} },
+	"source":   func(issue model.Issue) []string { return []string{issue.SourceName()} },
+	"state":    func(issue model.Issue) []string { return []string{issue.State} },
+	"category": func(issue model.Issue) []string { return []string{issue.Category} },
+	"priority": func(issue model.Issue) []string { return []string{issue.Priority} },
+	"team":     func(issue model.Issue) []string { return []string{issue.AssignedTeam} },
+	"platform": func(issue model.Issue) []string { return issue.Platforms },
+	"label": func(issue model.Issue) []string {
+		labels := make([]string, len(issue.Labels))
+		for i, label := range issue.Labels {
+			labels[i] = label.Name
+		}
+		return labels
+	},
+}
+
+// handleListIssues lists stored issues matching the search query in q,
+// e.g. GET /api/issues?q=category:performance&sort=score&limit=20&offset=40&fields=number,title,score.
+// With classification=true every issue carries the reasons for its category.
+// facets=category,priority counts the values of those fields over all
+// matching issues, not just the page.
+// format=csv or ndjson, or an Accept header asking for text/csv or
+// application/x-ndjson, returns the page as rows instead of a JSON object.
+func (s *Server) handleListIssues(w http.ResponseWriter, r *http.Request) {
+	s.serveIssueList(w, r, false)
+}
+
+// handleSearch lists issues like /api/issues but requires a query in q
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	s.serveIssueList(w, r, true)
+}
+
+// serveIssueList answers a paginated issue search
+func (s *Server) serveIssueList(w http.ResponseWriter, r *http.Request, requireQuery bool) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.apiAuthorized(w, r) {
+		return
+	}
+
+	params := r.URL.Query()
+	if requireQuery && strings.TrimSpace(params.Get("q")) == "" {
+		http.Error(w, "q is required", http.StatusBadRequest)
+		return
+	}
+	search, err := query.Parse(params.Get("q"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var fields []string
+	if spec := params.Get("fields"); spec != "" {
+		if fields, err = output.ParseFields(spec); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	format, err := negotiateFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotAcceptable)
+		return
+	}
+	var facets []string
+	if spec := params.Get("facets"); spec != "" {
+		for _, field := range strings.Split(spec, ",") {
+			field = strings.TrimSpace(field)
+			if facetFields[field] == nil {
+				http.Error(w, fmt.Sprintf("unknown facet %q", field), http.StatusBadRequest)
+				return
+			}
+			facets = append(facets, field)
+		}
+	}
+	offset, limit, err := s.parsePage(params)
+	if err != nil {
+		pageError(w, err)
+		return
+	}
+	sortBy := params.Get("sort")
+	if sortBy == "" {
+		sortBy = query.SortScore
+		if search.HasText() {
+			sortBy = query.SortRelevance
+		}
+	}
+
+	issues, err := s.load()
+	if err != nil {
+		log.Printf("Error loading issues for API: %v", err)
+		http.Error(w, "issues unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	issues, _ = model.ExcludeDeletedUpstream(issues)
+
+	results := search.Filter(issues)
+	query.NewIndex(issues).Sort(search, results, sortBy, query.DefaultRelevanceWeights, time.Now())
+	response := listResponse{Total: len(results), Offset: offset, Limit: limit}
+	if len(facets) > 0 {
+		response.Facets = countFacets(results, facets)
+	}
+	results = results[min(offset, len(results)):min(offset+limit, len(results))]
+	if explain, _ := strconv.ParseBool(params.Get("classification")); explain && s.classify != nil {
+		s.classify(results)
+	}
+	setPageHeaders(w, r, response.Total, offset, limit)
+
+	switch {
+	case format == formatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		if fields == nil {
+			output.WriteCSV(w, results)
+		} else {
+			output.WriteCSVFields(w, results, fields)
+		}
+		return
+	case format == formatNDJSON:
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		if fields == nil {
+			output.WriteNDJSON(w, results)
+		} else {
+			output.WriteNDJSONFields(w, results, fields)
+		}
+		return
+	}
+
+	response.Issues = results
+	if fields != nil {
+		response.Issues = output.ProjectIssues(results, fields)
+	} else if len(results) == 0 {
+		response.Issues = []model.Issue{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// countFacets counts the values of each facet field, leaving out empty ones
+func countFacets(issues []model.Issue, fields []string) map[string]map[string]int {
+	counts := make(map[string]map[string]int, len(fields))
+	for _, field := range fields {
+		counts[field] = make(map[string]int)
+		for _, issue := range issues {
+			for _, value := range facetFields[field](issue) {
+				if value != "" {
+					counts[field][value]++
+				}
+			}
+		}
+	}
+	return counts
+}
+
+// Response formats of the issue lists
+const (
+	formatJSON   = "json"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+// negotiateFormat picks the response format of an issue list from the
+// format query parameter, or else the Accept header, defaulting to JSON
+func negotiateFormat(r *http.Request) (string, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		switch format {
+		case formatJSON, formatCSV, formatNDJSON:
+			return format, nil
+		}
+		return "", fmt.Errorf("format must be json, csv or ndjson")
+	}
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, _ := strings.Cut(strings.TrimSpace(accepted), ";")
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "text/csv":
+			return formatCSV, nil
+		case "application/x-ndjson", "application/ndjson":
+			return formatNDJSON, nil
+		case "application/json", "*/*", "application/*":
+			return formatJSON, nil
+		}
+	}
+	return formatJSON, nil
+}
+
+// apiAuthorized checks the API token, answering 401 when it does not match
+func (s *Server) apiAuthorized(w http.ResponseWriter, r *http.Request) bool {
+	token := s.config.APIToken
+	if token == "" {
+		return true
+	}
+	given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// handleListClassifications lists stored classification results, newest
+// run first, e.g. GET /api/classifications?repo=owner/name&issue=42.
+// With latest=true only the results of the latest run are listed.
+func (s *Server) handleListClassifications(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.apiAuthorized(w, r) {
+		return
+	}
+
+	params := r.URL.Query()
+	repo := params.Get("repo")
+	number := 0
+	if value := params.Get("issue"); value != "" {
+		var err error
+		if number, err = strconv.Atoi(value); err != nil || number <= 0 || repo == "" {
+			http.Error(w, "issue must be a positive integer and needs repo", http.StatusBadRequest)
+			return
+		}
+	}
+	latest, _ := strconv.ParseBool(params.Get("latest"))
+
+	records, err := s.classifications()
+	if err != nil {
+		log.Printf("Error loading classifications for API: %v", err)
+		http.Error(w, "classifications unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	results := []model.ClassificationRecord{}
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if latest && !record.RunAt.Equal(records[len(records)-1].RunAt) {
+			break
+		}
+		if repo != "" && !strings.EqualFold(record.Repository, repo) {
+			continue
+		}
+		if number != 0 && record.IssueNumber != number {
+			continue
+		}
+		results = append(results, record)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"total": len(results), "classifications": results})
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/query"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/similarity"
+)
+
+// maxSlackSkew bounds the age of a signed Slack request, against replays
+const maxSlackSkew = 5 * time.Minute
+
+// maxWorkaroundLength limits the workaround excerpt in answers
+const maxWorkaroundLength = 280
+
+// Answer is a corpus issue matching a chat question
+type Answer struct {
+	Issue model.Issue
+	Score float64
+}
+
+// FindAnswers ranks the corpus for a free-text question by combining TF-IDF
+// similarity with keyword relevance, returning at most limit answers
+func FindAnswers(issues map[string][]model.Issue, question string, limit int) []Answer {
+	now := time.Now()
+	search := query.AdvancedSearch{Terms: similarity.Tokenize(question), Mode: query.MatchAny}
+
+	scores := make(map[string]float64)
+	byRef := make(map[string]model.Issue)
+	for _, match := range similarity.NewEngine(issues).Search(question, limit*4, 0.05) {
+		ref := model.IssueRef(match.Issue.Repository, match.Issue.Number)
+		scores[ref] += 0.5 * match.Similarity
+		byRef[ref] = match.Issue
+	}
+	for _, issue := range search.Filter(issues) {
+		ref := model.IssueRef(issue.Repository, issue.Number)
+		scores[ref] += 0.5 * search.Relevance(issue, query.DefaultRelevanceWeights, now)
+		byRef[ref] = issue
+	}
+
+	answers := make([]Answer, 0, len(scores))
+	for ref, score := range scores {
+		answers = append(answers, Answer{Issue: byRef[ref], Score: score})
+	}
+	sort.Slice(answers, func(i, j int) bool {
+		if answers[i].Score != answers[j].Score {
+			return answers[i].Score > answers[j].Score
+		}
+		return answers[i].Issue.Score > answers[j].Issue.Score
+	})
+	if len(answers) > limit {
+		answers = answers[:limit]
+	}
+	return answers
+}
+
+// handleSlashCommand answers Slack and Mattermost slash commands
+// (/pitfall gorm connection pool) with the best matching pitfalls
+func (s *Server) handleSlashCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	if !s.authorized(r, body, form) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	question := strings.TrimSpace(form.Get("text"))
+	if question == "" {
+		writeChatReply(w, "用法: /pitfall <问题描述>, 例如 /pitfall gorm connection pool")
+		return
+	}
+
+	issues, err := s.load()
+	if err != nil {
+		log.Printf("Error loading issues for slash command: %v", err)
+		writeChatReply(w, "问题库暂时不可用, 请稍后再试")
+		return
+	}
+	issues, _ = model.ExcludeDeletedUpstream(issues)
+
+	limit := s.config.AnswerLimit
+	if limit <= 0 {
+		limit = 3
+	}
+	writeChatReply(w, formatAnswers(question, FindAnswers(issues, question, limit)))
+}
+
+// authorized verifies a Slack request signature or a Mattermost token
+func (s *Server) authorized(r *http.Request, body []byte, form url.Values) bool {
+	if signature := r.Header.Get("X-Slack-Signature"); signature != "" && s.config.SlackSigningSecret != "" {
+		timestamp := r.Header.Get("X-Slack-Request-Timestamp")
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil || time.Since(time.Unix(seconds, 0)).Abs() > maxSlackSkew {
+			return false
+		}
+		mac := hmac.New(sha256.New, []byte(s.config.SlackSigningSecret))
+		fmt.Fprintf(mac, "v0:%s:", timestamp)
+		mac.Write(body)
+		expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+		return hmac.Equal([]byte(expected), []byte(signature))
+	}
+
+	if s.config.MattermostToken != "" {
+		token := form.Get("token")
+		if token == "" {
+			token = strings.TrimPrefix(r.Header.Get("Authorization"), "Token ")
+		}
+		return subtle.ConstantTimeCompare([]byte(token), []byte(s.config.MattermostToken)) == 1
+	}
+	return false
+}
+
+// formatAnswers renders answers as a chat message with links and the best
+// workaround of each issue
+func formatAnswers(question string, answers []Answer) string {
+	if len(answers) == 0 {
+		return fmt.Sprintf("没有找到与 “%s” 相关的踩坑问题", question)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "与 “%s” 最相关的踩坑问题:\n", question)
+	for i, answer := range answers {
+		issue := answer.Issue
+		fmt.Fprintf(&b, "%d. <%s|%s> (%s, 评分 %.1f, %s)\n", i+1, issue.URL, chatEscape(issue.Title), issue.Repository, issue.Score, issue.State)
+		if issue.Workaround != nil {
+			fmt.Fprintf(&b, "   > 绕过方法: %s\n", chatEscape(excerpt(issue.Workaround.Body, maxWorkaroundLength)))
+		} else if issue.FixedBy != "" {
+			fmt.Fprintf(&b, "   > 已修复: %s\n", issue.FixedBy)
+		}
+	}
+	return b.String()
+}
+
+// writeChatReply writes a reply visible only to the user who asked
+func writeChatReply(w http.ResponseWriter, text string) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(map[string]string{
+		"response_type": "ephemeral",
+		"text":          text,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(buf.Bytes())
+}
+
+// excerpt collapses whitespace and truncates text to max runes
+func excerpt(text string, max int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > max {
+		return string(runes[:max]) + "..."
+	}
+	return text
+}
+
+// chatEscape escapes the characters Slack and Mattermost treat as control sequences
+func chatEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// eventKeepAlive is how often an idle event stream gets a comment, so
+// proxies do not close it
+const eventKeepAlive = 30 * time.Second
+
+// Event is a live update sent to the clients of /api/events
+type Event struct {
+	Type string      // ingested, classified or deduplicated
+	Data interface{} // encoded as JSON
+}
+
+// eventBroker fans published events out to the connected clients
+type eventBroker struct {
+	mu      sync.Mutex
+	nextID  int
+	clients map[chan eventMessage]bool
+}
+
+// eventMessage is an event with its stream ID and encoded data
+type eventMessage struct {
+	id   int
+	kind string
+	data []byte
+}
+
+// SetEvents serves the events passed to Publish at /api/events as a
+// server-sent event stream
+func (s *Server) SetEvents() {
+	s.events = &eventBroker{clients: make(map[chan eventMessage]bool)}
+	s.mux.HandleFunc("/api/events", s.handleEvents)
+}
+
+// Publish sends an event to the clients of /api/events. A client too slow
+// to take it misses the event rather than holding up the others.
+func (s *Server) Publish(event Event) {
+	if s.events == nil {
+		return
+	}
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		log.Printf("Error encoding %s event: %v", event.Type, err)
+		return
+	}
+
+	s.events.mu.Lock()
+	defer s.events.mu.Unlock()
+	s.events.nextID++
+	message := eventMessage{id: s.events.nextID, kind: event.Type, data: data}
+	for client := range s.events.clients {
+		select {
+		case client <- message:
+		default:
+		}
+	}
+}
+
+// subscribe registers a client until the returned function is called
+func (b *eventBroker) subscribe() (chan eventMessage, func()) {
+	client := make(chan eventMessage, 16)
+	b.mu.Lock()
+	b.clients[client] = true
+	b.mu.Unlock()
+	return client, func() {
+		b.mu.Lock()
+		delete(b.clients, client)
+		b.mu.Unlock()
+	}
+}
+
+// handleEvents streams live updates, e.g. GET /api/events, until the
+// client disconnects or the server shuts down
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.apiAuthorized(w, r) {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	client, unsubscribe := s.events.subscribe()
+	defer unsubscribe()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.shutdown:
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case message := <-client:
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", message.id, message.kind, message.data)
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// errPageTooLarge is returned by parsePage for a limit above the maximum
+// page size
+var errPageTooLarge = errors.New("page too large")
+
+// maxPageSize returns the largest accepted limit of the list APIs
+func (s *Server) maxPageSize() int {
+	if s.config.MaxPageSize > 0 {
+		return s.config.MaxPageSize
+	}
+	return maxListLimit
+}
+
+// pageError answers a parsePage error with 422 for an oversized page and
+// 400 otherwise
+func pageError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, errPageTooLarge) {
+		status = http.StatusUnprocessableEntity
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// limited runs an API query within serve.query_timeout_seconds and
+// serve.max_result_bytes. The response is buffered, so a query still
+// running at the timeout answers 504 and a result larger than the limit
+// answers 422 instead of a truncated body. A timed-out query finishes in
+// the background without holding the connection.
+func (s *Server) limited(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if s.config.QueryTimeoutSeconds > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(s.config.QueryTimeoutSeconds*float64(time.Second)))
+			defer cancel()
+		}
+
+		response := &bufferedResponse{header: make(http.Header), status: http.StatusOK, limit: s.config.MaxResultBytes}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			handler(response, r.WithContext(ctx))
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			if r.Context().Err() == nil {
+				log.Printf("API query %s timed out after %gs", r.URL.RequestURI(), s.config.QueryTimeoutSeconds)
+				http.Error(w, fmt.Sprintf("query exceeded the %gs timeout; narrow it or page through the results", s.config.QueryTimeoutSeconds), http.StatusGatewayTimeout)
+			}
+			return
+		}
+
+		if response.overflow {
+			http.Error(w, fmt.Sprintf("result exceeds %d bytes; lower limit, select fields or narrow the query", response.limit), http.StatusUnprocessableEntity)
+			return
+		}
+		for key, values := range response.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(response.status)
+		w.Write(response.body.Bytes())
+	}
+}
+
+// bufferedResponse collects a response up to limit bytes (0 = no limit),
+// dropping the body once it grows past the limit
+type bufferedResponse struct {
+	header   http.Header
+	status   int
+	body     bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.overflow {
+		return len(p), nil
+	}
+	if b.limit > 0 && b.body.Len()+len(p) > b.limit {
+		b.overflow = true
+		b.body.Reset()
+		return len(p), nil
+	}
+	return b.body.Write(p)
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// poolMetrics are the Prometheus gauges of the latest slot usage sample
+var poolMetrics = []struct {
+	name, help string
+	value      func(model.PoolSample) float64
+}{
+	{"pitfall_api_pool_slots", "Concurrency slots per API host (politeness.max_concurrency).", func(s model.PoolSample) float64 { return float64(s.Slots) }},
+	{"pitfall_api_pool_requests", "Requests sent to the host in the latest scrape.", func(s model.PoolSample) float64 { return float64(s.Requests) }},
+	{"pitfall_api_pool_waits", "Requests of the latest scrape that found every slot busy.", func(s model.PoolSample) float64 { return float64(s.Waits) }},
+	{"pitfall_api_pool_wait_seconds", "Total time requests of the latest scrape waited for a slot.", func(s model.PoolSample) float64 { return s.WaitSeconds }},
+	{"pitfall_api_pool_max_wait_seconds", "Longest wait for a slot in the latest scrape.", func(s model.PoolSample) float64 { return s.MaxWaitSeconds }},
+	{"pitfall_api_pool_paced_seconds", "Total time requests of the latest scrape waited for the host interval, backoff and hourly ceiling.", func(s model.PoolSample) float64 { return s.PacedSeconds }},
+	{"pitfall_api_pool_sampled_timestamp_seconds", "When the sample was taken.", func(s model.PoolSample) float64 { return float64(s.SampledAt.Unix()) }},
+}
+
+// SetPoolMetrics serves the latest API host slot usage of the samples
+// loaded by load at /metrics, in the Prometheus text format
+func (s *Server) SetPoolMetrics(load func() ([]model.PoolSample, error)) {
+	s.poolSamples = load
+	s.mux.HandleFunc("/metrics", s.handleMetrics)
+}
+
+// handleMetrics writes the latest sample of each API host as gauges
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.apiAuthorized(w, r) {
+		return
+	}
+	samples, err := s.poolSamples()
+	if err != nil {
+		log.Printf("Error loading pool metrics: %v", err)
+		http.Error(w, "metrics unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	latest := model.LatestPoolSamples(samples)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	for _, metric := range poolMetrics {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", metric.name, metric.help, metric.name)
+		for _, sample := range latest {
+			fmt.Fprintf(w, "%s{host=%q} %g\n", metric.name, sample.Host, metric.value(sample))
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/output"
+)
+
+// uiFiles is the web UI served at /ui/
+//
+//go:embed ui
+var uiFiles embed.FS
+
+// reportResponse describes one generated report
+type reportResponse struct {
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+// SetUI serves the embedded web UI at /ui/. It browses and triages the
+// corpus through the API, so features follow the Set* calls made.
+func (s *Server) SetUI() {
+	files, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		panic(err)
+	}
+	s.mux.Handle("/ui/", http.StripPrefix("/ui/", http.FileServer(http.FS(files))))
+}
+
+// SetReports serves the reports generated in dir: GET /api/reports lists
+// them, newest first, and GET /reports/{name} returns one. Only files the
+// tool recorded as generated are served.
+func (s *Server) SetReports(dir string) {
+	s.reportsDir = dir
+	s.mux.HandleFunc("/api/reports", s.limited(s.handleListReports))
+	s.mux.HandleFunc("/reports/", s.handleReport)
+}
+
+// generatedReports lists the generated reports, answering 503 when the
+// list cannot be read
+func (s *Server) generatedReports(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	if !s.apiAuthorized(w, r) {
+		return nil, false
+	}
+	names, err := output.GeneratedFiles(s.reportsDir)
+	if err != nil {
+		log.Printf("Error listing reports: %v", err)
+		http.Error(w, "reports unavailable", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	return names, true
+}
+
+// handleListReports lists the generated reports, e.g. GET /api/reports
+func (s *Server) handleListReports(w http.ResponseWriter, r *http.Request) {
+	names, ok := s.generatedReports(w, r)
+	if !ok {
+		return
+	}
+	reports := make([]reportResponse, 0, len(names))
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(s.reportsDir, filepath.FromSlash(name)))
+		if err != nil {
+			continue
+		}
+		reports = append(reports, reportResponse{Name: name, Size: info.Size(), ModifiedAt: info.ModTime()})
+	}
+	sort.SliceStable(reports, func(i, j int) bool { return reports[i].ModifiedAt.After(reports[j].ModifiedAt) })
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"total": len(reports), "reports": reports})
+}
+
+// handleReport returns one generated report, e.g. GET /reports/summary.md
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	names, ok := s.generatedReports(w, r)
+	if !ok {
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/reports/")
+	for _, generated := range names {
+		if generated == name {
+			if strings.HasSuffix(name, ".md") {
+				w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+			}
+			http.ServeFile(w, r, filepath.Join(s.reportsDir, filepath.FromSlash(name)))
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
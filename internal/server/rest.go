@@ -0,0 +1,402 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// repositoryResponse summarizes the stored issues of one repository
+type repositoryResponse struct {
+	Name         string    `json:"name"`
+	Issues       int       `json:"issues"`
+	Open         int       `json:"open"`
+	AverageScore float64   `json:"average_score"`
+	LastUpdated  time.Time `json:"last_updated"`
+}
+
+// statsResponse is the body of GET /api/stats
+type statsResponse struct {
+	Issues       int            `json:"issues"`
+	Repositories int            `json:"repositories"`
+	AverageScore float64        `json:"average_score"`
+	ByState      map[string]int `json:"by_state"`
+	ByCategory   map[string]int `json:"by_category"`
+	ByPriority   map[string]int `json:"by_priority"`
+}
+
+// parsePage reads the offset and limit query parameters. A limit above
+// the maximum page size fails with errPageTooLarge.
+func (s *Server) parsePage(params url.Values) (int, int, error) {
+	offset, limit := 0, defaultListLimit
+	var err error
+	if value := params.Get("offset"); value != "" {
+		if offset, err = strconv.Atoi(value); err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+	}
+	if value := params.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			return 0, 0, errors.New("limit must be a positive integer")
+		}
+		if maxLimit := s.maxPageSize(); limit > maxLimit {
+			return 0, 0, fmt.Errorf("%w: limit must be at most %d", errPageTooLarge, maxLimit)
+		}
+	}
+	return offset, limit, nil
+}
+
+// setPageHeaders sets X-Total-Count and a Link header with the next and
+// previous pages of a list response
+func setPageHeaders(w http.ResponseWriter, r *http.Request, total, offset, limit int) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+
+	page := func(offset int, rel string) string {
+		u := *r.URL
+		params := u.Query()
+		params.Set("offset", strconv.Itoa(offset))
+		params.Set("limit", strconv.Itoa(limit))
+		u.RawQuery = params.Encode()
+		return fmt.Sprintf("<%s>; rel=%q", u.RequestURI(), rel)
+	}
+	var links []string
+	if offset+limit < total {
+		links = append(links, page(offset+limit, "next"))
+	}
+	if offset > 0 {
+		links = append(links, page(max(offset-limit, 0), "prev"))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+// loadLive loads the stored issues without those deleted upstream,
+// answering 503 when they cannot be read
+func (s *Server) loadLive(w http.ResponseWriter, r *http.Request) (map[string][]model.Issue, bool) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	if !s.apiAuthorized(w, r) {
+		return nil, false
+	}
+	issues, err := s.load()
+	if err != nil {
+		log.Printf("Error loading issues for API: %v", err)
+		http.Error(w, "issues unavailable", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	issues, _ = model.ExcludeDeletedUpstream(issues)
+	return issues, true
+}
+
+// inPortfolio keeps the issues of the portfolio named by the portfolio
+// query parameter, or all issues without one
+func inPortfolio(issues map[string][]model.Issue, r *http.Request) map[string][]model.Issue {
+	name := r.URL.Query().Get("portfolio")
+	if name == "" {
+		return issues
+	}
+	selected := make(map[string][]model.Issue)
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			for _, portfolio := range issue.Portfolios {
+				if strings.EqualFold(portfolio, name) {
+					selected[repoName] = append(selected[repoName], issue)
+					break
+				}
+			}
+		}
+	}
+	return selected
+}
+
+// handleIssue routes /api/issues/{id}, /api/issues/{id}/refresh and
+// /api/issues/{id}/triage
+func (s *Server) handleIssue(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/issues/")
+	if id, ok := strings.CutSuffix(path, "/refresh"); ok {
+		s.handleRefreshIssue(w, r, id)
+		return
+	}
+	if id, ok := strings.CutSuffix(path, "/triage"); ok {
+		s.handleTriageIssue(w, r, id)
+		return
+	}
+	s.limited(func(w http.ResponseWriter, r *http.Request) {
+		s.handleGetIssue(w, r, path)
+	})(w, r)
+}
+
+// handleGetIssue returns one issue by its ID, e.g. GET /api/issues/1234567.
+// With classification=true the issue carries the reasons for its category.
+func (s *Server) handleGetIssue(w http.ResponseWriter, r *http.Request, id string) {
+	issues, ok := s.loadLive(w, r)
+	if !ok {
+		return
+	}
+	issue, ok := findIssue(w, issues, id)
+	if !ok {
+		return
+	}
+
+	found := []model.Issue{issue}
+	if explain, _ := strconv.ParseBool(r.URL.Query().Get("classification")); explain && s.classify != nil {
+		s.classify(found)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(found[0])
+}
+
+// handleRefreshIssue re-fetches a stored issue from GitHub right away,
+// e.g. POST /api/issues/1234567/refresh, and returns the refreshed issue
+func (s *Server) handleRefreshIssue(w http.ResponseWriter, r *http.Request, id string) {
+	if s.refresh == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.apiAuthorized(w, r) {
+		return
+	}
+	issues, err := s.load()
+	if err != nil {
+		log.Printf("Error loading issues for API: %v", err)
+		http.Error(w, "issues unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	issue, ok := findIssue(w, issues, id)
+	if !ok {
+		return
+	}
+
+	refreshed, err := s.refresh(r.Context(), issue.Repository, issue.Number)
+	if err != nil {
+		log.Printf("Error refreshing %s: %v", model.IssueRef(issue.Repository, issue.Number), err)
+		http.Error(w, "refresh failed: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(refreshed)
+}
+
+// findIssue looks up a stored issue by ID, answering 400 or 404 when it
+// cannot be found
+func findIssue(w http.ResponseWriter, issues map[string][]model.Issue, value string) (model.Issue, bool) {
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		http.Error(w, "issue ID must be a positive integer", http.StatusBadRequest)
+		return model.Issue{}, false
+	}
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			if issue.ID == id {
+				issue.Repository = repoName
+				return issue, true
+			}
+		}
+	}
+	http.Error(w, "issue not found", http.StatusNotFound)
+	return model.Issue{}, false
+}
+
+// handleListRepositories lists the repositories with stored issues, by
+// name, e.g. GET /api/repositories?limit=20&offset=20&portfolio=serving-stack
+func (s *Server) handleListRepositories(w http.ResponseWriter, r *http.Request) {
+	issues, ok := s.loadLive(w, r)
+	if !ok {
+		return
+	}
+	issues = inPortfolio(issues, r)
+	offset, limit, err := s.parsePage(r.URL.Query())
+	if err != nil {
+		pageError(w, err)
+		return
+	}
+
+	repositories := make([]repositoryResponse, 0, len(issues))
+	for repoName, repoIssues := range issues {
+		repo := repositoryResponse{Name: repoName, Issues: len(repoIssues)}
+		total := 0.0
+		for _, issue := range repoIssues {
+			if issue.State == "open" {
+				repo.Open++
+			}
+			total += issue.Score
+			if issue.UpdatedAt.After(repo.LastUpdated) {
+				repo.LastUpdated = issue.UpdatedAt
+			}
+		}
+		if len(repoIssues) > 0 {
+			repo.AverageScore = total / float64(len(repoIssues))
+		}
+		repositories = append(repositories, repo)
+	}
+	sort.Slice(repositories, func(i, j int) bool { return repositories[i].Name < repositories[j].Name })
+
+	total := len(repositories)
+	repositories = repositories[min(offset, total):min(offset+limit, total)]
+	setPageHeaders(w, r, total, offset, limit)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"total": total, "offset": offset, "limit": limit, "repositories": repositories})
+}
+
+// handleStats summarizes the stored issues, e.g. GET /api/stats, or those
+// of one portfolio with ?portfolio=serving-stack
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	issues, ok := s.loadLive(w, r)
+	if !ok {
+		return
+	}
+	issues = inPortfolio(issues, r)
+
+	stats := statsResponse{
+		Repositories: len(issues),
+		ByState:      make(map[string]int),
+		ByCategory:   make(map[string]int),
+		ByPriority:   make(map[string]int),
+	}
+	total := 0.0
+	for _, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			stats.Issues++
+			total += issue.Score
+			stats.ByState[orUnknown(issue.State)]++
+			stats.ByCategory[orUnknown(issue.Category)]++
+			stats.ByPriority[orUnknown(issue.Priority)]++
+		}
+	}
+	if stats.Issues > 0 {
+		stats.AverageScore = total / float64(stats.Issues)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// handleListEscalations lists recorded escalations, newest first, e.g.
+// GET /api/escalations?repo=owner/name&kind=reopened&since=2024-05-01
+func (s *Server) handleListEscalations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.apiAuthorized(w, r) {
+		return
+	}
+
+	params := r.URL.Query()
+	offset, limit, err := s.parsePage(params)
+	if err != nil {
+		pageError(w, err)
+		return
+	}
+	var since time.Time
+	if value := params.Get("since"); value != "" {
+		if since, err = time.Parse("2006-01-02", value); err != nil {
+			http.Error(w, "since must be a date (YYYY-MM-DD)", http.StatusBadRequest)
+			return
+		}
+	}
+	repo, kind := params.Get("repo"), params.Get("kind")
+
+	escalations, err := s.escalations()
+	if err != nil {
+		log.Printf("Error loading escalations for API: %v", err)
+		http.Error(w, "escalations unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	results := []model.Escalation{}
+	for i := len(escalations) - 1; i >= 0; i-- {
+		escalation := escalations[i]
+		if escalation.DetectedAt.Before(since) {
+			continue
+		}
+		if repo != "" && !strings.EqualFold(escalation.Repository, repo) {
+			continue
+		}
+		if kind != "" && escalation.Kind != kind {
+			continue
+		}
+		results = append(results, escalation)
+	}
+
+	total := len(results)
+	results = results[min(offset, total):min(offset+limit, total)]
+	setPageHeaders(w, r, total, offset, limit)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"total": total, "offset": offset, "limit": limit, "escalations": results})
+}
+
+// handleListTombstones lists the issues deleted from the store, oldest
+// first, leaving out those stored again since, e.g.
+// GET /api/tombstones?since=2024-05-01T00:00:00Z&repo=owner/name
+func (s *Server) handleListTombstones(w http.ResponseWriter, r *http.Request) {
+	issues, ok := s.loadLive(w, r)
+	if !ok {
+		return
+	}
+
+	params := r.URL.Query()
+	offset, limit, err := s.parsePage(params)
+	if err != nil {
+		pageError(w, err)
+		return
+	}
+	var since time.Time
+	if value := params.Get("since"); value != "" {
+		if since, err = time.Parse(time.RFC3339, value); err != nil {
+			if since, err = time.Parse("2006-01-02", value); err != nil {
+				http.Error(w, "since must be a date (YYYY-MM-DD) or an RFC 3339 time", http.StatusBadRequest)
+				return
+			}
+		}
+	}
+	repo := params.Get("repo")
+
+	tombstones, err := s.tombstones()
+	if err != nil {
+		log.Printf("Error loading tombstones for API: %v", err)
+		http.Error(w, "tombstones unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	live := make(map[string]bool)
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			live[model.IssueRef(repoName, issue.Number)] = true
+		}
+	}
+	results := []model.Tombstone{}
+	for _, tombstone := range model.TombstonesSince(tombstones, since, live) {
+		if repo == "" || strings.EqualFold(tombstone.Repository, repo) {
+			results = append(results, tombstone)
+		}
+	}
+
+	total := len(results)
+	results = results[min(offset, total):min(offset+limit, total)]
+	setPageHeaders(w, r, total, offset, limit)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"total": total, "offset": offset, "limit": limit, "tombstones": results})
+}
+
+// orUnknown names empty values in breakdowns
+func orUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+	return value
+}
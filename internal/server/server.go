@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// Config configures serve mode
+type Config struct {
+	Addr               string `yaml:"addr"`
+	SlackSigningSecret string `yaml:"slack_signing_secret"`
+	MattermostToken    string `yaml:"mattermost_token"`
+	AnswerLimit        int    `yaml:"answer_limit"`
+	APIToken           string `yaml:"api_token"`      // Bearer token for /api/*; empty leaves it open
+	WebhookSecret      string `yaml:"webhook_secret"` // GitHub webhook secret for --webhook-listen
+	// Limits of API queries; 0 disables the timeout and the result size limit
+	MaxPageSize         int     `yaml:"max_page_size"`
+	QueryTimeoutSeconds float64 `yaml:"query_timeout_seconds"`
+	MaxResultBytes      int     `yaml:"max_result_bytes"`
+}
+
+// Server serves the stored corpus over HTTP
+type Server struct {
+	config   Config
+	load     func() (map[string][]model.Issue, error)
+	classify func([]model.Issue)
+	// classifications loads stored classification results
+	classifications func() ([]model.ClassificationRecord, error)
+	// escalations loads recorded escalations
+	escalations func() ([]model.Escalation, error)
+	// tombstones loads recorded deletions
+	tombstones func() ([]model.Tombstone, error)
+	// poolSamples loads recorded API host slot usage
+	poolSamples func() ([]model.PoolSample, error)
+	// refresh re-fetches and stores one issue
+	refresh func(ctx context.Context, repoName string, number int) (model.Issue, error)
+	// triage corrects an issue's category and priority
+	triage                 func(issue model.Issue, category, priority, author string) (model.Issue, error)
+	categories, priorities []string
+	// duplicateLinks, duplicateReviews and reviewDuplicate back the
+	// duplicate review queue
+	duplicateLinks   func() ([]model.DuplicateLink, error)
+	duplicateReviews func() ([]model.DuplicateReview, error)
+	reviewDuplicate  func(model.DuplicateReview) error
+	// reportsDir holds the generated reports
+	reportsDir string
+	// events fans live updates out to /api/events
+	events *eventBroker
+	// shutdown is closed when ListenAndServe stops, ending event streams
+	shutdown <-chan struct{}
+	mux      *http.ServeMux
+}
+
+// NewServer creates a server reading the corpus with load on every request,
+// so it always answers from the latest scrape
+func NewServer(config Config, load func() (map[string][]model.Issue, error)) *Server {
+	s := &Server{config: config, load: load, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/commands/pitfall", s.handleSlashCommand)
+	s.mux.HandleFunc("/api/issues", s.limited(s.handleListIssues))
+	s.mux.HandleFunc("/api/issues/", s.handleIssue)
+	s.mux.HandleFunc("/api/search", s.limited(s.handleSearch))
+	s.mux.HandleFunc("/api/repositories", s.limited(s.handleListRepositories))
+	s.mux.HandleFunc("/api/stats", s.limited(s.handleStats))
+	return s
+}
+
+// SetClassifier sets the function explaining the categories of listed
+// issues when the API is asked for classification reasons
+func (s *Server) SetClassifier(classify func([]model.Issue)) {
+	s.classify = classify
+}
+
+// SetClassifications serves the stored classification results loaded by
+// load at /api/classifications
+func (s *Server) SetClassifications(load func() ([]model.ClassificationRecord, error)) {
+	s.classifications = load
+	s.mux.HandleFunc("/api/classifications", s.limited(s.handleListClassifications))
+}
+
+// SetEscalations serves the recorded escalations loaded by load at
+// /api/escalations
+func (s *Server) SetEscalations(load func() ([]model.Escalation, error)) {
+	s.escalations = load
+	s.mux.HandleFunc("/api/escalations", s.limited(s.handleListEscalations))
+}
+
+// SetTombstones serves the recorded deletions loaded by load at
+// /api/tombstones, so mirrors of the API can delete the same issues
+func (s *Server) SetTombstones(load func() ([]model.Tombstone, error)) {
+	s.tombstones = load
+	s.mux.HandleFunc("/api/tombstones", s.limited(s.handleListTombstones))
+}
+
+// SetRefresher enables POST /api/issues/{id}/refresh, which re-fetches a
+// stored issue with refresh and returns the refreshed issue
+func (s *Server) SetRefresher(refresh func(ctx context.Context, repoName string, number int) (model.Issue, error)) {
+	s.refresh = refresh
+}
+
+// Handle registers an additional handler
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe serves until ctx is cancelled, then shuts down gracefully
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	s.shutdown = ctx.Done()
+	return ListenAndServe(ctx, s.config.Addr, s)
+}
+
+// ListenAndServe serves handler on addr until ctx is cancelled, then shuts
+// down gracefully
+func ListenAndServe(ctx context.Context, addr string, handler http.Handler) error {
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		log.Printf("Serving on %s", addr)
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,310 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// maxTriageBody bounds the JSON bodies of the triage and review endpoints
+const maxTriageBody = 64 << 10
+
+// triageRequest is the body of POST /api/issues/{id}/triage; an empty
+// category or priority is left unchanged
+type triageRequest struct {
+	Category string `json:"category"`
+	Priority string `json:"priority"`
+	Author   string `json:"author"`
+}
+
+// triageOptions is the body of GET /api/issues/{id}/triage
+type triageOptions struct {
+	Category   string   `json:"category"`
+	Priority   string   `json:"priority"`
+	Categories []string `json:"categories"`
+	Priorities []string `json:"priorities"`
+}
+
+// reviewRequest is the body of POST /api/duplicates/review
+type reviewRequest struct {
+	Duplicate string `json:"duplicate"` // owner/repo#number
+	Canonical string `json:"canonical"`
+	Verdict   string `json:"verdict"` // confirmed or rejected
+	Reviewer  string `json:"reviewer"`
+}
+
+// duplicateItem is one entry of the duplicate review queue
+type duplicateItem struct {
+	Duplicate  duplicateIssue `json:"duplicate"`
+	Canonical  duplicateIssue `json:"canonical"`
+	Source     string         `json:"source,omitempty"`
+	LinkedAt   *time.Time     `json:"linked_at,omitempty"`
+	Verdict    string         `json:"verdict,omitempty"`
+	Reviewer   string         `json:"reviewer,omitempty"`
+	ReviewedAt *time.Time     `json:"reviewed_at,omitempty"`
+}
+
+// duplicateIssue summarizes one side of a duplicate pair; only Ref is set
+// when the issue is no longer stored
+type duplicateIssue struct {
+	Ref   string  `json:"ref"`
+	ID    int     `json:"id,omitempty"`
+	Title string  `json:"title,omitempty"`
+	State string  `json:"state,omitempty"`
+	URL   string  `json:"url,omitempty"`
+	Score float64 `json:"score,omitempty"`
+}
+
+// SetTriage enables GET and POST /api/issues/{id}/triage, which show and
+// correct an issue's category and priority with triage, accepting the
+// given categories and priorities
+func (s *Server) SetTriage(categories, priorities []string, triage func(issue model.Issue, category, priority, author string) (model.Issue, error)) {
+	s.categories, s.priorities = categories, priorities
+	s.triage = triage
+}
+
+// SetDuplicateReview serves the review queue of suggested duplicate links
+// at GET /api/duplicates and records verdicts with review at
+// POST /api/duplicates/review
+func (s *Server) SetDuplicateReview(links func() ([]model.DuplicateLink, error), reviews func() ([]model.DuplicateReview, error), review func(model.DuplicateReview) error) {
+	s.duplicateLinks, s.duplicateReviews, s.reviewDuplicate = links, reviews, review
+	s.mux.HandleFunc("/api/duplicates", s.limited(s.handleListDuplicates))
+	s.mux.HandleFunc("/api/duplicates/review", s.handleReviewDuplicate)
+}
+
+// handleTriageIssue shows the triage options of an issue, or corrects its
+// category and priority, e.g. POST /api/issues/1234567/triage with
+// {"category": "memory", "priority": "high", "author": "alice"}
+func (s *Server) handleTriageIssue(w http.ResponseWriter, r *http.Request, id string) {
+	if s.triage == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.apiAuthorized(w, r) {
+		return
+	}
+	issues, err := s.load()
+	if err != nil {
+		log.Printf("Error loading issues for API: %v", err)
+		http.Error(w, "issues unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	issue, ok := findIssue(w, issues, id)
+	if !ok {
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(triageOptions{Category: issue.Category, Priority: issue.Priority, Categories: s.categories, Priorities: s.priorities})
+		return
+	}
+
+	var request triageRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTriageBody)).Decode(&request); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	switch {
+	case request.Category == "" && request.Priority == "":
+		http.Error(w, "category or priority is required", http.StatusBadRequest)
+		return
+	case request.Category != "" && !containsValue(s.categories, request.Category):
+		http.Error(w, fmt.Sprintf("category must be one of: %s", strings.Join(s.categories, ", ")), http.StatusBadRequest)
+		return
+	case request.Priority != "" && !containsValue(s.priorities, request.Priority):
+		http.Error(w, fmt.Sprintf("priority must be one of: %s", strings.Join(s.priorities, ", ")), http.StatusBadRequest)
+		return
+	}
+
+	triaged, err := s.triage(issue, request.Category, request.Priority, request.Author)
+	if err != nil {
+		log.Printf("Error triaging %s: %v", model.IssueRef(issue.Repository, issue.Number), err)
+		http.Error(w, "triage failed", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(triaged)
+}
+
+// handleListDuplicates lists suggested duplicate links by review status,
+// e.g. GET /api/duplicates?status=pending&limit=20. Pending links come
+// newest first; confirmed and rejected ones most recently reviewed first.
+func (s *Server) handleListDuplicates(w http.ResponseWriter, r *http.Request) {
+	issues, ok := s.loadLive(w, r)
+	if !ok {
+		return
+	}
+	params := r.URL.Query()
+	offset, limit, err := s.parsePage(params)
+	if err != nil {
+		pageError(w, err)
+		return
+	}
+	status := params.Get("status")
+	if status == "" {
+		status = "pending"
+	}
+	if status != "pending" && status != model.DuplicateConfirmed && status != model.DuplicateRejected {
+		http.Error(w, "status must be pending, confirmed or rejected", http.StatusBadRequest)
+		return
+	}
+
+	links, err := s.duplicateLinks()
+	if err != nil {
+		log.Printf("Error loading duplicate links for API: %v", err)
+		http.Error(w, "duplicates unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	reviews, err := s.duplicateReviews()
+	if err != nil {
+		log.Printf("Error loading duplicate reviews for API: %v", err)
+		http.Error(w, "duplicates unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	stored := make(map[string]model.Issue)
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			stored[model.IssueRef(repoName, issue.Number)] = issue
+		}
+	}
+	summary := func(repository string, number int) duplicateIssue {
+		ref := model.IssueRef(repository, number)
+		issue, ok := stored[ref]
+		if !ok {
+			return duplicateIssue{Ref: ref}
+		}
+		return duplicateIssue{Ref: ref, ID: issue.ID, Title: issue.Title, State: issue.State, URL: issue.URL, Score: issue.Score}
+	}
+	verdicts := make(map[string]model.DuplicateReview)
+	for _, review := range reviews {
+		verdicts[review.PairKey()] = review
+	}
+
+	results := []duplicateItem{}
+	if status == model.DuplicateRejected {
+		for _, review := range reviews {
+			if review.Verdict == model.DuplicateRejected {
+				reviewedAt := review.ReviewedAt
+				results = append(results, duplicateItem{
+					Duplicate: summary(review.Repository, review.IssueNumber),
+					Canonical: summary(review.CanonicalRepository, review.CanonicalNumber),
+					Verdict:   review.Verdict, Reviewer: review.Reviewer, ReviewedAt: &reviewedAt,
+				})
+			}
+		}
+	} else {
+		seen := make(map[string]bool)
+		for _, link := range links {
+			key := link.PairKey()
+			review, reviewed := verdicts[key]
+			if !link.Suggested() || seen[key] || (status == "pending") == reviewed || (reviewed && review.Verdict != status) {
+				continue
+			}
+			seen[key] = true
+			linkedAt := link.LinkedAt
+			item := duplicateItem{
+				Duplicate: summary(link.Repository, link.IssueNumber),
+				Canonical: summary(link.CanonicalRepository, link.CanonicalNumber),
+				Source:    link.Source, LinkedAt: &linkedAt,
+			}
+			if reviewed {
+				reviewedAt := review.ReviewedAt
+				item.Verdict, item.Reviewer, item.ReviewedAt = review.Verdict, review.Reviewer, &reviewedAt
+			}
+			results = append(results, item)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].ReviewedAt != nil && results[j].ReviewedAt != nil {
+			return results[i].ReviewedAt.After(*results[j].ReviewedAt)
+		}
+		return results[i].LinkedAt != nil && results[j].LinkedAt != nil && results[i].LinkedAt.After(*results[j].LinkedAt)
+	})
+
+	total := len(results)
+	results = results[min(offset, total):min(offset+limit, total)]
+	setPageHeaders(w, r, total, offset, limit)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"total": total, "offset": offset, "limit": limit, "duplicates": results})
+}
+
+// handleReviewDuplicate records a verdict on a suggested duplicate link,
+// e.g. POST /api/duplicates/review with {"duplicate": "owner/repo#2",
+// "canonical": "owner/repo#1", "verdict": "rejected", "reviewer": "alice"}
+func (s *Server) handleReviewDuplicate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.apiAuthorized(w, r) {
+		return
+	}
+	var request reviewRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTriageBody)).Decode(&request); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if request.Verdict != model.DuplicateConfirmed && request.Verdict != model.DuplicateRejected {
+		http.Error(w, "verdict must be confirmed or rejected", http.StatusBadRequest)
+		return
+	}
+	duplicateRepo, duplicateNumber, ok := model.ParseIssueRef(request.Duplicate)
+	canonicalRepo, canonicalNumber, ok2 := model.ParseIssueRef(request.Canonical)
+	if !ok || !ok2 {
+		http.Error(w, "duplicate and canonical must be owner/repo#number references", http.StatusBadRequest)
+		return
+	}
+
+	links, err := s.duplicateLinks()
+	if err != nil {
+		log.Printf("Error loading duplicate links for API: %v", err)
+		http.Error(w, "duplicates unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	key := model.DuplicateLink{Repository: duplicateRepo, IssueNumber: duplicateNumber, CanonicalRepository: canonicalRepo, CanonicalNumber: canonicalNumber}.PairKey()
+	for _, link := range links {
+		if !link.Suggested() || link.PairKey() != key {
+			continue
+		}
+		review := model.DuplicateReview{
+			Repository:          link.Repository,
+			IssueNumber:         link.IssueNumber,
+			CanonicalRepository: link.CanonicalRepository,
+			CanonicalNumber:     link.CanonicalNumber,
+			Verdict:             request.Verdict,
+			Reviewer:            request.Reviewer,
+			ReviewedAt:          time.Now(),
+		}
+		if err := s.reviewDuplicate(review); err != nil {
+			log.Printf("Error recording duplicate review: %v", err)
+			http.Error(w, "review failed", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(review)
+		return
+	}
+	http.Error(w, "no suggested duplicate link between these issues", http.StatusNotFound)
+}
+
+// containsValue reports whether values holds value
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
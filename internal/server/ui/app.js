@@ -0,0 +1,284 @@
+// Web UI over the serve mode API: browse issues with facets, triage them,
+// review suggested duplicates and read generated reports.
+"use strict";
+
+const PAGE_SIZE = 50;
+const FACETS = ["category", "priority", "state", "team", "repo", "label"];
+const state = { offset: 0, total: 0, selected: null };
+
+const $ = (selector) => document.querySelector(selector);
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  Object.entries(attrs || {}).forEach(([key, value]) => {
+    if (key.startsWith("on")) node.addEventListener(key.slice(2), value);
+    else node.setAttribute(key, value);
+  });
+  children.flat().forEach((child) => node.append(child instanceof Node ? child : String(child ?? "")));
+  return node;
+}
+
+function headers(extra) {
+  const token = localStorage.getItem("apiToken");
+  return Object.assign(token ? { Authorization: "Bearer " + token } : {}, extra);
+}
+
+async function api(path, options = {}) {
+  const response = await fetch(path, Object.assign({}, options, { headers: headers(options.headers) }));
+  if (!response.ok) {
+    const message = (await response.text()).trim();
+    throw new Error(`${response.status} ${message}`);
+  }
+  return response;
+}
+
+function showError(err) {
+  const box = $("#error");
+  box.hidden = !err;
+  box.textContent = err ? "请求失败: " + err.message : "";
+}
+
+// quote wraps qualifier values with spaces so the query parser keeps them whole
+function quote(value) {
+  return /\s/.test(value) ? `"${value}"` : value;
+}
+
+// Issues
+
+async function loadIssues() {
+  const params = new URLSearchParams({
+    q: $("#q").value,
+    offset: state.offset,
+    limit: PAGE_SIZE,
+    facets: FACETS.join(","),
+    fields: "id,number,repository,title,score,category,priority,state",
+  });
+  if ($("#sort").value) params.set("sort", $("#sort").value);
+  try {
+    const list = await (await api("/api/issues?" + params)).json();
+    state.total = list.total;
+    renderIssues(list.issues);
+    renderFacets(list.facets || {});
+    $("#summary").textContent = `共 ${list.total} 个问题, 第 ${list.total ? state.offset + 1 : 0}-${state.offset + list.issues.length} 个`;
+    $("#prev").disabled = state.offset === 0;
+    $("#next").disabled = state.offset + PAGE_SIZE >= list.total;
+    showError(null);
+  } catch (err) {
+    showError(err);
+  }
+}
+
+function renderIssues(issues) {
+  const body = $("#issues tbody");
+  body.replaceChildren(...issues.map((issue) =>
+    el("tr", { class: issue.id === state.selected ? "selected" : "", onclick: () => showIssue(issue.id) },
+      el("td", {}, issue.score.toFixed(1)),
+      el("td", {}, el("span", { class: "muted" }, `${issue.repository}#${issue.number} `), issue.title),
+      el("td", {}, issue.category),
+      el("td", {}, issue.priority || ""),
+      el("td", {}, issue.state))));
+}
+
+function renderFacets(facets) {
+  const aside = $("#facets");
+  aside.replaceChildren();
+  FACETS.forEach((field) => {
+    const values = Object.entries(facets[field] || {}).sort((a, b) => b[1] - a[1]).slice(0, 12);
+    if (!values.length) return;
+    aside.append(el("h4", {}, field));
+    values.forEach(([value, count]) => aside.append(
+      el("a", { href: "#issues", onclick: (event) => { event.preventDefault(); refine(field, value); } },
+        el("span", {}, value), el("span", { class: "muted" }, count))));
+  });
+}
+
+function refine(field, value) {
+  const qualifier = `${field}:${quote(value)}`;
+  const q = $("#q").value.trim();
+  if (!q.split(/\s+/).includes(qualifier)) $("#q").value = q ? `${q} ${qualifier}` : qualifier;
+  state.offset = 0;
+  loadIssues();
+}
+
+async function showIssue(id) {
+  state.selected = id;
+  document.querySelectorAll("#issues tbody tr").forEach((row) => row.classList.remove("selected"));
+  const detail = $("#detail");
+  try {
+    const issue = await (await api(`/api/issues/${id}?classification=true`)).json();
+    const classification = issue.classification || {};
+    detail.replaceChildren(
+      el("h3", {}, el("a", { href: issue.url, target: "_blank", rel: "noopener" }, `${issue.repository}#${issue.number}`), " ", issue.title),
+      el("p", { class: "muted" }, `${issue.state} · 分数 ${issue.score.toFixed(1)} · ${issue.author} · 更新于 ${new Date(issue.updated_at).toLocaleString()}`),
+      el("p", {}, (issue.labels || []).map((label) => el("span", { class: "label" }, label.name))),
+      // serve reads corrections at startup, so a fresh correction shows as the stored category only
+      el("p", {}, `分类 ${issue.category}`, classification.category === issue.category ? ` (${classification.source}${classification.corrected_by ? ", " + classification.corrected_by : ""})` : "",
+        (classification.matches || []).length ? ` · 匹配: ${classification.matches.map((m) => `${m.category}: ${m.keywords.join(", ")}`).join("; ")}` : ""),
+      issue.score_reason && issue.score_reason.length ? el("p", { class: "muted" }, "评分依据: " + issue.score_reason.join("; ")) : "",
+      el("div", { id: "triage" }),
+      el("pre", {}, issue.plain_text || issue.body || ""),
+    );
+    detail.hidden = false;
+    renderTriage(issue);
+    showError(null);
+  } catch (err) {
+    showError(err);
+  }
+}
+
+async function renderTriage(issue) {
+  const box = $("#triage");
+  let options;
+  try {
+    options = await (await api(`/api/issues/${issue.id}/triage`)).json();
+  } catch (err) {
+    return; // triage is not enabled on this server
+  }
+  const select = (name, values, current) => el("select", { name },
+    el("option", { value: "" }, "(不变)"), values.map((value) => {
+      const option = el("option", { value }, value);
+      option.selected = value === current;
+      return option;
+    }));
+  const form = el("form", {
+    onsubmit: async (event) => {
+      event.preventDefault();
+      const data = new FormData(form);
+      const body = { category: data.get("category"), priority: data.get("priority"), author: localStorage.getItem("author") || "" };
+      if (body.category === options.category) body.category = "";
+      if (body.priority === options.priority) body.priority = "";
+      try {
+        await api(`/api/issues/${issue.id}/triage`, { method: "POST", headers: { "Content-Type": "application/json" }, body: JSON.stringify(body) });
+        await showIssue(issue.id);
+        loadIssues();
+      } catch (err) {
+        showError(err);
+      }
+    },
+  }, select("category", options.categories, options.category), select("priority", options.priorities, options.priority),
+  el("button", { type: "submit" }, "修正分类"));
+  box.replaceChildren(form);
+}
+
+// Duplicate review
+
+async function loadDuplicates() {
+  const status = $("#duplicate-status").value;
+  try {
+    const list = await (await api(`/api/duplicates?status=${status}&limit=${PAGE_SIZE}`)).json();
+    $("#duplicate-summary").textContent = `共 ${list.total} 对`;
+    const issueCell = (issue) => el("td", {},
+      issue.url ? el("a", { href: issue.url, target: "_blank", rel: "noopener" }, issue.ref) : issue.ref, " ",
+      issue.title || el("span", { class: "muted" }, "(未保存)"));
+    $("#duplicates tbody").replaceChildren(...list.duplicates.map((item) => {
+      const review = (verdict) => async () => {
+        try {
+          await api("/api/duplicates/review", {
+            method: "POST",
+            headers: { "Content-Type": "application/json" },
+            body: JSON.stringify({ duplicate: item.duplicate.ref, canonical: item.canonical.ref, verdict, reviewer: localStorage.getItem("author") || "" }),
+          });
+          loadDuplicates();
+        } catch (err) {
+          showError(err);
+        }
+      };
+      return el("tr", {},
+        issueCell(item.duplicate), issueCell(item.canonical),
+        el("td", {}, item.source || ""),
+        el("td", {}, status === "pending"
+          ? [el("button", { type: "button", onclick: review("confirmed") }, "确认"), " ", el("button", { type: "button", onclick: review("rejected") }, "驳回")]
+          : `${item.reviewer || ""} ${item.reviewed_at ? new Date(item.reviewed_at).toLocaleString() : ""}`));
+    }));
+    showError(null);
+  } catch (err) {
+    showError(err);
+  }
+}
+
+// Reports
+
+async function loadReports() {
+  try {
+    const list = await (await api("/api/reports")).json();
+    $("#reports").replaceChildren(...list.reports.map((report) => el("li", {},
+      el("a", { href: "#reports", onclick: (event) => { event.preventDefault(); showReport(report.name); } }, report.name),
+      el("span", { class: "muted" }, ` ${(report.size / 1024).toFixed(1)} KB · ${new Date(report.modified_at).toLocaleString()}`))));
+    showError(null);
+  } catch (err) {
+    showError(err);
+  }
+}
+
+async function showReport(name) {
+  try {
+    const text = await (await api("/reports/" + name.split("/").map(encodeURIComponent).join("/"))).text();
+    const html = name.endsWith(".html");
+    $("#report").hidden = html;
+    $("#report-html").hidden = !html;
+    if (html) $("#report-html").srcdoc = text;
+    else $("#report").textContent = text;
+  } catch (err) {
+    showError(err);
+  }
+}
+
+// Live updates: fetch keeps the API token header, which EventSource cannot send
+
+async function listen() {
+  try {
+    const response = await api("/api/events");
+    $("#live").classList.add("on");
+    const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+    let buffer = "";
+    for (;;) {
+      const { value, done } = await reader.read();
+      if (done) break;
+      buffer += value;
+      let end;
+      while ((end = buffer.indexOf("\n\n")) >= 0) {
+        const event = buffer.slice(0, end).split("\n").find((line) => line.startsWith("event: "));
+        buffer = buffer.slice(end + 2);
+        if (!event) continue;
+        if (current() === "issues") loadIssues();
+        if (current() === "duplicates" && event !== "event: ingested") loadDuplicates();
+      }
+    }
+  } catch (err) {
+    // events are not enabled, or the server went away
+  }
+  $("#live").classList.remove("on");
+  setTimeout(listen, 10000);
+}
+
+// Navigation
+
+function current() {
+  return (location.hash || "#issues").slice(1);
+}
+
+function route() {
+  const view = current();
+  ["issues", "duplicates", "reports"].forEach((name) => {
+    $(`#view-${name}`).hidden = name !== view;
+    $(`nav a[data-view=${name}]`).classList.toggle("active", name === view);
+  });
+  ({ issues: loadIssues, duplicates: loadDuplicates, reports: loadReports })[view]?.();
+}
+
+$("#search").addEventListener("submit", (event) => { event.preventDefault(); state.offset = 0; loadIssues(); });
+$("#sort").addEventListener("change", () => { state.offset = 0; loadIssues(); });
+$("#prev").addEventListener("click", () => { state.offset = Math.max(0, state.offset - PAGE_SIZE); loadIssues(); });
+$("#next").addEventListener("click", () => { state.offset += PAGE_SIZE; loadIssues(); });
+$("#duplicate-status").addEventListener("change", loadDuplicates);
+$("#token").addEventListener("click", () => {
+  const token = prompt("API token (serve.api_token), 留空则清除", localStorage.getItem("apiToken") || "");
+  if (token === null) return;
+  localStorage.setItem("apiToken", token);
+  const author = prompt("修改人 (记录在分类修正和重复审核中)", localStorage.getItem("author") || "");
+  if (author !== null) localStorage.setItem("author", author);
+  route();
+});
+window.addEventListener("hashchange", route);
+route();
+listen();
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// maxWebhookBody bounds webhook payloads; GitHub caps them at 25 MB
+const maxWebhookBody = 25 << 20
+
+// WebhookEvent is an issue changed on GitHub, from an issues or
+// issue_comment delivery
+type WebhookEvent struct {
+	Delivery   string
+	Event      string
+	Action     string
+	Repository string
+	Number     int
+}
+
+// webhookPayload holds the fields of issues and issue_comment payloads
+// needed to find the issue
+type webhookPayload struct {
+	Action string `json:"action"`
+	Issue  struct {
+		Number      int             `json:"number"`
+		PullRequest json.RawMessage `json:"pull_request"`
+	} `json:"issue"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// WebhookHandler receives GitHub webhook deliveries signed with secret and
+// hands issues and issue_comment events on issues (not pull requests) to
+// ingest. Ingesting takes API calls, so ingest should queue the event: the
+// delivery is acknowledged right away, as GitHub expects within 10 seconds.
+func WebhookHandler(secret string, ingest func(WebhookEvent)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+		if err != nil {
+			http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		if !validWebhookSignature(secret, body, r.Header.Get("X-Hub-Signature-256")) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		event := WebhookEvent{Delivery: r.Header.Get("X-GitHub-Delivery"), Event: r.Header.Get("X-GitHub-Event")}
+		switch event.Event {
+		case "ping":
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case "issues", "issue_comment":
+		default:
+			// Subscribed to more events than needed; nothing to do
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+			http.Error(w, "content type must be application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+		var payload webhookPayload
+		if err := json.Unmarshal(body, &payload); err != nil || payload.Repository.FullName == "" || payload.Issue.Number <= 0 {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+		if len(payload.Issue.PullRequest) > 0 && string(payload.Issue.PullRequest) != "null" {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		event.Action = payload.Action
+		event.Repository = payload.Repository.FullName
+		event.Number = payload.Issue.Number
+		log.Printf("Webhook %s %s.%s for %s#%d", event.Delivery, event.Event, event.Action, event.Repository, event.Number)
+		ingest(event)
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+// validWebhookSignature checks the X-Hub-Signature-256 header, the
+// HMAC-SHA256 of the body keyed with the webhook secret
+func validWebhookSignature(secret string, body []byte, signature string) bool {
+	if secret == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
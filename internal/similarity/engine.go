@@ -0,0 +1,184 @@
+package similarity
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// stopWords are common English words ignored when comparing text
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "can": true, "for": true, "from": true,
+	"has": true, "have": true, "i": true, "if": true, "in": true, "is": true,
+	"it": true, "its": true, "not": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "was": true, "we": true,
+	"when": true, "with": true, "after": true, "using": true, "there": true,
+}
+
+// titleWeight repeats title terms so they count more than body terms
+const titleWeight = 3
+
+// Match is a corpus issue ranked by similarity to a query
+type Match struct {
+	Issue      model.Issue `json:"issue"`
+	Similarity float64     `json:"similarity"`
+}
+
+// Engine ranks issues by TF-IDF cosine similarity
+type Engine struct {
+	issues  []model.Issue
+	vectors []map[string]float64
+	idf     map[string]float64
+}
+
+// NewEngine indexes all issues of a corpus
+func NewEngine(issues map[string][]model.Issue) *Engine {
+	engine := &Engine{idf: make(map[string]float64)}
+
+	var termCounts []map[string]int
+	docFreq := make(map[string]int)
+	for _, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			counts := termFrequencies(issueText(issue))
+			for term := range counts {
+				docFreq[term]++
+			}
+			engine.issues = append(engine.issues, issue)
+			termCounts = append(termCounts, counts)
+		}
+	}
+
+	n := float64(len(engine.issues))
+	for term, df := range docFreq {
+		engine.idf[term] = math.Log(1+n/float64(df)) + 1
+	}
+
+	for _, counts := range termCounts {
+		engine.vectors = append(engine.vectors, engine.vectorize(counts))
+	}
+
+	return engine
+}
+
+// Search returns up to limit issues most similar to text, above minSimilarity
+func (e *Engine) Search(text string, limit int, minSimilarity float64) []Match {
+	query := e.vectorize(termFrequencies(text))
+	if len(query) == 0 {
+		return nil
+	}
+
+	var matches []Match
+	for i, vector := range e.vectors {
+		similarity := cosine(query, vector)
+		if similarity > 0 && similarity >= minSimilarity {
+			matches = append(matches, Match{Issue: e.issues[i], Similarity: similarity})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Similarity > matches[j].Similarity
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Pair is two indexed issues that look like the same problem
+type Pair struct {
+	A, B       model.Issue
+	Similarity float64
+}
+
+// CrossSourcePairs returns pairs of indexed issues from different sources
+// (e.g. a GitHub issue and a Stack Overflow question) whose similarity
+// reaches minSimilarity, most similar first
+func (e *Engine) CrossSourcePairs(minSimilarity float64) []Pair {
+	var pairs []Pair
+	for i := range e.issues {
+		for j := i + 1; j < len(e.issues); j++ {
+			if e.issues[i].SourceName() == e.issues[j].SourceName() {
+				continue
+			}
+			if similarity := cosine(e.vectors[i], e.vectors[j]); similarity > 0 && similarity >= minSimilarity {
+				pairs = append(pairs, Pair{A: e.issues[i], B: e.issues[j], Similarity: similarity})
+			}
+		}
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].Similarity > pairs[j].Similarity
+	})
+	return pairs
+}
+
+// Similarity compares two issues using the corpus term weights
+func (e *Engine) Similarity(a, b model.Issue) float64 {
+	return cosine(e.vectorize(termFrequencies(issueText(a))), e.vectorize(termFrequencies(issueText(b))))
+}
+
+// vectorize turns term counts into a normalized TF-IDF vector.
+// Terms unknown to the corpus get the maximum IDF.
+func (e *Engine) vectorize(counts map[string]int) map[string]float64 {
+	maxIDF := math.Log(1+float64(len(e.issues))) + 1
+	vector := make(map[string]float64, len(counts))
+	var norm float64
+
+	for term, count := range counts {
+		idf, ok := e.idf[term]
+		if !ok {
+			idf = maxIDF
+		}
+		weight := (1 + math.Log(float64(count))) * idf
+		vector[term] = weight
+		norm += weight * weight
+	}
+
+	norm = math.Sqrt(norm)
+	for term := range vector {
+		vector[term] /= norm
+	}
+	return vector
+}
+
+// Tokenize lowercases text and splits it into terms, dropping stop words
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+
+	tokens := fields[:0]
+	for _, field := range fields {
+		if len(field) > 1 && !stopWords[field] {
+			tokens = append(tokens, field)
+		}
+	}
+	return tokens
+}
+
+func issueText(issue model.Issue) string {
+	return strings.Repeat(issue.Title+" ", titleWeight) + issue.SearchText() + " " + issue.DiscussionText()
+}
+
+func termFrequencies(text string) map[string]int {
+	counts := make(map[string]int)
+	for _, token := range Tokenize(text) {
+		counts[token]++
+	}
+	return counts
+}
+
+func cosine(a, b map[string]float64) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	var dot float64
+	for term, weight := range a {
+		dot += weight * b[term]
+	}
+	return dot
+}
@@ -0,0 +1,1310 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/analytics"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/export"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/jobs"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/notify"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/pipeline"
+)
+
+const (
+	issuesFile                = "issues.json"
+	repoSnapshotsFile         = "repo_snapshots.json"
+	reportersFile             = "reporters.json"
+	notesFile                 = "notes.json"
+	duplicatesFile            = "duplicates.json"
+	duplicateReviewsFile      = "duplicate_reviews.json"
+	pipelineFile              = "pipeline_state.json"
+	pipelineRunsFile          = "pipeline_runs.json"
+	classificationHistoryFile = "classification_history.json"
+	exportsFile               = "exports.json"
+	jobsFile                  = "jobs.json"
+	cvesFile                  = "cves.json"
+	digestFile                = "digest_state.json"
+	correctionsFile           = "corrections.json"
+	providerUsageFile         = "provider_usage.json"
+	repoETagsFile             = "repo_etags.json"
+	classificationsFile       = "classifications.json"
+	emptyScrapesFile          = "empty_scrapes.json"
+	ciFindingsFile            = "ci_findings.json"
+	scrapeStateFile           = "scrape_state.json"
+	notificationsSentFile     = "notifications_sent.json"
+	escalationsFile           = "escalations.json"
+	pullRequestsFile          = "pull_requests.json"
+	commentsFile              = "comments.json"
+	portfolioRiskFile         = "portfolio_risk.json"
+	tombstonesFile            = "tombstones.json"
+	poolMetricsFile           = "pool_metrics.json"
+
+	// lockFile is locked by Update, so separate processes sharing the
+	// directory (serve, daemon, webhook listener, jobs) take turns
+	lockFile = ".lock"
+)
+
+// maxClassificationRuns bounds how many runs of classification results are kept
+const maxClassificationRuns = 10
+
+// Store persists scraped data between runs as JSON files in a directory
+type Store struct {
+	dir string
+
+	// mu serializes Update calls from goroutines sharing the store
+	mu sync.Mutex
+	// jobsMu serializes job updates from concurrently running jobs
+	jobsMu sync.Mutex
+}
+
+// NewStore creates a store rooted at dir
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Update runs update while holding the store's write lock, so the loads
+// and saves it makes cannot interleave with those of other updates: two
+// runs rewriting the issues file one after the other keep each other's
+// changes instead of the last save winning. The lock is shared with other
+// processes through an advisory lock on the directory. Updates must not be
+// nested.
+func (s *Store) Update(update func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := lockDir(s.dir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return update()
+}
+
+// MalformedIssue is a stored issue that could not be decoded. It is kept in
+// the issues file as it is, so fixing the file by hand recovers it.
+type MalformedIssue struct {
+	Repository string
+	Index      int
+	Error      string
+}
+
+// LoadIssues returns the stored issues keyed by repository. Issues are
+// decoded one by one, so a malformed issue is skipped and logged instead of
+// failing the whole load; MalformedIssues lists the skipped ones.
+func (s *Store) LoadIssues() (map[string][]model.Issue, error) {
+	issues, malformed, err := s.loadIssues()
+	if err != nil {
+		return nil, err
+	}
+	if len(malformed) > 0 {
+		log.Printf("Skipped %d malformed issues in %s (first: %s[%d]: %s)", len(malformed), issuesFile, malformed[0].Repository, malformed[0].Index, malformed[0].Error)
+	}
+	return issues, nil
+}
+
+// MalformedIssues lists the stored issues LoadIssues skips
+func (s *Store) MalformedIssues() ([]MalformedIssue, error) {
+	_, malformed, err := s.loadIssues()
+	return malformed, err
+}
+
+// loadIssues decodes the issues file, returning the issues that could not
+// be decoded separately, ordered by repository and position
+func (s *Store) loadIssues() (map[string][]model.Issue, []MalformedIssue, error) {
+	raw, err := s.loadRawIssues()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issues := make(map[string][]model.Issue, len(raw))
+	var malformed []MalformedIssue
+	for repoName, rawIssues := range raw {
+		repoIssues := make([]model.Issue, 0, len(rawIssues))
+		for i, data := range rawIssues {
+			var issue model.Issue
+			if err := json.Unmarshal(data, &issue); err != nil {
+				malformed = append(malformed, MalformedIssue{Repository: repoName, Index: i, Error: err.Error()})
+				continue
+			}
+			repoIssues = append(repoIssues, issue)
+		}
+		issues[repoName] = repoIssues
+	}
+	sort.Slice(malformed, func(i, j int) bool {
+		if malformed[i].Repository != malformed[j].Repository {
+			return malformed[i].Repository < malformed[j].Repository
+		}
+		return malformed[i].Index < malformed[j].Index
+	})
+	return issues, malformed, nil
+}
+
+func (s *Store) loadRawIssues() (map[string][]json.RawMessage, error) {
+	var raw map[string][]json.RawMessage
+	if err := s.load(issuesFile, &raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// saveIssues writes the issues file. Stored entries that could not be
+// decoded are written back unchanged after the issues of their repository,
+// so rewriting the file never drops them.
+func (s *Store) saveIssues(issues map[string][]model.Issue) error {
+	raw, err := s.loadRawIssues()
+	if err != nil {
+		return err
+	}
+
+	entries := make(map[string][]interface{}, len(issues))
+	for repoName, repoIssues := range issues {
+		list := make([]interface{}, 0, len(repoIssues))
+		for _, issue := range repoIssues {
+			list = append(list, issue)
+		}
+		entries[repoName] = list
+	}
+	for repoName, rawIssues := range raw {
+		for _, data := range rawIssues {
+			var issue model.Issue
+			if json.Unmarshal(data, &issue) != nil {
+				entries[repoName] = append(entries[repoName], data)
+			}
+		}
+	}
+	return s.save(issuesFile, entries)
+}
+
+// SaveIssues replaces the stored issues for the given repositories.
+// FirstSeenAt is carried over from previously stored copies so local
+// ages survive re-scrapes, and category or priority changes against the
+// stored copies are appended to the classification history.
+func (s *Store) SaveIssues(issues map[string][]model.Issue) error {
+	stored, err := s.LoadIssues()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var changes []model.ClassificationChange
+	for repoName, repoIssues := range issues {
+		previous := make(map[int]model.Issue)
+		for _, issue := range stored[repoName] {
+			previous[issue.Number] = issue
+		}
+
+		merged := make([]model.Issue, len(repoIssues))
+		for i, issue := range repoIssues {
+			old, ok := previous[issue.Number]
+			if ok && !old.FirstSeenAt.IsZero() {
+				issue.FirstSeenAt = old.FirstSeenAt
+			} else if issue.FirstSeenAt.IsZero() {
+				issue.FirstSeenAt = now
+			}
+			if ok {
+				if change, changed := model.ClassificationChanged(old, issue, now); changed {
+					change.Repository = repoName
+					changes = append(changes, change)
+				}
+			}
+			// Comments live in the comments store
+			issue.Discussion = nil
+			merged[i] = issue
+		}
+		stored[repoName] = merged
+	}
+
+	if err := s.saveIssues(stored); err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	history, err := s.LoadClassificationHistory()
+	if err != nil {
+		return err
+	}
+	return s.save(classificationHistoryFile, append(history, changes...))
+}
+
+// UpdateIssue replaces one stored issue, or adds it to its repository,
+// keeping where it was transferred from
+func (s *Store) UpdateIssue(repoName string, issue model.Issue) error {
+	stored, err := s.LoadIssues()
+	if err != nil {
+		return err
+	}
+
+	repoIssues := append([]model.Issue(nil), stored[repoName]...)
+	for i, old := range repoIssues {
+		if old.Number != issue.Number {
+			continue
+		}
+		if issue.TransferredFrom == "" {
+			issue.TransferredFrom = old.TransferredFrom
+		}
+		repoIssues[i] = issue
+		return s.SaveIssues(map[string][]model.Issue{repoName: repoIssues})
+	}
+	return s.SaveIssues(map[string][]model.Issue{repoName: append(repoIssues, issue)})
+}
+
+// LoadClassificationHistory returns recorded category and priority changes, oldest first
+func (s *Store) LoadClassificationHistory() ([]model.ClassificationChange, error) {
+	var history []model.ClassificationChange
+	if err := s.load(classificationHistoryFile, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// LoadClassifications returns the stored classification results, oldest
+// run first
+func (s *Store) LoadClassifications() ([]model.ClassificationRecord, error) {
+	var records []model.ClassificationRecord
+	if err := s.load(classificationsFile, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// AppendClassifications stores the classification results of a run,
+// dropping the results of runs beyond maxClassificationRuns
+func (s *Store) AppendClassifications(records []model.ClassificationRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	stored, err := s.LoadClassifications()
+	if err != nil {
+		return err
+	}
+	stored = append(stored, records...)
+
+	runs := make(map[time.Time]bool)
+	for i := len(stored) - 1; i >= 0; i-- {
+		runs[stored[i].RunAt] = true
+		if len(runs) > maxClassificationRuns {
+			stored = stored[i+1:]
+			break
+		}
+	}
+	return s.save(classificationsFile, stored)
+}
+
+// LoadCorrections returns the manual category corrections
+func (s *Store) LoadCorrections() ([]model.CategoryCorrection, error) {
+	var corrections []model.CategoryCorrection
+	if err := s.load(correctionsFile, &corrections); err != nil {
+		return nil, err
+	}
+	return corrections, nil
+}
+
+// SetCorrection records a manual correction, replacing an earlier
+// correction of the same issue
+func (s *Store) SetCorrection(correction model.CategoryCorrection) error {
+	return s.SetCorrections([]model.CategoryCorrection{correction})
+}
+
+// SetCorrections records manual corrections. A correction that leaves the
+// category or priority empty keeps the one of an earlier correction of the
+// same issue.
+func (s *Store) SetCorrections(corrections []model.CategoryCorrection) error {
+	existing, err := s.LoadCorrections()
+	if err != nil {
+		return err
+	}
+	index := make(map[string]int, len(existing))
+	for i, correction := range existing {
+		index[model.IssueRef(correction.Repository, correction.IssueNumber)] = i
+	}
+	for _, correction := range corrections {
+		ref := model.IssueRef(correction.Repository, correction.IssueNumber)
+		i, ok := index[ref]
+		if !ok {
+			index[ref] = len(existing)
+			existing = append(existing, correction)
+			continue
+		}
+		if correction.Category == "" {
+			correction.Category = existing[i].Category
+			correction.Predicted = existing[i].Predicted
+		}
+		if correction.Priority == "" {
+			correction.Priority = existing[i].Priority
+		}
+		existing[i] = correction
+	}
+	return s.save(correctionsFile, existing)
+}
+
+// ApplyCorrections sets the corrected category and priority on the stored
+// issues, records the corrections so later runs keep them, and appends the
+// resulting changes to the classification history under the correction's
+// author. Nothing is written when a corrected issue is not stored. It
+// returns the number of issues changed.
+func (s *Store) ApplyCorrections(corrections []model.CategoryCorrection) (int, error) {
+	issues, err := s.LoadIssues()
+	if err != nil {
+		return 0, err
+	}
+	stored := make(map[string]*model.Issue)
+	for repoName, repoIssues := range issues {
+		for i := range repoIssues {
+			stored[model.IssueRef(repoName, repoIssues[i].Number)] = &issues[repoName][i]
+		}
+	}
+
+	var changes []model.ClassificationChange
+	for _, correction := range corrections {
+		issue, ok := stored[model.IssueRef(correction.Repository, correction.IssueNumber)]
+		if !ok {
+			return 0, fmt.Errorf("issue %s#%d is not stored", correction.Repository, correction.IssueNumber)
+		}
+		change := model.ClassificationChange{
+			Repository:       correction.Repository,
+			IssueNumber:      issue.Number,
+			PreviousCategory: issue.Category,
+			PreviousPriority: issue.Priority,
+			PreviousVersion:  issue.ClassificationVersion,
+			Version:          issue.ClassificationVersion,
+			ChangedAt:        correction.CorrectedAt,
+			Author:           correction.Author,
+		}
+		if correction.Category != "" {
+			issue.Category = correction.Category
+		}
+		if correction.Priority != "" {
+			issue.Priority = correction.Priority
+		}
+		if issue.Category == change.PreviousCategory && issue.Priority == change.PreviousPriority {
+			continue
+		}
+		change.Category, change.Priority = issue.Category, issue.Priority
+		changes = append(changes, change)
+	}
+
+	// Each file is replaced atomically; the issues go first so an
+	// interrupted run at worst leaves corrections to be re-applied
+	if err := s.saveIssues(issues); err != nil {
+		return 0, err
+	}
+	if err := s.SetCorrections(corrections); err != nil {
+		return 0, err
+	}
+	if len(changes) == 0 {
+		return 0, nil
+	}
+	history, err := s.LoadClassificationHistory()
+	if err != nil {
+		return 0, err
+	}
+	return len(changes), s.save(classificationHistoryFile, append(history, changes...))
+}
+
+// LoadProviderUsage returns the recorded API usage per month and provider
+func (s *Store) LoadProviderUsage() ([]model.ProviderUsage, error) {
+	var usage []model.ProviderUsage
+	if err := s.load(providerUsageFile, &usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// RecordProviderUsage adds one run's requests per provider to the usage of
+// the month the run happened in
+func (s *Store) RecordProviderUsage(counts map[string]int, now time.Time) error {
+	if len(counts) == 0 {
+		return nil
+	}
+	usage, err := s.LoadProviderUsage()
+	if err != nil {
+		return err
+	}
+
+	month := model.UsageMonth(now)
+	for provider, requests := range counts {
+		found := false
+		for i := range usage {
+			if usage[i].Month == month && usage[i].Provider == provider {
+				usage[i].Requests += requests
+				usage[i].Runs++
+				usage[i].UpdatedAt = now
+				found = true
+				break
+			}
+		}
+		if !found {
+			usage = append(usage, model.ProviderUsage{Month: month, Provider: provider, Requests: requests, Runs: 1, UpdatedAt: now})
+		}
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Month != usage[j].Month {
+			return usage[i].Month < usage[j].Month
+		}
+		return usage[i].Provider < usage[j].Provider
+	})
+	return s.save(providerUsageFile, usage)
+}
+
+// LoadRepoSnapshots returns all stored repository snapshots in capture order
+func (s *Store) LoadRepoSnapshots() ([]model.RepoSnapshot, error) {
+	var snapshots []model.RepoSnapshot
+	if err := s.load(repoSnapshotsFile, &snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// AppendRepoSnapshots adds new repository snapshots to the history
+func (s *Store) AppendRepoSnapshots(snapshots []model.RepoSnapshot) error {
+	history, err := s.LoadRepoSnapshots()
+	if err != nil {
+		return err
+	}
+	return s.save(repoSnapshotsFile, append(history, snapshots...))
+}
+
+// LoadEscalations returns the recorded escalations, oldest first
+func (s *Store) LoadEscalations() ([]model.Escalation, error) {
+	var escalations []model.Escalation
+	if err := s.load(escalationsFile, &escalations); err != nil {
+		return nil, err
+	}
+	return escalations, nil
+}
+
+// AppendEscalations adds newly detected escalations to the history
+func (s *Store) AppendEscalations(escalations []model.Escalation) error {
+	if len(escalations) == 0 {
+		return nil
+	}
+	history, err := s.LoadEscalations()
+	if err != nil {
+		return err
+	}
+	return s.save(escalationsFile, append(history, escalations...))
+}
+
+// LoadTombstones returns the recorded deletions, oldest first
+func (s *Store) LoadTombstones() ([]model.Tombstone, error) {
+	var tombstones []model.Tombstone
+	if err := s.load(tombstonesFile, &tombstones); err != nil {
+		return nil, err
+	}
+	return tombstones, nil
+}
+
+// AppendTombstones records deleted issues. Deletions record their
+// tombstones before saving the corpus without the issues, so a deletion
+// is never lost to exports; tombstones of issues still stored are ignored.
+func (s *Store) AppendTombstones(tombstones []model.Tombstone) error {
+	if len(tombstones) == 0 {
+		return nil
+	}
+	history, err := s.LoadTombstones()
+	if err != nil {
+		return err
+	}
+	return s.save(tombstonesFile, append(history, tombstones...))
+}
+
+// LoadPoolSamples returns the recorded API host slot usage, oldest first
+func (s *Store) LoadPoolSamples() ([]model.PoolSample, error) {
+	var samples []model.PoolSample
+	if err := s.load(poolMetricsFile, &samples); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// AppendPoolSamples adds the slot usage of a scrape to the history
+func (s *Store) AppendPoolSamples(samples []model.PoolSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+	history, err := s.LoadPoolSamples()
+	if err != nil {
+		return err
+	}
+	return s.save(poolMetricsFile, append(history, samples...))
+}
+
+// LoadPullRequests returns the stored pull requests keyed by repository
+func (s *Store) LoadPullRequests() (map[string][]model.PullRequest, error) {
+	pulls := make(map[string][]model.PullRequest)
+	if err := s.load(pullRequestsFile, &pulls); err != nil {
+		return nil, err
+	}
+	return pulls, nil
+}
+
+// SavePullRequests adds a repository's scraped pull requests to the stored
+// ones, replacing stored copies, most recently updated first
+func (s *Store) SavePullRequests(repoName string, pulls []model.PullRequest) error {
+	stored, err := s.LoadPullRequests()
+	if err != nil {
+		return err
+	}
+
+	byNumber := make(map[int]model.PullRequest)
+	for _, pull := range stored[repoName] {
+		byNumber[pull.Number] = pull
+	}
+	for _, pull := range pulls {
+		byNumber[pull.Number] = pull
+	}
+	merged := make([]model.PullRequest, 0, len(byNumber))
+	for _, pull := range byNumber {
+		merged = append(merged, pull)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].UpdatedAt.After(merged[j].UpdatedAt) })
+	stored[repoName] = merged
+	return s.save(pullRequestsFile, stored)
+}
+
+// LoadComments returns the stored issue comments, keyed by issue reference
+func (s *Store) LoadComments() (map[string]model.IssueComments, error) {
+	comments := make(map[string]model.IssueComments)
+	if err := s.load(commentsFile, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// SaveComments stores fetched issue comments, replacing those previously
+// stored for the same issues
+func (s *Store) SaveComments(fetched map[string]model.IssueComments) error {
+	if len(fetched) == 0 {
+		return nil
+	}
+	comments, err := s.LoadComments()
+	if err != nil {
+		return err
+	}
+	for ref, entry := range fetched {
+		comments[ref] = entry
+	}
+	return s.save(commentsFile, comments)
+}
+
+// AttachComments adds stored comments to the matching issues in place
+func (s *Store) AttachComments(issues map[string][]model.Issue) error {
+	comments, err := s.LoadComments()
+	if err != nil {
+		return err
+	}
+	for repoName, repoIssues := range issues {
+		for i := range repoIssues {
+			repoIssues[i].Discussion = comments[model.IssueRef(repoName, repoIssues[i].Number)].Comments
+		}
+	}
+	return nil
+}
+
+// LoadRepoETags returns the ETags of the last repository metadata fetches
+func (s *Store) LoadRepoETags() (map[string]string, error) {
+	etags := make(map[string]string)
+	if err := s.load(repoETagsFile, &etags); err != nil {
+		return nil, err
+	}
+	return etags, nil
+}
+
+// SaveRepoETags replaces the stored repository metadata ETags
+func (s *Store) SaveRepoETags(etags map[string]string) error {
+	return s.save(repoETagsFile, etags)
+}
+
+// LoadEmptyScrapes returns the repositories whose last scrape found no
+// matching issue, keyed by repository
+func (s *Store) LoadEmptyScrapes() (map[string]model.EmptyScrape, error) {
+	empty := make(map[string]model.EmptyScrape)
+	if err := s.load(emptyScrapesFile, &empty); err != nil {
+		return nil, err
+	}
+	return empty, nil
+}
+
+// UpdateEmptyScrapes records repositories that were scraped without a
+// match and forgets the matched ones, which have results again
+func (s *Store) UpdateEmptyScrapes(empty []model.EmptyScrape, matched []string) error {
+	if len(empty) == 0 && len(matched) == 0 {
+		return nil
+	}
+	stored, err := s.LoadEmptyScrapes()
+	if err != nil {
+		return err
+	}
+	for _, record := range empty {
+		stored[record.Repository] = record
+	}
+	for _, repoName := range matched {
+		delete(stored, repoName)
+	}
+	return s.save(emptyScrapesFile, stored)
+}
+
+// LoadCIFindings returns when the ci command first reported each pitfall,
+// keyed by issue reference
+func (s *Store) LoadCIFindings() (map[string]time.Time, error) {
+	findings := make(map[string]time.Time)
+	if err := s.load(ciFindingsFile, &findings); err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// SaveCIFindings replaces the pitfalls reported by the ci command
+func (s *Store) SaveCIFindings(findings map[string]time.Time) error {
+	return s.save(ciFindingsFile, findings)
+}
+
+// LoadLastScraped returns when each repository's issues were last fetched
+// and saved, keyed by repository
+func (s *Store) LoadLastScraped() (map[string]time.Time, error) {
+	lastScraped := make(map[string]time.Time)
+	if err := s.load(scrapeStateFile, &lastScraped); err != nil {
+		return nil, err
+	}
+	return lastScraped, nil
+}
+
+// MarkScraped records when repositories were scraped, once their issues
+// are saved. A zero time forgets the repository's scrape time, so its next
+// scrape is complete.
+func (s *Store) MarkScraped(scrapedAt map[string]time.Time) error {
+	if len(scrapedAt) == 0 {
+		return nil
+	}
+	lastScraped, err := s.LoadLastScraped()
+	if err != nil {
+		return err
+	}
+	for repoName, at := range scrapedAt {
+		if at.IsZero() {
+			delete(lastScraped, repoName)
+		} else {
+			lastScraped[repoName] = at
+		}
+	}
+	return s.save(scrapeStateFile, lastScraped)
+}
+
+// LoadReporters returns the stored reporter reputation scores
+func (s *Store) LoadReporters() ([]analytics.ReporterStats, error) {
+	var reporters []analytics.ReporterStats
+	if err := s.load(reportersFile, &reporters); err != nil {
+		return nil, err
+	}
+	return reporters, nil
+}
+
+// SaveReporters replaces the stored reporter reputation scores
+func (s *Store) SaveReporters(reporters []analytics.ReporterStats) error {
+	return s.save(reportersFile, reporters)
+}
+
+// LoadPortfolioRisk returns the recorded portfolio risk scores, oldest first
+func (s *Store) LoadPortfolioRisk() ([]analytics.PortfolioRisk, error) {
+	var risks []analytics.PortfolioRisk
+	if err := s.load(portfolioRiskFile, &risks); err != nil {
+		return nil, err
+	}
+	return risks, nil
+}
+
+// AppendPortfolioRisk adds portfolio risk scores to the history
+func (s *Store) AppendPortfolioRisk(risks []analytics.PortfolioRisk) error {
+	history, err := s.LoadPortfolioRisk()
+	if err != nil {
+		return err
+	}
+	return s.save(portfolioRiskFile, append(history, risks...))
+}
+
+// LoadNotes returns all stored notes
+func (s *Store) LoadNotes() ([]model.Note, error) {
+	var notes []model.Note
+	if err := s.load(notesFile, &notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// AddNote stores a new note and returns it with its assigned ID
+func (s *Store) AddNote(note model.Note) (model.Note, error) {
+	notes, err := s.LoadNotes()
+	if err != nil {
+		return model.Note{}, err
+	}
+
+	for _, existing := range notes {
+		if existing.ID >= note.ID {
+			note.ID = existing.ID + 1
+		}
+	}
+	if note.ID == 0 {
+		note.ID = 1
+	}
+	if note.CreatedAt.IsZero() {
+		note.CreatedAt = time.Now()
+	}
+
+	return note, s.save(notesFile, append(notes, note))
+}
+
+// UpdateNote replaces the text of an existing note
+func (s *Store) UpdateNote(id int, text string) error {
+	notes, err := s.LoadNotes()
+	if err != nil {
+		return err
+	}
+
+	for i := range notes {
+		if notes[i].ID == id {
+			notes[i].Text = text
+			return s.save(notesFile, notes)
+		}
+	}
+	return fmt.Errorf("note %d not found", id)
+}
+
+// DeleteNote removes a note by ID
+func (s *Store) DeleteNote(id int) error {
+	notes, err := s.LoadNotes()
+	if err != nil {
+		return err
+	}
+
+	for i := range notes {
+		if notes[i].ID == id {
+			return s.save(notesFile, append(notes[:i], notes[i+1:]...))
+		}
+	}
+	return fmt.Errorf("note %d not found", id)
+}
+
+// AttachFirstSeen sets FirstSeenAt of issues from their stored copies in
+// place. SaveIssues stamps it on its own copies, so freshly scraped issues
+// need it before local ages can be measured.
+func (s *Store) AttachFirstSeen(issues map[string][]model.Issue) error {
+	stored, err := s.LoadIssues()
+	if err != nil {
+		return err
+	}
+
+	for repoName, repoIssues := range issues {
+		firstSeen := make(map[int]time.Time, len(stored[repoName]))
+		for _, issue := range stored[repoName] {
+			firstSeen[issue.Number] = issue.FirstSeenAt
+		}
+		for i := range repoIssues {
+			if seen := firstSeen[repoIssues[i].Number]; !seen.IsZero() {
+				repoIssues[i].FirstSeenAt = seen
+			}
+		}
+	}
+	return nil
+}
+
+// AttachNotes adds stored notes to the matching issues in place
+func (s *Store) AttachNotes(issues map[string][]model.Issue) error {
+	notes, err := s.LoadNotes()
+	if err != nil {
+		return err
+	}
+
+	byIssue := make(map[string][]model.Note)
+	for _, note := range notes {
+		key := model.IssueRef(note.Repository, note.IssueNumber)
+		byIssue[key] = append(byIssue[key], note)
+	}
+
+	for repoName, repoIssues := range issues {
+		for i := range repoIssues {
+			repoIssues[i].Notes = byIssue[model.IssueRef(repoName, repoIssues[i].Number)]
+		}
+	}
+	return nil
+}
+
+// LoadDuplicateLinks returns the stored duplicate links
+func (s *Store) LoadDuplicateLinks() ([]model.DuplicateLink, error) {
+	var links []model.DuplicateLink
+	if err := s.load(duplicatesFile, &links); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// LinkDuplicates records an upstream duplicate link for every issue with a
+// known canonical issue. An existing upstream link for the same issue is replaced.
+func (s *Store) LinkDuplicates(issues map[string][]model.Issue) error {
+	links, err := s.LoadDuplicateLinks()
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string]int)
+	for i, link := range links {
+		if link.Source == model.DuplicateSourceUpstream {
+			index[model.IssueRef(link.Repository, link.IssueNumber)] = i
+		}
+	}
+
+	changed := false
+	now := time.Now()
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			canonicalRepo, canonicalNumber, ok := model.ParseIssueRef(issue.DuplicateOf)
+			if !ok {
+				continue
+			}
+
+			link := model.DuplicateLink{
+				Repository:          repoName,
+				IssueNumber:         issue.Number,
+				CanonicalRepository: canonicalRepo,
+				CanonicalNumber:     canonicalNumber,
+				Source:              model.DuplicateSourceUpstream,
+				LinkedAt:            now,
+			}
+			key := model.IssueRef(repoName, issue.Number)
+			if i, exists := index[key]; exists {
+				if strings.EqualFold(links[i].CanonicalRepository, canonicalRepo) && links[i].CanonicalNumber == canonicalNumber {
+					continue
+				}
+				links[i] = link
+			} else {
+				index[key] = len(links)
+				links = append(links, link)
+			}
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return s.save(duplicatesFile, links)
+}
+
+// AddDuplicateLinks stores new duplicate links, skipping links already
+// recorded for the same pair of issues and source in either direction, and
+// suggested links of pairs a reviewer rejected
+func (s *Store) AddDuplicateLinks(newLinks []model.DuplicateLink) error {
+	links, err := s.LoadDuplicateLinks()
+	if err != nil {
+		return err
+	}
+
+	pairKey := func(source, a, b string) string {
+		if a > b {
+			a, b = b, a
+		}
+		return source + " " + a + " " + b
+	}
+
+	known := make(map[string]bool)
+	for _, link := range links {
+		known[pairKey(link.Source, model.IssueRef(link.Repository, link.IssueNumber), model.IssueRef(link.CanonicalRepository, link.CanonicalNumber))] = true
+	}
+	rejected, err := s.rejectedPairs()
+	if err != nil {
+		return err
+	}
+
+	added := 0
+	for _, link := range newLinks {
+		key := pairKey(link.Source, model.IssueRef(link.Repository, link.IssueNumber), model.IssueRef(link.CanonicalRepository, link.CanonicalNumber))
+		if known[key] || (link.Suggested() && rejected[link.PairKey()]) {
+			continue
+		}
+		known[key] = true
+		links = append(links, link)
+		added++
+	}
+
+	if added == 0 {
+		return nil
+	}
+	return s.save(duplicatesFile, links)
+}
+
+// ReplaceDuplicateLinks replaces all stored links of a source with links,
+// keeping the original link time of pairs that were already linked and
+// leaving out suggested links of pairs a reviewer rejected
+func (s *Store) ReplaceDuplicateLinks(source string, newLinks []model.DuplicateLink) error {
+	links, err := s.LoadDuplicateLinks()
+	if err != nil {
+		return err
+	}
+
+	pairKey := func(link model.DuplicateLink) string {
+		return model.IssueRef(link.Repository, link.IssueNumber) + " " + model.IssueRef(link.CanonicalRepository, link.CanonicalNumber)
+	}
+
+	linkedAt := make(map[string]time.Time)
+	kept := links[:0]
+	for _, link := range links {
+		if link.Source == source {
+			linkedAt[pairKey(link)] = link.LinkedAt
+			continue
+		}
+		kept = append(kept, link)
+	}
+
+	rejected, err := s.rejectedPairs()
+	if err != nil {
+		return err
+	}
+	for _, link := range newLinks {
+		if link.Suggested() && rejected[link.PairKey()] {
+			continue
+		}
+		if at, ok := linkedAt[pairKey(link)]; ok {
+			link.LinkedAt = at
+		}
+		kept = append(kept, link)
+	}
+	return s.save(duplicatesFile, kept)
+}
+
+// LoadDuplicateReviews returns the verdicts on suggested duplicate links
+func (s *Store) LoadDuplicateReviews() ([]model.DuplicateReview, error) {
+	var reviews []model.DuplicateReview
+	if err := s.load(duplicateReviewsFile, &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// ReviewDuplicate records a verdict on a duplicate link, replacing an
+// earlier verdict on the same pair. A rejected pair loses its suggested
+// links, and AddDuplicateLinks and ReplaceDuplicateLinks skip it from then on.
+func (s *Store) ReviewDuplicate(review model.DuplicateReview) error {
+	reviews, err := s.LoadDuplicateReviews()
+	if err != nil {
+		return err
+	}
+	kept := reviews[:0]
+	for _, existing := range reviews {
+		if existing.PairKey() != review.PairKey() {
+			kept = append(kept, existing)
+		}
+	}
+	if err := s.save(duplicateReviewsFile, append(kept, review)); err != nil {
+		return err
+	}
+	if review.Verdict != model.DuplicateRejected {
+		return nil
+	}
+
+	links, err := s.LoadDuplicateLinks()
+	if err != nil {
+		return err
+	}
+	remaining := links[:0]
+	for _, link := range links {
+		if !link.Suggested() || link.PairKey() != review.PairKey() {
+			remaining = append(remaining, link)
+		}
+	}
+	if len(remaining) == len(links) {
+		return nil
+	}
+	return s.save(duplicatesFile, remaining)
+}
+
+// rejectedPairs returns the pair keys of rejected duplicate links
+func (s *Store) rejectedPairs() (map[string]bool, error) {
+	reviews, err := s.LoadDuplicateReviews()
+	if err != nil {
+		return nil, err
+	}
+	rejected := make(map[string]bool)
+	for _, review := range reviews {
+		if review.Verdict == model.DuplicateRejected {
+			rejected[review.PairKey()] = true
+		}
+	}
+	return rejected, nil
+}
+
+// AttachCrossSource fills AlsoReportedOn with the other members of each
+// issue's cross-source group
+func (s *Store) AttachCrossSource(issues map[string][]model.Issue) error {
+	links, err := s.LoadDuplicateLinks()
+	if err != nil {
+		return err
+	}
+
+	others := make(map[string][]string)
+	for _, group := range model.DuplicateGroups(links, model.DuplicateSourceCrossSource) {
+		for _, ref := range group {
+			for _, other := range group {
+				if other != ref {
+					others[ref] = append(others[ref], other)
+				}
+			}
+		}
+	}
+
+	for repoName, repoIssues := range issues {
+		for i := range repoIssues {
+			repoIssues[i].AlsoReportedOn = others[model.IssueRef(repoName, repoIssues[i].Number)]
+		}
+	}
+	return nil
+}
+
+// ApplyUpstreamChanges marks stored issues of a repository that were deleted
+// upstream and moves transferred issues (number -> owner/repo#number) to
+// their new repository, returning how many issues were marked and moved
+func (s *Store) ApplyUpstreamChanges(repository string, deleted []int, transferred map[int]string) (int, int, error) {
+	issues, err := s.LoadIssues()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	gone := make(map[int]bool, len(deleted))
+	for _, number := range deleted {
+		gone[number] = true
+	}
+
+	marked, moved := 0, 0
+	kept := issues[repository][:0]
+	for _, issue := range issues[repository] {
+		if gone[issue.Number] && !issue.DeletedUpstream {
+			issue.DeletedUpstream = true
+			marked++
+		}
+
+		if ref, ok := transferred[issue.Number]; ok {
+			if newRepo, newNumber, ok := model.ParseIssueRef(ref); ok {
+				issue.TransferredFrom = model.IssueRef(repository, issue.Number)
+				issue.Repository = newRepo
+				issue.Number = newNumber
+				issue.URL = fmt.Sprintf("https://github.com/%s/issues/%d", newRepo, newNumber)
+				if !containsIssue(issues[newRepo], newNumber) {
+					issues[newRepo] = append(issues[newRepo], issue)
+				}
+				moved++
+				continue
+			}
+		}
+		kept = append(kept, issue)
+	}
+	issues[repository] = kept
+
+	if marked == 0 && moved == 0 {
+		return 0, 0, nil
+	}
+	return marked, moved, s.saveIssues(issues)
+}
+
+func containsIssue(issues []model.Issue, number int) bool {
+	for _, issue := range issues {
+		if issue.Number == number {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadPipelineState returns the progress of the latest pipeline run
+func (s *Store) LoadPipelineState() (pipeline.State, error) {
+	var state pipeline.State
+	if err := s.load(pipelineFile, &state); err != nil {
+		return pipeline.State{}, err
+	}
+	return state, nil
+}
+
+// SavePipelineState records the progress of the current pipeline run
+func (s *Store) SavePipelineState(state pipeline.State) error {
+	return s.save(pipelineFile, state)
+}
+
+// maxPipelineRuns bounds the stored pipeline run history
+const maxPipelineRuns = 500
+
+// LoadPipelineRuns returns the recorded pipeline runs, oldest first
+func (s *Store) LoadPipelineRuns() ([]pipeline.RunRecord, error) {
+	var runs []pipeline.RunRecord
+	if err := s.load(pipelineRunsFile, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// AppendPipelineRun adds a run to the history, dropping the oldest runs
+// beyond maxPipelineRuns
+func (s *Store) AppendPipelineRun(run pipeline.RunRecord) error {
+	runs, err := s.LoadPipelineRuns()
+	if err != nil {
+		return err
+	}
+	runs = append(runs, run)
+	if len(runs) > maxPipelineRuns {
+		runs = runs[len(runs)-maxPipelineRuns:]
+	}
+	return s.save(pipelineRunsFile, runs)
+}
+
+// LoadExportResults returns the recorded export uploads, oldest first
+func (s *Store) LoadExportResults() ([]export.Result, error) {
+	var results []export.Result
+	if err := s.load(exportsFile, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// AppendExportResults records export uploads and their destination URLs
+func (s *Store) AppendExportResults(results []export.Result) error {
+	history, err := s.LoadExportResults()
+	if err != nil {
+		return err
+	}
+	return s.save(exportsFile, append(history, results...))
+}
+
+// LoadJobs returns the persisted jobs, oldest first
+func (s *Store) LoadJobs() ([]jobs.Job, error) {
+	var list []jobs.Job
+	if err := s.load(jobsFile, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// SaveJobs replaces the persisted jobs
+func (s *Store) SaveJobs(list []jobs.Job) error {
+	return s.save(jobsFile, list)
+}
+
+// UpdateJobs reloads the jobs, applies update to them and saves the result.
+// Updates from goroutines sharing the store are applied one at a time.
+func (s *Store) UpdateJobs(update func([]jobs.Job) ([]jobs.Job, error)) error {
+	s.jobsMu.Lock()
+	defer s.jobsMu.Unlock()
+
+	list, err := s.LoadJobs()
+	if err != nil {
+		return err
+	}
+	if list, err = update(list); err != nil {
+		return err
+	}
+	return s.SaveJobs(list)
+}
+
+// UpdateJob reloads the job with the given ID, applies update to it and
+// saves it, returning the updated job
+func (s *Store) UpdateJob(id int, update func(*jobs.Job) error) (jobs.Job, error) {
+	var updated jobs.Job
+	err := s.UpdateJobs(func(list []jobs.Job) ([]jobs.Job, error) {
+		i := jobs.Find(list, id)
+		if i < 0 {
+			return nil, fmt.Errorf("job %d not found", id)
+		}
+		if err := update(&list[i]); err != nil {
+			return nil, err
+		}
+		updated = list[i]
+		return list, nil
+	})
+	return updated, err
+}
+
+// LoadCVEs returns the stored CVE metadata keyed by CVE identifier
+func (s *Store) LoadCVEs() (map[string]model.CVE, error) {
+	cves := make(map[string]model.CVE)
+	if err := s.load(cvesFile, &cves); err != nil {
+		return nil, err
+	}
+	return cves, nil
+}
+
+// SaveCVEs replaces the stored CVE metadata
+func (s *Store) SaveCVEs(cves map[string]model.CVE) error {
+	return s.save(cvesFile, cves)
+}
+
+// LoadDigestState returns when the last automatic digest was posted
+func (s *Store) LoadDigestState() (notify.DigestState, error) {
+	var state notify.DigestState
+	if err := s.load(digestFile, &state); err != nil {
+		return notify.DigestState{}, err
+	}
+	return state, nil
+}
+
+// SaveDigestState records an automatic digest posting
+func (s *Store) SaveDigestState(state notify.DigestState) error {
+	return s.save(digestFile, state)
+}
+
+// LoadNotificationsSent returns what each alert last notified, keyed by
+// alert name and issue reference
+func (s *Store) LoadNotificationsSent() (map[string]map[string]notify.Sent, error) {
+	sent := make(map[string]map[string]notify.Sent)
+	if err := s.load(notificationsSentFile, &sent); err != nil {
+		return nil, err
+	}
+	return sent, nil
+}
+
+// SaveNotificationsSent replaces the record of sent notifications
+func (s *Store) SaveNotificationsSent(sent map[string]map[string]notify.Sent) error {
+	return s.save(notificationsSentFile, sent)
+}
+
+// load decodes a JSON file into v, leaving v untouched if the file does not exist
+func (s *Store) load(name string, v interface{}) error {
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+	return nil
+}
+
+// save writes v as JSON, replacing the file atomically
+func (s *Store) save(name string, v interface{}) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+
+	path := filepath.Join(s.dir, name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", name, err)
+	}
+	return nil
+}
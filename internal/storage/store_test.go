@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// TestUpdateAcrossStores adds notes through two stores on the same
+// directory, as separate processes would, and expects none to be lost
+func TestUpdateAcrossStores(t *testing.T) {
+	dir := t.TempDir()
+	stores := []*Store{NewStore(dir), NewStore(dir)}
+	const perStore = 20
+
+	var wg sync.WaitGroup
+	for _, store := range stores {
+		for i := 0; i < perStore; i++ {
+			wg.Add(1)
+			go func(store *Store) {
+				defer wg.Done()
+				err := store.Update(func() error {
+					_, err := store.AddNote(model.Note{Repository: "acme/infer", IssueNumber: 1, Text: "seen in production"})
+					return err
+				})
+				if err != nil {
+					t.Error(err)
+				}
+			}(store)
+		}
+	}
+	wg.Wait()
+
+	notes, err := stores[0].LoadNotes()
+	if err != nil {
+		t.Fatalf("Failed to load notes: %v", err)
+	}
+	ids := make(map[int]bool)
+	for _, note := range notes {
+		ids[note.ID] = true
+	}
+	if len(notes) != 2*perStore || len(ids) != len(notes) {
+		t.Errorf("Expected %d notes with distinct IDs, got %d notes and %d IDs", 2*perStore, len(notes), len(ids))
+	}
+
+	// The lock file is not data
+	usage, err := stores[0].Usage(notes[0].CreatedAt)
+	if err != nil || len(usage.Files) != 1 {
+		t.Errorf("Expected only the notes file in the usage, got %v (%v)", usage.Files, err)
+	}
+}
+
+func TestApplyCorrections(t *testing.T) {
+	store := NewStore(t.TempDir())
+	issues := map[string][]model.Issue{"acme/infer": {
+		{Repository: "acme/infer", Number: 1, Category: "crashes", Priority: "P2"},
+		{Repository: "acme/infer", Number: 2, Category: "crashes"},
+	}}
+	if err := store.SaveIssues(issues); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unknown issue fails the whole batch before anything is written
+	_, err := store.ApplyCorrections([]model.CategoryCorrection{
+		{Repository: "acme/infer", IssueNumber: 1, Category: "performance"},
+		{Repository: "acme/infer", IssueNumber: 9, Category: "performance"},
+	})
+	if err == nil {
+		t.Fatal("Expected a correction of an unknown issue to fail")
+	}
+	if corrections, _ := store.LoadCorrections(); len(corrections) != 0 {
+		t.Errorf("Expected no corrections recorded, got %v", corrections)
+	}
+
+	changed, err := store.ApplyCorrections([]model.CategoryCorrection{
+		{Repository: "acme/infer", IssueNumber: 1, Predicted: "crashes", Category: "performance", Author: "alice"},
+		{Repository: "acme/infer", IssueNumber: 2, Category: "crashes"},
+	})
+	if err != nil || changed != 1 {
+		t.Fatalf("Expected one issue changed, got %d (%v)", changed, err)
+	}
+	// A later priority-only correction keeps the corrected category
+	if _, err := store.ApplyCorrections([]model.CategoryCorrection{{Repository: "acme/infer", IssueNumber: 1, Priority: "P1", Author: "bob"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := store.LoadIssues()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue := stored["acme/infer"][0]; issue.Category != "performance" || issue.Priority != "P1" {
+		t.Errorf("Expected #1 stored as performance/P1, got %s/%s", issue.Category, issue.Priority)
+	}
+	corrections, err := store.LoadCorrections()
+	if err != nil || len(corrections) != 2 {
+		t.Fatalf("Expected one correction per issue, got %v (%v)", corrections, err)
+	}
+	if c := corrections[0]; c.Category != "performance" || c.Predicted != "crashes" || c.Priority != "P1" || c.Author != "bob" {
+		t.Errorf("Expected the merged correction of #1, got %+v", c)
+	}
+	history, err := store.LoadClassificationHistory()
+	if err != nil || len(history) != 2 {
+		t.Fatalf("Expected two recorded changes, got %v (%v)", history, err)
+	}
+	if h := history[0]; h.PreviousCategory != "crashes" || h.Category != "performance" || h.Author != "alice" {
+		t.Errorf("Expected alice's category change recorded, got %+v", h)
+	}
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// staleTempAge is how old a temporary file must be to count as left by an
+// interrupted save rather than belonging to a save in progress
+const staleTempAge = 10 * time.Minute
+
+// FileUsage is the size of one file of the store
+type FileUsage struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Usage summarizes the disk space taken by the store
+type Usage struct {
+	Files []FileUsage // data files, largest first
+	Total int64       // bytes of all files, temporary ones included
+	Stale []FileUsage // temporary files left by interrupted saves
+}
+
+// Usage measures the files of the store
+func (s *Store) Usage(now time.Time) (Usage, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return Usage{}, nil
+	}
+	if err != nil {
+		return Usage{}, fmt.Errorf("failed to read storage directory: %w", err)
+	}
+
+	var usage Usage
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == lockFile {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return Usage{}, fmt.Errorf("failed to stat %s: %w", entry.Name(), err)
+		}
+		file := FileUsage{Name: entry.Name(), Size: info.Size(), ModTime: info.ModTime()}
+		usage.Total += file.Size
+		if strings.HasSuffix(file.Name, ".tmp") {
+			if now.Sub(file.ModTime) >= staleTempAge {
+				usage.Stale = append(usage.Stale, file)
+			}
+			continue
+		}
+		usage.Files = append(usage.Files, file)
+	}
+	sort.SliceStable(usage.Files, func(i, j int) bool { return usage.Files[i].Size > usage.Files[j].Size })
+	return usage, nil
+}
+
+// RemoveStale removes temporary files left by interrupted saves, as
+// listed by Usage, and returns the bytes freed
+func (s *Store) RemoveStale(stale []FileUsage) (int64, error) {
+	var freed int64
+	for _, file := range stale {
+		if err := os.Remove(filepath.Join(s.dir, file.Name)); err != nil && !os.IsNotExist(err) {
+			return freed, fmt.Errorf("failed to remove %s: %w", file.Name, err)
+		}
+		freed += file.Size
+	}
+	return freed, nil
+}
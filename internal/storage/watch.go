@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Parts of the store reported by Watch
+const (
+	ChangeIssues          = "issues"
+	ChangeClassifications = "classifications"
+	ChangeDuplicates      = "duplicates"
+)
+
+// watchedFiles are the files behind each kind of change
+var watchedFiles = map[string]string{
+	ChangeIssues:          issuesFile,
+	ChangeClassifications: classificationsFile,
+	ChangeDuplicates:      duplicatesFile,
+}
+
+// Watch polls the store every interval until ctx is cancelled and calls
+// changed with the kind of every watched file saved since the previous
+// poll. Scrapes, the pipeline and the webhook listener run in their own
+// processes, so the files are the only signal they share with a server.
+func (s *Store) Watch(ctx context.Context, interval time.Duration, changed func(kind string)) {
+	type version struct {
+		modTime time.Time
+		size    int64
+	}
+	stat := func(name string) version {
+		info, err := os.Stat(filepath.Join(s.dir, name))
+		if err != nil {
+			return version{}
+		}
+		return version{info.ModTime(), info.Size()}
+	}
+
+	seen := make(map[string]version)
+	for kind, name := range watchedFiles {
+		seen[kind] = stat(name)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		// Report in a fixed order: issues are saved before their links
+		for _, kind := range []string{ChangeIssues, ChangeClassifications, ChangeDuplicates} {
+			if current := stat(watchedFiles[kind]); current != seen[kind] {
+				seen[kind] = current
+				changed(kind)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"log"
+	"time"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+)
+
+// repoBatch is one repository's issues queued for writing
+type repoBatch struct {
+	repository string
+	issues     []model.Issue
+	scrapedAt  time.Time
+}
+
+// Writer saves scraped repositories in the background. Write blocks once
+// buffer repositories are queued, so a slow disk slows scraping down instead
+// of piling up memory, while short stalls do not hold up HTTP fetches.
+type Writer struct {
+	store   *Store
+	batch   int
+	queue   chan repoBatch
+	done    chan struct{}
+	pending map[string][]model.Issue
+	// scrapedAt holds when pending repositories were fetched
+	scrapedAt map[string]time.Time
+	err       error
+}
+
+// NewWriter starts a writer that queues up to buffer repositories and saves
+// up to batch repositories per write
+func NewWriter(store *Store, buffer, batch int) *Writer {
+	if buffer < 1 {
+		buffer = 1
+	}
+	if batch < 1 {
+		batch = 1
+	}
+
+	w := &Writer{
+		store:     store,
+		batch:     batch,
+		queue:     make(chan repoBatch, buffer),
+		done:      make(chan struct{}),
+		pending:   make(map[string][]model.Issue),
+		scrapedAt: make(map[string]time.Time),
+	}
+	go w.run()
+	return w
+}
+
+// Write queues a repository's issues, blocking while the queue is full.
+// Once they are saved the repository is marked as scraped at scrapedAt, so
+// an interrupted run resumes from the repositories actually saved.
+func (w *Writer) Write(repository string, issues []model.Issue, scrapedAt time.Time) {
+	w.queue <- repoBatch{repository: repository, issues: issues, scrapedAt: scrapedAt}
+}
+
+// Close flushes queued repositories and returns the error of the last write
+// if repositories could not be saved
+func (w *Writer) Close() error {
+	close(w.queue)
+	<-w.done
+	return w.err
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+
+	for item := range w.queue {
+		w.pending[item.repository] = item.issues
+		w.scrapedAt[item.repository] = item.scrapedAt
+
+		// Take whatever else is already queued, up to the batch size
+	drain:
+		for len(w.pending) < w.batch {
+			select {
+			case next, ok := <-w.queue:
+				if !ok {
+					break drain
+				}
+				w.pending[next.repository] = next.issues
+				w.scrapedAt[next.repository] = next.scrapedAt
+			default:
+				break drain
+			}
+		}
+
+		w.flush()
+	}
+
+	// A failed write is retried once more before giving up
+	if len(w.pending) > 0 {
+		w.flush()
+	}
+}
+
+// flush saves pending repositories in one store update. On failure they
+// stay pending and are retried with the next batch instead of being dropped.
+func (w *Writer) flush() {
+	err := w.store.Update(func() error {
+		if err := w.store.SaveIssues(w.pending); err != nil {
+			return err
+		}
+		if err := w.store.MarkScraped(w.scrapedAt); err != nil {
+			log.Printf("Error recording scrape times: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error saving %d repositories, will retry: %v", len(w.pending), err)
+		w.err = err
+		return
+	}
+	w.pending = make(map[string][]model.Issue)
+	w.scrapedAt = make(map[string]time.Time)
+	w.err = nil
+}
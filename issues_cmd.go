@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/query"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// issuesPreviewLimit is the number of matching issues listed by a dry run
+const issuesPreviewLimit = 20
+
+// issuesCommand groups edits of locally stored issues
+func issuesCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "issues",
+		Aliases: []string{"issue"},
+		Usage:   "修改本地已保存的问题 (批量修正、按需刷新)",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "set",
+				Usage: "批量修正匹配查询的问题的分类和优先级 (后续分类以修正为准)",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "filter",
+						Usage:    "要修改的问题, 语法同 search, 例如 \"repo:vllm-project/vllm category:other\"",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "category",
+						Usage: fmt.Sprintf("新的分类 (%v)", scraper.Categories()),
+					},
+					&cli.StringFlag{
+						Name:  "priority",
+						Usage: fmt.Sprintf("新的优先级 (%v)", scraper.Priorities),
+					},
+					&cli.StringFlag{
+						Name:  "author",
+						Value: os.Getenv("USER"),
+						Usage: "修改人 (记录在分类变更历史中)",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "只预览会被修改的问题, 不写入",
+					},
+				},
+				Action: runIssuesSet,
+			},
+			{
+				Name:      "refresh",
+				Usage:     "立即从 GitHub 重新抓取单个问题 (含评论和时间线) 并更新本地数据",
+				ArgsUsage: "<id|owner/repo#number>",
+				Action:    runIssuesRefresh,
+			},
+		},
+	}
+}
+
+// runIssuesSet corrects the category and priority of all stored issues
+// matching the filter in one go
+func runIssuesSet(c *cli.Context) error {
+	category, priority := c.String("category"), c.String("priority")
+	if category == "" && priority == "" {
+		return fmt.Errorf("--category or --priority is required")
+	}
+	if category != "" && !contains(scraper.Categories(), category) {
+		return fmt.Errorf("--category must be one of: %v", scraper.Categories())
+	}
+	if priority != "" && !contains(scraper.Priorities, priority) {
+		return fmt.Errorf("--priority must be one of: %v", scraper.Priorities)
+	}
+	search, err := query.Parse(c.String("filter"))
+	if err != nil {
+		return fmt.Errorf("invalid --filter: %w", err)
+	}
+
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+
+	// Only issues whose category or priority actually change are corrected
+	var matched []model.Issue
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			issue.Repository = repoName
+			if !search.Match(issue) {
+				continue
+			}
+			if (category == "" || issue.Category == category) && (priority == "" || issue.Priority == priority) {
+				continue
+			}
+			matched = append(matched, issue)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if matched[i].Repository != matched[j].Repository {
+			return matched[i].Repository < matched[j].Repository
+		}
+		return matched[i].Number < matched[j].Number
+	})
+	if len(matched) == 0 {
+		fmt.Println("没有需要修改的问题")
+		return nil
+	}
+
+	if c.Bool("dry-run") {
+		for i, issue := range matched {
+			if i == issuesPreviewLimit {
+				fmt.Printf("  ... 其余 %d 个\n", len(matched)-issuesPreviewLimit)
+				break
+			}
+			fmt.Printf("  %s#%d  %s/%s -> %s/%s  %s\n", issue.Repository, issue.Number,
+				issue.Category, issue.Priority, orDefault(category, issue.Category), orDefault(priority, issue.Priority), issue.Title)
+		}
+		fmt.Printf("\n将修改 %d 个问题 (dry-run, 未写入)\n", len(matched))
+		return nil
+	}
+
+	filter := scraper.NewFilter(config.Filter)
+	now := time.Now()
+	corrections := make([]model.CategoryCorrection, 0, len(matched))
+	for _, issue := range matched {
+		corrections = append(corrections, newCorrection(filter, issue, category, priority, c.String("author"), now))
+	}
+
+	var changed int
+	err = store.Update(func() error {
+		changed, err = store.ApplyCorrections(corrections)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply corrections: %w", err)
+	}
+	fmt.Printf("✅ 已修改 %d 个问题\n", changed)
+	return nil
+}
+
+// newCorrection corrects an issue's category and priority; an empty one
+// is left unchanged
+func newCorrection(filter *scraper.Filter, issue model.Issue, category, priority, author string, now time.Time) model.CategoryCorrection {
+	correction := model.CategoryCorrection{
+		Repository:  issue.Repository,
+		IssueNumber: issue.Number,
+		Category:    category,
+		Priority:    priority,
+		Author:      author,
+		CorrectedAt: now,
+	}
+	if category != "" {
+		correction.Predicted = filter.Categorize(issue)
+	}
+	return correction
+}
+
+// triageIssue corrects the category and priority of one stored issue, as
+// issues set does, and returns the corrected issue. The correction is
+// applied under the store lock, since serve mode triages concurrently.
+func triageIssue(config scraper.Config, store *storage.Store, issue model.Issue, category, priority, author string) (model.Issue, error) {
+	correction := newCorrection(scraper.NewFilter(config.Filter), issue, category, priority, author, time.Now())
+	err := store.Update(func() error {
+		_, err := store.ApplyCorrections([]model.CategoryCorrection{correction})
+		return err
+	})
+	if err != nil {
+		return model.Issue{}, fmt.Errorf("failed to apply correction: %w", err)
+	}
+	if category != "" {
+		issue.Category = category
+	}
+	if priority != "" {
+		issue.Priority = priority
+	}
+	return issue, nil
+}
+
+// orDefault returns value, or fallback when value is empty
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// runIssuesRefresh re-fetches one issue, given by stored ID or reference
+func runIssuesRefresh(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("expected one issue ID or owner/repo#number")
+	}
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if token := c.String("token"); token != "" {
+		config.GitHubToken = token
+	}
+	store := storage.NewStore(config.Storage.Dir)
+
+	repoName, number, ok := model.ParseIssueRef(c.Args().First())
+	if !ok {
+		if repoName, number, err = storedIssueRef(store, c.Args().First()); err != nil {
+			return err
+		}
+	}
+
+	scraperInstance := scraper.NewScraper(config)
+	useCorrections(scraperInstance, store)
+	issue, err := refreshIssue(c.Context, scraperInstance, store, repoName, number)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🔄 已刷新 %s: %s\n", model.IssueRef(repoName, number), issue.Title)
+	fmt.Printf("   状态: %s  评分: %.1f  优先级: %s  分类: %s\n", issue.State, issue.Score, orDefault(issue.Priority, "-"), issue.Category)
+	if issue.FixedBy != "" {
+		fmt.Printf("   修复: %s\n", issue.FixedBy)
+	}
+	if issue.DuplicateOf != "" {
+		fmt.Printf("   重复: %s\n", issue.DuplicateOf)
+	}
+	if issue.Workaround != nil {
+		fmt.Printf("   变通方法: %s (置信度 %.2f)\n", issue.Workaround.URL, issue.Workaround.Confidence)
+	}
+	return nil
+}
+
+// storedIssueRef finds the repository and number of a stored issue by ID
+func storedIssueRef(store *storage.Store, value string) (string, int, error) {
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid issue %q (expected an ID or owner/repo#number)", value)
+	}
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			if issue.ID == id {
+				return repoName, issue.Number, nil
+			}
+		}
+	}
+	return "", 0, fmt.Errorf("no stored issue with ID %d", id)
+}
+
+// refreshIssue re-fetches one issue and saves it to the store. It runs
+// under the store lock and reloads the manual corrections first, so a
+// triage made since the scraper was created is kept, not overwritten by
+// the freshly classified copy.
+func refreshIssue(ctx context.Context, scraperInstance *scraper.Scraper, store *storage.Store, repoName string, number int) (model.Issue, error) {
+	var issue model.Issue
+	err := store.Update(func() error {
+		useCorrections(scraperInstance, store)
+		var err error
+		if issue, err = scraperInstance.RefreshIssue(ctx, repoName, number); err != nil {
+			return fmt.Errorf("failed to refresh %s: %w", model.IssueRef(repoName, number), err)
+		}
+		if err := store.UpdateIssue(repoName, issue); err != nil {
+			return fmt.Errorf("failed to save %s: %w", model.IssueRef(repoName, number), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Issue{}, err
+	}
+	return issue, nil
+}
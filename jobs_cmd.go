@@ -0,0 +1,381 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/jobs"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/pipeline"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// jobHeartbeat is how often a running job reports that its worker is alive
+// and checks for cancellation
+var jobHeartbeat = 30 * time.Second
+
+// jobsCommand manages persisted background jobs
+func jobsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "jobs",
+		Usage: "管理可在重启后继续的后台任务 (流水线步骤和导出)",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "add",
+				Usage:     "将任务加入队列",
+				ArgsUsage: "<classify|score|dedup|summarize|refresh-views|export>...",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "tier",
+						Usage: "调度层级 (interactive, background), 默认导出为 interactive, 其余为 background",
+					},
+				},
+				Action: runJobsAdd,
+			},
+			{
+				Name:  "list",
+				Usage: "列出任务及其状态和进度",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "status",
+						Usage: "只列出该状态的任务 (queued, running, failed, done, cancelled)",
+					},
+				},
+				Action: runJobsList,
+			},
+			{
+				Name:      "cancel",
+				Usage:     "取消排队中的任务, 或请求运行中的任务停止",
+				ArgsUsage: "<id>",
+				Action:    runJobsCancel,
+			},
+			{
+				Name:      "retry",
+				Usage:     "重新排队失败或已取消的任务",
+				ArgsUsage: "<id>",
+				Action:    runJobsRetry,
+			},
+			{
+				Name:  "run",
+				Usage: "依次执行排队中的任务, 直到队列为空",
+				Flags: []cli.Flag{
+					&cli.DurationFlag{
+						Name:  "stale-after",
+						Value: 10 * time.Minute,
+						Usage: "运行中的任务超过该时间没有心跳则视为中断并重新排队",
+					},
+				},
+				Action: runJobsWorker,
+			},
+		},
+	}
+}
+
+// runJobsAdd enqueues one job per argument
+func runJobsAdd(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("usage: jobs add <kind>...")
+	}
+
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	store := storage.NewStore(config.Storage.Dir)
+
+	runner, err := newPipeline(config, store, nil)
+	if err != nil {
+		return err
+	}
+	tier := c.String("tier")
+	if tier != "" && !contains(jobs.Tiers, tier) {
+		return fmt.Errorf("unknown tier %q (available: %v)", tier, jobs.Tiers)
+	}
+	kinds := append(runner.StepNames(), jobs.KindExport)
+	for _, kind := range c.Args().Slice() {
+		if !contains(kinds, kind) {
+			return fmt.Errorf("unknown job kind %q (available: %v)", kind, kinds)
+		}
+	}
+
+	return store.UpdateJobs(func(list []jobs.Job) ([]jobs.Job, error) {
+		for _, kind := range c.Args().Slice() {
+			var job jobs.Job
+			list, job = jobs.Enqueue(list, kind, tier, time.Now())
+			fmt.Printf("已加入任务 #%d (%s, %s)\n", job.ID, job.Kind, job.Tier)
+		}
+		return list, nil
+	})
+}
+
+// runJobsList prints the persisted jobs
+func runJobsList(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	list, err := storage.NewStore(config.Storage.Dir).LoadJobs()
+	if err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	status := c.String("status")
+	shown := 0
+	for _, job := range list {
+		if status != "" && job.Status != status {
+			continue
+		}
+		shown++
+
+		progress := "-"
+		if job.Total > 0 {
+			progress = fmt.Sprintf("%d/%d", job.Progress, job.Total)
+		}
+		fmt.Printf("#%-5d %-14s %-12s %-10s %-10s 尝试 %d  更新于 %s", job.ID, job.Kind, job.TierName(), job.Status, progress, job.Attempts, job.UpdatedAt.Format("2006-01-02 15:04:05"))
+		if job.Owner != "" {
+			fmt.Printf("  %s", job.Owner)
+		}
+		if job.CancelRequested && !job.Finished() {
+			fmt.Print("  (已请求取消)")
+		}
+		if job.Error != "" {
+			fmt.Printf("  %s", job.Error)
+		}
+		if job.Note != "" {
+			fmt.Printf("  %s", job.Note)
+		}
+		fmt.Println()
+	}
+
+	if shown == 0 {
+		fmt.Println("没有任务")
+	}
+	return nil
+}
+
+// runJobsCancel cancels a queued job or asks a running one to stop
+func runJobsCancel(c *cli.Context) error {
+	return updateJobCommand(c, jobs.Cancel, "已取消任务 #%d (%s)\n")
+}
+
+// runJobsRetry queues a failed or cancelled job again
+func runJobsRetry(c *cli.Context) error {
+	return updateJobCommand(c, jobs.Retry, "已重新排队任务 #%d (%s)\n")
+}
+
+// updateJobCommand applies a job transition to the job named by the first argument
+func updateJobCommand(c *cli.Context, transition func(*jobs.Job, time.Time) error, message string) error {
+	id, err := strconv.Atoi(c.Args().First())
+	if err != nil {
+		return fmt.Errorf("usage: jobs %s <id>", c.Command.Name)
+	}
+
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	job, err := storage.NewStore(config.Storage.Dir).UpdateJob(id, func(job *jobs.Job) error {
+		return transition(job, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf(message, job.ID, job.Status)
+	return nil
+}
+
+// runJobsWorker re-queues jobs of dead workers and then runs queued jobs
+// until the queue is empty, starting interactive jobs before background ones
+// within the per-tier concurrency limits. Every current kind writes the
+// store or the output directory, so started jobs take turns on the store
+// lock for that part.
+func runJobsWorker(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	store := storage.NewStore(config.Storage.Dir)
+
+	err = store.UpdateJobs(func(list []jobs.Job) ([]jobs.Job, error) {
+		if requeued := jobs.RequeueStale(list, c.Duration("stale-after"), time.Now()); requeued > 0 {
+			log.Printf("🔁 %d 个中断的任务已重新排队", requeued)
+		}
+		return list, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update jobs: %w", err)
+	}
+
+	owner := jobs.Owner()
+	running := make(map[string]int)
+	active := 0
+	finished := make(chan jobs.Job)
+	for {
+		for {
+			job, ok, err := claimJob(store, owner, running, config.Jobs)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			running[job.Tier]++
+			active++
+
+			log.Printf("▶️  任务 #%d (%s, %s) 开始, 第 %d 次尝试", job.ID, job.Kind, job.Tier, job.Attempts)
+			go func(job jobs.Job) {
+				items, runErr := runJob(c.Context, config, store, job)
+				finishJob(store, job, items, runErr)
+				finished <- job
+			}(job)
+		}
+
+		if active == 0 {
+			log.Println("✅ 队列已清空")
+			return nil
+		}
+		job := <-finished
+		running[job.Tier]--
+		active--
+	}
+}
+
+// claimJob marks the next job the tier limits allow as running by owner
+func claimJob(store *storage.Store, owner string, running map[string]int, config jobs.Config) (jobs.Job, bool, error) {
+	var claimed jobs.Job
+	found := false
+	err := store.UpdateJobs(func(list []jobs.Job) ([]jobs.Job, error) {
+		i := jobs.Pick(list, running, config)
+		if i < 0 {
+			return list, nil
+		}
+
+		now := time.Now()
+		list[i].Tier = list[i].TierName()
+		list[i].Status = jobs.StatusRunning
+		list[i].Owner = owner
+		list[i].Attempts++
+		list[i].StartedAt = now
+		list[i].UpdatedAt = now
+		claimed, found = list[i], true
+		return list, nil
+	})
+	if err != nil {
+		return jobs.Job{}, false, fmt.Errorf("failed to claim job: %w", err)
+	}
+	return claimed, found, nil
+}
+
+// finishJob records the final status of a job. A job is cancelled only if
+// the cancellation stopped its work; one that finished before noticing the
+// request is done, with a note saying so.
+func finishJob(store *storage.Store, job jobs.Job, items int, runErr error) {
+	finished, err := store.UpdateJob(job.ID, func(j *jobs.Job) error {
+		now := time.Now()
+		j.UpdatedAt = now
+		j.FinishedAt = now
+		j.Progress, j.Total = items, items
+		switch {
+		case j.CancelRequested && errors.Is(runErr, context.Canceled):
+			j.Status = jobs.StatusCancelled
+		case runErr != nil:
+			j.Status = jobs.StatusFailed
+			j.Error = runErr.Error()
+		default:
+			j.Status = jobs.StatusDone
+			if j.CancelRequested {
+				j.Note = "cancellation was requested after the work finished"
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("⚠️  警告: 未能更新任务 #%d 的状态: %v", job.ID, err)
+		return
+	}
+	log.Printf("⏹️  任务 #%d (%s): %s", finished.ID, finished.Kind, finished.Status)
+}
+
+// runJob runs a claimed job while a heartbeat keeps it from being considered
+// stale and cancels it once a cancellation is requested. A cancelled job
+// stops before its work starts and returns context.Canceled; work already
+// under way runs to completion. It returns the number of items processed.
+func runJob(ctx context.Context, config scraper.Config, store *storage.Store, job jobs.Job) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var heartbeat sync.WaitGroup
+	done := make(chan struct{})
+	heartbeat.Add(1)
+	defer heartbeat.Wait()
+	defer close(done)
+	go func() {
+		defer heartbeat.Done()
+		ticker := time.NewTicker(jobHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				updated, err := store.UpdateJob(job.ID, func(j *jobs.Job) error {
+					j.UpdatedAt = time.Now()
+					return nil
+				})
+				if err != nil {
+					log.Printf("⚠️  警告: 未能更新任务 #%d 的心跳: %v", job.ID, err)
+					continue
+				}
+				if updated.CancelRequested {
+					cancel()
+				}
+			}
+		}
+	}()
+
+	// Exports write the shared output directory and pipeline steps rewrite
+	// the corpus, so both run under the store lock: jobs the tier limits
+	// start together take turns there instead of losing each other's writes
+	if job.Kind == jobs.KindExport {
+		items := 0
+		err := store.Update(func() error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			issues, err := store.LoadIssues()
+			if err != nil {
+				return fmt.Errorf("failed to load stored issues: %w", err)
+			}
+			for _, repoIssues := range issues {
+				items += len(repoIssues)
+			}
+			return writeReports(config, store, issues)
+		})
+		return items, err
+	}
+
+	results, err := runPipeline(ctx, config, store, []string{job.Kind}, false)
+	if err != nil {
+		return 0, err
+	}
+	items := 0
+	for _, result := range results {
+		items += result.Items
+		if result.Status == pipeline.StatusSkipped && ctx.Err() != nil {
+			return items, ctx.Err()
+		}
+		if result.Status != pipeline.StatusDone {
+			return items, fmt.Errorf("step %s %s: %s", result.Step, result.Status, result.Error)
+		}
+	}
+	return items, nil
+}
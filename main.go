@@ -6,15 +6,23 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 	"github.com/urfave/cli/v2"
 
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/analytics"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/client"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/export"
 	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/notify"
 	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/output"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/query"
 	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
 )
 
 func main() {
@@ -39,7 +47,35 @@ func main() {
 			&cli.StringFlag{
 				Name:  "format",
 				Value: "markdown",
-				Usage: "输出格式 (markdown/json)",
+				Usage: "输出格式 (markdown/json/csv/ndjson/groups)",
+			},
+			&cli.StringFlag{
+				Name:  "abandoned",
+				Usage: "无人响应问题的处理方式 (include/exclude/only)",
+			},
+			&cli.IntFlag{
+				Name:  "sample",
+				Usage: "每个仓库仅抽样 N 个问题 (按状态与时间分层)，用于快速评估",
+			},
+			&cli.BoolFlag{
+				Name:  "full",
+				Usage: "忽略上次抓取时间, 完整重新抓取所有问题",
+			},
+			&cli.BoolFlag{
+				Name:  "skip-empty",
+				Usage: "跳过上次以相同关键词和过滤条件抓取时没有匹配问题的仓库",
+			},
+			&cli.BoolFlag{
+				Name:  "serve",
+				Usage: "不抓取, 启动 HTTP 服务提供问题查询 REST API (同 serve 命令)",
+			},
+			&cli.StringFlag{
+				Name:  "webhook-listen",
+				Usage: "不轮询, 在此地址 (如 :8081) 接收 GitHub webhook (issues、issue_comment 事件, 用 serve.webhook_secret 校验签名) 并实时更新问题库",
+			},
+			&cli.BoolFlag{
+				Name:  "daemon",
+				Usage: "常驻运行: 按各仓库的 cron 计划 (schedule) 重新抓取, 并按 daemon.tasks 执行维护和分析任务",
 			},
 			&cli.BoolFlag{
 				Name:  "dry-run",
@@ -51,6 +87,36 @@ func main() {
 			},
 		},
 		Action: runApp,
+		Commands: []*cli.Command{
+			analyticsCommand(),
+			notesCommand(),
+			similarCommand(),
+			searchCommand(),
+			pipelineCommand(),
+			verifyCommand(),
+			reprocessCommand(),
+			housekeepCommand(),
+			dbCommand(),
+			jobsCommand(),
+			exportCommand(),
+			importCommand(),
+			verifyArtifactCommand(),
+			securityCommand(),
+			digestCommand(),
+			serveCommand(),
+			mcpCommand(),
+			scoreCommand(),
+			dedupCommand(),
+			classifyCommand(),
+			reposCommand(),
+			issuesCommand(),
+			feedsCommand(),
+			initCommand(),
+			ciCommand(),
+			alertCommand(),
+			pullsCommand(),
+			portfoliosCommand(),
+		},
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -69,6 +135,9 @@ func runApp(c *cli.Context) error {
 	if verbose {
 		log.SetFlags(log.LstdFlags | log.Lshortfile)
 	}
+	if c.Bool("serve") {
+		return runServe(c)
+	}
 
 	// Load configuration
 	config, err := loadConfig(configPath)
@@ -86,18 +155,36 @@ func runApp(c *cli.Context) error {
 	if format != "" {
 		config.Output.Format = format
 	}
+	if abandoned := c.String("abandoned"); abandoned != "" {
+		config.Filter.Abandoned = abandoned
+	}
+	if c.Bool("skip-empty") {
+		config.Filter.SkipKnownEmpty = true
+	}
+	if sample := c.Int("sample"); sample > 0 {
+		config.Sample = sample
+	}
 
 	log.Printf("🚀 启动 gh-pitfall-scraper...")
 	log.Printf("📁 配置文件: %s", configPath)
 	log.Printf("📤 输出目录: %s", config.Output.OutputDir)
 	log.Printf("📄 输出格式: %s", config.Output.Format)
+	if config.Sample > 0 {
+		log.Printf("🎲 抽样模式: 每个仓库 %d 个问题", config.Sample)
+	}
 
 	if dryRun {
 		log.Println("🔍 试运行模式 - 将模拟数据")
 		return runDryRun(config)
 	}
+	if addr := c.String("webhook-listen"); addr != "" {
+		return runWebhookListen(c.Context, config, addr)
+	}
+	if c.Bool("daemon") {
+		return runDaemon(c.Context, config, c.Bool("full"))
+	}
 
-	return runScrape(config)
+	return runScrape(context.Background(), config, c.Bool("full"))
 }
 
 // loadConfig loads configuration from YAML file
@@ -107,12 +194,74 @@ func loadConfig(configPath string) (scraper.Config, error) {
 
 	// Set defaults
 	viper.SetDefault("filter.min_score", 20.0)
+	viper.SetDefault("scoring.keyword_weight", scraper.DefaultScoreWeights.Keyword)
+	viper.SetDefault("scoring.pattern_weight", scraper.DefaultScoreWeights.Pattern)
+	viper.SetDefault("scoring.label_weight", scraper.DefaultScoreWeights.Label)
+	viper.SetDefault("scoring.status_weight", scraper.DefaultScoreWeights.Status)
+	viper.SetDefault("scoring.activity_weight", scraper.DefaultScoreWeights.Activity)
+	viper.SetDefault("scoring.question_deboost", 0.5)
 	viper.SetDefault("filter.required_state", "all")
 	viper.SetDefault("filter.max_issues", 50)
 	viper.SetDefault("output.format", "markdown")
 	viper.SetDefault("output.output_dir", "./output")
 	viper.SetDefault("output.sort_by", "score")
 	viper.SetDefault("output.include_raw", false)
+	viper.SetDefault("filter.abandoned", "include")
+	viper.SetDefault("abandoned.no_response_days", 30)
+	viper.SetDefault("abandoned.stale_bots", []string{"stale[bot]", "github-actions[bot]"})
+	viper.SetDefault("search.relevance_weights.text", query.DefaultRelevanceWeights.Text)
+	viper.SetDefault("search.relevance_weights.score", query.DefaultRelevanceWeights.Score)
+	viper.SetDefault("search.relevance_weights.recency", query.DefaultRelevanceWeights.Recency)
+	viper.SetDefault("search.relevance_weights.phrase", query.DefaultRelevanceWeights.Phrase)
+	viper.SetDefault("politeness.min_interval_ms", 100)
+	viper.SetDefault("politeness.max_concurrency", 4)
+	viper.SetDefault("app.max_workers", 4)
+	viper.SetDefault("app.worker_queue", 4)
+	viper.SetDefault("politeness.requests_per_hour", 4500)
+	viper.SetDefault("politeness.max_backoff_seconds", 300)
+	viper.SetDefault("politeness.max_retries", 5)
+	viper.SetDefault("politeness.rate_limit_reserve", 50)
+	viper.SetDefault("enrich.concurrency", 1)
+	viper.SetDefault("enrich.max_comments", 100)
+	viper.SetDefault("stackoverflow.max_questions", 50)
+	viper.SetDefault("dedup.cross_source", true)
+	viper.SetDefault("dedup.cross_source_min_similarity", 0.5)
+	viper.SetDefault("dedup.policy", scraper.DuplicatePolicyMarkOnly)
+	viper.SetDefault("maintenance.confirm_threshold", 100)
+	viper.SetDefault("daemon.schedule", "0 */6 * * *")
+	viper.SetDefault("storage.dir", "./data")
+	viper.SetDefault("storage.write_buffer", 8)
+	viper.SetDefault("storage.write_batch", 4)
+	viper.SetDefault("export.retries", 3)
+	viper.SetDefault("notify.digest_period", "7d")
+	viper.SetDefault("notify.digest_format", output.DigestMarkdown)
+	viper.SetDefault("privacy", scraper.PrivacyStandard)
+	viper.SetDefault("ci.min_score", 60.0)
+	viper.SetDefault("ci.min_priority", "high")
+	viper.SetDefault("escalation.reaction_spike", 10)
+	viper.SetDefault("escalation.reaction_ratio", 2.0)
+	viper.SetDefault("pull_requests.max_pulls", 100)
+	viper.SetDefault("pull_requests.max_review_comments", 50)
+	viper.SetDefault("comments.max_per_issue", 50)
+	viper.SetDefault("comments.requests_per_minute", 60)
+	viper.SetDefault("portfolio_risk.unresolved_weight", 0.5)
+	viper.SetDefault("portfolio_risk.health_weight", 0.3)
+	viper.SetDefault("portfolio_risk.trend_weight", 0.2)
+	viper.SetDefault("portfolio_risk.trend_days", 30)
+	viper.SetDefault("attribution.user_agent", "gh-pitfall-scraper")
+	viper.SetDefault("serve.addr", ":8080")
+	viper.SetDefault("serve.answer_limit", 3)
+	viper.SetDefault("serve.max_page_size", 500)
+	viper.SetDefault("serve.query_timeout_seconds", 30)
+	viper.SetDefault("serve.max_result_bytes", 16<<20)
+	viper.SetDefault("jobs.interactive_concurrency", 2)
+	viper.SetDefault("jobs.background_concurrency", 1)
+	viper.SetDefault("sla.untriaged_days", 7)
+	viper.SetDefault("sla.unanswered_days", 14)
+	viper.SetDefault("severity.labels", map[string]string{
+		"p0": "critical", "p1": "high", "p2": "medium", "p3": "low",
+		"sev-1": "critical", "sev-2": "high", "sev-3": "medium",
+	})
 
 	// Read configuration
 	if err := viper.ReadInConfig(); err != nil {
@@ -120,7 +269,9 @@ func loadConfig(configPath string) (scraper.Config, error) {
 	}
 
 	var config scraper.Config
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := viper.Unmarshal(&config, func(dc *mapstructure.DecoderConfig) {
+		dc.TagName = "yaml"
+	}); err != nil {
 		return scraper.Config{}, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
@@ -129,12 +280,18 @@ func loadConfig(configPath string) (scraper.Config, error) {
 		return scraper.Config{}, fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	// Strict privacy is enforced on the shared HTTP transport before any
+	// client is created
+	if config.Privacy == scraper.PrivacyStrict {
+		client.RestrictToGitHub()
+	}
+
 	return config, nil
 }
 
 // validateConfig validates the configuration
 func validateConfig(config scraper.Config) error {
-	if len(config.Repositories) == 0 {
+	if len(config.Repositories) == 0 && !config.StackOverflow.Enabled {
 		return fmt.Errorf("no repositories configured")
 	}
 
@@ -145,13 +302,207 @@ func validateConfig(config scraper.Config) error {
 		if !strings.Contains(repo.Name, "/") {
 			return fmt.Errorf("repository name %s must be in format owner/repo", repo.Name)
 		}
+		if repo.Source != "" && !contains(scraper.ValidSources, repo.Source) {
+			return fmt.Errorf("repository %s: source must be one of: %v", repo.Name, scraper.ValidSources)
+		}
+		if repo.Source == model.SourceGitea && repo.BaseURL == "" {
+			return fmt.Errorf("repository %s: gitea source requires base_url", repo.Name)
+		}
+	}
+
+	if config.StackOverflow.Enabled {
+		if len(config.StackOverflow.Tags) == 0 {
+			return fmt.Errorf("stackoverflow.tags must not be empty when stackoverflow is enabled")
+		}
+		for _, tag := range config.StackOverflow.Tags {
+			if tag == "" || strings.ContainsAny(tag, "/ ") {
+				return fmt.Errorf("invalid stackoverflow tag %q", tag)
+			}
+		}
+	}
+
+	if config.Dedup.CrossSourceMinSimilarity < 0 || config.Dedup.CrossSourceMinSimilarity > 1 {
+		return fmt.Errorf("dedup.cross_source_min_similarity must be between 0 and 1")
+	}
+	if !contains(scraper.ValidDuplicatePolicies, config.Dedup.Policy) {
+		return fmt.Errorf("dedup.policy must be one of: %v", scraper.ValidDuplicatePolicies)
+	}
+	if config.Dedup.Policy == scraper.DuplicatePolicyDeleteAfterDays && config.Dedup.DeleteAfterDays <= 0 {
+		return fmt.Errorf("dedup.delete_after_days must be positive with the %s policy", scraper.DuplicatePolicyDeleteAfterDays)
 	}
 
 	if config.Filter.MinScore < 0 || config.Filter.MinScore > 100 {
 		return fmt.Errorf("min_score must be between 0 and 100")
 	}
+	if w := config.Scoring.Weights(); w.Keyword < 0 || w.Pattern < 0 || w.Label < 0 || w.Status < 0 || w.Activity < 0 {
+		return fmt.Errorf("scoring weights must not be negative")
+	}
+	if config.Scoring.QuestionDeboost < 0 || config.Scoring.QuestionDeboost > 1 {
+		return fmt.Errorf("scoring.question_deboost must be between 0 and 1")
+	}
+
+	validAbandoned := []string{"include", "exclude", "only"}
+	if !contains(validAbandoned, config.Filter.Abandoned) {
+		return fmt.Errorf("filter.abandoned must be one of: %v", validAbandoned)
+	}
+
+	if config.Enrich.Concurrency < 0 || config.Enrich.MaxComments < 0 || config.Enrich.MaxTimelineEvents < 0 {
+		return fmt.Errorf("enrich.concurrency, enrich.max_comments and enrich.max_timeline_events must not be negative")
+	}
+
+	for _, destination := range config.Export.Destinations {
+		if _, err := export.NewDestination(destination); err != nil {
+			return err
+		}
+	}
+	if r := config.Output.Retention; r.MaxFiles < 0 || r.MaxAgeDays < 0 || r.MaxTotalMB < 0 {
+		return fmt.Errorf("output.retention limits must not be negative")
+	}
+	if config.Export.Retries < 0 {
+		return fmt.Errorf("export.retries must not be negative")
+	}
+	feedNames := make(map[string]bool)
+	for _, feed := range config.Export.Feeds {
+		if _, err := export.NewFeed(feed); err != nil {
+			return fmt.Errorf("export.feeds: %w", err)
+		}
+		if feedNames[feed.Name] {
+			return fmt.Errorf("export.feeds: duplicate feed name %s", feed.Name)
+		}
+		feedNames[feed.Name] = true
+	}
+	if _, err := analytics.ParsePeriod(config.Notify.DigestPeriod); err != nil {
+		return fmt.Errorf("notify.digest_period: %w", err)
+	}
+	if !contains(output.DigestFormats, config.Notify.DigestFormat) {
+		return fmt.Errorf("notify.digest_format must be one of: %v", output.DigestFormats)
+	}
+	portfolioNames := make(map[string]bool)
+	for _, portfolio := range config.Portfolios {
+		if portfolio.Name == "" || strings.ContainsAny(portfolio.Name, " \t/") {
+			return fmt.Errorf("portfolios: every portfolio needs a name without spaces or slashes")
+		}
+		if portfolioNames[strings.ToLower(portfolio.Name)] {
+			return fmt.Errorf("portfolios: duplicate portfolio name %s", portfolio.Name)
+		}
+		portfolioNames[strings.ToLower(portfolio.Name)] = true
+		if len(portfolio.Repositories) == 0 {
+			return fmt.Errorf("portfolio %s has no repositories", portfolio.Name)
+		}
+		for _, search := range portfolio.Searches {
+			if search.Name == "" || search.Query == "" {
+				return fmt.Errorf("portfolio %s: every saved search needs a name and a query", portfolio.Name)
+			}
+		}
+	}
+	risk := config.PortfolioRisk
+	if risk.UnresolvedWeight < 0 || risk.HealthWeight < 0 || risk.TrendWeight < 0 {
+		return fmt.Errorf("portfolio_risk: weights must not be negative")
+	}
+	if risk.UnresolvedWeight+risk.HealthWeight+risk.TrendWeight == 0 {
+		return fmt.Errorf("portfolio_risk: at least one weight must be positive")
+	}
+	if risk.TrendDays <= 0 {
+		return fmt.Errorf("portfolio_risk.trend_days must be positive")
+	}
+	// Saved searches of portfolios are checked like alerts
+	alertNames := make(map[string]bool)
+	for _, alert := range config.Alerts() {
+		if alert.Name == "" {
+			return fmt.Errorf("notify.alerts: every alert needs a name")
+		}
+		if alertNames[alert.Name] {
+			return fmt.Errorf("notify.alerts: duplicate alert name %s", alert.Name)
+		}
+		alertNames[alert.Name] = true
+		if _, err := query.Parse(alert.Query); err != nil {
+			return fmt.Errorf("notify.alerts %s: %w", alert.Name, err)
+		}
+	}
+	if strings.ContainsAny(config.Attribution.UserAgent+config.Attribution.Contact, "\r\n") {
+		return fmt.Errorf("attribution.user_agent and attribution.contact must be single lines")
+	}
+	if config.Attribution.Contact != "" && !strings.Contains(config.Attribution.Contact, "@") {
+		return fmt.Errorf("attribution.contact must be an email address")
+	}
+	if _, err := time.LoadLocation(config.Timezone); err != nil {
+		return fmt.Errorf("timezone: unknown zone %q (expected an IANA name such as Asia/Shanghai)", config.Timezone)
+	}
+	if _, err := analytics.NewCalendar(config.Trends.Holidays, nil); err != nil {
+		return fmt.Errorf("trends.holidays: %w", err)
+	}
+	if !contains(scraper.PrivacyModes, config.Privacy) {
+		return fmt.Errorf("privacy must be one of: %v", scraper.PrivacyModes)
+	}
+	if config.Privacy == scraper.PrivacyStrict {
+		if violations := config.PrivacyViolations(); len(violations) > 0 {
+			return fmt.Errorf("privacy: strict allows no network calls except GitHub, but %s", strings.Join(violations, "; "))
+		}
+	}
+	if config.Budget.MonthlyRequests < 0 {
+		return fmt.Errorf("budget.monthly_requests must not be negative")
+	}
+	if config.CI.MinPriority != "" && !contains(scraper.Priorities, config.CI.MinPriority) {
+		return fmt.Errorf("ci.min_priority must be one of: %v", scraper.Priorities)
+	}
+	if config.CI.MaxNew < 0 {
+		return fmt.Errorf("ci.max_new must not be negative")
+	}
+	if config.Escalation.ReactionSpike < 0 {
+		return fmt.Errorf("escalation.reaction_spike must not be negative")
+	}
+	if config.Escalation.ReactionRatio < 1 {
+		return fmt.Errorf("escalation.reaction_ratio must be at least 1")
+	}
+	if config.PullRequests.MaxPulls < 1 || config.PullRequests.MaxReviewComments < 0 {
+		return fmt.Errorf("pull_requests.max_pulls must be positive and pull_requests.max_review_comments must not be negative")
+	}
+	if config.App.MaxWorkers < 1 || config.App.WorkerQueue < 0 {
+		return fmt.Errorf("app.max_workers must be positive and app.worker_queue must not be negative")
+	}
+	if config.Comments.MaxPerIssue < 0 || config.Comments.RequestsPerMinute < 0 {
+		return fmt.Errorf("comments.max_per_issue and comments.requests_per_minute must not be negative")
+	}
+	if config.Serve.AnswerLimit < 1 {
+		return fmt.Errorf("serve.answer_limit must be at least 1")
+	}
+	if config.Serve.MaxPageSize < 1 || config.Serve.QueryTimeoutSeconds < 0 || config.Serve.MaxResultBytes < 0 {
+		return fmt.Errorf("serve.max_page_size must be at least 1 and serve.query_timeout_seconds and serve.max_result_bytes must not be negative")
+	}
+	if config.Jobs.InteractiveConcurrency < 1 || config.Jobs.BackgroundConcurrency < 1 {
+		return fmt.Errorf("jobs.interactive_concurrency and jobs.background_concurrency must be at least 1")
+	}
+
+	if config.Maintenance.ConfirmThreshold < 0 {
+		return fmt.Errorf("maintenance.confirm_threshold must not be negative")
+	}
+	if err := validateDaemon(config); err != nil {
+		return err
+	}
+	if config.Storage.WriteBuffer < 0 || config.Storage.WriteBatch < 0 {
+		return fmt.Errorf("storage.write_buffer and storage.write_batch must not be negative")
+	}
+	if config.Storage.MaxSizeMB < 0 {
+		return fmt.Errorf("storage.max_size_mb must not be negative")
+	}
+	if config.PoolAlerts.MaxWaits < 0 || config.PoolAlerts.MaxWaitSeconds < 0 {
+		return fmt.Errorf("pool_alerts thresholds must not be negative")
+	}
+
+	if p := config.Politeness; p.MinIntervalMs < 0 || p.MaxConcurrency < 0 || p.RequestsPerHour < 0 || p.MaxBackoffSeconds < 0 || p.MaxRetries < 0 || p.RateLimitReserve < 0 {
+		return fmt.Errorf("politeness settings must not be negative")
+	}
+
+	if err := config.Severity.Validate(); err != nil {
+		return err
+	}
+
+	validSorts := []string{query.SortRelevance, query.SortScore, query.SortUpdated, query.SortCreated}
+	if !contains(validSorts, config.Output.SortBy) {
+		return fmt.Errorf("sort_by must be one of: %v", validSorts)
+	}
 
-	validFormats := []string{"markdown", "json"}
+	validFormats := []string{"markdown", "json", "csv", "ndjson", "groups"}
 	if !contains(validFormats, config.Output.Format) {
 		return fmt.Errorf("output format must be one of: %v", validFormats)
 	}
@@ -159,20 +510,108 @@ func validateConfig(config scraper.Config) error {
 	return nil
 }
 
-// runScrape executes the main scraping logic
-func runScrape(config scraper.Config) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+// runScrape executes the main scraping logic. Repositories scraped before
+// are fetched incrementally (GitHub issues updated since the last scrape,
+// merged into the stored ones) unless full.
+func runScrape(ctx context.Context, config scraper.Config, full bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Minute)
 	defer cancel()
 
 	// Create scraper
 	scraperInstance := scraper.NewScraper(config)
+	store := storage.NewStore(config.Storage.Dir)
+	useCorrections(scraperInstance, store)
+	config = skipKnownEmpty(config, store)
+
+	if config.Scoring.ReporterReputationWeight > 0 {
+		reporters, err := store.LoadReporters()
+		if err != nil {
+			log.Printf("⚠️  警告: 未能读取报告者信誉: %v", err)
+		}
+		scraperInstance.UseReporterReputation(analytics.ReputationMap(reporters), config.Scoring.ReporterReputationWeight)
+	}
 
-	// Scrape repositories
+	// Scrape repositories. Each repository is filtered, scored and enriched
+	// (fix links, workarounds, upstream duplicates) as soon as it is fetched,
+	// then handed to a background writer so saving overlaps with fetching.
 	log.Println("🔍 开始抓取仓库数据...")
-	allIssues, err := scraperInstance.ScrapeRepositories(ctx, config)
+	enrich := config.Enrich.FixLinks || config.Enrich.Workarounds || config.Enrich.Duplicates
+	if enrich && budgetExceeded(config, store) {
+		log.Printf("💸 本月 API 请求已超出预算 (budget.monthly_requests = %d), 暂停补充信息抓取", config.Budget.MonthlyRequests)
+		enrich = false
+	}
+	comments := config.Comments.Enabled
+	if comments && budgetExceeded(config, store) {
+		log.Printf("💸 本月 API 请求已超出预算 (budget.monthly_requests = %d), 暂停评论抓取", config.Budget.MonthlyRequests)
+		comments = false
+	}
+	var storedComments map[string]model.IssueComments
+	if comments {
+		var err error
+		if storedComments, err = store.LoadComments(); err != nil {
+			return fmt.Errorf("failed to load stored comments: %w", err)
+		}
+	}
+	allIssues := make(map[string][]model.Issue)
+	filteredIssues := make(map[string][]model.Issue)
+	var emptyScrapes []model.EmptyScrape
+	var matchedRepos []string
+	var escalations []model.Escalation
+
+	// Samples are not complete copies, so they neither use nor leave a
+	// scrape time to continue from
+	// Stored issues are also compared with re-scraped ones to detect escalations
+	var stored map[string][]model.Issue
+	var scrapedAt time.Time
+	if config.Sample == 0 {
+		scrapedAt = time.Now()
+		var err error
+		if stored, err = store.LoadIssues(); err != nil {
+			return fmt.Errorf("failed to load stored issues: %w", err)
+		}
+		lastScraped, err := store.LoadLastScraped()
+		if err != nil {
+			log.Printf("⚠️  警告: 未能读取上次抓取时间, 将完整抓取: %v", err)
+		} else if !full && len(lastScraped) > 0 {
+			scraperInstance.UseIncremental(lastScraped)
+			log.Println("⏩ 增量抓取: 只获取上次抓取后更新的问题 (使用 --full 完整抓取)")
+		}
+	}
+	writer := storage.NewWriter(store, config.Storage.WriteBuffer, config.Storage.WriteBatch)
+
+	err := scraperInstance.ScrapeEach(ctx, config, func(repoName string, issues []model.Issue) {
+		allIssues[repoName] = issues
+		repoFiltered := scraperInstance.FilterAndScoreIssues(map[string][]model.Issue{repoName: issues}, config)
+		if enrich {
+			scraperInstance.EnrichIssues(ctx, repoFiltered)
+		}
+		if scraperInstance.FetchedSince(repoName) {
+			repoFiltered[repoName] = scraperInstance.MergeIncremental(stored[repoName], issues, repoFiltered[repoName])
+		}
+		if comments {
+			// Comments take part in classification, so classify again with them
+			fetched := scraperInstance.FetchComments(ctx, repoFiltered, storedComments)
+			scraperInstance.ClassifyIssues(repoFiltered)
+			if err := store.SaveComments(fetched); err != nil {
+				log.Printf("⚠️  警告: 未能保存 %s 的评论: %v", repoName, err)
+			}
+		}
+		filteredIssues[repoName] = repoFiltered[repoName]
+		escalations = append(escalations, config.Escalation.Escalations(stored[repoName], repoFiltered[repoName], time.Now())...)
+		writer.Write(repoName, repoFiltered[repoName], scrapedAt)
+		if len(repoFiltered[repoName]) == 0 {
+			emptyScrapes = append(emptyScrapes, config.EmptyScrape(repoName, len(issues), time.Now()))
+		} else {
+			matchedRepos = append(matchedRepos, repoName)
+		}
+	})
+	writeErr := writer.Close()
 	if err != nil {
 		return fmt.Errorf("failed to scrape repositories: %w", err)
 	}
+	if err := store.UpdateEmptyScrapes(emptyScrapes, matchedRepos); err != nil {
+		log.Printf("⚠️  警告: 未能记录无匹配的仓库: %v", err)
+	}
 
 	if len(allIssues) == 0 {
 		log.Println("⚠️  没有抓取到任何数据")
@@ -180,34 +619,390 @@ func runScrape(config scraper.Config) error {
 	}
 
 	log.Printf("✅ 抓取完成，共获取 %d 个仓库的数据", len(allIssues))
-
-	// Filter and score issues
-	log.Println("🎯 开始过滤和评分...")
-	filteredIssues := scraperInstance.FilterAndScoreIssues(allIssues, config)
+	if config.PullRequests.Enabled && config.Sample == 0 {
+		scrapePullRequests(ctx, config, scraperInstance, store, allIssues)
+	}
 
 	// Print statistics
 	stats := scraperInstance.GetStatistics(allIssues, filteredIssues)
 	printStatistics(stats)
+	recordProviderUsage(config, store, scraperInstance.RequestCounts())
+	recordPoolMetrics(ctx, config, store, scraperInstance.PoolStats())
+	token, requests := scraperInstance.GitHubRequests()
+	log.Printf("🔑 GitHub 请求 (token %s): %d", token, requests)
+
+	// Link duplicates and refresh reporter reputation once results are saved
+	if writeErr != nil {
+		log.Printf("⚠️  警告: 未能保存抓取结果: %v", writeErr)
+	} else if _, err := runPipeline(ctx, config, store, []string{stepDedup, stepSummarize}, false); err != nil {
+		log.Printf("⚠️  警告: 未能运行处理流水线: %v", err)
+	}
+	if writeErr == nil {
+		recordClassifications(scraperInstance, store, filteredIssues)
+		if err := store.AppendEscalations(escalations); err != nil {
+			log.Printf("⚠️  警告: 未能记录问题升级: %v", err)
+		}
+		postEscalations(ctx, config, escalations)
+	}
+
+	if err := store.AttachFirstSeen(filteredIssues); err != nil {
+		log.Printf("⚠️  警告: 未能读取首次发现时间: %v", err)
+	}
+	if err := store.AttachNotes(filteredIssues); err != nil {
+		log.Printf("⚠️  警告: 未能读取备注: %v", err)
+	}
+	if err := store.AttachCrossSource(filteredIssues); err != nil {
+		log.Printf("⚠️  警告: 未能读取跨来源关联: %v", err)
+	}
+	if err := store.AttachComments(filteredIssues); err != nil {
+		log.Printf("⚠️  警告: 未能读取评论: %v", err)
+	}
+
+	// Snapshot repository health
+	log.Println("💓 记录仓库健康快照...")
+	if err := store.AppendRepoSnapshots(scraperInstance.SnapshotRepositories(ctx, filteredIssues)); err != nil {
+		log.Printf("⚠️  警告: 未能保存仓库快照: %v", err)
+	}
 
 	// Generate output
 	log.Println("📝 生成输出文件...")
-	formatter := output.NewFormatter()
-	if err := formatter.FormatIssues(filteredIssues, config.Output.Format, config.Output.OutputDir); err != nil {
-		return fmt.Errorf("failed to format output: %w", err)
+	if err := store.Update(func() error { return writeReports(config, store, filteredIssues) }); err != nil {
+		return err
 	}
+	recordPortfolioRisk(config, store, filteredIssues)
 
 	// Create summary report
 	if err := createSummaryReport(config, allIssues, filteredIssues); err != nil {
 		log.Printf("⚠️  警告: 未能创建摘要报告: %v", err)
 	}
 
+	printFailures(append(scraperInstance.Failures(), malformedIssueFailures(store)...))
+	if _, err := maintainStorage(ctx, config, store); err != nil {
+		log.Printf("⚠️  警告: 存储维护失败: %v", err)
+	}
+	postScheduledDigest(ctx, config, store)
+	if config.AlertsEnabled() {
+		if err := postAlerts(ctx, config, store, config.Alerts(), false, false); err != nil {
+			log.Printf("⚠️  警告: 告警发送失败: %v", err)
+		}
+	}
+
 	log.Printf("🎉 处理完成！结果保存在: %s", config.Output.OutputDir)
 	return nil
 }
 
+// budgetExceeded reports whether this month's API requests reached the
+// configured budget
+func budgetExceeded(config scraper.Config, store *storage.Store) bool {
+	if config.Budget.MonthlyRequests <= 0 {
+		return false
+	}
+	usage, err := store.LoadProviderUsage()
+	if err != nil {
+		log.Printf("⚠️  警告: 未能读取 API 用量: %v", err)
+		return false
+	}
+	return model.MonthlyRequests(usage, model.UsageMonth(time.Now())) >= config.Budget.MonthlyRequests
+}
+
+// skipKnownEmpty lists the repositories whose last scrape with the same
+// keywords and filters found nothing and, with filter.skip_known_empty,
+// disables them
+func skipKnownEmpty(config scraper.Config, store *storage.Store) scraper.Config {
+	empty, err := store.LoadEmptyScrapes()
+	if err != nil {
+		log.Printf("⚠️  警告: 未能读取无匹配的仓库: %v", err)
+		return config
+	}
+	known := config.KnownEmpty(empty)
+	if len(known) == 0 {
+		return config
+	}
+	names := make([]string, len(known))
+	skip := make(map[string]bool)
+	for i, record := range known {
+		names[i] = record.Repository
+		skip[record.Repository] = true
+	}
+	if !config.Filter.SkipKnownEmpty {
+		log.Printf("🕳️  %d 个仓库上次抓取没有匹配的问题 (使用 --skip-empty 跳过): %s", len(known), strings.Join(names, ", "))
+		return config
+	}
+
+	log.Printf("⏭️  跳过 %d 个已知没有匹配问题的仓库: %s", len(known), strings.Join(names, ", "))
+	repos := make([]scraper.RepositoryConfig, len(config.Repositories))
+	for i, repo := range config.Repositories {
+		repos[i] = repo
+		if skip[repo.Name] {
+			repos[i].Enabled = false
+		}
+	}
+	config.Repositories = repos
+	return config
+}
+
+// scrapeCoverage counts the enabled repositories with stored matches or
+// a recorded empty scrape under the current filters
+func scrapeCoverage(config scraper.Config, store *storage.Store, issues map[string][]model.Issue) (model.Coverage, error) {
+	empty, err := store.LoadEmptyScrapes()
+	if err != nil {
+		return model.Coverage{}, err
+	}
+	coverage := model.Coverage{Empty: config.KnownEmpty(empty)}
+	coverage.Scanned = len(coverage.Empty)
+	for _, repo := range config.Repositories {
+		if repo.Enabled && len(issues[repo.Name]) > 0 {
+			coverage.Scanned++
+		}
+	}
+	return coverage, nil
+}
+
+// recordProviderUsage stores this run's API requests and prints them with
+// the month's totals
+func recordProviderUsage(config scraper.Config, store *storage.Store, counts map[string]int) {
+	now := time.Now()
+	if err := store.RecordProviderUsage(counts, now); err != nil {
+		log.Printf("⚠️  警告: 未能记录 API 用量: %v", err)
+		return
+	}
+	usage, err := store.LoadProviderUsage()
+	if err != nil {
+		return
+	}
+
+	month := model.UsageMonth(now)
+	log.Printf("📡 API 用量 (%s):", month)
+	for _, u := range usage {
+		if u.Month == month {
+			log.Printf("   %s: 本次 %d, 本月 %d", u.Provider, counts[u.Provider], u.Requests)
+		}
+	}
+	if config.Budget.MonthlyRequests > 0 {
+		total := model.MonthlyRequests(usage, month)
+		log.Printf("   本月合计: %d / %d (%.1f%%)", total, config.Budget.MonthlyRequests, float64(total)/float64(config.Budget.MonthlyRequests)*100)
+	}
+}
+
+// recordPoolMetrics stores the concurrency slot usage of a scrape per API
+// host and alerts notify.webhooks about hosts over the pool_alerts
+// thresholds, whose politeness.max_concurrency is likely too small
+func recordPoolMetrics(ctx context.Context, config scraper.Config, store *storage.Store, stats map[string]client.PoolStats) {
+	now := time.Now()
+	samples := make([]model.PoolSample, 0, len(stats))
+	for host, s := range stats {
+		samples = append(samples, model.PoolSample{
+			Host:           host,
+			SampledAt:      now,
+			Slots:          s.Slots,
+			Requests:       s.Requests,
+			Waits:          s.Waits,
+			WaitSeconds:    s.WaitTime.Seconds(),
+			MaxWaitSeconds: s.MaxWait.Seconds(),
+			PacedSeconds:   s.Paced.Seconds(),
+		})
+	}
+	samples = model.LatestPoolSamples(samples)
+	if err := store.AppendPoolSamples(samples); err != nil {
+		log.Printf("⚠️  警告: 未能记录连接槽位指标: %v", err)
+	}
+
+	var lines []string
+	for _, sample := range samples {
+		if sample.Waits > 0 {
+			log.Printf("🚦 %s: %d/%d 次请求等待空闲槽位 (共 %d 个), 累计 %.1fs, 最长 %.1fs", sample.Host, sample.Waits, sample.Requests, sample.Slots, sample.WaitSeconds, sample.MaxWaitSeconds)
+		}
+		if config.PoolAlerts.Exceeded(sample) {
+			lines = append(lines, fmt.Sprintf("%s: %d of %d requests waited for one of %d slots, %.1fs in total (longest %.1fs)",
+				sample.Host, sample.Waits, sample.Requests, sample.Slots, sample.WaitSeconds, sample.MaxWaitSeconds))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	log.Printf("⚠️  警告: %d 个 API 主机的并发槽位不足, 可提高 politeness.max_concurrency", len(lines))
+	if config.Notify.Enabled() {
+		text := "API concurrency slots look undersized (raise politeness.max_concurrency):\n" + strings.Join(lines, "\n")
+		if err := notify.Post(ctx, config.Notify, map[string]interface{}{"text": text}); err != nil {
+			log.Printf("⚠️  警告: 未能发送连接槽位告警: %v", err)
+		}
+	}
+}
+
+// malformedIssueFailures reports the stored issues that could not be
+// decoded as failures. They are kept in the store but left out of the run.
+func malformedIssueFailures(store *storage.Store) []scraper.Failure {
+	malformed, err := store.MalformedIssues()
+	if err != nil {
+		log.Printf("⚠️  警告: 未能检查已存储的问题: %v", err)
+		return nil
+	}
+	failures := make([]scraper.Failure, 0, len(malformed))
+	for _, issue := range malformed {
+		failures = append(failures, scraper.Failure{
+			Stage: "load",
+			Item:  fmt.Sprintf("%s[%d]", issue.Repository, issue.Index),
+			Error: issue.Error,
+		})
+	}
+	return failures
+}
+
+// printFailures summarizes the repositories and issues that could not be
+// processed; everything else was still saved and reported
+func printFailures(failures []scraper.Failure) {
+	if len(failures) == 0 {
+		return
+	}
+
+	counts := scraper.CountFailures(failures)
+	stages := make([]string, 0, len(counts))
+	for stage, count := range counts {
+		stages = append(stages, fmt.Sprintf("%s %d", stage, count))
+	}
+	sort.Strings(stages)
+	log.Printf("⚠️  %d 个项目处理失败 (%s), 其余结果已正常保存:", len(failures), strings.Join(stages, ", "))
+
+	const maxListed = 20
+	for i, failure := range failures {
+		if i == maxListed {
+			log.Printf("   ... 另有 %d 个", len(failures)-maxListed)
+			break
+		}
+		log.Printf("   [%s] %s: %s", failure.Stage, failure.Item, failure.Error)
+	}
+}
+
+// writeReports writes the configured reports for issues, including the
+// summary sections backed by local storage
+func writeReports(config scraper.Config, store *storage.Store, issues map[string][]model.Issue) error {
+	if !config.Output.IncludeDeleted {
+		var excluded int
+		if issues, excluded = model.ExcludeDeletedUpstream(issues); excluded > 0 {
+			log.Printf("🗑️  已排除 %d 个上游已删除的问题", excluded)
+		}
+	}
+
+	formatter := output.NewFormatter()
+	formatter.SetLocation(config.Location())
+	formatter.AddSection(output.SLASection(checkSLA(config, issues)))
+	formatter.AddSection(output.AbandonedSection(issues))
+	formatter.AddSection(output.FixStatusSection(issues))
+	if snapshots, err := store.LoadRepoSnapshots(); err != nil {
+		log.Printf("⚠️  警告: 未能读取仓库快照: %v", err)
+	} else if len(snapshots) > 0 {
+		model.MarkRepoRisks(issues, snapshots)
+		formatter.AddSection(output.RepoHealthSection(snapshots))
+	}
+	if len(config.Portfolios) > 0 {
+		config.Portfolios.MarkCorpus(issues)
+		formatter.AddSection(output.PortfolioSection(analytics.PortfolioRollup(issues, config.Portfolios.Names())))
+		if history, err := store.LoadPortfolioRisk(); err != nil {
+			log.Printf("⚠️  警告: 未能读取 portfolio 风险分历史: %v", err)
+		} else {
+			formatter.AddSection(output.PortfolioRiskSection(portfolioRisk(config, issues), history))
+		}
+	}
+	if coverage, err := scrapeCoverage(config, store, issues); err != nil {
+		log.Printf("⚠️  警告: 未能读取无匹配的仓库: %v", err)
+	} else if len(coverage.Empty) > 0 {
+		formatter.AddSection(output.CoverageSection(coverage))
+	}
+	if quality, err := classificationQuality(config, store); err != nil {
+		log.Printf("⚠️  警告: 未能评估分类质量: %v", err)
+	} else if quality.Total > 0 {
+		formatter.AddSection(output.ClassificationQualitySection(quality))
+	}
+	if links, err := store.LoadDuplicateLinks(); err != nil {
+		log.Printf("⚠️  警告: 未能读取重复问题关联: %v", err)
+	} else {
+		formatter.SetDuplicateLinks(links)
+	}
+	if err := formatter.FormatIssues(issues, config.Output.Format, config.Output.OutputDir); err != nil {
+		return fmt.Errorf("failed to format output: %w", err)
+	}
+	if config.Output.SplitByTeam {
+		if err := formatter.FormatTeams(issues, config.Output.Format, config.Output.OutputDir); err != nil {
+			return fmt.Errorf("failed to format team output: %w", err)
+		}
+	}
+	if config.Output.SplitByPortfolio {
+		if err := formatter.FormatPortfolios(issues, config.Output.Format, config.Output.OutputDir); err != nil {
+			return fmt.Errorf("failed to format portfolio output: %w", err)
+		}
+	}
+
+	files := formatter.Files()
+	integrityFiles, err := writeChecksums(config, filepath.Join(config.Output.OutputDir, output.ChecksumsFile), files)
+	if err != nil {
+		return err
+	}
+	files = append(files, integrityFiles...)
+	if err := output.RecordGenerated(config.Output.OutputDir, files); err != nil {
+		log.Printf("⚠️  警告: 未能记录生成的文件: %v", err)
+	}
+
+	if len(config.Export.Destinations) > 0 {
+		uploadReports(config, store, files)
+	}
+	if config.Output.Retention.Enabled() {
+		if _, err := housekeepOutput(config, files, false, false); err != nil {
+			log.Printf("⚠️  警告: 未清理输出目录: %v", err)
+		}
+	}
+	return nil
+}
+
+// writeChecksums writes a SHA-256 manifest for files and signs it when a
+// signing key is configured, returning the manifest and signature paths
+func writeChecksums(config scraper.Config, manifest string, files []string) ([]string, error) {
+	if err := output.WriteChecksums(manifest, files); err != nil {
+		return nil, fmt.Errorf("failed to write checksums: %w", err)
+	}
+	written := []string{manifest}
+
+	signature, err := output.SignManifest(context.Background(), config.Integrity, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign checksums: %w", err)
+	}
+	if signature != "" {
+		written = append(written, signature)
+	}
+	return written, nil
+}
+
+// uploadReports uploads written reports to the configured export
+// destinations and records where each one ended up
+func uploadReports(config scraper.Config, store *storage.Store, files []string) {
+	uploader, err := export.NewUploader(config.Export)
+	if err != nil {
+		log.Printf("⚠️  警告: 导出目标配置无效: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	log.Printf("📤 上传 %d 个输出文件到 %d 个导出目标...", len(files), len(config.Export.Destinations))
+	results := uploader.UploadAll(ctx, config.Output.OutputDir, files)
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+			log.Printf("⚠️  警告: 未能上传 %s 到 %s: %s", result.File, result.Destination, result.Error)
+		}
+	}
+	log.Printf("📤 上传完成: 成功 %d, 失败 %d", len(results)-failed, failed)
+
+	if err := store.AppendExportResults(results); err != nil {
+		log.Printf("⚠️  警告: 未能记录导出结果: %v", err)
+	}
+}
+
 // runDryRun simulates the scraping process
 func runDryRun(config scraper.Config) error {
 	log.Println("🔍 生成模拟数据...")
+	config = skipKnownEmpty(config, storage.NewStore(config.Storage.Dir))
 
 	// Generate sample issues for demonstration
 	sampleIssues := generateSampleIssues()
@@ -241,9 +1036,21 @@ func runDryRun(config scraper.Config) error {
 	// Generate output
 	log.Println("📝 生成模拟输出文件...")
 	formatter := output.NewFormatter()
+	formatter.SetLocation(config.Location())
+	formatter.AddSection(output.SLASection(checkSLA(config, filteredIssues)))
+	formatter.AddSection(output.AbandonedSection(filteredIssues))
+	formatter.AddSection(output.FixStatusSection(filteredIssues))
 	if err := formatter.FormatIssues(filteredIssues, config.Output.Format, config.Output.OutputDir); err != nil {
 		return fmt.Errorf("failed to format output: %w", err)
 	}
+	if config.Output.SplitByTeam {
+		if err := formatter.FormatTeams(filteredIssues, config.Output.Format, config.Output.OutputDir); err != nil {
+			return fmt.Errorf("failed to format team output: %w", err)
+		}
+	}
+	if err := output.RecordGenerated(config.Output.OutputDir, formatter.Files()); err != nil {
+		log.Printf("⚠️  警告: 未能记录生成的文件: %v", err)
+	}
 
 	log.Printf("🎉 模拟完成！示例结果保存在: %s", config.Output.OutputDir)
 	return nil
@@ -261,6 +1068,7 @@ func generateSampleIssues() []model.Issue {
 			UpdatedAt:   time.Now().AddDate(0, 0, -2),
 			Comments:    15,
 			Reactions:   8,
+			Author:      "gpu-whisperer",
 			Labels: []model.Label{
 				{Name: "bug", Color: "d73a4a"},
 				{Name: "performance", Color: "fbca04"},
@@ -275,6 +1083,7 @@ func generateSampleIssues() []model.Issue {
 			UpdatedAt:   time.Now().AddDate(0, 0, -1),
 			Comments:    23,
 			Reactions:   12,
+			Author:      "kernel-hunter",
 			Labels: []model.Label{
 				{Name: "critical", Color: "d73a4a"},
 				{Name: "cuda", Color: "1d76db"},
@@ -289,6 +1098,7 @@ func generateSampleIssues() []model.Issue {
 			UpdatedAt:   time.Now().AddDate(0, 0, -3),
 			Comments:    8,
 			Reactions:   5,
+			Author:      "gpu-whisperer",
 			Labels: []model.Label{
 				{Name: "bug", Color: "d73a4a"},
 				{Name: "distributed", Color: "0e8a16"},
@@ -336,13 +1146,28 @@ func createSummaryReport(config scraper.Config, allIssues, filteredIssues map[st
 
 总计: %d 个仓库, %d 个问题, %d 个高价值问题
 过滤率: %.2f%%
+覆盖: %d 个仓库没有匹配关键词和过滤条件的问题
 
 工具: gh-pitfall-scraper
 `, len(allIssues), getTotalIssues(allIssues), getTotalIssues(filteredIssues),
-		float64(getTotalIssues(filteredIssues))/float64(getTotalIssues(allIssues))*100)
+		float64(getTotalIssues(filteredIssues))/float64(getTotalIssues(allIssues))*100,
+		len(allIssues)-countMatchedRepos(filteredIssues))
 
-	_, err = file.WriteString(summary)
-	return err
+	if _, err := file.WriteString(summary); err != nil {
+		return err
+	}
+	return output.RecordGenerated(config.Output.OutputDir, []string{summaryPath})
+}
+
+// countMatchedRepos counts the repositories with at least one issue
+func countMatchedRepos(issues map[string][]model.Issue) int {
+	matched := 0
+	for _, repoIssues := range issues {
+		if len(repoIssues) > 0 {
+			matched++
+		}
+	}
+	return matched
 }
 
 // Helper functions
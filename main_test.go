@@ -1,12 +1,37 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/google/go-github/v67/github"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/analytics"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/client"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/jobs"
 	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/output"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/query"
 	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/server"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/similarity"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
 )
 
 func TestScorer(t *testing.T) {
@@ -179,4 +204,1382 @@ func TestCategorization(t *testing.T) {
 	if len(categories["distributed"]) == 0 {
 		t.Error("Expected distributed training issues to be categorized")
 	}
-}
\ No newline at end of file
+}
+func TestTeamAssignment(t *testing.T) {
+	assigner := scraper.NewTeamAssigner([]scraper.TeamConfig{
+		{Name: "inference", Categories: []string{"model_serving"}, Repositories: []string{"vllm-project/vllm"}},
+		{Name: "training", Categories: []string{"distributed"}},
+	})
+	
+	issues := []model.Issue{
+		{ID: 1, Repository: "vllm-project/vllm", Category: "distributed"},
+		{ID: 2, Repository: "microsoft/DeepSpeed", Category: "distributed"},
+		{ID: 3, Repository: "pytorch/pytorch", Category: "crashes"},
+	}
+	
+	assigner.AssignIssues(issues)
+	
+	// Repository mapping takes precedence over category mapping
+	if issues[0].AssignedTeam != "inference" {
+		t.Errorf("Expected repository mapping to win, got %q", issues[0].AssignedTeam)
+	}
+	if issues[1].AssignedTeam != "training" {
+		t.Errorf("Expected category mapping, got %q", issues[1].AssignedTeam)
+	}
+	if issues[2].AssignedTeam != "" {
+		t.Errorf("Expected no team for unmapped issue, got %q", issues[2].AssignedTeam)
+	}
+}
+
+func TestDetectWorkaround(t *testing.T) {
+	comment := func(body, association string) *github.IssueComment {
+		return &github.IssueComment{
+			Body:              github.String(body),
+			AuthorAssociation: github.String(association),
+			User:              &github.User{Login: github.String("someone")},
+		}
+	}
+	
+	comments := []*github.IssueComment{
+		comment("Same here, any update?", "NONE"),
+		comment("As a workaround, set `NCCL_P2P_DISABLE=1` before launching.", "MEMBER"),
+		comment("You can try a smaller batch size", "NONE"),
+	}
+	
+	workaround := scraper.DetectWorkaround(comments)
+	if workaround == nil {
+		t.Fatal("Expected a workaround to be detected")
+	}
+	if workaround.Body != *comments[1].Body {
+		t.Errorf("Expected the maintainer workaround to win, got %q", workaround.Body)
+	}
+	
+	if scraper.DetectWorkaround(comments[:1]) != nil {
+		t.Error("Expected no workaround in a plain status comment")
+	}
+}
+
+func TestSimilaritySearch(t *testing.T) {
+	corpus := map[string][]model.Issue{
+		"org/db": {
+			{Number: 1, Title: "Connection pool exhausted under heavy load", Body: "All connections in the pool are busy and requests time out"},
+			{Number: 2, Title: "Typo in README", Body: "The install section has a typo"},
+		},
+		"org/gpu": {
+			{Number: 3, Title: "CUDA OOM with large batch", Body: "GPU runs out of memory"},
+		},
+	}
+	
+	engine := similarity.NewEngine(corpus)
+	matches := engine.Search("connection pool exhausted under load", 5, 0.05)
+	
+	if len(matches) == 0 {
+		t.Fatal("Expected at least one similar issue")
+	}
+	if matches[0].Issue.Number != 1 {
+		t.Errorf("Expected issue #1 to rank first, got #%d", matches[0].Issue.Number)
+	}
+	for _, match := range matches {
+		if match.Issue.Number == 2 {
+			t.Errorf("Unrelated issue should not match, got similarity %.2f", match.Similarity)
+		}
+	}
+}
+
+func TestCrossSourceLinking(t *testing.T) {
+	corpus := map[string][]model.Issue{
+		"org/db": {
+			{Repository: "org/db", Number: 1, Title: "Connection pool exhausted under heavy load", Body: "All connections in the pool are busy and requests time out", Source: model.SourceGitHub},
+			{Repository: "org/db", Number: 2, Title: "Connection pool exhausted when load spikes", Body: "Pool connections busy, requests time out", Source: model.SourceGitHub},
+		},
+		"stackoverflow/gorm": {
+			{Repository: "stackoverflow/gorm", Number: 900, Title: "Connection pool exhausted under load", Body: "Requests time out because all pool connections are busy", Source: model.SourceStackOverflow},
+			{Repository: "stackoverflow/gorm", Number: 901, Title: "How to rename a column", Body: "Migration renames a column", Source: model.SourceStackOverflow},
+		},
+	}
+	
+	pairs := similarity.NewEngine(corpus).CrossSourcePairs(0.3)
+	var links []model.DuplicateLink
+	for _, pair := range pairs {
+		if pair.A.SourceName() == pair.B.SourceName() {
+			t.Errorf("Pair within one source: %s#%d and %s#%d", pair.A.Repository, pair.A.Number, pair.B.Repository, pair.B.Number)
+		}
+		links = append(links, model.DuplicateLink{
+			Repository: pair.A.Repository, IssueNumber: pair.A.Number,
+			CanonicalRepository: pair.B.Repository, CanonicalNumber: pair.B.Number,
+			Source: model.DuplicateSourceCrossSource,
+		})
+	}
+	
+	groups := model.DuplicateGroups(links, model.DuplicateSourceCrossSource)
+	if len(groups) != 1 {
+		t.Fatalf("Expected one cross-source group, got %v", groups)
+	}
+	for _, ref := range groups[0] {
+		if ref == "stackoverflow/gorm#901" {
+			t.Errorf("Unrelated question should not be grouped: %v", groups[0])
+		}
+	}
+	if !strings.Contains(strings.Join(groups[0], " "), "stackoverflow/gorm#900") {
+		t.Errorf("Expected the matching question in the group, got %v", groups[0])
+	}
+}
+
+func TestQueryLanguage(t *testing.T) {
+	search, err := query.Parse(`repo:golang/go category:performance score:>20 label:regression "memory leak" -label:question`)
+	if err != nil {
+		t.Fatalf("Failed to parse query: %v", err)
+	}
+	
+	issue := model.Issue{
+		Repository: "golang/go",
+		Category:   "performance",
+		Score:      35,
+		Title:      "Memory leak in net/http",
+		Labels:     []model.Label{{Name: "regression"}},
+	}
+	if !search.Match(issue) {
+		t.Error("Expected issue to match query")
+	}
+	
+	issue.Labels = append(issue.Labels, model.Label{Name: "question"})
+	if search.Match(issue) {
+		t.Error("Expected negated label to exclude issue")
+	}
+	
+	issue.Labels = issue.Labels[:1]
+	issue.Score = 10
+	if search.Match(issue) {
+		t.Error("Expected score condition to exclude issue")
+	}
+	
+	abandoned, err := query.Parse("is:abandoned")
+	if err != nil {
+		t.Fatalf("Failed to parse is:abandoned: %v", err)
+	}
+	if abandoned.Match(issue) {
+		t.Error("Expected is:abandoned to exclude an active issue")
+	}
+	issue.IsAbandoned = true
+	if !abandoned.Match(issue) {
+		t.Error("Expected is:abandoned to match an abandoned issue")
+	}
+	
+	if _, err := query.Parse("unknown:field"); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+	if _, err := query.Parse("is:stale"); err == nil {
+		t.Error("Expected error for unknown is: value")
+	}
+}
+
+func TestIssueFormParsing(t *testing.T) {
+	body := "### Version\n\n0.4.2\n\n### Operating system\n\nUbuntu 22.04\n\n### Steps to reproduce\n\n1. run server\n2. send request\n\n### Additional context\n\n_No response_"
+	
+	fields := scraper.ParseIssueForm(body)
+	if fields["version"] != "0.4.2" {
+		t.Errorf("Expected version 0.4.2, got %q", fields["version"])
+	}
+	if fields["operating_system"] != "Ubuntu 22.04" {
+		t.Errorf("Expected operating system Ubuntu 22.04, got %q", fields["operating_system"])
+	}
+	if _, ok := fields["additional_context"]; ok {
+		t.Error("Expected empty form field to be omitted")
+	}
+	if !scraper.HasReproSteps(fields) {
+		t.Error("Expected reproduction steps to be detected")
+	}
+	if scraper.ParseIssueForm("plain body without headings") != nil {
+		t.Error("Expected nil fields for a body without form headings")
+	}
+	
+	search, err := query.Parse("form.operating_system:ubuntu")
+	if err != nil {
+		t.Fatalf("Failed to parse query: %v", err)
+	}
+	if !search.Match(model.Issue{StructuredFields: fields}) {
+		t.Error("Expected form field query to match")
+	}
+	if search.Match(model.Issue{}) {
+		t.Error("Expected form field query not to match an issue without forms")
+	}
+}
+
+func TestDetectPlatforms(t *testing.T) {
+	platforms := scraper.DetectPlatforms("Segfault on Ubuntu 22.04 (aarch64), works fine on x86_64")
+	expected := []string{"amd64", "arm64", "linux"}
+	if strings.Join(platforms, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected platforms %v, got %v", expected, platforms)
+	}
+	
+	if platforms := scraper.DetectPlatforms("Slow tokenizer in the decoding loop"); len(platforms) != 0 {
+		t.Errorf("Expected no platforms, got %v", platforms)
+	}
+	
+	search, err := query.Parse("platform:linux platform:arm64")
+	if err != nil {
+		t.Fatalf("Failed to parse query: %v", err)
+	}
+	if search.Match(model.Issue{Platforms: []string{"linux"}}) {
+		t.Error("Expected repeated platform qualifiers to require all platforms")
+	}
+	if !search.Match(model.Issue{Platforms: []string{"arm64", "linux"}}) {
+		t.Error("Expected issue with both platforms to match")
+	}
+}
+
+func TestSeverityMapping(t *testing.T) {
+	mapper := scraper.NewSeverityMapper(scraper.SeverityConfig{
+		Labels: map[string]string{"p1": "high", "P0": "critical"},
+		Repositories: []scraper.RepoSeverityConfig{
+			{Name: "pytorch/pytorch", Labels: map[string]string{"high priority": "high", "p1": "medium"}},
+		},
+	})
+	
+	issue := model.Issue{Repository: "vllm-project/vllm", Labels: []model.Label{{Name: "P1"}, {Name: "p0"}}}
+	if priority := mapper.Priority(issue); priority != "critical" {
+		t.Errorf("Expected most severe priority critical, got %q", priority)
+	}
+	
+	issue = model.Issue{Repository: "pytorch/pytorch", Labels: []model.Label{{Name: "P1"}}}
+	if priority := mapper.Priority(issue); priority != "medium" {
+		t.Errorf("Expected repository mapping to override global mapping, got %q", priority)
+	}
+	
+	issue.Labels = []model.Label{{Name: "question"}}
+	if priority := mapper.Priority(issue); priority != "" {
+		t.Errorf("Expected no priority for unmapped labels, got %q", priority)
+	}
+	
+	invalid := scraper.SeverityConfig{Labels: map[string]string{"p0": "blocker"}}
+	if err := invalid.Validate(); err == nil {
+		t.Error("Expected error for unknown priority")
+	}
+}
+
+func TestSearchMatchModes(t *testing.T) {
+	issue := model.Issue{Title: "CUDA out of memory during prefill", Platforms: []string{"linux"}}
+	
+	cases := []struct {
+		query string
+		want  bool
+	}{
+		{"cuda leak", true},
+		{"match:all cuda leak", false},
+		{"match:all cuda memory", true},
+		{"match:phrase out of memory", true},
+		{"match:phrase memory out", false},
+		{"match:any platform:linux platform:arm64", true},
+	}
+	
+	for _, c := range cases {
+		search, err := query.Parse(c.query)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", c.query, err)
+		}
+		if got := search.Match(issue); got != c.want {
+			t.Errorf("Query %q: expected match %v, got %v", c.query, c.want, got)
+		}
+	}
+	
+	if _, err := query.Parse("match:some cuda"); err == nil {
+		t.Error("Expected error for unknown match mode")
+	}
+}
+
+func TestDetectWeaknesses(t *testing.T) {
+	cwes, owasp := scraper.DetectWeaknesses("Path traversal in the model loader (see CWE-502 for the pickle.load part)")
+	if strings.Join(cwes, ",") != "CWE-22,CWE-502" {
+		t.Errorf("Expected CWE-22 and CWE-502, got %v", cwes)
+	}
+	expected := []string{scraper.OWASPBrokenAccessControl, scraper.OWASPIntegrity}
+	if strings.Join(owasp, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected OWASP categories %v, got %v", expected, owasp)
+	}
+	
+	if cwes, _ := scraper.DetectWeaknesses("Slow tokenizer in the decoding loop"); len(cwes) != 0 {
+		t.Errorf("Expected no weaknesses, got %v", cwes)
+	}
+	
+	search, err := query.Parse("cwe:CWE-22 owasp:A01*")
+	if err != nil {
+		t.Fatalf("Failed to parse query: %v", err)
+	}
+	if !search.Match(model.Issue{CWEs: cwes, OWASP: owasp}) {
+		t.Error("Expected weakness qualifiers to match")
+	}
+}
+
+func TestStrictPrivacy(t *testing.T) {
+	// Loading a strict config restricts the shared transport; undo it for
+	// the tests that follow
+	restore := client.RestrictToGitHub()
+	defer restore()
+	
+	dir := t.TempDir()
+	writeConfig := func(extra string) string {
+		path := filepath.Join(dir, "config.yaml")
+		content := "privacy: strict\nrepositories:\n  - name: vllm-project/vllm\n" + extra
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		return path
+	}
+	
+	if _, err := loadConfig(writeConfig("notify:\n  webhooks: [\"https://hooks.example.com/x\"]\n")); err == nil {
+		t.Error("Expected strict privacy to reject chat webhooks")
+	}
+	if _, err := loadConfig(writeConfig("")); err != nil {
+		t.Fatalf("Failed to load strict config: %v", err)
+	}
+	
+	// Once a strict config is loaded, requests to other hosts never leave
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hits++ }))
+	defer server.Close()
+	
+	if _, err := http.Get(server.URL); err == nil || !strings.Contains(err.Error(), "privacy") {
+		t.Errorf("Expected privacy error for non-GitHub host, got %v", err)
+	}
+	if hits != 0 {
+		t.Errorf("Expected blocked request not to reach the server, got %d hits", hits)
+	}
+	
+	for host, expected := range map[string]bool{"api.github.com": true, "objects.githubusercontent.com": true, "github.com.example.com": false} {
+		if client.IsGitHubHost(host) != expected {
+			t.Errorf("IsGitHubHost(%q) = %v, expected %v", host, !expected, expected)
+		}
+	}
+}
+
+func TestIssueAPI(t *testing.T) {
+	issues := map[string][]model.Issue{"owner/repo": {
+		{ID: 101, Number: 1, Title: "memory leak", State: "open", Score: 30},
+		{ID: 102, Number: 2, Title: "crash on start", State: "closed", Score: 20},
+		{ID: 103, Number: 3, Title: "slow build", State: "open", Score: 10},
+	}}
+	srv := server.NewServer(server.Config{}, func() (map[string][]model.Issue, error) { return issues, nil })
+	
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		srv.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+	
+	page := get("/api/issues?limit=2&offset=1")
+	if page.Code != http.StatusOK || page.Header().Get("X-Total-Count") != "3" {
+		t.Fatalf("Expected 3 issues in total, got %d %q", page.Code, page.Header().Get("X-Total-Count"))
+	}
+	if link := page.Header().Get("Link"); !strings.Contains(link, `offset=0`) || !strings.Contains(link, `rel="prev"`) || strings.Contains(link, `rel="next"`) {
+		t.Errorf("Expected only a previous page link, got %q", link)
+	}
+	if !strings.Contains(page.Body.String(), "crash on start") || strings.Contains(page.Body.String(), "memory leak") {
+		t.Errorf("Expected the second and third issues by score, got %s", page.Body.String())
+	}
+	
+	if found := get("/api/issues/103"); found.Code != http.StatusOK || !strings.Contains(found.Body.String(), "slow build") {
+		t.Errorf("Expected issue 103, got %d %s", found.Code, found.Body.String())
+	}
+	if missing := get("/api/issues/999"); missing.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown issue, got %d", missing.Code)
+	}
+	if search := get("/api/search"); search.Code != http.StatusBadRequest {
+		t.Errorf("Expected search without q to be rejected, got %d", search.Code)
+	}
+	if stats := get("/api/stats"); !strings.Contains(stats.Body.String(), `"by_state":{"closed":1,"open":2}`) {
+		t.Errorf("Unexpected stats: %s", stats.Body.String())
+	}
+}
+
+func TestIssueAPIFormats(t *testing.T) {
+	issues := map[string][]model.Issue{"owner/repo": {
+		{ID: 101, Number: 1, Title: "memory leak", State: "open", Score: 30},
+		{ID: 102, Number: 2, Title: "crash, on start", State: "closed", Score: 20},
+	}}
+	srv := server.NewServer(server.Config{}, func() (map[string][]model.Issue, error) { return issues, nil })
+	
+	get := func(path, accept string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		request.Header.Set("Accept", accept)
+		recorder := httptest.NewRecorder()
+		srv.ServeHTTP(recorder, request)
+		return recorder
+	}
+	
+	csv := get("/api/issues?fields=number,title", "text/csv")
+	if csv.Header().Get("Content-Type") != "text/csv; charset=utf-8" || csv.Body.String() != "number,title\n1,memory leak\n2,\"crash, on start\"\n" {
+		t.Errorf("Expected CSV rows, got %q %q", csv.Header().Get("Content-Type"), csv.Body.String())
+	}
+	ndjson := get("/api/search?q=leak&format=ndjson", "application/json")
+	if ndjson.Header().Get("Content-Type") != "application/x-ndjson" || strings.Count(ndjson.Body.String(), "\n") != 1 || !strings.Contains(ndjson.Body.String(), `"title":"memory leak"`) {
+		t.Errorf("Expected the format parameter to win with one NDJSON line, got %q", ndjson.Body.String())
+	}
+	if page := get("/api/issues?limit=1", "application/x-ndjson"); page.Header().Get("X-Total-Count") != "2" || strings.Count(page.Body.String(), "\n") != 1 {
+		t.Errorf("Expected paging to apply to NDJSON, got %q", page.Body.String())
+	}
+	if json := get("/api/issues", "text/html, */*;q=0.8"); !strings.HasPrefix(json.Body.String(), `{"total":2`) {
+		t.Errorf("Expected JSON by default, got %q", json.Body.String())
+	}
+	if bad := get("/api/issues?format=xml", ""); bad.Code != http.StatusNotAcceptable {
+		t.Errorf("Expected 406 for an unknown format, got %d", bad.Code)
+	}
+}
+
+func TestLiveEvents(t *testing.T) {
+	store := storage.NewStore(t.TempDir())
+	srv := server.NewServer(server.Config{}, store.LoadIssues)
+	srv.SetEvents()
+	httpServer := httptest.NewServer(srv)
+	defer httpServer.Close()
+	
+	// The deadline ends the stream should an expected event never come
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, httpServer.URL+"/api/events", nil)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if response.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %q", response.Header.Get("Content-Type"))
+	}
+	lines := bufio.NewScanner(response.Body)
+	next := func() string {
+		var event []string
+		for lines.Scan() && lines.Text() != "" {
+			event = append(event, lines.Text())
+		}
+		return strings.Join(event, "\n")
+	}
+	if connected := next(); connected != ": connected" {
+		t.Fatalf("Expected the stream to open with a comment, got %q", connected)
+	}
+	
+	go watchStoreEvents(ctx, store, 10*time.Millisecond, srv.Publish)
+	time.Sleep(30 * time.Millisecond)
+	for _, c := range []struct {
+		change func() error
+		event  string
+	}{
+		{func() error {
+			return store.SaveIssues(map[string][]model.Issue{"owner/repo": {{Number: 1, Title: "leak"}, {Number: 2, Title: "leak again"}}})
+		}, `event: ingested` + "\n" + `data: {"issues":2,"new":["owner/repo#1","owner/repo#2"],"updated":[]}`},
+		{func() error {
+			return store.AddDuplicateLinks([]model.DuplicateLink{{Repository: "owner/repo", IssueNumber: 2, CanonicalRepository: "owner/repo", CanonicalNumber: 1, Source: "manual"}})
+		}, `event: deduplicated` + "\n" + `data: {"links":[{"canonical":"owner/repo#1","duplicate":"owner/repo#2","source":"manual"}],"total":1}`},
+	} {
+		if err := c.change(); err != nil {
+			t.Fatal(err)
+		}
+		if event := next(); !strings.HasSuffix(event, c.event) {
+			t.Errorf("Expected\n%s\ngot\n%s", c.event, event)
+		}
+	}
+}
+
+func TestWebUIEndpoints(t *testing.T) {
+	store := storage.NewStore(t.TempDir())
+	if err := store.SaveIssues(map[string][]model.Issue{"owner/repo": {
+		{ID: 101, Number: 1, Repository: "owner/repo", Title: "OOM on load", State: "open", Category: "other", Score: 30},
+		{ID: 102, Number: 2, Repository: "owner/repo", Title: "out of memory while loading", State: "open", Category: "other", Score: 20},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	suggested := model.DuplicateLink{Repository: "owner/repo", IssueNumber: 2, CanonicalRepository: "owner/repo", CanonicalNumber: 1, Source: model.DuplicateSourceCrossSource, LinkedAt: time.Now()}
+	if err := store.AddDuplicateLinks([]model.DuplicateLink{suggested}); err != nil {
+		t.Fatal(err)
+	}
+	reports := t.TempDir()
+	for _, name := range []string{"summary.md", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(reports, name), []byte("# "+name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := output.RecordGenerated(reports, []string{filepath.Join(reports, "summary.md")}); err != nil {
+		t.Fatal(err)
+	}
+	
+	srv := server.NewServer(server.Config{}, store.LoadIssues)
+	srv.SetTriage(scraper.Categories(), scraper.Priorities, func(issue model.Issue, category, priority, author string) (model.Issue, error) {
+		return triageIssue(scraper.Config{}, store, issue, category, priority, author)
+	})
+	srv.SetDuplicateReview(store.LoadDuplicateLinks, store.LoadDuplicateReviews, store.ReviewDuplicate)
+	srv.SetReports(reports)
+	srv.SetUI()
+	
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		srv.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return recorder
+	}
+	
+	if page := do(http.MethodGet, "/ui/", ""); page.Code != http.StatusOK || !strings.Contains(page.Body.String(), `<script src="app.js">`) {
+		t.Errorf("Expected the embedded UI, got %d", page.Code)
+	}
+	if page := do(http.MethodGet, "/api/issues?facets=category,state&fields=number", ""); !strings.Contains(page.Body.String(), `"facets":{"category":{"other":2},"state":{"open":2}}`) {
+		t.Errorf("Expected facet counts, got %s", page.Body.String())
+	}
+	
+	// Triage records a correction like issues set
+	category := scraper.Categories()[0]
+	if bad := do(http.MethodPost, "/api/issues/101/triage", `{"category":"nonsense"}`); bad.Code != http.StatusBadRequest {
+		t.Errorf("Expected an unknown category to be rejected, got %d", bad.Code)
+	}
+	if triaged := do(http.MethodPost, "/api/issues/101/triage", `{"category":"`+category+`","priority":"high","author":"alice"}`); triaged.Code != http.StatusOK {
+		t.Fatalf("Expected triage to succeed, got %d %s", triaged.Code, triaged.Body.String())
+	}
+	stored, _ := store.LoadIssues()
+	corrections, _ := store.LoadCorrections()
+	if stored["owner/repo"][0].Category != category || stored["owner/repo"][0].Priority != "high" || len(corrections) != 1 || corrections[0].Author != "alice" {
+		t.Errorf("Expected the stored issue corrected, got %+v and %+v", stored["owner/repo"][0], corrections)
+	}
+	
+	// A rejected suggestion leaves the queue and is not linked again
+	if pending := do(http.MethodGet, "/api/duplicates", ""); !strings.Contains(pending.Body.String(), `"total":1`) || !strings.Contains(pending.Body.String(), "out of memory while loading") {
+		t.Errorf("Expected one pending suggestion, got %s", pending.Body.String())
+	}
+	if reviewed := do(http.MethodPost, "/api/duplicates/review", `{"duplicate":"owner/repo#1","canonical":"owner/repo#2","verdict":"rejected","reviewer":"alice"}`); reviewed.Code != http.StatusOK {
+		t.Fatalf("Expected the review to be recorded, got %d %s", reviewed.Code, reviewed.Body.String())
+	}
+	if pending := do(http.MethodGet, "/api/duplicates?status=pending", ""); !strings.Contains(pending.Body.String(), `"total":0`) {
+		t.Errorf("Expected an empty queue, got %s", pending.Body.String())
+	}
+	if rejected := do(http.MethodGet, "/api/duplicates?status=rejected", ""); !strings.Contains(rejected.Body.String(), `"reviewer":"alice"`) {
+		t.Errorf("Expected the rejected pair listed, got %s", rejected.Body.String())
+	}
+	if err := store.AddDuplicateLinks([]model.DuplicateLink{suggested}); err != nil {
+		t.Fatal(err)
+	}
+	if links, _ := store.LoadDuplicateLinks(); len(links) != 0 {
+		t.Errorf("Expected the rejected pair not to be linked again, got %+v", links)
+	}
+	
+	// Only generated reports are listed and served
+	if list := do(http.MethodGet, "/api/reports", ""); !strings.Contains(list.Body.String(), `"name":"summary.md"`) || strings.Contains(list.Body.String(), "notes.txt") {
+		t.Errorf("Expected only the generated report listed, got %s", list.Body.String())
+	}
+	if report := do(http.MethodGet, "/reports/summary.md", ""); report.Code != http.StatusOK || report.Body.String() != "# summary.md" {
+		t.Errorf("Expected the report served, got %d %q", report.Code, report.Body.String())
+	}
+	if other := do(http.MethodGet, "/reports/notes.txt", ""); other.Code != http.StatusNotFound {
+		t.Errorf("Expected files the tool did not generate to stay private, got %d", other.Code)
+	}
+}
+
+func TestAPIQueryLimits(t *testing.T) {
+	issues := map[string][]model.Issue{"owner/repo": {
+		{ID: 101, Number: 1, Title: "memory leak", State: "open", Score: 30},
+		{ID: 102, Number: 2, Title: "crash on start", State: "open", Score: 20},
+	}}
+	var slow atomic.Bool
+	load := func() (map[string][]model.Issue, error) {
+		if slow.Load() {
+			time.Sleep(200 * time.Millisecond)
+		}
+		return issues, nil
+	}
+	srv := server.NewServer(server.Config{MaxPageSize: 10, QueryTimeoutSeconds: 0.05, MaxResultBytes: 400}, load)
+	
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		srv.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+	
+	if page := get("/api/issues?fields=number,title"); page.Code != http.StatusOK || page.Header().Get("X-Total-Count") != "2" {
+		t.Errorf("Expected a small query to pass the limits, got %d %s", page.Code, page.Body.String())
+	}
+	if page := get("/api/issues?limit=11"); page.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for a page above max_page_size, got %d", page.Code)
+	}
+	if page := get("/api/issues"); page.Code != http.StatusUnprocessableEntity || strings.Contains(page.Body.String(), "memory leak") {
+		t.Errorf("Expected 422 for a result above max_result_bytes, got %d %s", page.Code, page.Body.String())
+	}
+	slow.Store(true)
+	if page := get("/api/stats"); page.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected 504 for a query past the timeout, got %d", page.Code)
+	}
+}
+
+func TestEscalations(t *testing.T) {
+	config := scraper.EscalationConfig{ReactionSpike: 10, ReactionRatio: 2}
+	stored := []model.Issue{
+		{Number: 1, Priority: "medium", State: "open", Reactions: 5},
+		{Number: 2, State: "closed", Reactions: 30},
+		{Number: 3, Priority: "high", State: "open", Reactions: 0},
+	}
+	current := []model.Issue{
+		{Number: 1, Priority: "critical", State: "open", Reactions: 12},
+		{Number: 2, State: "open", Reactions: 45},
+		{Number: 3, Priority: "low", State: "open", Reactions: 10},
+		{Number: 4, Priority: "critical", State: "open", Reactions: 100},
+	}
+	
+	kinds := make(map[int][]string)
+	for _, escalation := range config.Escalations(stored, current, time.Now()) {
+		kinds[escalation.IssueNumber] = append(kinds[escalation.IssueNumber], escalation.Kind)
+	}
+	expected := map[int][]string{
+		1: {model.EscalationPriority},
+		2: {model.EscalationReopened},
+		3: {model.EscalationReactions},
+	}
+	if fmt.Sprint(kinds) != fmt.Sprint(expected) {
+		t.Errorf("Expected escalations %v, got %v", expected, kinds)
+	}
+}
+
+func TestLinkedIssues(t *testing.T) {
+	body := "Fixes #12, closes other/repo#7 and resolves https://github.com/third/repo/issues/3.\nSee #99. Fixes #12 again."
+	linked := scraper.LinkedIssues(body, "owner/repo")
+	expected := []string{"owner/repo#12", "other/repo#7", "third/repo#3"}
+	if fmt.Sprint(linked) != fmt.Sprint(expected) {
+		t.Errorf("Expected linked issues %v, got %v", expected, linked)
+	}
+}
+
+func TestPlainTextSearch(t *testing.T) {
+	issue := model.Issue{
+		Title: "Crash",
+		Body:  "<!-- Please fill in -->\n<table><tr><td>Version</td><td>0.4&nbsp;&amp;&nbsp;CUDA</td></tr></table>\n<img src=\"x.png\"> ![stack trace](y.png)\nSegfault on `load()`",
+	}
+	issue.SetPlainText()
+	if issue.PlainText != "Version 0.4 & CUDA\n\nstack trace\nSegfault on `load()`" {
+		t.Errorf("Unexpected plain text: %q", issue.PlainText)
+	}
+	
+	search, err := query.Parse(`"0.4 & cuda" -fill`)
+	if err != nil {
+		t.Fatalf("Failed to parse query: %v", err)
+	}
+	if !search.Match(issue) {
+		t.Error("Expected search to match the plain text instead of the HTML")
+	}
+	
+	plain := model.Issue{Body: "Plain  markdown body"}
+	plain.SetPlainText()
+	if plain.PlainText != "" || plain.SearchText() != plain.Body {
+		t.Errorf("Expected plain bodies not to be stored twice, got %q", plain.PlainText)
+	}
+}
+
+func TestDiscussionClassification(t *testing.T) {
+	filter := scraper.NewFilter(scraper.FilterConfig{})
+	issue := model.Issue{Title: "Training hangs after a few steps", Body: "No output at all"}
+	if category := filter.Categorize(issue); category != "other" {
+		t.Fatalf("Expected other without comments, got %s", category)
+	}
+	
+	issue.Discussion = []model.Comment{{Body: "Same here, py-spy shows all ranks stuck in NCCL allreduce"}}
+	if category := filter.Categorize(issue); category != "distributed" {
+		t.Errorf("Expected comments to classify the issue as distributed, got %s", category)
+	}
+}
+
+func TestQuestionDeboost(t *testing.T) {
+	questions := []model.Issue{
+		{Title: "How to run vLLM on two GPUs?"},
+		{Title: "Does the scheduler support priorities?", Body: "Any help would be appreciated"},
+		{Title: "Crash on startup", Labels: []model.Label{{Name: "question"}}},
+	}
+	for _, issue := range questions {
+		if !scraper.IsQuestion(issue) {
+			t.Errorf("Expected %q to be a question", issue.Title)
+		}
+	}
+	
+	defects := []model.Issue{
+		{Title: "How to avoid OOM? It crashes", Body: "Traceback (most recent call last):"},
+		{Title: "How to run on ROCm?", Labels: []model.Label{{Name: "bug"}}},
+		{Title: "Is this expected?"},
+	}
+	for _, issue := range defects {
+		if scraper.IsQuestion(issue) {
+			t.Errorf("Expected %q not to be a question", issue.Title)
+		}
+	}
+	
+	scorer := scraper.NewScorer()
+	issue := model.Issue{Title: "Memory leak", Body: "memory leak in the allocator", State: "open", Comments: 10}
+	full, _ := scorer.ScoreIssue(&issue)
+	issue.Type = model.IssueTypeQuestion
+	scorer.SetQuestionDeboost(0.5)
+	deboosted, reasons := scorer.ScoreIssue(&issue)
+	if full == 0 || deboosted != full/2 {
+		t.Errorf("Expected the question to keep half of %.1f, got %.1f (%v)", full, deboosted, reasons)
+	}
+}
+
+func TestPortfolios(t *testing.T) {
+	portfolios := scraper.Portfolios{
+		{Name: "serving", Repositories: []string{"vllm-project/vllm", "sgl-project/*"}, Searches: []scraper.SavedSearch{{Name: "open", Query: "state:open"}}},
+		{Name: "training", Repositories: []string{"pytorch/pytorch"}},
+	}
+	issues := map[string][]model.Issue{
+		"vllm-project/vllm":  {{Number: 1, State: "open", Priority: "high", Category: "gpu_memory", Score: 40}},
+		"SGL-Project/sglang": {{Number: 2, State: "closed", Category: "gpu_memory", Score: 20}},
+		"pytorch/pytorch":    {{Number: 3, State: "open", Category: "distributed"}},
+	}
+	portfolios.MarkCorpus(issues)
+	
+	rollup := analytics.PortfolioRollup(issues, portfolios.Names())
+	serving := rollup[0]
+	if serving.Repositories != 2 || serving.Issues != 2 || serving.Open != 1 || serving.HighPriority != 1 || serving.AverageScore != 30 || serving.TopCategory != "gpu_memory" {
+		t.Errorf("Unexpected serving rollup: %+v", serving)
+	}
+	
+	alerts := portfolios.Alerts()
+	if len(alerts) != 1 || alerts[0].Name != "serving/open" {
+		t.Fatalf("Expected one portfolio alert, got %+v", alerts)
+	}
+	search, err := query.Parse(alerts[0].Query)
+	if err != nil {
+		t.Fatalf("Failed to parse portfolio query: %v", err)
+	}
+	results := search.Filter(issues)
+	if len(results) != 1 || results[0].Number != 1 {
+		t.Errorf("Expected the saved search to match only vllm-project/vllm#1, got %v", results)
+	}
+}
+
+// cannedTransport answers requests with prepared responses in order
+type cannedTransport struct {
+	responses []*http.Response
+	requests  int
+}
+
+func (t *cannedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp := t.responses[min(t.requests, len(t.responses)-1)]
+	t.requests++
+	resp.Request = req
+	return resp, nil
+}
+
+func TestGitHubRateLimitRetry(t *testing.T) {
+	response := func(status int, headers map[string]string, body string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body))}
+		for name, value := range headers {
+			resp.Header.Set(name, value)
+		}
+		return resp
+	}
+	transport := &cannedTransport{responses: []*http.Response{
+		response(http.StatusTooManyRequests, map[string]string{"Retry-After": "0"}, `{"message":"slow down"}`),
+		response(http.StatusOK, map[string]string{"X-RateLimit-Remaining": "4000"}, `[{"number":7,"title":"Leak"}]`),
+	}}
+	
+	politeness := client.PolitenessConfig{MaxBackoffSeconds: 1, MaxRetries: 2}
+	githubClient := client.NewGitHubClient("", client.AttributionConfig{}, politeness, transport)
+	issues, err := githubClient.GetIssues(context.Background(), "owner", "repo", "all", 10)
+	if err != nil {
+		t.Fatalf("Expected the rate-limited request to be retried, got %v", err)
+	}
+	if len(issues) != 1 || transport.requests != 2 || githubClient.Requests() != 1 {
+		t.Errorf("Expected 1 issue after 2 attempts of 1 request, got %d issues, %d attempts, %d requests", len(issues), transport.requests, githubClient.Requests())
+	}
+}
+
+func TestPortfolioRisk(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	issues := map[string][]model.Issue{
+		"vllm-project/vllm": {
+			{Number: 1, State: "open", Priority: "critical", CreatedAt: now.AddDate(0, 0, -3), Portfolios: []string{"serving"}, RepoRisks: []string{model.RepoRiskArchived}},
+			{Number: 2, State: "open", Priority: "high", CreatedAt: now.AddDate(0, 0, -10), Portfolios: []string{"serving"}, IsAbandoned: true},
+		},
+		"pytorch/pytorch": {
+			{Number: 3, State: "closed", Priority: "high", CreatedAt: now.AddDate(0, 0, -45), Portfolios: []string{"training"}},
+			{Number: 4, State: "open", Priority: "low", CreatedAt: now.AddDate(0, 0, -5), Portfolios: []string{"training"}},
+		},
+	}
+	weights := analytics.RiskWeights{Unresolved: 0.5, Health: 0.3, Trend: 0.2}
+	
+	risks := analytics.PortfolioRiskScores(issues, []string{"serving", "training"}, weights, 30*24*time.Hour, now)
+	serving, training := risks[0], risks[1]
+	if serving.Unresolved != 2 || serving.Archived != 1 || serving.Unresponsive != 0.5 || serving.Trend != analytics.TrendRising {
+		t.Errorf("Unexpected serving risk: %+v", serving)
+	}
+	if training.Unresolved != 0 || training.Trend != analytics.TrendFalling {
+		t.Errorf("Unexpected training risk: %+v", training)
+	}
+	if serving.Score <= training.Score || serving.Score > 100 || training.Score < 0 {
+		t.Errorf("Expected serving (%.1f) to be riskier than training (%.1f)", serving.Score, training.Score)
+	}
+}
+
+func TestColumnMapping(t *testing.T) {
+	records := []map[string]interface{}{
+		{"id": "7", "Headline": "OOM", "Opened": "05/01/2024", "Tags": "bug, memory", "status": "OPEN", "score": "42.5"},
+	}
+	mapping := output.ColumnMapping{
+		Fields: []output.FieldMapping{
+			{Source: "id", Target: "number"},
+			{Source: "Headline", Target: "title"},
+			{Source: "Opened", Target: "created_at", Transform: output.TransformDate, Layout: "01/02/2006"},
+			{Source: "Tags", Target: "labels", Transform: output.TransformSplit},
+			{Source: "status", Target: "state", Transform: output.TransformLower},
+		},
+		Defaults: map[string]string{"repository": "acme/infer"},
+	}
+	
+	issues, err := output.ApplyMapping(records, mapping)
+	if err != nil {
+		t.Fatalf("Failed to apply mapping: %v", err)
+	}
+	issue := issues[0]
+	if issue.Number != 7 || issue.ID != 0 || issue.Title != "OOM" || issue.State != "open" || issue.Score != 42.5 || issue.Repository != "acme/infer" {
+		t.Errorf("Unexpected mapped issue: %+v", issue)
+	}
+	if !issue.CreatedAt.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) || len(issue.Labels) != 2 || issue.Labels[1].Name != "memory" {
+		t.Errorf("Unexpected date or labels: %v %v", issue.CreatedAt, issue.Labels)
+	}
+	
+	mapping.Fields[1].Source = "Title"
+	if _, err := output.ApplyMapping(records, mapping); err == nil {
+		t.Error("Expected a missing source column to be rejected")
+	}
+	if err := (output.ColumnMapping{Fields: []output.FieldMapping{{Source: "a", Target: "nope"}}}).Validate(); err == nil {
+		t.Error("Expected an unknown target field to be rejected")
+	}
+}
+
+func TestDuplicatePruneConfirmation(t *testing.T) {
+	issues := map[string][]model.Issue{
+		"acme/infer": {{Number: 1, Score: 80}, {Number: 2, Score: 30}, {Number: 3, Score: 20}, {Number: 4, Score: 90}},
+	}
+	var links []model.DuplicateLink
+	for _, number := range []int{2, 3, 4} {
+		links = append(links, model.DuplicateLink{Repository: "acme/infer", IssueNumber: number, CanonicalRepository: "acme/infer", CanonicalNumber: 1, Source: model.DuplicateSourceUpstream})
+	}
+	dedup := scraper.DedupConfig{Policy: scraper.DuplicatePolicyDeleteLowScore}
+	
+	duplicates := scraper.DuplicatesToPrune(issues, links, dedup, time.Now())
+	if len(duplicates) != 2 || duplicates[0].Number != 2 || duplicates[1].Number != 3 {
+		t.Fatalf("Expected #2 and #3 to be pruned, got %+v", duplicates)
+	}
+	
+	config := scraper.Config{Maintenance: scraper.MaintenanceConfig{ConfirmThreshold: 1}}
+	if err := checkConfirmed(config, len(duplicates), false); err == nil {
+		t.Error("Expected deleting more than the threshold to need confirmation")
+	}
+	if err := checkConfirmed(config, len(duplicates), true); err != nil {
+		t.Errorf("Expected a confirmed deletion to pass, got %v", err)
+	}
+	if removed := scraper.RemoveIssues(issues, duplicates); removed != 2 || len(issues["acme/infer"]) != 2 {
+		t.Errorf("Expected 2 issues removed and 2 kept, got %d removed and %v", removed, issues["acme/infer"])
+	}
+}
+
+func TestBM25Ranking(t *testing.T) {
+	issues := map[string][]model.Issue{
+		"acme/infer": {
+			{Number: 1, Title: "Memory leak in scheduler", Body: "leak grows over time"},
+			{Number: 2, Title: "Crash on startup", Body: "cuda error after upgrade"},
+			{Number: 3, Title: "Leak in tokenizer", Body: "small leak"},
+			{Number: 4, Title: "Leak in cache", Body: "another leak"},
+		},
+	}
+	search, err := query.Parse("leak cuda")
+	if err != nil {
+		t.Fatalf("Failed to parse query: %v", err)
+	}
+	results := search.Filter(issues)
+	
+	// The rare term outweighs the common one
+	query.NewIndex(issues).Sort(search, results, query.SortRelevance, query.RelevanceWeights{Text: 1}, time.Now())
+	if len(results) != 4 || results[0].Number != 2 {
+		t.Errorf("Expected the issue matching the rare term first, got %v", results)
+	}
+	
+	// Comments are searched once attached
+	issues["acme/infer"][1].Discussion = []model.Comment{{Body: "Workaround: pin the tokenizer version"}}
+	search, _ = query.Parse("workaround")
+	if results := search.Filter(issues); len(results) != 1 || results[0].Number != 2 {
+		t.Errorf("Expected the comment to match, got %v", results)
+	}
+}
+
+func TestCronSchedule(t *testing.T) {
+	cron, err := jobs.ParseCron("0 */6 * * *")
+	if err != nil {
+		t.Fatalf("Failed to parse schedule: %v", err)
+	}
+	at := time.Date(2024, 5, 1, 7, 30, 0, 0, time.UTC)
+	if next := cron.Next(at); !next.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the next run at 12:00, got %v", next)
+	}
+	
+	// Restricted day of month and weekday match either, as in cron
+	cron, _ = jobs.ParseCron("30 9 15 * 1")
+	if next := cron.Next(at); !next.Equal(time.Date(2024, 5, 6, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected the next run on Monday May 6th, got %v", next)
+	}
+	
+	// 7 is Sunday too, alone or ending a range
+	for _, spec := range []string{"0 0 * * 7", "0 0 * * 6-7"} {
+		cron, err = jobs.ParseCron(spec)
+		if err != nil || !cron.Matches(time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected %q to fire on Sunday May 5th (%v)", spec, err)
+		}
+	}
+	for _, spec := range []string{"* * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *"} {
+		if _, err := jobs.ParseCron(spec); err == nil {
+			t.Errorf("Expected %q to be rejected", spec)
+		}
+	}
+	
+	// Repositories due at the same minute are scraped together
+	config := scraper.Config{
+		Repositories: []scraper.RepositoryConfig{
+			{Name: "acme/infer", Enabled: true},
+			{Name: "acme/train", Enabled: true, Schedule: "@hourly"},
+		},
+		Daemon: scraper.DaemonConfig{Schedule: "0 */6 * * *", Tasks: map[string]string{"housekeep": "0 3 * * *"}},
+	}
+	scheduled, err := daemonJobs(config)
+	if err != nil || len(scheduled) != 3 {
+		t.Fatalf("Expected 3 jobs, got %v (%v)", scheduled, err)
+	}
+	if due, next := nextDaemonRun(scheduled, at); len(due) != 1 || next.Hour() != 8 {
+		t.Errorf("Expected the hourly scrape alone at 08:00, got %v at %v", due, next)
+	}
+	if due, _ := nextDaemonRun(scheduled, time.Date(2024, 5, 1, 11, 30, 0, 0, time.UTC)); len(due) != 2 {
+		t.Errorf("Expected both scrapes at 12:00, got %v", due)
+	}
+	config.Daemon.Tasks["vacuum"] = "@daily"
+	if err := validateDaemon(config); err == nil {
+		t.Error("Expected an unknown task to be rejected")
+	}
+}
+
+func TestTombstones(t *testing.T) {
+	store := storage.NewStore(t.TempDir())
+	base := output.Export{GeneratedAt: time.Now().Add(-time.Hour)}
+	corpus := map[string][]model.Issue{
+		"acme/infer": {
+			{ID: 11, Number: 1, Repository: "acme/infer"},
+			{ID: 12, Number: 2, Repository: "acme/infer"},
+		},
+	}
+	removed, err := deleteIssues(store, corpus, []model.Issue{corpus["acme/infer"][1]}, model.TombstoneDuplicate)
+	if err != nil || removed != 1 {
+		t.Fatalf("Expected 1 issue deleted, got %d (%v)", removed, err)
+	}
+	tombstones, err := store.LoadTombstones()
+	if err != nil || len(tombstones) != 1 || tombstones[0].ID != 12 || tombstones[0].Reason != model.TombstoneDuplicate {
+		t.Fatalf("Expected a tombstone for the deleted issue, got %v (%v)", tombstones, err)
+	}
+	
+	// Deltas carry deletions since the base, except of issues stored again
+	if since := model.TombstonesSince(tombstones, base.GeneratedAt, map[string]bool{"acme/infer#2": true}); len(since) != 0 {
+		t.Errorf("Expected no tombstone for a re-stored issue, got %v", since)
+	}
+	delta, manifest := output.DiffExport(base, output.NewExport(corpus, time.Now()), model.TombstonesSince(tombstones, base.GeneratedAt, nil))
+	if len(delta.Tombstones) != 1 || len(manifest.Deleted) != 1 || manifest.Deleted[0] != "acme/infer#2" {
+		t.Errorf("Expected the deletion in the delta, got %v and %v", delta.Tombstones, manifest.Deleted)
+	}
+	
+	// Older schemas do not know tombstones
+	converted, err := output.ConvertExport(delta, 7)
+	if err != nil {
+		t.Fatalf("Failed to convert delta: %v", err)
+	}
+	if _, ok := converted["tombstones"]; ok {
+		t.Error("Expected schema 7 deltas without tombstones")
+	}
+}
+
+func TestStorageMaintenance(t *testing.T) {
+	dir := t.TempDir()
+	store := storage.NewStore(dir)
+	if err := store.AppendTombstones([]model.Tombstone{{Repository: "acme/infer", Number: 1}}); err != nil {
+		t.Fatalf("Failed to save tombstones: %v", err)
+	}
+	stale := filepath.Join(dir, "issues.json.tmp")
+	if err := os.WriteFile(stale, []byte("{"), 0644); err != nil {
+		t.Fatalf("Failed to write temporary file: %v", err)
+	}
+	os.Chtimes(stale, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour))
+	os.WriteFile(filepath.Join(dir, "escalations.json.tmp"), []byte("["), 0644)
+	
+	// Only temporary files old enough to be left by interrupted saves go
+	usage, err := maintainStorage(context.Background(), scraper.Config{}, store)
+	if err != nil {
+		t.Fatalf("Failed to maintain storage: %v", err)
+	}
+	if len(usage.Files) != 1 || len(usage.Stale) != 1 {
+		t.Errorf("Expected 1 data file and 1 stale temporary file, got %v", usage)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("Expected the stale temporary file to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escalations.json.tmp")); err != nil {
+		t.Error("Expected a recent temporary file to be kept")
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	var received []server.WebhookEvent
+	handler := server.WebhookHandler("s3cret", func(event server.WebhookEvent) { received = append(received, event) })
+	deliver := func(event, body, secret string) int {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-GitHub-Event", event)
+		req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	
+	issue := `{"action":"opened","issue":{"number":7},"repository":{"full_name":"acme/infer"}}`
+	if code := deliver("issues", issue, "wrong"); code != http.StatusUnauthorized || len(received) != 0 {
+		t.Errorf("Expected a bad signature to be rejected, got %d", code)
+	}
+	if code := deliver("issues", issue, "s3cret"); code != http.StatusAccepted || len(received) != 1 || received[0].Repository != "acme/infer" || received[0].Number != 7 {
+		t.Errorf("Expected the issue event to be ingested, got %d and %v", code, received)
+	}
+	
+	// Comments on pull requests and other events are acknowledged only
+	deliver("issue_comment", `{"action":"created","issue":{"number":8,"pull_request":{"url":"x"}},"repository":{"full_name":"acme/infer"}}`, "s3cret")
+	deliver("push", `{}`, "s3cret")
+	if len(received) != 1 {
+		t.Errorf("Expected pull request comments and pushes to be skipped, got %v", received)
+	}
+}
+
+func TestPoolMetrics(t *testing.T) {
+	// One slot, with one of two requests waiting a second for it
+	stats := map[string]client.PoolStats{
+		"api.github.com": {Slots: 1, Requests: 2, Waits: 1, WaitTime: time.Second, MaxWait: time.Second},
+	}
+	store := storage.NewStore(t.TempDir())
+	config := scraper.Config{PoolAlerts: scraper.PoolAlertConfig{MaxWaits: 0, MaxWaitSeconds: 0.5}}
+	recordPoolMetrics(context.Background(), config, store, stats)
+	samples, err := store.LoadPoolSamples()
+	if err != nil || len(samples) != 1 || samples[0].Host != "api.github.com" || !config.PoolAlerts.Exceeded(samples[0]) {
+		t.Fatalf("Expected a sample over the wait threshold, got %v (%v)", samples, err)
+	}
+	if (scraper.PoolAlertConfig{MaxWaits: 2}).Exceeded(samples[0]) {
+		t.Errorf("Expected 1 wait to stay under a threshold of 2")
+	}
+	
+	srv := server.NewServer(server.Config{}, func() (map[string][]model.Issue, error) { return nil, nil })
+	srv.SetPoolMetrics(store.LoadPoolSamples)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rec.Body.String(), `pitfall_api_pool_waits{host="api.github.com"} 1`) {
+		t.Errorf("Expected the waits gauge in the metrics, got %s", rec.Body.String())
+	}
+}
+
+func TestMalformedIssuesKept(t *testing.T) {
+	dir := t.TempDir()
+	data := `{"acme/infer": [{"number": 1, "title": "OOM"}, {"number": "two"}]}`
+	if err := os.WriteFile(filepath.Join(dir, "issues.json"), []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write issues: %v", err)
+	}
+	store := storage.NewStore(dir)
+	
+	// The malformed issue is reported and survives rewriting the repository
+	issues, err := store.LoadIssues()
+	if err != nil || len(issues["acme/infer"]) != 1 {
+		t.Fatalf("Expected 1 decodable issue, got %v (%v)", issues, err)
+	}
+	issues["acme/infer"][0].Score = 50
+	if err := store.SaveIssues(issues); err != nil {
+		t.Fatalf("Failed to save issues: %v", err)
+	}
+	failures := malformedIssueFailures(store)
+	if len(failures) != 1 || failures[0].Item != "acme/infer[1]" {
+		t.Errorf("Expected the malformed issue as a load failure, got %v", failures)
+	}
+	saved, _ := os.ReadFile(filepath.Join(dir, "issues.json"))
+	if !strings.Contains(string(saved), `"number": "two"`) {
+		t.Errorf("Expected the malformed issue to be written back, got %s", saved)
+	}
+}
+
+func TestHousekeepOnlyGenerated(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+	for _, name := range []string{"summary.md", "notes.txt", "base.json"} {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte("x"), 0644)
+		os.Chtimes(path, old, old)
+	}
+	if err := output.RecordGenerated(dir, []string{filepath.Join(dir, "summary.md"), "/elsewhere/report.md"}); err != nil {
+		t.Fatalf("Failed to record generated files: %v", err)
+	}
+	
+	// Only the report the tool wrote is old enough to go; user files stay
+	expired, err := output.Housekeep(dir, output.RetentionConfig{MaxAgeDays: 1}, nil, false, time.Now())
+	if err != nil || len(expired) != 1 || filepath.Base(expired[0].Path) != "summary.md" {
+		t.Fatalf("Expected only summary.md to expire, got %v (%v)", expired, err)
+	}
+	for _, name := range []string{"notes.txt", "base.json", output.GeneratedFile} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to survive housekeeping: %v", name, err)
+		}
+	}
+}
+
+func TestSLAUsesFirstSeen(t *testing.T) {
+	store := storage.NewStore(t.TempDir())
+	created := time.Now().Add(-30 * 24 * time.Hour)
+	issue := model.Issue{Number: 1, Title: "OOM", State: "open", Comments: 2, CreatedAt: created}
+	if err := store.SaveIssues(map[string][]model.Issue{"acme/infer": {issue}}); err != nil {
+		t.Fatalf("Failed to save issues: %v", err)
+	}
+	
+	// A re-scraped copy has no FirstSeenAt until it is taken from the store
+	scraped := map[string][]model.Issue{"acme/infer": {issue}}
+	if err := store.AttachFirstSeen(scraped); err != nil {
+		t.Fatalf("Failed to attach first seen times: %v", err)
+	}
+	config := scraper.Config{SLA: scraper.SLAConfig{UntriagedDays: 7}}
+	report := checkSLA(config, scraped)
+	if report.Untriaged != 1 || len(report.Breaches) != 0 || report.AvgUntriagedAge > time.Hour {
+		t.Errorf("Expected the untriaged age to count from first seen, got %+v", report)
+	}
+}
+
+func TestReputationDoesNotFeedItself(t *testing.T) {
+	issue := model.Issue{
+		Title:     "CUDA OOM after upgrade",
+		Body:      "Memory leak causes OOM on large batches",
+		State:     "open",
+		Author:    "alice",
+		CreatedAt: time.Now().AddDate(0, 0, -3),
+		UpdatedAt: time.Now().AddDate(0, 0, -1),
+	}
+	corpus := func(reputation map[string]float64) map[string][]model.Issue {
+		scorer := scraper.NewScorer()
+		scorer.SetReporterReputation(reputation, 20)
+		scored := issue
+		scored.Score, scored.ScoreReason = scorer.ScoreIssue(&scored)
+		return map[string][]model.Issue{"acme/infer": {scored}}
+	}
+	
+	// Scoring with last run's reputation leaves the next reputation unchanged
+	first := analytics.ComputeReporters(corpus(nil))
+	second := analytics.ComputeReporters(corpus(analytics.ReputationMap(first)))
+	if len(first) != 1 || first[0].Reputation == 0 || math.Abs(second[0].Reputation-first[0].Reputation) > 1e-9 {
+		t.Errorf("Expected a stable reputation, got %v then %v", first, second)
+	}
+}
+
+func TestTeamNotifications(t *testing.T) {
+	posted := make(chan string, 4)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		posted <- r.URL.Path + " " + string(body)
+	}))
+	defer hook.Close()
+	
+	// Escalations of a team's issues also go to the team's webhook
+	config := scraper.Config{Teams: []scraper.TeamConfig{
+		{Name: "inference", Webhooks: []string{hook.URL + "/inference"}},
+		{Name: "training", Webhooks: []string{hook.URL + "/training"}},
+	}}
+	stored := []model.Issue{{Number: 1, Priority: "medium", Title: "KV cache OOM"}}
+	current := []model.Issue{{Number: 1, Priority: "critical", Title: "KV cache OOM", AssignedTeam: "inference"}}
+	escalations := config.Escalation.Escalations(stored, current, time.Now())
+	postEscalations(context.Background(), config, escalations)
+	if len(posted) != 1 {
+		t.Fatalf("Expected one post, got %d", len(posted))
+	}
+	if message := <-posted; !strings.HasPrefix(message, "/inference ") || !strings.Contains(message, "KV cache OOM") {
+		t.Errorf("Expected the escalation posted to the inference webhook, got %s", message)
+	}
+	if violations := config.PrivacyViolations(); len(violations) != 2 {
+		t.Errorf("Expected team webhooks to violate strict privacy, got %v", violations)
+	}
+}
+
+func TestConcurrentMutatingJobs(t *testing.T) {
+	store := storage.NewStore(t.TempDir())
+	corpus := map[string][]model.Issue{}
+	for number := 1; number <= 200; number++ {
+		corpus["acme/infer"] = append(corpus["acme/infer"], model.Issue{
+			ID: number, Number: number, State: "open", Comments: 5,
+			Title:     "CUDA out of memory during inference",
+			Body:      "GPU OOM with large batch sizes after upgrading.",
+			CreatedAt: time.Now().AddDate(0, 0, -10),
+			UpdatedAt: time.Now().AddDate(0, 0, -2),
+		})
+	}
+	if err := store.SaveIssues(corpus); err != nil {
+		t.Fatalf("Failed to save issues: %v", err)
+	}
+	
+	// Both jobs rewrite the whole corpus; neither may drop the other's changes
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, kind := range []string{stepClassify, stepScore} {
+		wg.Add(1)
+		go func(i int, kind string) {
+			defer wg.Done()
+			_, errs[i] = runJob(context.Background(), scraper.Config{}, store, jobs.Job{ID: i + 1, Kind: kind})
+		}(i, kind)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("Job failed: %v", err)
+		}
+	}
+	
+	stored, err := store.LoadIssues()
+	if err != nil {
+		t.Fatalf("Failed to load issues: %v", err)
+	}
+	for _, issue := range stored["acme/infer"] {
+		if issue.Category == "" || issue.Score == 0 {
+			t.Fatalf("Expected #%d classified and scored, got category %q and score %.1f", issue.Number, issue.Category, issue.Score)
+		}
+	}
+}
+
+// handlerTransport answers requests with a handler instead of the network
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	t.handler.ServeHTTP(recorder, req)
+	return recorder.Result(), nil
+}
+
+func TestRefreshKeepsTriage(t *testing.T) {
+	defaultTransport := http.DefaultTransport
+	defer func() { http.DefaultTransport = defaultTransport }()
+	http.DefaultTransport = handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/repos/acme/infer/issues/1" {
+			fmt.Fprint(w, `{"id":101,"number":1,"title":"Server crash under load","body":"Process exits with an error","state":"open","user":{"login":"bob"},"created_at":"2024-05-01T00:00:00Z","updated_at":"2024-05-02T00:00:00Z"}`)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	})}
+	
+	config := scraper.Config{Repositories: []scraper.RepositoryConfig{{Name: "acme/infer", Enabled: true}}}
+	store := storage.NewStore(t.TempDir())
+	issue := model.Issue{ID: 101, Number: 1, Repository: "acme/infer", Title: "Server crash under load", Category: "crashes"}
+	if err := store.SaveIssues(map[string][]model.Issue{"acme/infer": {issue}}); err != nil {
+		t.Fatalf("Failed to save issues: %v", err)
+	}
+	
+	// The serve scraper loaded its corrections before the triage
+	scraperInstance := scraper.NewScraper(config)
+	useCorrections(scraperInstance, store)
+	if _, err := triageIssue(config, store, issue, "performance", "", "alice"); err != nil {
+		t.Fatalf("Failed to triage: %v", err)
+	}
+	refreshed, err := refreshIssue(context.Background(), scraperInstance, store, "acme/infer", 1)
+	if err != nil {
+		t.Fatalf("Failed to refresh: %v", err)
+	}
+	stored, err := store.LoadIssues()
+	if err != nil {
+		t.Fatalf("Failed to load issues: %v", err)
+	}
+	if refreshed.Category != "performance" || len(stored["acme/infer"]) != 1 || stored["acme/infer"][0].Category != "performance" {
+		t.Errorf("Expected the refresh to keep the triaged category, got %q returned and %v stored", refreshed.Category, stored["acme/infer"])
+	}
+}
+
+func TestCancelRunningJob(t *testing.T) {
+	defaultHeartbeat := jobHeartbeat
+	jobHeartbeat = 10 * time.Millisecond
+	defer func() { jobHeartbeat = defaultHeartbeat }()
+	
+	store := storage.NewStore(t.TempDir())
+	corpus := map[string][]model.Issue{"acme/infer": {{ID: 1, Number: 1, State: "open", Title: "CUDA out of memory during inference"}}}
+	if err := store.SaveIssues(corpus); err != nil {
+		t.Fatalf("Failed to save issues: %v", err)
+	}
+	start := func() jobs.Job {
+		err := store.UpdateJobs(func(list []jobs.Job) ([]jobs.Job, error) {
+			list, _ = jobs.Enqueue(list, stepClassify, "", time.Now())
+			return list, nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to enqueue job: %v", err)
+		}
+		job, ok, err := claimJob(store, "test", map[string]int{}, jobs.Config{BackgroundConcurrency: 1})
+		if err != nil || !ok {
+			t.Fatalf("Failed to claim job: %v", err)
+		}
+		return job
+	}
+	cancel := func(job jobs.Job) jobs.Job {
+		cancelled, err := store.UpdateJob(job.ID, func(j *jobs.Job) error {
+			return jobs.Cancel(j, time.Now())
+		})
+		if err != nil {
+			t.Fatalf("Failed to cancel job: %v", err)
+		}
+		return cancelled
+	}
+	status := func(job jobs.Job) jobs.Job {
+		list, err := store.LoadJobs()
+		if err != nil {
+			t.Fatalf("Failed to load jobs: %v", err)
+		}
+		return list[jobs.Find(list, job.ID)]
+	}
+	
+	// A job cancelled while it waits for the store lock never starts its work
+	locked, release := make(chan struct{}), make(chan struct{})
+	go store.Update(func() error {
+		close(locked)
+		<-release
+		return nil
+	})
+	<-locked
+	job := start()
+	finished := make(chan error)
+	go func() {
+		items, err := runJob(context.Background(), scraper.Config{}, store, job)
+		finishJob(store, job, items, err)
+		finished <- err
+	}()
+	
+	// The heartbeat after the one that saw the request runs after the cancel
+	last, beats := cancel(job).UpdatedAt, 0
+	for beats < 2 {
+		time.Sleep(jobHeartbeat / 2)
+		if updated := status(job).UpdatedAt; !updated.Equal(last) {
+			last, beats = updated, beats+1
+		}
+	}
+	close(release)
+	if err := <-finished; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the job to stop with context.Canceled, got %v", err)
+	}
+	if got := status(job); got.Status != jobs.StatusCancelled {
+		t.Errorf("Expected the job cancelled, got %s", got.Status)
+	}
+	stored, err := store.LoadIssues()
+	if err != nil {
+		t.Fatalf("Failed to load issues: %v", err)
+	}
+	if category := stored["acme/infer"][0].Category; category != "" {
+		t.Errorf("Expected the cancelled job to leave the issue unclassified, got %q", category)
+	}
+	
+	// A job whose work finished before the request is done, with a note
+	job = start()
+	items, err := runJob(context.Background(), scraper.Config{}, store, job)
+	if err != nil {
+		t.Fatalf("Job failed: %v", err)
+	}
+	cancel(job)
+	finishJob(store, job, items, err)
+	if got := status(job); got.Status != jobs.StatusDone || got.Note == "" {
+		t.Errorf("Expected the job done with a note, got %s (%q)", got.Status, got.Note)
+	}
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/mcp"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/query"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/similarity"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// mcpMaxLimit caps the number of results a tool call may request
+const mcpMaxLimit = 50
+
+// mcpCommand exposes the stored corpus to AI coding assistants over MCP
+func mcpCommand() *cli.Command {
+	return &cli.Command{
+		Name:   "mcp",
+		Usage:  "以 MCP (Model Context Protocol) 服务器模式运行 (stdio), 供 AI 编程助手查询问题库",
+		Action: runMCP,
+	}
+}
+
+// pitfallSummary is the compact form of an issue returned by search tools
+type pitfallSummary struct {
+	Repository string            `json:"repository"`
+	Number     int               `json:"number"`
+	Title      string            `json:"title"`
+	URL        string            `json:"url"`
+	State      string            `json:"state"`
+	Category   string            `json:"category,omitempty"`
+	Score      float64           `json:"score"`
+	Similarity float64           `json:"similarity,omitempty"`
+	FixedBy    string            `json:"fixed_by,omitempty"`
+	Workaround *model.Workaround `json:"workaround,omitempty"`
+}
+
+// runMCP serves MCP over stdin/stdout until stdin closes
+func runMCP(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	// stdout carries the protocol, so logs must go elsewhere
+	log.SetOutput(os.Stderr)
+
+	version := "dev"
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		version = info.Main.Version
+	}
+
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	store := storage.NewStore(config.Storage.Dir)
+	server := mcp.NewServer("gh-pitfall-scraper", version, pitfallTools(config, store))
+	return server.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// pitfallTools are the corpus tools exposed over MCP. The corpus is read on
+// every call so answers follow the latest scrape.
+func pitfallTools(config scraper.Config, store *storage.Store) []mcp.Tool {
+	load := func() (map[string][]model.Issue, error) {
+		issues, err := store.LoadIssues()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load stored issues: %w", err)
+		}
+		model.ApplyScoreDecay(issues, config.Scoring.DecayHalfLife(), time.Now())
+		if !config.Output.IncludeDeleted {
+			issues, _ = model.ExcludeDeletedUpstream(issues)
+		}
+		return issues, nil
+	}
+
+	return []mcp.Tool{
+		{
+			Name:        "search_pitfalls",
+			Description: "Search known pitfalls (bugs, regressions, gotchas) scraped from GitHub issues. Supports free text and qualifiers such as repo:go-gorm/gorm, category:performance, label:bug, state:open, score:>20.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"query": stringProperty("Search query, e.g. \"repo:go-gorm/gorm connection pool\""),
+				"limit": integerProperty("Maximum number of results (default 10)"),
+			}, "query"),
+			Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+				var args struct {
+					Query string `json:"query"`
+					Limit int    `json:"limit"`
+				}
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return nil, err
+				}
+				search, err := query.Parse(args.Query)
+				if err != nil {
+					return nil, fmt.Errorf("invalid query: %w", err)
+				}
+				issues, err := load()
+				if err != nil {
+					return nil, err
+				}
+
+				sortBy := config.Output.SortBy
+				if search.HasText() {
+					sortBy = query.SortRelevance
+				}
+				weights := query.RelevanceWeights{
+					Text:    config.Search.RelevanceWeights.Text,
+					Score:   config.Search.RelevanceWeights.Score,
+					Recency: config.Search.RelevanceWeights.Recency,
+					Phrase:  config.Search.RelevanceWeights.Phrase,
+				}
+				results := search.Filter(issues)
+				query.NewIndex(issues).Sort(search, results, sortBy, weights, time.Now())
+
+				summaries := make([]pitfallSummary, 0, len(results))
+				for _, issue := range truncateIssues(results, args.Limit) {
+					summaries = append(summaries, summarizePitfall(issue, 0))
+				}
+				return map[string]interface{}{"total": len(results), "results": summaries}, nil
+			},
+		},
+		{
+			Name:        "similar_issues",
+			Description: "Find known pitfalls similar to a problem description or to a stored issue, ranked by text similarity.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"text":       stringProperty("Problem description, e.g. an error message"),
+				"repository": stringProperty("Repository of a stored issue to compare against (owner/name), instead of text"),
+				"number":     integerProperty("Number of the stored issue to compare against"),
+				"limit":      integerProperty("Maximum number of results (default 10)"),
+			}),
+			Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+				var args struct {
+					Text       string `json:"text"`
+					Repository string `json:"repository"`
+					Number     int    `json:"number"`
+					Limit      int    `json:"limit"`
+				}
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return nil, err
+				}
+				issues, err := load()
+				if err != nil {
+					return nil, err
+				}
+
+				text := args.Text
+				if args.Repository != "" {
+					issue, ok := findIssue(issues, args.Repository, args.Number)
+					if !ok {
+						return nil, fmt.Errorf("issue %s#%d not found", args.Repository, args.Number)
+					}
+					text = issue.Title + "\n" + issue.SearchText()
+				}
+				if text == "" {
+					return nil, fmt.Errorf("either text or repository and number is required")
+				}
+
+				limit := clampLimit(args.Limit)
+				var summaries []pitfallSummary
+				for _, match := range similarity.NewEngine(issues).Search(text, limit+1, 0.05) {
+					if match.Issue.Repository == args.Repository && match.Issue.Number == args.Number {
+						continue
+					}
+					if len(summaries) < limit {
+						summaries = append(summaries, summarizePitfall(match.Issue, match.Similarity))
+					}
+				}
+				return map[string]interface{}{"results": summaries}, nil
+			},
+		},
+		{
+			Name:        "get_issue",
+			Description: "Get the full stored record of a pitfall: body, labels, category, platforms, score breakdown, fix and workaround.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"repository": stringProperty("Repository (owner/name)"),
+				"number":     integerProperty("Issue number"),
+			}, "repository", "number"),
+			Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+				var args struct {
+					Repository string `json:"repository"`
+					Number     int    `json:"number"`
+				}
+				if err := json.Unmarshal(raw, &args); err != nil {
+					return nil, err
+				}
+				issues, err := load()
+				if err != nil {
+					return nil, err
+				}
+				issue, ok := findIssue(issues, args.Repository, args.Number)
+				if !ok {
+					return nil, fmt.Errorf("issue %s#%d not found", args.Repository, args.Number)
+				}
+				return issue, nil
+			},
+		},
+	}
+}
+
+// summarizePitfall reduces an issue to the fields useful in search results
+func summarizePitfall(issue model.Issue, similarity float64) pitfallSummary {
+	return pitfallSummary{
+		Repository: issue.Repository,
+		Number:     issue.Number,
+		Title:      issue.Title,
+		URL:        issue.URL,
+		State:      issue.State,
+		Category:   issue.Category,
+		Score:      issue.Score,
+		Similarity: similarity,
+		FixedBy:    issue.FixedBy,
+		Workaround: issue.Workaround,
+	}
+}
+
+// findIssue looks up a stored issue by repository and number
+func findIssue(issues map[string][]model.Issue, repository string, number int) (model.Issue, bool) {
+	for _, issue := range issues[repository] {
+		if issue.Number == number {
+			return issue, true
+		}
+	}
+	return model.Issue{}, false
+}
+
+// clampLimit applies the default and maximum result count of tool calls
+func clampLimit(limit int) int {
+	if limit <= 0 {
+		return 10
+	}
+	if limit > mcpMaxLimit {
+		return mcpMaxLimit
+	}
+	return limit
+}
+
+func truncateIssues(issues []model.Issue, limit int) []model.Issue {
+	if limit = clampLimit(limit); len(issues) > limit {
+		return issues[:limit]
+	}
+	return issues
+}
+
+func objectSchema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func stringProperty(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description}
+}
+
+func integerProperty(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "integer", "description": description}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// notesCommand manages team notes attached to scraped issues
+func notesCommand() *cli.Command {
+	issueFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:     "repo",
+			Usage:    "仓库 (owner/repo)",
+			Required: true,
+		},
+		&cli.IntFlag{
+			Name:     "issue",
+			Usage:    "Issue 编号",
+			Required: true,
+		},
+	}
+
+	idFlag := &cli.IntFlag{
+		Name:     "id",
+		Usage:    "备注 ID",
+		Required: true,
+	}
+
+	return &cli.Command{
+		Name:  "notes",
+		Usage: "管理团队为问题记录的备注",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "add",
+				Usage: "为问题添加备注",
+				Flags: append(issueFlags,
+					&cli.StringFlag{
+						Name:     "text",
+						Usage:    "备注内容",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "author",
+						Value: os.Getenv("USER"),
+						Usage: "备注作者",
+					},
+				),
+				Action: runNotesAdd,
+			},
+			{
+				Name:  "list",
+				Usage: "列出备注",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "repo",
+						Usage: "只显示该仓库的备注",
+					},
+					&cli.IntFlag{
+						Name:  "issue",
+						Usage: "只显示该 Issue 的备注",
+					},
+				},
+				Action: runNotesList,
+			},
+			{
+				Name:  "edit",
+				Usage: "修改备注内容",
+				Flags: []cli.Flag{
+					idFlag,
+					&cli.StringFlag{
+						Name:     "text",
+						Usage:    "新的备注内容",
+						Required: true,
+					},
+				},
+				Action: runNotesEdit,
+			},
+			{
+				Name:   "delete",
+				Usage:  "删除备注",
+				Flags:  []cli.Flag{idFlag},
+				Action: runNotesDelete,
+			},
+		},
+	}
+}
+
+// runNotesAdd adds a note to an issue
+func runNotesAdd(c *cli.Context) error {
+	store, err := openStore(c)
+	if err != nil {
+		return err
+	}
+
+	var note model.Note
+	err = store.Update(func() error {
+		note, err = store.AddNote(model.Note{
+			Repository:  c.String("repo"),
+			IssueNumber: c.Int("issue"),
+			Author:      c.String("author"),
+			Text:        c.String("text"),
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add note: %w", err)
+	}
+
+	fmt.Printf("已添加备注 %d 到 %s#%d\n", note.ID, note.Repository, note.IssueNumber)
+	return nil
+}
+
+// runNotesList prints notes, optionally limited to a repository or issue
+func runNotesList(c *cli.Context) error {
+	store, err := openStore(c)
+	if err != nil {
+		return err
+	}
+
+	notes, err := store.LoadNotes()
+	if err != nil {
+		return fmt.Errorf("failed to load notes: %w", err)
+	}
+
+	for _, note := range notes {
+		if repo := c.String("repo"); repo != "" && !strings.EqualFold(note.Repository, repo) {
+			continue
+		}
+		if issue := c.Int("issue"); issue != 0 && note.IssueNumber != issue {
+			continue
+		}
+		fmt.Printf("[%d] %s#%d  %s  %s\n    %s\n",
+			note.ID, note.Repository, note.IssueNumber, note.Author,
+			note.CreatedAt.Format("2006-01-02 15:04"), note.Text)
+	}
+
+	return nil
+}
+
+// runNotesEdit replaces the text of a note
+func runNotesEdit(c *cli.Context) error {
+	id := c.Int("id")
+	store, err := openStore(c)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Update(func() error { return store.UpdateNote(id, c.String("text")) }); err != nil {
+		return fmt.Errorf("failed to update note: %w", err)
+	}
+
+	fmt.Printf("已更新备注 %d\n", id)
+	return nil
+}
+
+// runNotesDelete deletes a note
+func runNotesDelete(c *cli.Context) error {
+	id := c.Int("id")
+	store, err := openStore(c)
+	if err != nil {
+		return err
+	}
+
+	if err := store.Update(func() error { return store.DeleteNote(id) }); err != nil {
+		return fmt.Errorf("failed to delete note: %w", err)
+	}
+
+	fmt.Printf("已删除备注 %d\n", id)
+	return nil
+}
+
+// openStore loads the configuration and opens the local store
+func openStore(c *cli.Context) (*storage.Store, error) {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return storage.NewStore(config.Storage.Dir), nil
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/analytics"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/pipeline"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// Post-ingest pipeline steps, in dependency order
+const (
+	stepClassify     = "classify"
+	stepScore        = "score"
+	stepDedup        = "dedup"
+	stepSummarize    = "summarize"
+	stepRefreshViews = "refresh-views"
+)
+
+// pipelineCommand runs post-ingest processing over locally stored issues
+func pipelineCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "pipeline",
+		Usage: "对本地已保存的问题运行入库后处理流水线",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "run",
+				Usage: "按依赖顺序运行处理步骤",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "steps",
+						Usage: "逗号分隔的步骤 (classify,score,dedup,summarize,refresh-views), 默认全部",
+					},
+					&cli.BoolFlag{
+						Name:  "resume",
+						Usage: "跳过上次未完成运行中已成功的步骤",
+					},
+				},
+				Action: runPipelineCommand,
+			},
+			{
+				Name:  "stats",
+				Usage: "按步骤统计历次运行的耗时、处理量和失败次数",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "last",
+						Usage: "只统计最近 N 次运行 (默认全部)",
+					},
+				},
+				Action: runPipelineStats,
+			},
+		},
+	}
+}
+
+// runPipelineCommand runs the selected steps and prints per-step results
+func runPipelineCommand(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var steps []string
+	for _, step := range strings.Split(c.String("steps"), ",") {
+		if step = strings.TrimSpace(step); step != "" {
+			steps = append(steps, step)
+		}
+	}
+
+	results, err := runPipeline(context.Background(), config, storage.NewStore(config.Storage.Dir), steps, c.Bool("resume"))
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		fmt.Printf("%-14s %-8s %6d 项  %s", result.Step, result.Status, result.Items, result.Duration.Round(time.Millisecond))
+		if result.Error != "" {
+			fmt.Printf("  %s", result.Error)
+			failed++
+		}
+		fmt.Println()
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 个步骤未成功, 可使用 --resume 重试", failed)
+	}
+	return nil
+}
+
+// runPipeline runs the selected post-ingest steps over the stored corpus.
+// With resume, steps completed by an unfinished run of the same steps are skipped.
+// The run holds the store's write lock from loading the corpus to saving
+// it, so runs started together (e.g. by jobs workers) take turns instead of
+// overwriting each other's changes.
+func runPipeline(ctx context.Context, config scraper.Config, store *storage.Store, steps []string, resume bool) ([]pipeline.Result, error) {
+	var results []pipeline.Result
+	err := store.Update(func() error {
+		var err error
+		results, err = runPipelineSteps(ctx, config, store, steps, resume)
+		return err
+	})
+	return results, err
+}
+
+// runPipelineSteps runs the pipeline for runPipeline under the store lock
+func runPipelineSteps(ctx context.Context, config scraper.Config, store *storage.Store, steps []string, resume bool) ([]pipeline.Result, error) {
+	corpus, err := store.LoadIssues()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	if err := store.AttachComments(corpus); err != nil {
+		return nil, fmt.Errorf("failed to load stored comments: %w", err)
+	}
+	printFailures(malformedIssueFailures(store))
+
+	runner, err := newPipeline(config, store, corpus)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := runner.Plan(steps)
+	if err != nil {
+		return nil, err
+	}
+
+	state := pipeline.State{StartedAt: time.Now(), Steps: plan}
+	if resume {
+		previous, err := store.LoadPipelineState()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load pipeline state: %w", err)
+		}
+		if !previous.Finished && strings.Join(previous.Steps, ",") == strings.Join(plan, ",") {
+			state = previous
+		}
+	}
+
+	record := pipeline.RunRecord{StartedAt: time.Now()}
+	record.Results = runner.Run(ctx, plan, &state, store.SavePipelineState)
+	record.FinishedAt = time.Now()
+	if err := store.AppendPipelineRun(record); err != nil {
+		log.Printf("Error recording pipeline run: %v", err)
+	}
+	return record.Results, nil
+}
+
+// runPipelineStats prints per-step metrics over the recorded pipeline runs
+func runPipelineStats(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	runs, err := storage.NewStore(config.Storage.Dir).LoadPipelineRuns()
+	if err != nil {
+		return fmt.Errorf("failed to load pipeline runs: %w", err)
+	}
+	if last := c.Int("last"); last > 0 && len(runs) > last {
+		runs = runs[len(runs)-last:]
+	}
+	if len(runs) == 0 {
+		fmt.Println("暂无流水线运行记录")
+		return nil
+	}
+
+	fmt.Printf("共 %d 次运行 (%s 至 %s)\n\n", len(runs), runs[0].StartedAt.Format("2006-01-02 15:04"), runs[len(runs)-1].StartedAt.Format("2006-01-02 15:04"))
+	fmt.Printf("%-14s %5s %5s %5s %10s %10s %10s %10s %10s\n", "步骤", "运行", "失败", "跳过", "平均耗时", "最长耗时", "最近耗时", "最近处理", "项/秒")
+	for _, step := range pipeline.Summarize(runs) {
+		fmt.Printf("%-14s %5d %5d %5d %10s %10s %10s %10d %10.1f\n",
+			step.Step, step.Runs, step.Failures, step.Skipped,
+			step.AvgDuration().Round(time.Millisecond),
+			step.MaxDuration.Round(time.Millisecond),
+			step.LastDuration.Round(time.Millisecond),
+			step.LastItems, step.ItemsPerSecond())
+	}
+	return nil
+}
+
+// newPipeline wires the post-ingest steps to the stored corpus. Steps that
+// change issues save them back before reporting success.
+func newPipeline(config scraper.Config, store *storage.Store, corpus map[string][]model.Issue) (*pipeline.Runner, error) {
+	scraperInstance := scraper.NewScraper(config)
+	useCorrections(scraperInstance, store)
+
+	return pipeline.NewRunner(
+		pipeline.Step{
+			Name: stepClassify,
+			Run: func(ctx context.Context) (int, error) {
+				failed := len(scraperInstance.Failures())
+				count := scraperInstance.ClassifyIssues(corpus)
+				printFailures(scraperInstance.Failures()[failed:])
+				if err := store.SaveIssues(corpus); err != nil {
+					return count, err
+				}
+				recordClassifications(scraperInstance, store, corpus)
+				return count, nil
+			},
+		},
+		pipeline.Step{
+			Name:     stepScore,
+			Requires: []string{stepClassify},
+			Run: func(ctx context.Context) (int, error) {
+				failed := len(scraperInstance.Failures())
+				count := scraperInstance.ScoreIssues(corpus)
+				printFailures(scraperInstance.Failures()[failed:])
+				return count, store.SaveIssues(corpus)
+			},
+		},
+		pipeline.Step{
+			Name: stepDedup,
+			Run: func(ctx context.Context) (int, error) {
+				if err := store.LinkDuplicates(corpus); err != nil {
+					return 0, err
+				}
+				if err := store.ReplaceDuplicateLinks(model.DuplicateSourceContentHash, model.ContentHashLinks(corpus, time.Now())); err != nil {
+					return 0, err
+				}
+				if config.Dedup.CrossSource {
+					if err := linkCrossSource(store, config.Dedup.CrossSourceMinSimilarity); err != nil {
+						return 0, err
+					}
+				}
+				links, err := store.LoadDuplicateLinks()
+				if err != nil {
+					return 0, err
+				}
+				duplicates := scraper.DuplicatesToPrune(corpus, links, config.Dedup, time.Now())
+				if err := checkConfirmed(config, len(duplicates), false); err != nil {
+					log.Printf("Kept %d duplicate issues (dedup policy %s): %v; use dedup prune", len(duplicates), config.Dedup.Policy, err)
+				} else if len(duplicates) > 0 {
+					removed, err := deleteIssues(store, corpus, duplicates, model.TombstoneDuplicate)
+					if err != nil {
+						return 0, err
+					}
+					log.Printf("Removed %d duplicate issues (dedup policy %s)", removed, config.Dedup.Policy)
+				}
+				return len(links), nil
+			},
+		},
+		pipeline.Step{
+			Name: stepSummarize,
+			Run: func(ctx context.Context) (int, error) {
+				reporters := analytics.ComputeReporters(corpus)
+				return len(reporters), store.SaveReporters(reporters)
+			},
+		},
+		pipeline.Step{
+			Name:     stepRefreshViews,
+			Requires: []string{stepScore, stepDedup},
+			Run: func(ctx context.Context) (int, error) {
+				if err := store.AttachNotes(corpus); err != nil {
+					return 0, err
+				}
+				if err := store.AttachCrossSource(corpus); err != nil {
+					return 0, err
+				}
+				return getTotalIssues(corpus), writeReports(config, store, corpus)
+			},
+		},
+	)
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/analytics"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/query"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// portfoliosCommand summarizes and searches the configured portfolios
+func portfoliosCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "portfolios",
+		Usage: "按 portfolios 中配置的仓库分组查看问题库",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "stats",
+				Usage: "每个 portfolio 的仓库数、问题数、未关闭和高优先级问题数、平均评分和主要类别",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Value: "text",
+						Usage: "输出格式 (text/json)",
+					},
+				},
+				Action: runPortfoliosStats,
+			},
+			{
+				Name:      "search",
+				Usage:     "运行 portfolio 的保存搜索; 不指定搜索名时运行全部",
+				ArgsUsage: "<portfolio> [搜索名]",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "limit",
+						Value: 20,
+						Usage: "每个搜索最多显示的问题数 (0 = 全部)",
+					},
+				},
+				Action: runPortfoliosSearch,
+			},
+		},
+	}
+}
+
+// runPortfoliosStats prints the per-portfolio rollup of the stored issues
+func runPortfoliosStats(c *cli.Context) error {
+	format := c.String("format")
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unknown format %q (expected text or json)", format)
+	}
+	config, issues, err := loadPortfolioIssues(c)
+	if err != nil {
+		return err
+	}
+	if len(config.Portfolios) == 0 {
+		fmt.Println("未配置 portfolio (portfolios)")
+		return nil
+	}
+
+	rollup := analytics.PortfolioRollup(issues, config.Portfolios.Names())
+	if format == "json" {
+		data, err := json.MarshalIndent(rollup, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%-24s %6s %6s %6s %8s %8s  %s\n", "PORTFOLIO", "仓库", "问题", "未关闭", "高优先级", "平均评分", "主要类别")
+	for _, stats := range rollup {
+		fmt.Printf("%-24s %6d %6d %6d %8d %8.1f  %s\n",
+			stats.Name, stats.Repositories, stats.Issues, stats.Open, stats.HighPriority, stats.AverageScore, orDefault(stats.TopCategory, "-"))
+	}
+	return nil
+}
+
+// runPortfoliosSearch runs the saved searches of a portfolio against the
+// stored issues
+func runPortfoliosSearch(c *cli.Context) error {
+	if c.NArg() < 1 || c.NArg() > 2 {
+		return fmt.Errorf("usage: portfolios search <portfolio> [search]")
+	}
+	config, issues, err := loadPortfolioIssues(c)
+	if err != nil {
+		return err
+	}
+	portfolio, ok := config.Portfolios.Find(c.Args().Get(0))
+	if !ok {
+		return fmt.Errorf("unknown portfolio: %s", c.Args().Get(0))
+	}
+
+	searches := portfolio.Searches
+	if name := c.Args().Get(1); name != "" {
+		searches = nil
+		for _, search := range portfolio.Searches {
+			if search.Name == name {
+				searches = append(searches, search)
+			}
+		}
+		if len(searches) == 0 {
+			return fmt.Errorf("portfolio %s has no saved search %s", portfolio.Name, name)
+		}
+	}
+	if len(searches) == 0 {
+		fmt.Printf("portfolio %s 没有保存搜索 (searches)\n", portfolio.Name)
+		return nil
+	}
+
+	now := time.Now()
+	for i, saved := range searches {
+		if i > 0 {
+			fmt.Println()
+		}
+		search, err := query.Parse(portfolio.SearchQuery(saved))
+		if err != nil {
+			return fmt.Errorf("saved search %s/%s: %w", portfolio.Name, saved.Name, err)
+		}
+		results := search.Filter(issues)
+		search.Sort(results, query.SortScore, query.RelevanceWeights{}, now)
+
+		fmt.Printf("🔎 %s/%s (%s): %d 个问题\n", portfolio.Name, saved.Name, saved.Query, len(results))
+		for j, issue := range results {
+			if limit := c.Int("limit"); limit > 0 && j == limit {
+				fmt.Printf("    …还有 %d 个\n", len(results)-limit)
+				break
+			}
+			fmt.Printf("%2d. [%.1f] %s#%d %s\n    %s\n", j+1, issue.Score, issue.Repository, issue.Number, issue.Title, issue.URL)
+		}
+	}
+	return nil
+}
+
+// loadPortfolioIssues loads the config and the live stored issues, tagged
+// with their current portfolios
+func loadPortfolioIssues(c *cli.Context) (scraper.Config, map[string][]model.Issue, error) {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return scraper.Config{}, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	issues, err := storage.NewStore(config.Storage.Dir).LoadIssues()
+	if err != nil {
+		return scraper.Config{}, nil, fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	issues, _ = model.ExcludeDeletedUpstream(issues)
+	config.Portfolios.MarkCorpus(issues)
+	return config, issues, nil
+}
+
+// portfolioRisk scores the configured portfolios from issues tagged with
+// their portfolios and repository risks
+func portfolioRisk(config scraper.Config, issues map[string][]model.Issue) []analytics.PortfolioRisk {
+	weights := analytics.RiskWeights{
+		Unresolved: config.PortfolioRisk.UnresolvedWeight,
+		Health:     config.PortfolioRisk.HealthWeight,
+		Trend:      config.PortfolioRisk.TrendWeight,
+	}
+	window := time.Duration(config.PortfolioRisk.TrendDays) * 24 * time.Hour
+	return analytics.PortfolioRiskScores(issues, config.Portfolios.Names(), weights, window, time.Now())
+}
+
+// recordPortfolioRisk adds the current risk scores of the portfolios to
+// the history charted in reports
+func recordPortfolioRisk(config scraper.Config, store *storage.Store, issues map[string][]model.Issue) {
+	if len(config.Portfolios) == 0 {
+		return
+	}
+	config.Portfolios.MarkCorpus(issues)
+	if snapshots, err := store.LoadRepoSnapshots(); err != nil {
+		log.Printf("⚠️  警告: 未能读取仓库快照: %v", err)
+	} else {
+		model.MarkRepoRisks(issues, snapshots)
+	}
+	if err := store.AppendPortfolioRisk(portfolioRisk(config, issues)); err != nil {
+		log.Printf("⚠️  警告: 未能记录 portfolio 风险分: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// pullsCommand lists the stored pull requests
+func pullsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "pulls",
+		Usage: "列出已保存的拉取请求 (需启用 pull_requests.enabled 抓取)",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "repo",
+				Usage: "只列出指定仓库的拉取请求",
+			},
+			&cli.StringFlag{
+				Name:  "issue",
+				Usage: "只列出关闭了指定问题的拉取请求 (owner/repo#number)",
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Value: 20,
+				Usage: "最多列出的数量",
+			},
+		},
+		Action: runPulls,
+	}
+}
+
+// runPulls prints stored pull requests, most recently updated first
+func runPulls(c *cli.Context) error {
+	issueRef := c.String("issue")
+	if issueRef != "" {
+		repoName, number, ok := model.ParseIssueRef(issueRef)
+		if !ok {
+			return fmt.Errorf("invalid --issue %q (expected owner/repo#number)", issueRef)
+		}
+		issueRef = model.IssueRef(repoName, number)
+	}
+
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	store := storage.NewStore(config.Storage.Dir)
+	stored, err := store.LoadPullRequests()
+	if err != nil {
+		return fmt.Errorf("failed to load pull requests: %w", err)
+	}
+
+	var pulls []model.PullRequest
+	for repoName, repoPulls := range stored {
+		if repo := c.String("repo"); repo != "" && !strings.EqualFold(repo, repoName) {
+			continue
+		}
+		for _, pull := range repoPulls {
+			if issueRef == "" || containsFold(pull.LinkedIssues, issueRef) {
+				pulls = append(pulls, pull)
+			}
+		}
+	}
+	sort.Slice(pulls, func(i, j int) bool { return pulls[i].UpdatedAt.After(pulls[j].UpdatedAt) })
+	if len(pulls) == 0 {
+		fmt.Println("没有匹配的拉取请求")
+		return nil
+	}
+
+	for i, pull := range pulls {
+		if i == c.Int("limit") {
+			fmt.Printf("…还有 %d 个\n", len(pulls)-i)
+			break
+		}
+		state := "已关闭"
+		if pull.Merged {
+			state = "已合并"
+		}
+		fmt.Printf("🔀 %s [%s] %s\n", model.IssueRef(pull.Repository, pull.Number), state, pull.Title)
+		fmt.Printf("   %s\n", pull.URL)
+		fmt.Printf("   关联问题: %s  审查评论: %d\n", orDefault(strings.Join(pull.LinkedIssues, ", "), "-"), len(pull.ReviewComments))
+	}
+	return nil
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// scrapePullRequests scrapes and stores the closed pull requests of the
+// GitHub repositories scraped in this run
+func scrapePullRequests(ctx context.Context, config scraper.Config, scraperInstance *scraper.Scraper, store *storage.Store, scraped map[string][]model.Issue) {
+	if budgetExceeded(config, store) {
+		log.Printf("💸 本月 API 请求已超出预算 (budget.monthly_requests = %d), 跳过拉取请求抓取", config.Budget.MonthlyRequests)
+		return
+	}
+
+	log.Println("🔀 抓取已关闭的拉取请求...")
+	total := 0
+	for _, repo := range config.Repositories {
+		if _, ok := scraped[repo.Name]; !ok || (repo.Source != "" && repo.Source != model.SourceGitHub) {
+			continue
+		}
+		pulls, err := scraperInstance.ScrapePullRequests(ctx, repo)
+		if err != nil {
+			log.Printf("⚠️  警告: 未能抓取 %s 的拉取请求: %v", repo.Name, err)
+			continue
+		}
+		if err := store.SavePullRequests(repo.Name, pulls); err != nil {
+			log.Printf("⚠️  警告: 未能保存 %s 的拉取请求: %v", repo.Name, err)
+			continue
+		}
+		total += len(pulls)
+	}
+	log.Printf("🔀 已保存 %d 个拉取请求", total)
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/client"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// reposCommand manages the metadata of tracked repositories
+func reposCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "repos",
+		Usage: "管理已跟踪仓库的元数据",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "refresh",
+				Usage:  "用条件请求刷新仓库的 star、fork、归档状态和默认分支, 不抓取问题",
+				Action: runReposRefresh,
+			},
+			{
+				Name:  "coverage",
+				Usage: "对照本地项目的依赖图, 统计哪些依赖仓库已在踩坑库中, 并可将缺失的加入配置",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "project",
+						Value: ".",
+						Usage: "本地项目目录 (读取 go.mod、go.sum、package.json 和 package-lock.json)",
+					},
+					&cli.BoolFlag{
+						Name:  "direct",
+						Usage: "只统计直接依赖",
+					},
+					&cli.StringFlag{
+						Name:  "format",
+						Value: "text",
+						Usage: "输出格式 (text/json)",
+					},
+					&cli.BoolFlag{
+						Name:  "add",
+						Usage: "询问后将缺失的依赖仓库加入配置文件",
+					},
+					&cli.BoolFlag{
+						Name:  "yes",
+						Usage: "配合 --add, 不询问直接加入",
+					},
+				},
+				Action: runReposCoverage,
+			},
+		},
+	}
+}
+
+// runReposRefresh re-fetches the metadata of enabled GitHub repositories,
+// recording a snapshot only for the ones that changed since the last refresh
+func runReposRefresh(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	history, err := store.LoadRepoSnapshots()
+	if err != nil {
+		return fmt.Errorf("failed to load repository snapshots: %w", err)
+	}
+	etags, err := store.LoadRepoETags()
+	if err != nil {
+		return fmt.Errorf("failed to load repository ETags: %w", err)
+	}
+	latest := make(map[string]model.RepoSnapshot)
+	for _, snapshot := range history {
+		if snapshot.CapturedAt.After(latest[snapshot.Repository].CapturedAt) {
+			latest[snapshot.Repository] = snapshot
+		}
+	}
+
+	scraperInstance := scraper.NewScraper(config)
+	var snapshots []model.RepoSnapshot
+	unchanged, failed := 0, 0
+	for _, repo := range config.Repositories {
+		if !repo.Enabled || (repo.Source != "" && repo.Source != model.SourceGitHub) {
+			continue
+		}
+
+		// Without a previous snapshot there is nothing to compare against
+		etag := etags[repo.Name]
+		previous, known := latest[repo.Name]
+		if !known {
+			etag = ""
+		}
+
+		snapshot, etag, err := scraperInstance.RefreshRepository(c.Context, repo.Name, etag)
+		if errors.Is(err, client.ErrNotModified) {
+			unchanged++
+			continue
+		}
+		if err != nil {
+			log.Printf("⚠️  警告: 未能刷新 %s: %v", repo.Name, err)
+			failed++
+			continue
+		}
+		etags[repo.Name] = etag
+
+		snapshot.PitfallCount = len(issues[repo.Name])
+		snapshots = append(snapshots, snapshot)
+		printRepoChange(previous, known, snapshot)
+	}
+
+	if len(snapshots) > 0 {
+		if err := store.AppendRepoSnapshots(snapshots); err != nil {
+			return fmt.Errorf("failed to save repository snapshots: %w", err)
+		}
+	}
+	if err := store.SaveRepoETags(etags); err != nil {
+		return fmt.Errorf("failed to save repository ETags: %w", err)
+	}
+
+	_, requests := scraperInstance.GitHubRequests()
+	fmt.Printf("\n已更新 %d 个仓库, 未变化 %d 个, 失败 %d 个 (GitHub 请求: %d)\n", len(snapshots), unchanged, failed, requests)
+	return nil
+}
+
+// printRepoChange prints the refreshed metadata of a repository and how it
+// differs from its previous snapshot
+func printRepoChange(previous model.RepoSnapshot, known bool, current model.RepoSnapshot) {
+	if !known {
+		fmt.Printf("  %-40s ⭐ %d  🍴 %d  分支 %s\n", current.Repository, current.Stars, current.Forks, current.DefaultBranch)
+		return
+	}
+
+	fmt.Printf("  %-40s ⭐ %d (%+d)  🍴 %d (%+d)", current.Repository,
+		current.Stars, current.Stars-previous.Stars, current.Forks, current.Forks-previous.Forks)
+	if previous.DefaultBranch != "" && previous.DefaultBranch != current.DefaultBranch {
+		fmt.Printf("  分支 %s → %s", previous.DefaultBranch, current.DefaultBranch)
+	}
+	if previous.Archived != current.Archived {
+		if current.Archived {
+			fmt.Print("  已归档")
+		} else {
+			fmt.Print("  已取消归档")
+		}
+	}
+	fmt.Println()
+}
+
+// Coverage of a dependency by the pitfall database
+const (
+	coverageCovered  = "covered"  // tracked and scraped
+	coveragePending  = "pending"  // tracked, not scraped yet
+	coverageDisabled = "disabled" // configured but disabled
+	coverageMissing  = "missing"  // not configured
+)
+
+// dependencyCoverage is one dependency repository and its coverage
+type dependencyCoverage struct {
+	Repository string `json:"repository"`
+	Ecosystem  string `json:"ecosystem"`
+	Direct     bool   `json:"direct"`
+	Status     string `json:"status"`
+	Pitfalls   int    `json:"pitfalls"`
+}
+
+// runReposCoverage compares a project's dependency repositories with the
+// configured and scraped ones
+func runReposCoverage(c *cli.Context) error {
+	format := c.String("format")
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unknown format %q (expected text or json)", format)
+	}
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dependencies, err := detectDependencies(c.String("project"), !c.Bool("direct"))
+	if err != nil {
+		return fmt.Errorf("failed to read project manifests: %w", err)
+	}
+	if len(dependencies) == 0 {
+		fmt.Println("未在项目中检测到 GitHub 上的依赖")
+		return nil
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	empty, err := store.LoadEmptyScrapes()
+	if err != nil {
+		return fmt.Errorf("failed to load empty scrapes: %w", err)
+	}
+	scraped := make(map[string]int)
+	for repoName := range empty {
+		scraped[strings.ToLower(repoName)] = 0
+	}
+	for repoName, repoIssues := range issues {
+		scraped[strings.ToLower(repoName)] = len(repoIssues)
+	}
+	configured := make(map[string]bool)
+	for _, repo := range config.Repositories {
+		configured[strings.ToLower(repo.Name)] = configured[strings.ToLower(repo.Name)] || repo.Enabled
+	}
+
+	coverage := make([]dependencyCoverage, len(dependencies))
+	var missing []dependencyRepo
+	for i, dependency := range dependencies {
+		key := strings.ToLower(dependency.Name)
+		entry := dependencyCoverage{Repository: dependency.Name, Ecosystem: dependency.Ecosystem, Direct: dependency.Direct}
+		enabled, known := configured[key]
+		pitfalls, wasScraped := scraped[key]
+		switch {
+		case !known:
+			entry.Status = coverageMissing
+			missing = append(missing, dependency)
+		case !enabled:
+			entry.Status = coverageDisabled
+		case !wasScraped:
+			entry.Status = coveragePending
+		default:
+			entry.Status, entry.Pitfalls = coverageCovered, pitfalls
+		}
+		coverage[i] = entry
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(coverage, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		printDependencyCoverage(coverage)
+	}
+
+	if !c.Bool("add") || len(missing) == 0 {
+		return nil
+	}
+	p := &prompter{in: bufio.NewReader(os.Stdin), yes: c.Bool("yes")}
+	add, err := p.confirm(fmt.Sprintf("将 %d 个缺失的依赖仓库加入 %s?", len(missing), c.String("config")), false)
+	if err != nil || !add {
+		return err
+	}
+	if err := addRepositories(c.String("config"), missing); err != nil {
+		return err
+	}
+	fmt.Printf("📝 已加入 %d 个仓库, 下次抓取时生效\n", len(missing))
+	return nil
+}
+
+// printDependencyCoverage prints coverage shares for direct and all
+// dependencies, then the dependencies that are not covered
+func printDependencyCoverage(coverage []dependencyCoverage) {
+	var direct, directCovered, covered int
+	for _, entry := range coverage {
+		if entry.Direct {
+			direct++
+		}
+		if entry.Status == coverageCovered {
+			covered++
+			if entry.Direct {
+				directCovered++
+			}
+		}
+	}
+	fmt.Println("📦 依赖覆盖:")
+	if direct > 0 {
+		fmt.Printf("   直接依赖: %d/%d (%.1f%%)\n", directCovered, direct, float64(directCovered)/float64(direct)*100)
+	}
+	fmt.Printf("   全部依赖: %d/%d (%.1f%%)\n", covered, len(coverage), float64(covered)/float64(len(coverage))*100)
+
+	labels := map[string]string{
+		coverageCovered:  "已覆盖",
+		coveragePending:  "已配置, 尚未抓取",
+		coverageDisabled: "已配置, 未启用",
+		coverageMissing:  "缺失",
+	}
+	for _, status := range []string{coverageMissing, coverageDisabled, coveragePending, coverageCovered} {
+		var lines []string
+		for _, entry := range coverage {
+			if entry.Status != status {
+				continue
+			}
+			kind := "间接"
+			if entry.Direct {
+				kind = "直接"
+			}
+			line := fmt.Sprintf("  %-40s %s  %s", entry.Repository, ecosystemLabel(entry.Ecosystem), kind)
+			if status == coverageCovered {
+				line += fmt.Sprintf("  %d 个坑", entry.Pitfalls)
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) > 0 {
+			fmt.Printf("\n%s (%d):\n%s\n", labels[status], len(lines), strings.Join(lines, "\n"))
+		}
+	}
+}
+
+// addRepositories inserts repositories at the top of the repositories list
+// of a config file, keeping the rest of the file and its comments
+func addRepositories(configPath string, repos []dependencyRepo) error {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	content := string(data)
+	const header = "repositories:\n"
+	i := strings.Index(content, "\n"+header)
+	if strings.HasPrefix(content, header) {
+		i = -1
+	} else if i < 0 {
+		return fmt.Errorf("no repositories list found in %s", configPath)
+	}
+	at := i + 1 + len(header)
+
+	entries := renderRepoEntries(repos, ecosystemKeywords)
+	updated := content[:at] + entries + content[at:]
+	if err := os.WriteFile(configPath, []byte(updated), 0600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if _, err := loadConfig(configPath); err != nil {
+		os.WriteFile(configPath, data, 0600)
+		return fmt.Errorf("updated config is invalid, restored the original: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/query"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// reprocessCommand recomputes derived fields of locally stored issues
+func reprocessCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "reprocess",
+		Usage: "用当前规则和配置重新计算已保存问题的分类、平台和评分",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "outdated",
+				Usage: "只重新计算由旧版本规则或配置产生的字段",
+			},
+			&cli.BoolFlag{
+				Name:  "versions",
+				Usage: "只显示各字段的版本分布, 不做修改",
+			},
+			&cli.StringFlag{
+				Name:  "filter",
+				Usage: "只重新计算匹配该查询的问题, 语法同 search, 例如 \"category:security repo:vllm-project/*\"",
+			},
+		},
+		Action: runReprocess,
+	}
+}
+
+// runReprocess prints the version mix of stored issues and recomputes their
+// derived fields
+func runReprocess(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	if err := store.AttachComments(issues); err != nil {
+		return fmt.Errorf("failed to load stored comments: %w", err)
+	}
+
+	scraperInstance := scraper.NewScraper(config)
+	useCorrections(scraperInstance, store)
+	printVersionMix(issues, scraperInstance.Versions())
+	if c.Bool("versions") {
+		return nil
+	}
+
+	// Reprocess a filtered subset on copies, then put them back in place
+	subset := issues
+	var positions map[string][]int
+	if filter := c.String("filter"); filter != "" {
+		search, err := query.Parse(filter)
+		if err != nil {
+			return fmt.Errorf("invalid --filter: %w", err)
+		}
+		subset, positions = selectIssues(issues, search)
+		matched := 0
+		for _, indexes := range positions {
+			matched += len(indexes)
+		}
+		fmt.Printf("\n匹配 --filter 的问题: %d\n", matched)
+	}
+
+	var count int
+	if c.Bool("outdated") {
+		count = scraperInstance.ReprocessOutdated(subset)
+	} else {
+		scraperInstance.ClassifyIssues(subset)
+		count = scraperInstance.ScoreIssues(subset)
+	}
+	for repoName, indexes := range positions {
+		for i, index := range indexes {
+			issues[repoName][index] = subset[repoName][i]
+		}
+	}
+	printFailures(append(scraperInstance.Failures(), malformedIssueFailures(store)...))
+	if count == 0 {
+		fmt.Println("\n没有需要重新计算的问题")
+		return nil
+	}
+
+	if err := store.SaveIssues(issues); err != nil {
+		return fmt.Errorf("failed to save issues: %w", err)
+	}
+	recordClassifications(scraperInstance, store, subset)
+	fmt.Printf("\n已重新计算 %d 个问题\n", count)
+	return nil
+}
+
+// selectIssues copies the issues matching search, returning the copies and
+// their positions in the corpus
+func selectIssues(issues map[string][]model.Issue, search query.AdvancedSearch) (map[string][]model.Issue, map[string][]int) {
+	subset := make(map[string][]model.Issue)
+	positions := make(map[string][]int)
+	for repoName, repoIssues := range issues {
+		for i, issue := range repoIssues {
+			if search.Match(issue) {
+				subset[repoName] = append(subset[repoName], issue)
+				positions[repoName] = append(positions[repoName], i)
+			}
+		}
+	}
+	return subset, positions
+}
+
+// printVersionMix prints how many stored issues carry each version of every
+// derived field, marking the current versions
+func printVersionMix(issues map[string][]model.Issue, current scraper.Versions) {
+	fields := []struct {
+		name    string
+		current string
+		version func(model.Issue) string
+	}{
+		{"分类/优先级", current.Classification, func(i model.Issue) string { return i.ClassificationVersion }},
+		{"评分", current.Score, func(i model.Issue) string { return i.ScoreVersion }},
+		{"平台", current.Platforms, func(i model.Issue) string { return i.PlatformsVersion }},
+	}
+
+	for _, field := range fields {
+		counts := make(map[string]int)
+		for _, repoIssues := range issues {
+			for _, issue := range repoIssues {
+				counts[field.version(issue)]++
+			}
+		}
+
+		versions := make([]string, 0, len(counts))
+		for version := range counts {
+			versions = append(versions, version)
+		}
+		sort.Strings(versions)
+
+		fmt.Printf("%s:\n", field.name)
+		for _, version := range versions {
+			label := version
+			if label == "" {
+				label = "(未标记)"
+			}
+			marker := ""
+			if version == field.current {
+				marker = "  (当前)"
+			}
+			fmt.Printf("  %-14s %6d%s\n", label, counts[version], marker)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/analytics"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// scoreCommand groups scoring maintenance subcommands
+func scoreCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "score",
+		Usage: "评分相关工具",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "calibrate",
+				Usage: "根据人工标注校准评分权重并写回配置文件",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "labels",
+						Usage:    "标注 CSV 文件 (列: repository,number,relevant)",
+						Required: true,
+					},
+					&cli.Float64Flag{
+						Name:  "threshold",
+						Usage: "评估使用的分数阈值 (默认使用 filter.min_score)",
+					},
+					&cli.Float64Flag{
+						Name:  "step",
+						Value: 5,
+						Usage: "网格搜索的权重步长",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "只报告结果, 不写回配置文件",
+					},
+				},
+				Action: runScoreCalibrate,
+			},
+		},
+	}
+}
+
+// runScoreCalibrate fits the component weights to labeled issues and
+// writes them back to the config file
+func runScoreCalibrate(c *cli.Context) error {
+	configPath := c.String("config")
+	config, err := loadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	threshold := config.Filter.MinScore
+	if c.IsSet("threshold") {
+		threshold = c.Float64("threshold")
+	}
+	step := c.Float64("step")
+	if step <= 0 || step > 50 {
+		return fmt.Errorf("--step must be between 0 and 50")
+	}
+
+	file, err := os.Open(c.String("labels"))
+	if err != nil {
+		return fmt.Errorf("failed to open labels: %w", err)
+	}
+	labels, err := scraper.ReadRelevanceLabels(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read labels: %w", err)
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+
+	scorer := scraper.NewScorer()
+	if config.Scoring.ReporterReputationWeight > 0 {
+		reporters, err := store.LoadReporters()
+		if err != nil {
+			log.Printf("⚠️  警告: 未能读取报告者信誉: %v", err)
+		}
+		scorer.SetReporterReputation(analytics.ReputationMap(reporters), config.Scoring.ReporterReputationWeight)
+	}
+
+	byRef := make(map[string]model.Issue)
+	for _, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			byRef[model.IssueRef(issue.Repository, issue.Number)] = issue
+		}
+	}
+
+	var samples []scraper.CalibrationSample
+	var missing, relevant int
+	for _, label := range labels {
+		issue, ok := byRef[model.IssueRef(label.Repository, label.Number)]
+		if !ok {
+			missing++
+			continue
+		}
+		shares, fixed := scorer.Components(&issue)
+		samples = append(samples, scraper.CalibrationSample{Shares: shares, Fixed: fixed, Relevant: label.Relevant})
+		if label.Relevant {
+			relevant++
+		}
+	}
+	if missing > 0 {
+		fmt.Printf("⚠️  %d 条标注的问题不在问题库中, 已跳过\n", missing)
+	}
+	if relevant == 0 || relevant == len(samples) {
+		return fmt.Errorf("labels must include both relevant and irrelevant issues found in the store (%d of %d relevant)", relevant, len(samples))
+	}
+
+	current := config.Scoring.Weights()
+	before := scraper.Evaluate(samples, current, threshold)
+	tuned, after := scraper.Calibrate(samples, current, threshold, step)
+
+	fmt.Printf("📐 标注样本: %d (相关 %d), 阈值 %.1f\n\n", len(samples), relevant, threshold)
+	fmt.Printf("%-10s %8s %8s %8s %8s %8s %8s %8s %8s\n", "", "关键词", "模式", "标签", "状态", "活跃度", "精确率", "召回率", "F1")
+	printCalibrationRow("当前", current, before)
+	printCalibrationRow("校准后", tuned, after)
+
+	if tuned == current {
+		fmt.Println("\n当前权重已是最优, 无需修改")
+		return nil
+	}
+	if c.Bool("dry-run") {
+		return nil
+	}
+
+	err = setConfigValues(configPath, "scoring", map[string]string{
+		"keyword_weight":  formatWeight(tuned.Keyword),
+		"pattern_weight":  formatWeight(tuned.Pattern),
+		"label_weight":    formatWeight(tuned.Label),
+		"status_weight":   formatWeight(tuned.Status),
+		"activity_weight": formatWeight(tuned.Activity),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	fmt.Printf("\n✅ 已将校准后的权重写入 %s, 运行 reprocess 以重新评分\n", configPath)
+	return nil
+}
+
+func printCalibrationRow(name string, weights scraper.ScoreComponents, eval scraper.Evaluation) {
+	fmt.Printf("%-10s %8.1f %8.1f %8.1f %8.1f %8.1f %7.1f%% %7.1f%% %8.3f\n", name,
+		weights.Keyword, weights.Pattern, weights.Label, weights.Status, weights.Activity,
+		eval.Precision()*100, eval.Recall()*100, eval.F1())
+}
+
+func formatWeight(weight float64) string {
+	return strconv.FormatFloat(weight, 'f', -1, 64)
+}
+
+// configValuePattern matches a "key: value  # comment" line of a YAML section
+var configValuePattern = regexp.MustCompile(`^(\s+)([A-Za-z0-9_]+):(\s*)([^#]*?)(\s*#.*)?$`)
+
+// setConfigValues rewrites scalar values of a top-level YAML section in
+// place, keeping comments and layout, and adds keys the section lacks
+func setConfigValues(path, section string, values map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+
+	start := -1
+	for i, line := range lines {
+		if strings.TrimRight(line, " ") == section+":" {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		lines = append(lines, section+":")
+		start = len(lines) - 1
+	}
+
+	remaining := make(map[string]bool)
+	for key := range values {
+		remaining[key] = true
+	}
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+		if line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "#") {
+			break
+		}
+		match := configValuePattern.FindStringSubmatch(line)
+		if match == nil || !remaining[match[2]] {
+			continue
+		}
+		value := values[match[2]]
+		// Keep trailing comments aligned where possible
+		padding := ""
+		if match[5] != "" && len(match[4]) > len(value) {
+			padding = strings.Repeat(" ", len(match[4])-len(value))
+		}
+		lines[i] = match[1] + match[2] + ":" + match[3] + value + padding + match[5]
+		delete(remaining, match[2])
+	}
+
+	var added []string
+	for key := range values {
+		if remaining[key] {
+			added = append(added, "  "+key+": "+values[key])
+		}
+	}
+	if len(added) > 0 {
+		sort.Strings(added)
+		lines = append(lines[:start+1], append(added, lines[start+1:]...)...)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), info.Mode())
+}
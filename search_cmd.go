@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/output"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/query"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// searchCommand searches stored issues with the compact query syntax
+func searchCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "search",
+		Usage:     "使用查询语法搜索本地问题库",
+		ArgsUsage: `repo:owner/name category:performance score:>20 label:regression "memory leak" -label:question`,
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "limit",
+				Value: 20,
+				Usage: "最多返回的结果数",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Value: "table",
+				Usage: "结果格式 (table/csv/ndjson), csv/ndjson 直接写到标准输出",
+			},
+			&cli.StringFlag{
+				Name:  "match",
+				Usage: "关键词匹配方式 (any/all/phrase), 默认 any; 也可在查询中写 match:all",
+			},
+			&cli.StringFlag{
+				Name:  "sort",
+				Usage: "排序方式 (relevance/score/updated/created), 含文本查询时默认 relevance",
+			},
+			&cli.StringFlag{
+				Name:  "fields",
+				Usage: "csv/ndjson 只输出这些字段 (逗号分隔, 如 number,title,score,category,html_url)",
+			},
+			&cli.BoolFlag{
+				Name:  "comments",
+				Usage: "同时搜索已抓取的评论, 结果附带字段 discussion",
+			},
+			&cli.BoolFlag{
+				Name:  "explain",
+				Usage: "输出查询解析结果、各条件命中数和耗时 (写到标准错误)",
+			},
+		},
+		Action: runSearch,
+	}
+}
+
+// runSearch prints stored issues matching the query, highest score first
+func runSearch(c *cli.Context) error {
+	search, err := query.Parse(strings.Join(c.Args().Slice(), " "))
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	if mode := c.String("match"); mode != "" {
+		if !query.ValidMatchMode(mode) {
+			return fmt.Errorf("--match must be one of: %v", query.MatchModes)
+		}
+		search.Mode = mode
+	}
+
+	var fields []string
+	if spec := c.String("fields"); spec != "" {
+		if fields, err = output.ParseFields(spec); err != nil {
+			return fmt.Errorf("invalid --fields: %w", err)
+		}
+	}
+
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	start := time.Now()
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	if c.Bool("comments") {
+		if err := store.AttachComments(issues); err != nil {
+			return fmt.Errorf("failed to load stored comments: %w", err)
+		}
+	}
+	config.Portfolios.MarkCorpus(issues)
+	loaded := time.Since(start)
+
+	sortBy := c.String("sort")
+	if sortBy == "" {
+		sortBy = config.Output.SortBy
+		if search.HasText() {
+			sortBy = query.SortRelevance
+		}
+	}
+
+	weights := query.RelevanceWeights{
+		Text:    config.Search.RelevanceWeights.Text,
+		Score:   config.Search.RelevanceWeights.Score,
+		Recency: config.Search.RelevanceWeights.Recency,
+		Phrase:  config.Search.RelevanceWeights.Phrase,
+	}
+
+	now := time.Now()
+	model.ApplyScoreDecay(issues, config.Scoring.DecayHalfLife(), now)
+	if !config.Output.IncludeDeleted {
+		issues, _ = model.ExcludeDeletedUpstream(issues)
+	}
+
+	start = time.Now()
+	results := search.Filter(issues)
+	filtered := time.Since(start)
+	start = time.Now()
+	query.NewIndex(issues).Sort(search, results, sortBy, weights, now)
+	sorted := time.Since(start)
+
+	if c.Bool("explain") {
+		printExplanation(search.Explain(issues), sortBy, loaded, filtered, sorted)
+	}
+
+	if limit := c.Int("limit"); limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	switch format := c.String("format"); {
+	case format == "csv" && fields != nil:
+		return output.WriteCSVFields(os.Stdout, results, fields)
+	case format == "csv":
+		return output.WriteCSV(os.Stdout, results)
+	case format == "ndjson" && fields != nil:
+		return output.WriteNDJSONFields(os.Stdout, results, fields)
+	case format == "ndjson":
+		return output.WriteNDJSON(os.Stdout, results)
+	}
+
+	fmt.Printf("匹配问题数: %d\n", len(results))
+	for i, issue := range results {
+		fmt.Printf("%2d. [%.1f] %s#%d %s\n    %s\n", i+1, issue.Score, issue.Repository, issue.Number, issue.Title, issue.URL)
+	}
+
+	return nil
+}
+
+// printExplanation prints how a search was evaluated: each clause with the
+// number of issues it matches alone, and where the time went
+func printExplanation(explanation query.Explanation, sortBy string, loaded, filtered, sorted time.Duration) {
+	kinds := map[string]string{
+		query.ClauseRequired: "必须满足",
+		query.ClauseExcluded: "排除",
+		query.ClauseAnyOf:    "满足其一",
+	}
+
+	w := os.Stderr
+	fmt.Fprintf(w, "查询计划 (关键词匹配: %s, 排序: %s)\n", explanation.Mode, sortBy)
+	for _, clause := range explanation.Clauses {
+		kind := kinds[clause.Kind]
+		if clause.Group != "" {
+			kind += " [" + clause.Group + "]"
+		}
+		fmt.Fprintf(w, "  %-32s %-22s 单独命中 %d/%d\n", clause.Clause, kind, clause.Matched, explanation.Scanned)
+	}
+	if len(explanation.Clauses) == 0 {
+		fmt.Fprintln(w, "  (无条件, 匹配全部问题)")
+	}
+	fmt.Fprintf(w, "扫描 %d 个问题, 匹配 %d 个\n", explanation.Scanned, explanation.Matched)
+	fmt.Fprintf(w, "耗时: 读取 %s, 过滤 %s, 排序 %s\n\n",
+		loaded.Round(time.Microsecond), filtered.Round(time.Microsecond), sorted.Round(time.Microsecond))
+}
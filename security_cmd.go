@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// unknownProduct groups CVEs without NVD product data
+const unknownProduct = "(未知依赖)"
+
+// securityCommand reports on security-related pitfalls
+func securityCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "security",
+		Usage: "安全相关问题的报告",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "report",
+				Usage: "按受影响依赖汇总引用了 CVE 的问题",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "fetch",
+						Usage: "先从 NVD 获取尚未保存的 CVE 元数据",
+					},
+					&cli.BoolFlag{
+						Name:  "refresh",
+						Usage: "与 --fetch 一起使用, 重新获取所有 CVE 的元数据",
+					},
+				},
+				Action: runSecurityReport,
+			},
+		},
+	}
+}
+
+// productReport summarizes the CVE-linked pitfalls of one dependency
+type productReport struct {
+	product  string
+	cves     []model.CVE
+	issues   map[string]bool
+	maxScore float64
+}
+
+// runSecurityReport prints, per affected dependency, its CVEs and how many
+// stored issues reference them
+func runSecurityReport(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	cves, err := store.LoadCVEs()
+	if err != nil {
+		return fmt.Errorf("failed to load CVEs: %w", err)
+	}
+
+	if c.Bool("fetch") {
+		fetched, err := scraper.FetchCVEs(c.Context, config.NVD, issues, cves, c.Bool("refresh"))
+		if saveErr := store.SaveCVEs(cves); saveErr != nil {
+			return fmt.Errorf("failed to save CVEs: %w", saveErr)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("已从 NVD 获取 %d 个 CVE\n\n", fetched)
+	}
+
+	reports := make(map[string]*productReport)
+	missing := 0
+	for _, id := range scraper.ReferencedCVEs(issues) {
+		cve, ok := cves[id]
+		if !ok {
+			missing++
+			cve = model.CVE{ID: id}
+		}
+		products := cve.Products
+		if len(products) == 0 {
+			products = []string{unknownProduct}
+		}
+		for _, product := range products {
+			report := reports[product]
+			if report == nil {
+				report = &productReport{product: product, issues: make(map[string]bool)}
+				reports[product] = report
+			}
+			report.cves = append(report.cves, cve)
+			if cve.Score > report.maxScore {
+				report.maxScore = cve.Score
+			}
+		}
+	}
+	if len(reports) == 0 {
+		fmt.Println("没有引用 CVE 的问题")
+		return nil
+	}
+
+	for repoName, repoIssues := range issues {
+		for _, issue := range repoIssues {
+			for _, id := range issue.CVEs {
+				products := cves[id].Products
+				if len(products) == 0 {
+					products = []string{unknownProduct}
+				}
+				for _, product := range products {
+					reports[product].issues[model.IssueRef(repoName, issue.Number)] = true
+				}
+			}
+		}
+	}
+
+	sorted := make([]*productReport, 0, len(reports))
+	for _, report := range reports {
+		sorted = append(sorted, report)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].maxScore != sorted[j].maxScore {
+			return sorted[i].maxScore > sorted[j].maxScore
+		}
+		return sorted[i].product < sorted[j].product
+	})
+
+	for _, report := range sorted {
+		fmt.Printf("%s  (%d 个 CVE, %d 个问题, 最高 CVSS %.1f)\n", report.product, len(report.cves), len(report.issues), report.maxScore)
+		for _, cve := range report.cves {
+			severity := cve.Severity
+			if severity == "" {
+				severity = "-"
+			}
+			fmt.Printf("  %-16s %-8s %4.1f", cve.ID, severity, cve.Score)
+			if versions := productVersions(cve, report.product); versions != "" {
+				fmt.Printf("  影响版本: %s", versions)
+			}
+			fmt.Println()
+		}
+	}
+
+	if missing > 0 {
+		fmt.Printf("\n%d 个 CVE 尚无元数据, 可使用 --fetch 从 NVD 获取\n", missing)
+	}
+	return nil
+}
+
+// productVersions returns the affected version ranges of a CVE for a product
+func productVersions(cve model.CVE, product string) string {
+	var versions []string
+	for _, affected := range cve.AffectedVersions {
+		if rest, ok := strings.CutPrefix(affected, product+" "); ok {
+			versions = append(versions, rest)
+		}
+	}
+	return strings.Join(versions, ", ")
+}
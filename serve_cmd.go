@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/server"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// serveCommand serves the stored corpus over HTTP
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "启动 HTTP 服务 (网页界面、Slack/Mattermost 斜杠命令 /pitfall 和问题查询 REST API)",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "addr",
+				Usage: "监听地址 (默认使用 serve.addr)",
+			},
+		},
+		Action: runServe,
+	}
+}
+
+// runServe serves until interrupted
+func runServe(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if addr := c.String("addr"); addr != "" {
+		config.Serve.Addr = addr
+	}
+	if config.Serve.SlackSigningSecret == "" && config.Serve.MattermostToken == "" {
+		fmt.Println("⚠️  未配置 serve.slack_signing_secret 或 serve.mattermost_token, 斜杠命令请求将全部被拒绝")
+	}
+
+	store := storage.NewStore(config.Storage.Dir)
+	srv := server.NewServer(config.Serve, func() (map[string][]model.Issue, error) {
+		issues, err := store.LoadIssues()
+		if err != nil {
+			return nil, err
+		}
+		config.Portfolios.MarkCorpus(issues)
+		return issues, nil
+	})
+	// Corrections are read here and again by every refresh
+	scraperInstance := scraper.NewScraper(config)
+	useCorrections(scraperInstance, store)
+	srv.SetClassifier(scraperInstance.ExplainClassification)
+	srv.SetClassifications(store.LoadClassifications)
+	srv.SetEscalations(store.LoadEscalations)
+	srv.SetTombstones(store.LoadTombstones)
+	srv.SetPoolMetrics(store.LoadPoolSamples)
+	srv.SetRefresher(func(ctx context.Context, repoName string, number int) (model.Issue, error) {
+		return refreshIssue(ctx, scraperInstance, store, repoName, number)
+	})
+	srv.SetEvents()
+	srv.SetTriage(scraper.Categories(), scraper.Priorities, func(issue model.Issue, category, priority, author string) (model.Issue, error) {
+		return triageIssue(config, store, issue, category, priority, author)
+	})
+	// Handlers run concurrently and other processes write the same files, so
+	// writes go through the store lock
+	srv.SetDuplicateReview(store.LoadDuplicateLinks, store.LoadDuplicateReviews, func(review model.DuplicateReview) error {
+		return store.Update(func() error { return store.ReviewDuplicate(review) })
+	})
+	srv.SetReports(config.Output.OutputDir)
+	srv.SetUI()
+
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go watchStoreEvents(ctx, store, storeWatchInterval, srv.Publish)
+
+	fmt.Printf("🌐 服务已启动: %s (网页界面: GET /ui/, 斜杠命令: POST /commands/pitfall, REST API: GET /api/issues、/api/issues/{id}、POST /api/issues/{id}/refresh、/api/issues/{id}/triage、/api/search、/api/repositories、/api/stats、/api/classifications、/api/escalations、/api/tombstones、/api/duplicates、/api/reports, 实时事件 (SSE): GET /api/events, Prometheus: GET /metrics)\n", config.Serve.Addr)
+	return srv.ListenAndServe(ctx)
+}
+
+// storeWatchInterval is how often serve mode checks the store for changes
+// to stream to /api/events
+const storeWatchInterval = 2 * time.Second
+
+// watchStoreEvents publishes an event whenever another process ingests,
+// classifies or links issues in the store, until ctx is cancelled:
+// ingested lists new and updated issues, classified the latest
+// classification run and deduplicated the new duplicate links
+func watchStoreEvents(ctx context.Context, store *storage.Store, interval time.Duration, publish func(server.Event)) {
+	issueVersions := func() map[string]time.Time {
+		versions := make(map[string]time.Time)
+		issues, err := store.LoadIssues()
+		if err != nil {
+			log.Printf("Error loading issues for events: %v", err)
+			return nil
+		}
+		for repoName, repoIssues := range issues {
+			for _, issue := range repoIssues {
+				versions[model.IssueRef(repoName, issue.Number)] = issue.UpdatedAt
+			}
+		}
+		return versions
+	}
+	latestRun := func() (time.Time, int) {
+		records, err := store.LoadClassifications()
+		if err != nil || len(records) == 0 {
+			return time.Time{}, 0
+		}
+		runAt, count := records[len(records)-1].RunAt, 0
+		for _, record := range records {
+			if record.RunAt.Equal(runAt) {
+				count++
+			}
+		}
+		return runAt, count
+	}
+	linkKeys := func() map[string]model.DuplicateLink {
+		links, err := store.LoadDuplicateLinks()
+		if err != nil {
+			log.Printf("Error loading duplicate links for events: %v", err)
+			return nil
+		}
+		keys := make(map[string]model.DuplicateLink, len(links))
+		for _, link := range links {
+			keys[model.IssueRef(link.Repository, link.IssueNumber)] = link
+		}
+		return keys
+	}
+
+	versions, links := issueVersions(), linkKeys()
+	runAt, _ := latestRun()
+	store.Watch(ctx, interval, func(kind string) {
+		switch kind {
+		case storage.ChangeIssues:
+			current := issueVersions()
+			if current == nil {
+				return
+			}
+			added, updated := []string{}, []string{}
+			for ref, updatedAt := range current {
+				previous, known := versions[ref]
+				switch {
+				case !known:
+					added = append(added, ref)
+				case !updatedAt.Equal(previous):
+					updated = append(updated, ref)
+				}
+			}
+			versions = current
+			if len(added)+len(updated) > 0 {
+				sort.Strings(added)
+				sort.Strings(updated)
+				publish(server.Event{Type: "ingested", Data: map[string]interface{}{"new": added, "updated": updated, "issues": len(current)}})
+			}
+		case storage.ChangeClassifications:
+			latest, count := latestRun()
+			if count > 0 && !latest.Equal(runAt) {
+				runAt = latest
+				publish(server.Event{Type: "classified", Data: map[string]interface{}{"run_at": latest, "issues": count}})
+			}
+		case storage.ChangeDuplicates:
+			current := linkKeys()
+			if current == nil {
+				return
+			}
+			added := []map[string]string{}
+			for ref, link := range current {
+				if _, known := links[ref]; !known {
+					added = append(added, map[string]string{"duplicate": ref, "canonical": model.IssueRef(link.CanonicalRepository, link.CanonicalNumber), "source": link.Source})
+				}
+			}
+			links = current
+			if len(added) > 0 {
+				sort.Slice(added, func(i, j int) bool { return added[i]["duplicate"] < added[j]["duplicate"] })
+				publish(server.Event{Type: "deduplicated", Data: map[string]interface{}{"links": added, "total": len(current)}})
+			}
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/similarity"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// similarCommand searches the stored corpus for issues similar to free text
+func similarCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "similar",
+		Usage: "在本地问题库中查找与描述相似的问题",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "text",
+				Usage:    "问题描述, 例如 \"connection pool exhausted under load\"",
+				Required: true,
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Value: 10,
+				Usage: "最多返回的结果数",
+			},
+			&cli.Float64Flag{
+				Name:  "min-similarity",
+				Value: 0.05,
+				Usage: "最低相似度 (0-1)",
+			},
+		},
+		Action: runSimilar,
+	}
+}
+
+// runSimilar prints corpus issues ranked by similarity to the given text
+func runSimilar(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	issues, err := storage.NewStore(config.Storage.Dir).LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+
+	engine := similarity.NewEngine(issues)
+	matches := engine.Search(c.String("text"), c.Int("limit"), c.Float64("min-similarity"))
+
+	if len(matches) == 0 {
+		fmt.Println("没有找到相似的问题")
+		return nil
+	}
+
+	for i, match := range matches {
+		fmt.Printf("%2d. [%.2f] %s#%d %s\n    %s\n",
+			i+1, match.Similarity, match.Issue.Repository, match.Issue.Number, match.Issue.Title, match.Issue.URL)
+	}
+
+	return nil
+}
+
+// linkCrossSource links stored issues from different sources that look like
+// the same pitfall into the duplicate links
+func linkCrossSource(store *storage.Store, minSimilarity float64) error {
+	issues, err := store.LoadIssues()
+	if err != nil {
+		return err
+	}
+	if err := store.AttachComments(issues); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var links []model.DuplicateLink
+	for _, pair := range similarity.NewEngine(issues).CrossSourcePairs(minSimilarity) {
+		links = append(links, model.DuplicateLink{
+			Repository:          pair.A.Repository,
+			IssueNumber:         pair.A.Number,
+			CanonicalRepository: pair.B.Repository,
+			CanonicalNumber:     pair.B.Number,
+			Source:              model.DuplicateSourceCrossSource,
+			LinkedAt:            now,
+		})
+	}
+	return store.AddDuplicateLinks(links)
+}
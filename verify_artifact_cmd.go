@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/output"
+)
+
+// verifyArtifactCommand checks reports and exports against their checksum
+// manifest and signature
+func verifyArtifactCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "verify-artifact",
+		Usage:     "校验报告或导出文件的 SHA-256 清单和签名",
+		ArgsUsage: "<文件或 SHA256SUMS/.sha256 清单>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "public-key",
+				Usage: "minisign 公钥文件, 默认使用配置中的 integrity.public_key",
+			},
+		},
+		Action: runVerifyArtifact,
+	}
+}
+
+// runVerifyArtifact verifies the manifest signature when a public key is
+// available and then every checksum the manifest lists
+func runVerifyArtifact(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("usage: verify-artifact <file>")
+	}
+
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	manifest, err := output.FindManifest(c.Args().First())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("清单: %s\n", manifest)
+
+	publicKey := c.String("public-key")
+	if publicKey == "" {
+		publicKey = config.Integrity.PublicKey
+	}
+	if publicKey != "" {
+		if err := output.VerifySignature(c.Context, publicKey, manifest); err != nil {
+			return fmt.Errorf("签名校验失败: %w", err)
+		}
+		fmt.Println("✅ 签名有效")
+	} else {
+		fmt.Println("⚠️  未配置公钥, 跳过签名校验")
+	}
+
+	results, err := output.VerifyChecksums(manifest)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, result := range results {
+		if result.OK {
+			fmt.Printf("  OK    %s\n", result.File)
+			continue
+		}
+		failed++
+		fmt.Printf("  FAIL  %s: %s\n", result.File, result.Error)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d/%d 个文件校验失败", failed, len(results))
+	}
+	fmt.Printf("✅ %d 个文件校验通过\n", len(results))
+	return nil
+}
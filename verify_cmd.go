@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// verifyCommand reconciles stored issues of a repository with GitHub
+func verifyCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "verify",
+		Usage: "核对本地保存的问题与 GitHub 上的实际情况",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "repo",
+				Usage:    "要核对的已配置仓库 (owner/repo)",
+				Required: true,
+			},
+			&cli.BoolFlag{
+				Name:  "rescrape",
+				Usage: "发现差异时重新抓取该仓库",
+			},
+		},
+		Action: runVerify,
+	}
+}
+
+// runVerify prints the discrepancies of one repository and optionally re-scrapes it
+func runVerify(c *cli.Context) error {
+	config, err := loadConfig(c.String("config"))
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var repoConfig *scraper.RepositoryConfig
+	for i := range config.Repositories {
+		if strings.EqualFold(config.Repositories[i].Name, c.String("repo")) {
+			repoConfig = &config.Repositories[i]
+			break
+		}
+	}
+	if repoConfig == nil {
+		return fmt.Errorf("repository %s is not configured", c.String("repo"))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	store := storage.NewStore(config.Storage.Dir)
+	stored, err := store.LoadIssues()
+	if err != nil {
+		return fmt.Errorf("failed to load stored issues: %w", err)
+	}
+
+	result, err := scraper.NewScraper(config).Reconcile(ctx, *repoConfig, stored[repoConfig.Name])
+	if err != nil {
+		return fmt.Errorf("failed to verify %s: %w", repoConfig.Name, err)
+	}
+	printReconciliation(result)
+
+	marked, moved, err := store.ApplyUpstreamChanges(repoConfig.Name, result.Deleted, result.Transferred)
+	if err != nil {
+		return fmt.Errorf("failed to record upstream changes: %w", err)
+	}
+	if marked > 0 || moved > 0 {
+		fmt.Printf("\n已标记 %d 个上游已删除的问题, 迁移 %d 个已转移的问题\n", marked, moved)
+	}
+
+	if !result.HasDiscrepancies() {
+		fmt.Println("\n✅ 本地数据与 GitHub 一致")
+		return nil
+	}
+	if !c.Bool("rescrape") {
+		fmt.Println("\n可使用 --rescrape 重新抓取该仓库")
+		return nil
+	}
+
+	// Widen the window so the re-scrape covers every live issue
+	if result.MissedIssues > 0 {
+		repoConfig.MaxIssues = result.LiveOpen + result.LiveClosed
+	}
+	count, err := rescrapeRepository(ctx, config, store, *repoConfig)
+	if err != nil {
+		return fmt.Errorf("failed to re-scrape %s: %w", repoConfig.Name, err)
+	}
+	fmt.Printf("\n🔄 已重新抓取 %s, 保存 %d 个问题\n", repoConfig.Name, count)
+	return nil
+}
+
+// printReconciliation prints stored and live counts and every discrepancy found
+func printReconciliation(result scraper.Reconciliation) {
+	fmt.Printf("仓库: %s\n", result.Repository)
+	fmt.Printf("  本地保存: 开放 %d, 已关闭 %d\n", result.StoredOpen, result.StoredClosed)
+	fmt.Printf("  GitHub:   开放 %d, 已关闭 %d\n", result.LiveOpen, result.LiveClosed)
+
+	if result.MissedIssues > 0 {
+		fmt.Printf("  ⚠️  抓取窗口 (max_issues=%d) 之外还有 %d 个问题未抓取\n", result.Window, result.MissedIssues)
+	}
+	if len(result.StateChanged) > 0 {
+		fmt.Printf("  ⚠️  %d 个问题状态已变化: %s\n", len(result.StateChanged), joinNumbers(result.StateChanged))
+	}
+	if len(result.Deleted) > 0 {
+		fmt.Printf("  ⚠️  %d 个问题已在上游删除: %s\n", len(result.Deleted), joinNumbers(result.Deleted))
+	}
+	if len(result.Transferred) > 0 {
+		numbers := make([]int, 0, len(result.Transferred))
+		for number := range result.Transferred {
+			numbers = append(numbers, number)
+		}
+		sort.Ints(numbers)
+		fmt.Printf("  ⚠️  %d 个问题已转移:\n", len(numbers))
+		for _, number := range numbers {
+			fmt.Printf("      #%d -> %s\n", number, result.Transferred[number])
+		}
+	}
+	if len(result.Unchecked) > 0 {
+		fmt.Printf("  ℹ️  %d 个问题未能核对: %s\n", len(result.Unchecked), joinNumbers(result.Unchecked))
+	}
+}
+
+// rescrapeRepository scrapes, filters and enriches a single repository and
+// replaces its stored issues, returning how many were saved
+func rescrapeRepository(ctx context.Context, config scraper.Config, store *storage.Store, repoConfig scraper.RepositoryConfig) (int, error) {
+	repoConfig.Enabled = true
+	config.Repositories = []scraper.RepositoryConfig{repoConfig}
+	config.StackOverflow.Enabled = false
+
+	scraperInstance := scraper.NewScraper(config)
+	allIssues, err := scraperInstance.ScrapeRepositories(ctx, config)
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := allIssues[repoConfig.Name]; !ok {
+		return 0, fmt.Errorf("no issues fetched")
+	}
+
+	filteredIssues := scraperInstance.FilterAndScoreIssues(allIssues, config)
+	if config.Enrich.FixLinks || config.Enrich.Workarounds || config.Enrich.Duplicates {
+		scraperInstance.EnrichIssues(ctx, filteredIssues)
+	}
+	return len(filteredIssues[repoConfig.Name]), store.SaveIssues(filteredIssues)
+}
+
+// joinNumbers formats issue numbers as #1, #2, ...
+func joinNumbers(numbers []int) string {
+	refs := make([]string, len(numbers))
+	for i, number := range numbers {
+		refs[i] = fmt.Sprintf("#%d", number)
+	}
+	return strings.Join(refs, ", ")
+}
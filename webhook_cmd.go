@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/model"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/scraper"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/server"
+	"github.com/neo-cloud-ai/gh-pitfall-scraper/internal/storage"
+)
+
+// webhookQueue is how many deliveries may wait for ingestion; GitHub
+// redelivers on request, so overflowing deliveries are dropped and logged
+const webhookQueue = 256
+
+// runWebhookListen receives GitHub webhook deliveries on addr until
+// interrupted and upserts the changed issues of configured repositories
+func runWebhookListen(ctx context.Context, config scraper.Config, addr string) error {
+	if config.Serve.WebhookSecret == "" {
+		return fmt.Errorf("serve.webhook_secret is required to verify webhook deliveries")
+	}
+	store := storage.NewStore(config.Storage.Dir)
+	scraperInstance := scraper.NewScraper(config)
+	useCorrections(scraperInstance, store)
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	events := make(chan server.WebhookEvent, webhookQueue)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ingestWebhookEvents(ctx, config, scraperInstance, store, events)
+	}()
+	handler := server.WebhookHandler(config.Serve.WebhookSecret, func(event server.WebhookEvent) {
+		select {
+		case events <- event:
+		default:
+			log.Printf("⚠️  警告: 接收队列已满, 丢弃 %s (可在 GitHub 上重新投递)", event.Delivery)
+		}
+	})
+
+	fmt.Printf("🪝 Webhook 接收已启动: %s (订阅 issues 和 issue_comment 事件, Content type: application/json)\n", addr)
+	err := server.ListenAndServe(ctx, addr, handler)
+	close(events)
+	<-done
+	return err
+}
+
+// ingestWebhookEvents upserts the issues of queued events one batch at a
+// time: events queued together are coalesced per issue, and duplicates
+// are linked and reporters summarized once per batch
+func ingestWebhookEvents(ctx context.Context, config scraper.Config, scraperInstance *scraper.Scraper, store *storage.Store, events <-chan server.WebhookEvent) {
+	for event := range events {
+		batch := map[string]server.WebhookEvent{model.IssueRef(event.Repository, event.Number): event}
+	drain:
+		for {
+			select {
+			case queued, ok := <-events:
+				if !ok {
+					break drain
+				}
+				batch[model.IssueRef(queued.Repository, queued.Number)] = queued
+			default:
+				break drain
+			}
+		}
+		if ctx.Err() != nil {
+			log.Printf("⚠️  警告: 服务停止, 未处理 %d 个问题的事件", len(batch))
+			continue
+		}
+
+		refs := make([]string, 0, len(batch))
+		for ref := range batch {
+			refs = append(refs, ref)
+		}
+		sort.Strings(refs)
+		changed := 0
+		for _, ref := range refs {
+			ok, err := ingestWebhookEvent(ctx, config, scraperInstance, store, batch[ref])
+			if err != nil {
+				log.Printf("⚠️  警告: 未能处理 %s: %v", ref, err)
+			} else if ok {
+				changed++
+			}
+		}
+		if changed == 0 {
+			continue
+		}
+		if _, err := runPipeline(ctx, config, store, []string{stepDedup, stepSummarize}, false); err != nil {
+			log.Printf("⚠️  警告: 未能运行处理流水线: %v", err)
+		}
+	}
+}
+
+// ingestWebhookEvent stores the current state of an event's issue through
+// the scrape path (classification, scoring, filtering), reporting whether
+// the store changed. Stored issues are refreshed; new ones are kept only
+// when they pass the filters like scraped issues.
+func ingestWebhookEvent(ctx context.Context, config scraper.Config, scraperInstance *scraper.Scraper, store *storage.Store, event server.WebhookEvent) (bool, error) {
+	repoName := ""
+	for _, repo := range config.Repositories {
+		if repo.Enabled && (repo.Source == "" || repo.Source == model.SourceGitHub) && strings.EqualFold(repo.Name, event.Repository) {
+			repoName = repo.Name
+		}
+	}
+	if repoName == "" {
+		log.Printf("⏭️  %s 不在配置的仓库中, 忽略", model.IssueRef(event.Repository, event.Number))
+		return false, nil
+	}
+
+	stored, err := store.LoadIssues()
+	if err != nil {
+		return false, fmt.Errorf("failed to load stored issues: %w", err)
+	}
+	known := false
+	for _, issue := range stored[repoName] {
+		known = known || issue.Number == event.Number
+	}
+	if event.Event == "issues" && event.Action == "deleted" {
+		if !known {
+			return false, nil
+		}
+		var marked int
+		err := store.Update(func() error {
+			var err error
+			marked, _, err = store.ApplyUpstreamChanges(repoName, []int{event.Number}, nil)
+			return err
+		})
+		return marked > 0, err
+	}
+
+	if known {
+		issue, err := refreshIssue(ctx, scraperInstance, store, repoName, event.Number)
+		if err != nil {
+			return false, err
+		}
+		log.Printf("🔄 已更新 %s (%s.%s, 评分 %.1f)", model.IssueRef(repoName, event.Number), event.Event, event.Action, issue.Score)
+		return true, nil
+	}
+	issue, err := scraperInstance.RefreshIssue(ctx, repoName, event.Number)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch %s: %w", model.IssueRef(repoName, event.Number), err)
+	}
+	kept := scraperInstance.FilterAndScoreIssues(map[string][]model.Issue{repoName: {issue}}, config)[repoName]
+	if len(kept) == 0 {
+		return false, nil
+	}
+	if err := store.Update(func() error { return store.UpdateIssue(repoName, kept[0]) }); err != nil {
+		return false, fmt.Errorf("failed to save %s: %w", model.IssueRef(repoName, event.Number), err)
+	}
+	log.Printf("🆕 已收录 %s (%s.%s, 评分 %.1f)", model.IssueRef(repoName, event.Number), event.Event, event.Action, kept[0].Score)
+	return true, nil
+}